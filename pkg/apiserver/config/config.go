@@ -42,6 +42,9 @@ type WorkflowRuntimeConfig struct {
 	DefaultJobTimeout time.Duration
 	// MaxConcurrentWorkflows limits how many workflow controllers run in parallel.
 	MaxConcurrentWorkflows int
+	// MaxConcurrentPerProject caps queued and running tasks of a single project on top of
+	// MaxConcurrentWorkflows. 0 disables the per-project cap.
+	MaxConcurrentPerProject int
 	// WorkerMaxReadFailures is the max consecutive read failures before worker exits.
 	// Set to 0 for infinite retries (recommended for resilience).
 	WorkerMaxReadFailures int
@@ -52,6 +55,38 @@ type WorkflowRuntimeConfig struct {
 	WorkerBackoffMin time.Duration
 	// WorkerBackoffMax is the maximum backoff duration for worker retries.
 	WorkerBackoffMax time.Duration
+	// JobRetryAttempts is the default number of attempts (including the first run)
+	// for a job that fails with a transient error. 1 disables retries.
+	JobRetryAttempts int
+	// JobRetryAttemptsByType overrides JobRetryAttempts per job type (e.g. service_deploy=5).
+	JobRetryAttemptsByType map[string]int
+	// JobRetryDelay is the delay before the first retry.
+	JobRetryDelay time.Duration
+	// JobRetryBackoffFactor multiplies the delay after every retry.
+	JobRetryBackoffFactor float64
+	// JobRetryMaxDelay caps the delay between retries.
+	JobRetryMaxDelay time.Duration
+	// TaskTimeout bounds a whole workflow task, measured from its first run and
+	// excluding time spent waiting for approval or paused. 0 (default) disables it.
+	TaskTimeout time.Duration
+	// StepTimeout applies to steps that do not set timeout_seconds. 0 disables it.
+	StepTimeout time.Duration
+	// SchedulePollInterval determines how often the leader fires due workflow schedules.
+	SchedulePollInterval time.Duration
+	// WorkerDrainGracePeriod is how long in-flight tasks may keep running once the worker
+	// drains; tasks still running afterwards are handed back to the queue.
+	WorkerDrainGracePeriod time.Duration
+	// WorkerMaxDeliveries is how often a dispatch message may be delivered before it is moved
+	// to the dead-letter stream. 0 disables the limit.
+	WorkerMaxDeliveries int
+	// QueuedReconcileInterval determines how often the leader re-queues tasks left in queued
+	// without an unacknowledged dispatch message.
+	QueuedReconcileInterval time.Duration
+	// CancelBackend selects how cancel and pause signals reach running tasks: auto, memory,
+	// datastore or redis. auto uses Redis when a client is configured and the datastore otherwise.
+	CancelBackend string
+	// CancelPollInterval determines how often the datastore backend re-reads the task status.
+	CancelPollInterval time.Duration
 }
 
 // CORSConfig configures cross-origin resource sharing for the HTTP API server.
@@ -110,6 +145,9 @@ type Config struct {
 
 	// CORS controls cross-origin access to the HTTP APIs.
 	CORS CORSConfig
+
+	// IdempotencyWindow is how long an Idempotency-Key and its response are kept for replay.
+	IdempotencyWindow time.Duration
 }
 
 type RedisCacheConfig struct {
@@ -196,19 +234,33 @@ func NewConfig() *Config {
 			WorkerReadBlock:          2 * time.Second,
 			DefaultJobTimeout:        60 * time.Second,
 			MaxConcurrentWorkflows:   DefaultMaxConcurrentWorkflows,
+			MaxConcurrentPerProject:  DefaultMaxConcurrentPerProject,
 			WorkerMaxReadFailures:    0, // 0 = infinite retries (resilient)
 			WorkerMaxClaimFailures:   0, // 0 = infinite retries (resilient)
 			WorkerBackoffMin:         200 * time.Millisecond,
 			WorkerBackoffMax:         5 * time.Minute,
+			JobRetryAttempts:         DefaultJobRetryAttempts,
+			JobRetryDelay:            DefaultJobRetryDelay,
+			JobRetryBackoffFactor:    DefaultJobRetryBackoffFactor,
+			JobRetryMaxDelay:         DefaultJobRetryMaxDelay,
+			TaskTimeout:              DefaultWorkflowTaskTimeout,
+			StepTimeout:              0,
+			SchedulePollInterval:     DefaultSchedulePollInterval,
+			WorkerDrainGracePeriod:   DefaultWorkerDrainGracePeriod,
+			WorkerMaxDeliveries:      DefaultWorkerMaxDeliveries,
+			QueuedReconcileInterval:  DefaultQueuedReconcileInterval,
+			CancelBackend:            CancelBackendAuto,
+			CancelPollInterval:       DefaultCancelPollInterval,
 		},
 		CORS: CORSConfig{
 			AllowedOrigins:   []string{"*"},
 			AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-			AllowedHeaders:   []string{"Content-Type", "Authorization", "Accept", "Origin", "X-Requested-With"},
-			ExposedHeaders:   []string{},
+			AllowedHeaders:   []string{"Content-Type", "Authorization", "Accept", "Origin", "X-Requested-With", IdempotencyKeyHeader},
+			ExposedHeaders:   []string{IdempotencyReplayedHeader},
 			AllowCredentials: false,
 			MaxAge:           12 * time.Hour,
 		},
+		IdempotencyWindow: DefaultIdempotencyWindow,
 	}
 }
 
@@ -255,6 +307,53 @@ func (c *Config) Validate() []error {
 	if c.Workflow.MaxConcurrentWorkflows <= 0 {
 		errs = append(errs, fmt.Errorf("workflow max concurrent executions must be > 0"))
 	}
+	if c.Workflow.MaxConcurrentPerProject < 0 {
+		errs = append(errs, fmt.Errorf("workflow max concurrent executions per project must be >= 0"))
+	}
+	if c.Workflow.JobRetryAttempts <= 0 {
+		errs = append(errs, fmt.Errorf("workflow job retry attempts must be >= 1"))
+	}
+	for jobType, attempts := range c.Workflow.JobRetryAttemptsByType {
+		if attempts <= 0 {
+			errs = append(errs, fmt.Errorf("workflow job retry attempts for %s must be >= 1", jobType))
+		}
+	}
+	if c.Workflow.JobRetryDelay < 0 || c.Workflow.JobRetryMaxDelay < 0 {
+		errs = append(errs, fmt.Errorf("workflow job retry delays must be >= 0"))
+	}
+	if c.Workflow.JobRetryBackoffFactor < 1 {
+		errs = append(errs, fmt.Errorf("workflow job retry backoff factor must be >= 1"))
+	}
+	if c.Workflow.TaskTimeout < 0 || c.Workflow.StepTimeout < 0 {
+		errs = append(errs, fmt.Errorf("workflow task and step timeouts must be >= 0"))
+	}
+	if c.Workflow.SchedulePollInterval <= 0 {
+		errs = append(errs, fmt.Errorf("workflow schedule poll interval must be > 0"))
+	}
+	if c.Workflow.WorkerDrainGracePeriod <= 0 {
+		errs = append(errs, fmt.Errorf("workflow worker drain grace period must be > 0"))
+	}
+	if c.Workflow.WorkerMaxDeliveries < 0 {
+		errs = append(errs, fmt.Errorf("workflow worker max deliveries must be >= 0"))
+	}
+	if c.Workflow.QueuedReconcileInterval <= 0 {
+		errs = append(errs, fmt.Errorf("workflow queued reconcile interval must be > 0"))
+	}
+	switch strings.ToLower(strings.TrimSpace(c.Workflow.CancelBackend)) {
+	case CancelBackendAuto, CancelBackendMemory, CancelBackendDatastore:
+	case CancelBackendRedis:
+		if strings.TrimSpace(c.Cache.CacheHost) == "" || c.Cache.CacheProt <= 0 {
+			errs = append(errs, fmt.Errorf("redis cache host/port must be set when workflow cancel backend is redis"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unsupported workflow cancel backend: %s", c.Workflow.CancelBackend))
+	}
+	if c.Workflow.CancelPollInterval <= 0 {
+		errs = append(errs, fmt.Errorf("workflow cancel poll interval must be > 0"))
+	}
+	if c.IdempotencyWindow <= 0 {
+		errs = append(errs, fmt.Errorf("idempotency window must be > 0"))
+	}
 	// messaging basic checks
 	msgType := strings.ToLower(strings.TrimSpace(c.Messaging.Type))
 	switch msgType {
@@ -330,6 +429,21 @@ func (c *Config) AddFlags(fs *pflag.FlagSet, configParameter *Config) {
 	fs.DurationVar(&c.Workflow.WorkerReadBlock, "workflow-worker-read-block", configParameter.Workflow.WorkerReadBlock, "workflow worker stream read block duration")
 	fs.DurationVar(&c.Workflow.DefaultJobTimeout, "workflow-default-job-timeout", configParameter.Workflow.DefaultJobTimeout, "default workflow job timeout")
 	fs.IntVar(&c.Workflow.MaxConcurrentWorkflows, "workflow-max-concurrent", configParameter.Workflow.MaxConcurrentWorkflows, "maximum number of workflow controllers running concurrently")
+	fs.IntVar(&c.Workflow.MaxConcurrentPerProject, "workflow-max-concurrent-per-project", configParameter.Workflow.MaxConcurrentPerProject, "maximum number of queued or running workflow tasks per project (0 disables)")
+	fs.IntVar(&c.Workflow.JobRetryAttempts, "workflow-job-retry-attempts", configParameter.Workflow.JobRetryAttempts, "default attempts (including the first run) for jobs failing with transient errors (1 disables retries)")
+	fs.StringToIntVar(&c.Workflow.JobRetryAttemptsByType, "workflow-job-retry-attempts-by-type", configParameter.Workflow.JobRetryAttemptsByType, "per job type retry attempts, e.g. service_deploy=5,store_pvc_deploy=1")
+	fs.DurationVar(&c.Workflow.JobRetryDelay, "workflow-job-retry-delay", configParameter.Workflow.JobRetryDelay, "delay before the first job retry")
+	fs.Float64Var(&c.Workflow.JobRetryBackoffFactor, "workflow-job-retry-backoff-factor", configParameter.Workflow.JobRetryBackoffFactor, "multiplier applied to the retry delay after every attempt (>=1)")
+	fs.DurationVar(&c.Workflow.JobRetryMaxDelay, "workflow-job-retry-max-delay", configParameter.Workflow.JobRetryMaxDelay, "upper bound for the delay between job retries")
+	fs.DurationVar(&c.Workflow.TaskTimeout, "workflow-task-timeout", configParameter.Workflow.TaskTimeout, "deadline for a whole workflow task measured from its first run, excluding suspended time (0 disables)")
+	fs.DurationVar(&c.Workflow.StepTimeout, "workflow-step-timeout", configParameter.Workflow.StepTimeout, "default timeout for workflow steps without timeout_seconds (0 disables)")
+	fs.DurationVar(&c.Workflow.SchedulePollInterval, "workflow-schedule-poll-interval", configParameter.Workflow.SchedulePollInterval, "how often the leader fires due workflow schedules")
+	fs.DurationVar(&c.Workflow.WorkerDrainGracePeriod, "workflow-worker-drain-grace-period", configParameter.Workflow.WorkerDrainGracePeriod, "how long in-flight workflow tasks may run after a drain starts before they are handed back to the queue")
+	fs.IntVar(&c.Workflow.WorkerMaxDeliveries, "workflow-worker-max-deliveries", configParameter.Workflow.WorkerMaxDeliveries, "how often a dispatch message may be delivered before it is moved to the dead-letter stream (0 disables)")
+	fs.DurationVar(&c.Workflow.QueuedReconcileInterval, "workflow-queued-reconcile-interval", configParameter.Workflow.QueuedReconcileInterval, "how often the leader re-queues tasks left in queued without a dispatch message")
+	fs.StringVar(&c.Workflow.CancelBackend, "workflow-cancel-backend", configParameter.Workflow.CancelBackend, "how cancel and pause signals reach running tasks: auto|memory|datastore|redis (memory is single-instance only)")
+	fs.DurationVar(&c.Workflow.CancelPollInterval, "workflow-cancel-poll-interval", configParameter.Workflow.CancelPollInterval, "how often the datastore cancel backend re-reads the task status")
+	fs.DurationVar(&c.IdempotencyWindow, "idempotency-window", configParameter.IdempotencyWindow, "how long Idempotency-Key headers and their responses are kept for replay")
 	// profiling flags live in the profiling package; wire them here for convenience
 	profiling.AddFlags(fs)
 }
@@ -26,3 +26,62 @@ func TestValidateSequentialConcurrency(t *testing.T) {
 		require.Empty(t, errs)
 	})
 }
+
+func TestValidateWorkflowTimeouts(t *testing.T) {
+	cfg := NewConfig()
+	require.Equal(t, DefaultWorkflowTaskTimeout, cfg.Workflow.TaskTimeout)
+
+	cfg.Workflow.TaskTimeout = 0
+	require.Empty(t, cfg.Validate())
+
+	cfg.Workflow.StepTimeout = -1
+	require.NotEmpty(t, cfg.Validate())
+}
+
+func TestValidateIdempotencyWindow(t *testing.T) {
+	cfg := NewConfig()
+	require.Equal(t, DefaultIdempotencyWindow, cfg.IdempotencyWindow)
+
+	cfg.IdempotencyWindow = 0
+	require.NotEmpty(t, cfg.Validate())
+}
+
+func TestValidateWorkerDrainGracePeriod(t *testing.T) {
+	cfg := NewConfig()
+	require.Equal(t, DefaultWorkerDrainGracePeriod, cfg.Workflow.WorkerDrainGracePeriod)
+
+	cfg.Workflow.WorkerDrainGracePeriod = 0
+	require.NotEmpty(t, cfg.Validate())
+}
+
+func TestValidateDeadLetterSettings(t *testing.T) {
+	cfg := NewConfig()
+	require.Equal(t, DefaultWorkerMaxDeliveries, cfg.Workflow.WorkerMaxDeliveries)
+	require.Equal(t, DefaultQueuedReconcileInterval, cfg.Workflow.QueuedReconcileInterval)
+
+	cfg.Workflow.WorkerMaxDeliveries = 0
+	require.Empty(t, cfg.Validate(), "0 disables the delivery limit")
+
+	cfg.Workflow.WorkerMaxDeliveries = -1
+	cfg.Workflow.QueuedReconcileInterval = 0
+	require.Len(t, cfg.Validate(), 2)
+}
+
+func TestValidateCancelBackend(t *testing.T) {
+	cfg := NewConfig()
+	require.Equal(t, CancelBackendAuto, cfg.Workflow.CancelBackend)
+
+	for _, backend := range []string{CancelBackendMemory, CancelBackendDatastore, "Redis"} {
+		cfg.Workflow.CancelBackend = backend
+		require.Empty(t, cfg.Validate(), backend)
+	}
+
+	cfg.Workflow.CancelBackend = "etcd"
+	cfg.Workflow.CancelPollInterval = 0
+	require.Len(t, cfg.Validate(), 2)
+
+	cfg.Workflow.CancelBackend = CancelBackendRedis
+	cfg.Workflow.CancelPollInterval = DefaultCancelPollInterval
+	cfg.Cache.CacheHost = ""
+	require.NotEmpty(t, cfg.Validate())
+}
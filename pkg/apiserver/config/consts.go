@@ -50,6 +50,11 @@ const (
 	ConfJob   JobType = "config"
 	SecretJob JobType = "secret"
 	Service   JobType = "service"
+	// BatchJob 一次性批处理任务（如数据库迁移），CronJob 周期性批处理任务（如定时报表）
+	BatchJob JobType = "job"
+	CronJob  JobType = "cronjob"
+	// DaemonJob 在每个节点上运行一个副本的守护服务（如日志采集、节点代理）
+	DaemonJob JobType = "daemon"
 
 	JobDeploy                   JobType = "deploy"
 	JobDeployService            JobType = "service_deploy"
@@ -63,6 +68,17 @@ const (
 	JobDeployRoleBinding        JobType = "role_binding_deploy"
 	JobDeployClusterRole        JobType = "cluster_role_deploy"
 	JobDeployClusterRoleBinding JobType = "cluster_role_binding_deploy"
+	JobDeployBatchJob           JobType = "batch_job_deploy"
+	JobDeployCronJob            JobType = "cronjob_deploy"
+	JobDeployDaemonSet          JobType = "daemonset_deploy"
+	// JobApproval 审批步骤：执行到此处时挂起任务，人工批准后从下一步继续
+	JobApproval JobType = "approval"
+	// JobWait 内置步骤：等待固定时长，或轮询直到条件表达式为真
+	JobWait JobType = "wait"
+	// JobHTTPCheck 内置步骤：轮询 URL 直到返回期望的状态码或响应内容
+	JobHTTPCheck JobType = "http-check"
+	// JobNotify 内置步骤：向 URL POST 一次通知载荷
+	JobNotify JobType = "notify"
 
 	DefaultRun    JobRunPolicy = ""
 	DefaultNotRun JobRunPolicy = "default_not_run"
@@ -99,11 +115,46 @@ const (
 	DefaultWorkerBackoffMax       = 5 * time.Minute        // 最大退避时间
 	DefaultWorkerMaxReadFailures  = 10                     // 连续 10 次失败后退出
 	DefaultWorkerMaxClaimFailures = 10                     // 连续 10 次失败后退出
+
+	// Job retry settings
+	DefaultJobRetryAttempts      = 1 // 包含首次执行在内的总尝试次数，默认不重试
+	DefaultJobRetryDelay         = 2 * time.Second
+	DefaultJobRetryBackoffFactor = 2.0
+	DefaultJobRetryMaxDelay      = 30 * time.Second
+
+	// DefaultWorkflowTaskTimeout 整个工作流任务的默认时限，0 表示不限制
+	DefaultWorkflowTaskTimeout = time.Duration(0)
+	// DefaultSchedulePollInterval leader 检查到期定时执行的间隔
+	DefaultSchedulePollInterval = 30 * time.Second
+	// DefaultWorkerDrainGracePeriod 排空时等待运行中任务结束的默认时长，应小于 Pod 的 terminationGracePeriodSeconds
+	DefaultWorkerDrainGracePeriod = 25 * time.Second
+	// WorkerDrainHandoffTimeout 宽限期结束后等待被取消的任务交还队列的时长
+	WorkerDrainHandoffTimeout = 10 * time.Second
+	// DefaultWorkerMaxDeliveries 分发消息被投递超过该次数后转入死信，0 表示不限制
+	DefaultWorkerMaxDeliveries = 5
+	// DefaultQueuedReconcileInterval leader 检查停留在 queued 且没有未确认消息的任务的间隔
+	DefaultQueuedReconcileInterval = time.Minute
+	// DefaultDeadLetterListLimit 死信列表默认返回的条数
+	DefaultDeadLetterListLimit = 50
+	// MaxDeadLetterListLimit 死信列表单次返回的最大条数
+	MaxDeadLetterListLimit = 500
+	// DefaultCancelPollInterval 数据库取消后端回读任务状态的间隔
+	DefaultCancelPollInterval = 3 * time.Second
+	// DefaultMaxConcurrentPerProject 单个项目同时排队/运行的任务上限，0 表示不限制
+	DefaultMaxConcurrentPerProject = 0
+	// MaxWorkflowTaskPriority 任务优先级上限，数值越大越先调度
+	MaxWorkflowTaskPriority = 100
+
+	// Built-in step settings
+	DefaultBuiltinStepTimeout      = 10 * time.Minute // wait(until)/http-check 未设置步骤时限时的轮询上限
+	DefaultBuiltinStepPollInterval = 5 * time.Second
+	BuiltinStepRequestTimeout      = 10 * time.Second // 单次 HTTP 请求的时限
 )
 
 const (
 	StatusCompleted      Status = "completed"                      //执行完毕
 	StatusDisabled       Status = "disabled"                       //已关闭
+	StatusEnabled        Status = "enabled"                        //已开启
 	StatusCreated        Status = "created"                        //创建
 	StatusRunning        Status = "running"                        //运行中
 	StatusPassed         Status = "passed"                         //通过
@@ -126,16 +177,19 @@ const (
 	StatusDebugAfter     Status = "debug_after"                    //调试之后
 	StatusUnstable       Status = "unstable"                       //不稳定
 	StatusManualApproval Status = "wait_for_manual_error_handling" //等待手动错误处理
+	StatusRolledBack     Status = "rolled_back"                    //失败后已回滚
+	StatusRollbackFailed Status = "rollback_failed"                //失败后回滚失败
 )
 
 // ComponentStatus 组件运行时状态（由 Informer 同步）
 type ComponentStatus string
 
 const (
-	ComponentStatusRunning ComponentStatus = "Running" // 运行中（所有副本就绪）
-	ComponentStatusPending ComponentStatus = "Pending" // 部分副本就绪或正在启动
-	ComponentStatusFailed  ComponentStatus = "Failed"  // 失败
-	ComponentStatusUnknown ComponentStatus = "Unknown" // 未知状态
+	ComponentStatusRunning   ComponentStatus = "Running"   // 运行中（所有副本就绪）
+	ComponentStatusPending   ComponentStatus = "Pending"   // 部分副本就绪或正在启动
+	ComponentStatusFailed    ComponentStatus = "Failed"    // 失败
+	ComponentStatusSucceeded ComponentStatus = "Succeeded" // 批处理任务已成功执行完毕
+	ComponentStatusUnknown   ComponentStatus = "Unknown"   // 未知状态
 )
 
 const (
@@ -165,6 +219,143 @@ func (m WorkflowMode) IsParallel() bool {
 	return m == WorkflowModeDAG
 }
 
+// RollbackMode 工作流失败后的回滚方式
+type RollbackMode string
+
+const (
+	RollbackModeNone      RollbackMode = "none"      // 不回滚，保持失败现场
+	RollbackModeAutomatic RollbackMode = "automatic" // 失败后自动恢复到执行前的资源状态
+	RollbackModeManual    RollbackMode = "manual"    // 保留快照，由用户手动触发回滚
+)
+
+// ParseRollbackMode normalizes rollback mode values, defaulting to none when empty or unknown.
+func ParseRollbackMode(mode string) RollbackMode {
+	switch RollbackMode(strings.ToLower(strings.TrimSpace(mode))) {
+	case RollbackModeAutomatic:
+		return RollbackModeAutomatic
+	case RollbackModeManual:
+		return RollbackModeManual
+	default:
+		return RollbackModeNone
+	}
+}
+
+// IsValidRollbackMode reports whether mode is empty or one of the supported rollback modes.
+func IsValidRollbackMode(mode string) bool {
+	switch RollbackMode(strings.ToLower(strings.TrimSpace(mode))) {
+	case "", RollbackModeNone, RollbackModeAutomatic, RollbackModeManual:
+		return true
+	default:
+		return false
+	}
+}
+
+// ScheduleConcurrencyPolicy 定时执行到点时，上一次触发的任务仍未结束的处理方式
+type ScheduleConcurrencyPolicy string
+
+const (
+	ScheduleConcurrencyAllow   ScheduleConcurrencyPolicy = "allow"   // 照常创建新任务
+	ScheduleConcurrencyForbid  ScheduleConcurrencyPolicy = "forbid"  // 跳过本次执行
+	ScheduleConcurrencyReplace ScheduleConcurrencyPolicy = "replace" // 取消上一次的任务后创建新任务
+)
+
+// ParseScheduleConcurrencyPolicy normalizes policy values, defaulting to forbid when empty or unknown.
+func ParseScheduleConcurrencyPolicy(policy string) ScheduleConcurrencyPolicy {
+	switch ScheduleConcurrencyPolicy(strings.ToLower(strings.TrimSpace(policy))) {
+	case ScheduleConcurrencyAllow:
+		return ScheduleConcurrencyAllow
+	case ScheduleConcurrencyReplace:
+		return ScheduleConcurrencyReplace
+	default:
+		return ScheduleConcurrencyForbid
+	}
+}
+
+// IsValidScheduleConcurrencyPolicy reports whether policy is empty or one of the supported policies.
+func IsValidScheduleConcurrencyPolicy(policy string) bool {
+	switch ScheduleConcurrencyPolicy(strings.ToLower(strings.TrimSpace(policy))) {
+	case "", ScheduleConcurrencyAllow, ScheduleConcurrencyForbid, ScheduleConcurrencyReplace:
+		return true
+	default:
+		return false
+	}
+}
+
+// AppConcurrencyPolicy 同一应用已有未结束的任务时，新任务的处理方式
+type AppConcurrencyPolicy string
+
+const (
+	AppConcurrencyQueue     AppConcurrencyPolicy = "queue"     // 排队，等前一个任务结束后再派发
+	AppConcurrencyReject    AppConcurrencyPolicy = "reject"    // 拒绝新的执行请求
+	AppConcurrencySupersede AppConcurrencyPolicy = "supersede" // 取消较早的任务，只执行最新的任务
+)
+
+// ParseAppConcurrencyPolicy normalizes policy values, defaulting to queue when empty or unknown.
+func ParseAppConcurrencyPolicy(policy string) AppConcurrencyPolicy {
+	switch AppConcurrencyPolicy(strings.ToLower(strings.TrimSpace(policy))) {
+	case AppConcurrencyReject:
+		return AppConcurrencyReject
+	case AppConcurrencySupersede:
+		return AppConcurrencySupersede
+	default:
+		return AppConcurrencyQueue
+	}
+}
+
+// IsValidAppConcurrencyPolicy reports whether policy is empty or one of the supported policies.
+func IsValidAppConcurrencyPolicy(policy string) bool {
+	switch AppConcurrencyPolicy(strings.ToLower(strings.TrimSpace(policy))) {
+	case "", AppConcurrencyQueue, AppConcurrencyReject, AppConcurrencySupersede:
+		return true
+	default:
+		return false
+	}
+}
+
+// WebhookDeliveryResult 入站 webhook 投递的处理结果
+type WebhookDeliveryResult string
+
+const (
+	WebhookDeliveryTriggered WebhookDeliveryResult = "triggered" // 已更新版本并创建工作流任务
+	WebhookDeliveryUpdated   WebhookDeliveryResult = "updated"   // 已更新版本，组件无变化未创建任务
+	WebhookDeliveryIgnored   WebhookDeliveryResult = "ignored"   // 已停用或负载中缺少映射字段
+	WebhookDeliveryRejected  WebhookDeliveryResult = "rejected"  // 签名校验失败
+	WebhookDeliveryFailed    WebhookDeliveryResult = "failed"    // 版本更新或任务创建失败
+)
+
+const (
+	// WebhookSignatureHeader 请求体的 HMAC-SHA256 签名，格式为 sha256=<hex>
+	WebhookSignatureHeader = "X-KubeMin-Signature"
+	// WebhookHubSignatureHeader 兼容 GitHub 风格的签名头
+	WebhookHubSignatureHeader = "X-Hub-Signature-256"
+	// WebhookPayloadLogLimit 投递记录中保存的请求体最大字节数
+	WebhookPayloadLogLimit = 4096
+)
+
+// IdempotencyStatus 幂等记录的处理状态
+type IdempotencyStatus string
+
+const (
+	IdempotencyPending   IdempotencyStatus = "pending"   // 首个请求仍在处理
+	IdempotencyCompleted IdempotencyStatus = "completed" // 已保存响应，可重放
+)
+
+const (
+	// IdempotencyKeyHeader 客户端为可重试的写请求指定的幂等键
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotencyReplayedHeader 响应为重放时设置为 true
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+	// IdempotencyKeyMaxLength 幂等键的最大长度
+	IdempotencyKeyMaxLength = 255
+	// DefaultIdempotencyWindow 幂等键及其响应的默认保留时长
+	DefaultIdempotencyWindow = 24 * time.Hour
+	// IdempotencyPendingTimeout 处理中的记录超过该时长视为首个请求已中断，允许重新执行
+	IdempotencyPendingTimeout = 5 * time.Minute
+)
+
+// ExecutionPlanVersion 持久化执行计划的格式版本；计划或 JobInfo 的编码方式变化时递增，旧版本计划会被丢弃并重新生成
+const ExecutionPlanVersion = 1
+
 // 用户侧声明的存储类型（API 入参）
 const (
 	StorageTypePersistent  = "persistent"
@@ -248,3 +439,25 @@ func ParseComponentAction(action string) ComponentAction {
 		return ComponentActionUpdate
 	}
 }
+
+const (
+	// DiagnosticsLogTailLines 失败 Job 采集诊断时，每个异常容器保留的最后日志行数
+	DiagnosticsLogTailLines = 50
+	// DiagnosticsMaxPods 单个工作负载最多采集的 Pod 数，异常 Pod 优先
+	DiagnosticsMaxPods = 5
+	// DiagnosticsMaxEvents 保留的最近 Kubernetes Events 条数
+	DiagnosticsMaxEvents = 20
+	// DiagnosticsTimeout 采集诊断的总超时；Job 上下文可能已超时，采集使用独立的期限
+	DiagnosticsTimeout = 15 * time.Second
+)
+
+const (
+	// CancelBackendAuto 配置了 Redis 时使用 Redis，否则轮询数据库
+	CancelBackendAuto = "auto"
+	// CancelBackendMemory 进程内传递取消信号，仅适用于单实例部署
+	CancelBackendMemory = "memory"
+	// CancelBackendDatastore 轮询任务记录发现取消，无需额外组件即可跨实例生效
+	CancelBackendDatastore = "datastore"
+	// CancelBackendRedis 通过 Redis 键传递取消信号
+	CancelBackendRedis = "redis"
+)
@@ -16,4 +16,17 @@ const (
 	ResourceRoleBinding        ResourceKind = "rolebinding"
 	ResourceClusterRole        ResourceKind = "clusterrole"
 	ResourceClusterRoleBinding ResourceKind = "clusterrolebinding"
+	ResourceJob                ResourceKind = "job"
+	ResourceCronJob            ResourceKind = "cronjob"
+	ResourceDaemonSet          ResourceKind = "daemonset"
+)
+
+// PlanAction describes what executing a workflow would do to a resource.
+type PlanAction string
+
+const (
+	PlanActionCreate PlanAction = "create"
+	PlanActionUpdate PlanAction = "update"
+	PlanActionNoop   PlanAction = "no-op"
+	PlanActionDelete PlanAction = "delete"
 )
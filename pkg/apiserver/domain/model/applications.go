@@ -19,6 +19,8 @@ type Applications struct {
 	Description string `json:"description"` //详情
 	Icon        string `json:"icon"`        //图标
 	TmpEnable   bool   `json:"tmp_enable"`  // 是否允许作为模板被引用
+	// ConcurrencyPolicy 同一应用的工作流任务串行策略：queue（默认）、reject、supersede
+	ConcurrencyPolicy config.AppConcurrencyPolicy `json:"concurrency_policy,omitempty" gorm:"column:concurrency_policy"`
 	BaseModel
 }
 
@@ -105,6 +107,8 @@ func (w *ApplicationComponent) Index() map[string]interface{} {
 
 type Properties = spec.Properties
 
+type JobProperties = spec.JobProperties
+
 type Ports = spec.Ports
 
 // Traits 附加特性
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"kubemin-cli/pkg/apiserver/config"
+)
+
+func init() {
+	RegisterModel(&ExecutionPlan{})
+}
+
+// ExecutionPlan 任务首次运行时生成的步骤与 Job 列表，进程重启或消息被 AutoClaim 后按该计划继续执行，
+// 而不是依据当前的工作流与组件定义重新生成
+type ExecutionPlan struct {
+	TaskID  string        `gorm:"primaryKey;type:varchar(255)" json:"task_id"`
+	Version int           `json:"version"` //计划格式版本，与 config.ExecutionPlanVersion 不一致时重新生成
+	Steps   []PlannedStep `gorm:"serializer:json" json:"steps"`
+	BaseModel
+}
+
+// PlannedStep 一次步骤执行
+type PlannedStep struct {
+	Name      string                `json:"name"`
+	Mode      config.WorkflowMode   `json:"mode"`
+	Step      string                `json:"step,omitempty"`
+	DependsOn []string              `json:"depends_on,omitempty"`
+	Retry     *RetryPolicy          `json:"retry,omitempty"`
+	Approval  bool                  `json:"approval,omitempty"`
+	Condition string                `json:"condition,omitempty"`
+	Timeout   time.Duration         `json:"timeout,omitempty"`
+	Jobs      map[int][]*PlannedJob `json:"jobs,omitempty"` //按优先级分组
+}
+
+// PlannedJob 一个 Job 及其要写入集群的对象
+type PlannedJob struct {
+	Name       string          `json:"name"`
+	Namespace  string          `json:"namespace"`
+	WorkflowID string          `json:"workflow_id"`
+	ProjectID  string          `json:"project_id"`
+	AppID      string          `json:"app_id"`
+	JobType    string          `json:"job_type"`
+	Status     config.Status   `json:"status,omitempty"` //生成时已确定的状态，如共享资源被忽略时为 skipped
+	Timeout    int64           `json:"timeout"`
+	InfoKind   string          `json:"info_kind,omitempty"` //JobInfo 的具体类型
+	Info       json.RawMessage `json:"info,omitempty"`
+}
+
+func (p *ExecutionPlan) PrimaryKey() string {
+	return p.TaskID
+}
+
+func (p *ExecutionPlan) TableName() string {
+	return tableNamePrefix + "execution_plan"
+}
+
+func (p *ExecutionPlan) ShortTableName() string {
+	return "execution_plan"
+}
+
+func (p *ExecutionPlan) Index() map[string]interface{} {
+	index := make(map[string]interface{})
+	if p.TaskID != "" {
+		index["task_id"] = p.TaskID
+	}
+	return index
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutionPlan_EntityContract(t *testing.T) {
+	plan := &ExecutionPlan{TaskID: "task-1"}
+
+	require.Equal(t, "min_execution_plan", plan.TableName())
+	require.Equal(t, "execution_plan", plan.ShortTableName())
+	require.Equal(t, "task-1", plan.PrimaryKey())
+	require.Equal(t, "task-1", plan.Index()["task_id"])
+
+	registered := GetRegisterModels()
+	_, ok := registered[plan.TableName()]
+	require.True(t, ok, "expected model to be registered for auto-migration")
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"kubemin-cli/pkg/apiserver/config"
+)
+
+func init() {
+	RegisterModel(&IdempotencyRecord{})
+}
+
+// IdempotencyRecord 带 Idempotency-Key 的写请求及其响应，有效期内相同请求直接重放首次响应
+type IdempotencyRecord struct {
+	ID          string                   `gorm:"primaryKey;type:varchar(64)" json:"id"` //scope 与 key 的 SHA-256
+	Scope       string                   `json:"scope"`                                 //接口及其路径参数，如 workflow.exec:<appID>
+	Key         string                   `gorm:"column:idempotency_key;type:varchar(255)" json:"key"`
+	RequestHash string                   `json:"request_hash"`
+	Status      config.IdempotencyStatus `json:"status"`
+	Response    string                   `gorm:"type:text" json:"response,omitempty"` //首次成功响应的 JSON
+	ExpireTime  int64                    `json:"expire_time"`                         //Unix 秒，过期后该键可重新使用
+	BaseModel
+}
+
+func (r *IdempotencyRecord) PrimaryKey() string {
+	return r.ID
+}
+
+func (r *IdempotencyRecord) TableName() string {
+	return tableNamePrefix + "idempotency_record"
+}
+
+func (r *IdempotencyRecord) ShortTableName() string {
+	return "idempotency_record"
+}
+
+func (r *IdempotencyRecord) Index() map[string]interface{} {
+	index := make(map[string]interface{})
+	if r.ID != "" {
+		index["id"] = r.ID
+	}
+	if r.Scope != "" {
+		index["scope"] = r.Scope
+	}
+	return index
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyRecord_EntityContract(t *testing.T) {
+	record := &IdempotencyRecord{
+		ID:    "abc",
+		Scope: "applications.create",
+	}
+
+	require.Equal(t, "min_idempotency_record", record.TableName())
+	require.Equal(t, "idempotency_record", record.ShortTableName())
+	require.Equal(t, "abc", record.PrimaryKey())
+
+	index := record.Index()
+	require.Equal(t, "abc", index["id"])
+	require.Equal(t, "applications.create", index["scope"])
+
+	registered := GetRegisterModels()
+	_, ok := registered[record.TableName()]
+	require.True(t, ok, "expected model to be registered for auto-migration")
+}
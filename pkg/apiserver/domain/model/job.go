@@ -25,6 +25,7 @@ type JobInfo struct {
 	Error       string `json:"error"`
 	Production  bool   `json:"production"` // 是否生产
 	TargetEnv   string `json:"target_env"` //目标环境
+	Attempt     int    `json:"attempt"`    //第几次尝试，从 1 开始
 	BaseModel
 }
 
@@ -44,6 +45,13 @@ type JobTask struct {
 	Error      string
 	Timeout    int64
 	RetryCount int //重试次数
+	// RetryPolicy 控制失败后的重试；为空时只执行一次
+	RetryPolicy *RetryPolicy
+}
+
+// Attempt returns the 1-based number of the current execution attempt.
+func (j *JobTask) Attempt() int {
+	return j.RetryCount + 1
 }
 
 func (j *JobInfo) PrimaryKey() string {
@@ -75,3 +83,35 @@ type JobDeployInfo struct {
 	Replicas      int32 //期望副本数量
 	ReadyReplicas int32 //就绪副本数量
 }
+
+// DryRunJobResult 记录单个 Job 以 DryRun 方式提交到 API Server 的结果
+type DryRunJobResult struct {
+	Step      string              `json:"step"`
+	Name      string              `json:"name"`
+	JobType   string              `json:"job_type"`
+	Kind      config.ResourceKind `json:"kind,omitempty"`
+	Namespace string              `json:"namespace,omitempty"`
+	Resource  string              `json:"resource,omitempty"`
+	Status    config.Status       `json:"status"`
+	Error     string              `json:"error,omitempty"`
+}
+
+// ResourcePlan 描述执行工作流时单个资源将发生的变化，Changes 只包含期望对象中声明的字段
+type ResourcePlan struct {
+	Step      string              `json:"step,omitempty"`
+	Job       string              `json:"job,omitempty"`
+	JobType   string              `json:"job_type,omitempty"`
+	Kind      config.ResourceKind `json:"kind"`
+	Namespace string              `json:"namespace,omitempty"`
+	Name      string              `json:"name"`
+	Action    config.PlanAction   `json:"action"`
+	Changes   []FieldChange       `json:"changes,omitempty"`
+	Error     string              `json:"error,omitempty"`
+}
+
+// FieldChange 单个字段在线上与期望状态之间的差异，Path 形如 spec.template.spec.containers[0].image
+type FieldChange struct {
+	Path    string      `json:"path"`
+	Live    interface{} `json:"live,omitempty"`
+	Desired interface{} `json:"desired,omitempty"`
+}
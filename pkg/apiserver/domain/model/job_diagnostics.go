@@ -0,0 +1,83 @@
+package model
+
+import (
+	"strconv"
+
+	"kubemin-cli/pkg/apiserver/config"
+)
+
+func init() {
+	RegisterModel(&JobDiagnostics{})
+}
+
+// JobDiagnostics 记录工作负载类 Job 失败时采集的现场信息，通过 JobInfoID 关联到对应的 JobInfo
+type JobDiagnostics struct {
+	ID        int                 `json:"id" gorm:"primaryKey"`
+	JobInfoID int                 `gorm:"column:job_info_id" json:"job_info_id"`
+	TaskID    string              `gorm:"column:taskid" json:"task_id"`
+	AppID     string              `gorm:"column:app_id" json:"app_id"`
+	JobName   string              `json:"job_name"`
+	Kind      config.ResourceKind `json:"kind"`
+	Namespace string              `json:"namespace"`
+	Name      string              `json:"name"`                                    //工作负载名称
+	Pods      []PodDiagnostic     `json:"pods,omitempty" gorm:"serializer:json"`   //最多采集 config.DiagnosticsMaxPods 个 Pod
+	Events    []EventDiagnostic   `json:"events,omitempty" gorm:"serializer:json"` //工作负载及其 Pod 最近的 Kubernetes Events
+	Error     string              `json:"error,omitempty"`                         //采集过程中的错误，采集失败不影响 Job 结果
+	BaseModel
+}
+
+// PodDiagnostic Pod 的阶段、未就绪条件与容器状态
+type PodDiagnostic struct {
+	Name       string                `json:"name"`
+	Phase      string                `json:"phase"`
+	Reason     string                `json:"reason,omitempty"`
+	Message    string                `json:"message,omitempty"`
+	Conditions []string              `json:"conditions,omitempty"` //未满足的条件，格式 Type: Reason - Message
+	Containers []ContainerDiagnostic `json:"containers,omitempty"`
+}
+
+// ContainerDiagnostic 容器的等待/终止原因，以及异常容器最后的日志
+type ContainerDiagnostic struct {
+	Name              string `json:"name"`
+	Ready             bool   `json:"ready"`
+	RestartCount      int32  `json:"restart_count"`
+	WaitingReason     string `json:"waiting_reason,omitempty"`
+	WaitingMessage    string `json:"waiting_message,omitempty"`
+	TerminatedReason  string `json:"terminated_reason,omitempty"`
+	TerminatedMessage string `json:"terminated_message,omitempty"`
+	ExitCode          int32  `json:"exit_code,omitempty"`
+	Logs              string `json:"logs,omitempty"`
+}
+
+// EventDiagnostic 一条 Kubernetes Event
+type EventDiagnostic struct {
+	Object   string `json:"object"` //Kind/Name
+	Type     string `json:"type"`
+	Reason   string `json:"reason"`
+	Message  string `json:"message"`
+	Count    int32  `json:"count,omitempty"`
+	LastSeen int64  `json:"last_seen,omitempty"` //Unix 秒
+}
+
+func (d *JobDiagnostics) PrimaryKey() string {
+	return strconv.Itoa(d.ID)
+}
+
+func (d *JobDiagnostics) TableName() string {
+	return tableNamePrefix + "job_diagnostics"
+}
+
+func (d *JobDiagnostics) ShortTableName() string {
+	return "job_diagnostics"
+}
+
+func (d *JobDiagnostics) Index() map[string]interface{} {
+	index := make(map[string]interface{})
+	if d.TaskID != "" {
+		index["taskid"] = d.TaskID
+	}
+	if d.JobInfoID != 0 {
+		index["job_info_id"] = d.JobInfoID
+	}
+	return index
+}
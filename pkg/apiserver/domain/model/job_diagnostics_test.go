@@ -0,0 +1,27 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobDiagnostics_EntityContract(t *testing.T) {
+	diag := &JobDiagnostics{
+		ID:        3,
+		JobInfoID: 11,
+		TaskID:    "task-1",
+	}
+
+	require.Equal(t, "min_job_diagnostics", diag.TableName())
+	require.Equal(t, "job_diagnostics", diag.ShortTableName())
+	require.Equal(t, "3", diag.PrimaryKey())
+
+	index := diag.Index()
+	require.Equal(t, "task-1", index["taskid"])
+	require.Equal(t, 11, index["job_info_id"])
+
+	registered := GetRegisterModels()
+	_, ok := registered[diag.TableName()]
+	require.True(t, ok, "expected model to be registered for auto-migration")
+}
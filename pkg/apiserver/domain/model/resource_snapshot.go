@@ -0,0 +1,50 @@
+package model
+
+import (
+	"strconv"
+
+	"kubemin-cli/pkg/apiserver/config"
+)
+
+func init() {
+	RegisterModel(&ResourceSnapshot{})
+}
+
+// ResourceSnapshot 记录工作流任务修改资源之前的线上状态，用于失败后回滚
+type ResourceSnapshot struct {
+	ID        int                 `json:"id" gorm:"primaryKey"`
+	TaskID    string              `gorm:"column:taskid" json:"task_id"`
+	AppID     string              `gorm:"column:app_id" json:"app_id"`
+	Kind      config.ResourceKind `json:"kind"`
+	Namespace string              `json:"namespace"`
+	Name      string              `json:"name"`
+	Sequence  int                 `json:"sequence"`                                //捕获顺序，回滚时倒序恢复
+	Existed   bool                `json:"existed"`                                 //任务执行前资源是否已存在；不存在时回滚即删除
+	Object    *JSONStruct         `json:"object,omitempty" gorm:"serializer:json"` //执行前的资源定义（已去除 status 等服务端字段）
+	Status    config.Status       `json:"status"`                                  //回滚结果：空表示尚未回滚
+	Error     string              `json:"error,omitempty"`
+	BaseModel
+}
+
+func (r *ResourceSnapshot) PrimaryKey() string {
+	return strconv.Itoa(r.ID)
+}
+
+func (r *ResourceSnapshot) TableName() string {
+	return tableNamePrefix + "resource_snapshot"
+}
+
+func (r *ResourceSnapshot) ShortTableName() string {
+	return "resource_snapshot"
+}
+
+func (r *ResourceSnapshot) Index() map[string]interface{} {
+	index := make(map[string]interface{})
+	if r.TaskID != "" {
+		index["taskid"] = r.TaskID
+	}
+	if r.AppID != "" {
+		index["app_id"] = r.AppID
+	}
+	return index
+}
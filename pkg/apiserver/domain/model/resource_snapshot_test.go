@@ -0,0 +1,27 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceSnapshot_EntityContract(t *testing.T) {
+	snapshot := &ResourceSnapshot{
+		ID:     7,
+		TaskID: "task-1",
+		AppID:  "app-1",
+	}
+
+	require.Equal(t, "min_resource_snapshot", snapshot.TableName())
+	require.Equal(t, "resource_snapshot", snapshot.ShortTableName())
+	require.Equal(t, "7", snapshot.PrimaryKey())
+
+	index := snapshot.Index()
+	require.Equal(t, "task-1", index["taskid"])
+	require.Equal(t, "app-1", index["app_id"])
+
+	registered := GetRegisterModels()
+	_, ok := registered[snapshot.TableName()]
+	require.True(t, ok, "expected model to be registered for auto-migration")
+}
@@ -208,6 +208,25 @@ type RetryPolicy struct {
 	DelaySeconds  int     `json:"delay_seconds"`
 	BackoffFactor float64 `json:"backoff_factor,omitempty"`
 	MaxDelay      int     `json:"max_delay,omitempty"`
+	// DelayMillis/MaxDelayMillis 以毫秒记录运行时默认值，设置时优先于秒级字段，避免亚秒级延迟被截断
+	DelayMillis    int64 `json:"delay_ms,omitempty"`
+	MaxDelayMillis int64 `json:"max_delay_ms,omitempty"`
+}
+
+// BaseDelay returns the delay before the first retry.
+func (p *RetryPolicy) BaseDelay() time.Duration {
+	if p.DelayMillis > 0 {
+		return time.Duration(p.DelayMillis) * time.Millisecond
+	}
+	return time.Duration(p.DelaySeconds) * time.Second
+}
+
+// MaxBackoff returns the cap on the delay between retries; 0 means uncapped.
+func (p *RetryPolicy) MaxBackoff() time.Duration {
+	if p.MaxDelayMillis > 0 {
+		return time.Duration(p.MaxDelayMillis) * time.Millisecond
+	}
+	return time.Duration(p.MaxDelay) * time.Second
 }
 
 // TemplateVersion 模板版本管理
@@ -20,6 +20,7 @@ type Workflow struct {
 	WorkflowType config.WorkflowTaskType `gorm:"column:workflow_type" json:"workflow_type"` //工作流类型
 	Status       config.Status           `json:"status"`                                    //分为开启和关闭等状态
 	Steps        *JSONStruct             `json:"steps,omitempty" gorm:"serializer:json"`
+	RollbackMode config.RollbackMode     `gorm:"column:rollback_mode" json:"rollback_mode,omitempty"` //失败后的回滚方式：none, automatic, manual
 	BaseModel
 }
 
@@ -34,6 +35,36 @@ type WorkflowStep struct {
 	Mode         config.WorkflowMode `json:"mode,omitempty"`
 	Properties   []Policies          `json:"properties,omitempty"`
 	SubSteps     []*WorkflowSubStep  `json:"sub_steps,omitempty"`
+	// DependsOn 显式声明依赖的步骤名称；任一步骤声明后，工作流按依赖图并发调度
+	DependsOn []string `json:"depends_on,omitempty"`
+	// Retry 覆盖该步骤内所有任务的重试策略
+	Retry *RetryPolicy `json:"retry,omitempty"`
+	// Condition 执行该步骤前求值的条件表达式（可引用 inputs、components、steps），为假时跳过该步骤
+	Condition string `json:"condition,omitempty"`
+	// TimeoutSeconds 步骤的执行时限（秒），为 0 时使用全局默认值
+	TimeoutSeconds int `json:"timeout_seconds,omitempty"`
+	// Spec 内置步骤（wait、http-check、notify）的参数，部署步骤为空
+	Spec *StepSpec `json:"spec,omitempty"`
+}
+
+// StepSpec 内置步骤的参数，各字段按步骤类型取用
+type StepSpec struct {
+	// DurationSeconds wait 步骤固定等待的时长
+	DurationSeconds int `json:"duration_seconds,omitempty"`
+	// Until wait 步骤轮询的条件表达式，为真时结束等待
+	Until string `json:"until,omitempty"`
+	// URL http-check 轮询或 notify 投递的地址
+	URL     string            `json:"url,omitempty"`
+	Method  string            `json:"method,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body http-check 的请求体；notify 的载荷，为空时发送任务信息
+	Body string `json:"body,omitempty"`
+	// ExpectStatus http-check 期望的状态码，为空时接受 2xx
+	ExpectStatus []int `json:"expect_status,omitempty"`
+	// ExpectBody http-check 期望响应体包含的内容
+	ExpectBody string `json:"expect_body,omitempty"`
+	// IntervalSeconds wait/http-check 的轮询间隔
+	IntervalSeconds int `json:"interval_seconds,omitempty"`
 }
 
 type WorkflowSubStep struct {
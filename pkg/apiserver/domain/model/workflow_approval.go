@@ -0,0 +1,51 @@
+package model
+
+import (
+	"strconv"
+
+	"kubemin-cli/pkg/apiserver/config"
+)
+
+func init() {
+	RegisterModel(&WorkflowApproval{})
+}
+
+// WorkflowApproval 记录工作流任务中审批步骤的审批单
+type WorkflowApproval struct {
+	ID         int           `json:"id" gorm:"primaryKey"`
+	TaskID     string        `gorm:"column:taskid" json:"task_id"`
+	AppID      string        `gorm:"column:app_id" json:"app_id"`
+	WorkflowID string        `gorm:"column:workflow_id" json:"workflow_id"`
+	StepName   string        `gorm:"column:step_name" json:"step_name"`
+	Status     config.Status `json:"status"` //wait_for_approval, passed, reject
+	Approver   string        `json:"approver,omitempty"`
+	Comment    string        `json:"comment,omitempty"`
+	DecideTime int64         `gorm:"column:decide_time" json:"decide_time,omitempty"` //审批时间
+	BaseModel
+}
+
+func (a *WorkflowApproval) PrimaryKey() string {
+	return strconv.Itoa(a.ID)
+}
+
+func (a *WorkflowApproval) TableName() string {
+	return tableNamePrefix + "workflow_approval"
+}
+
+func (a *WorkflowApproval) ShortTableName() string {
+	return "workflow_approval"
+}
+
+func (a *WorkflowApproval) Index() map[string]interface{} {
+	index := make(map[string]interface{})
+	if a.TaskID != "" {
+		index["taskid"] = a.TaskID
+	}
+	if a.StepName != "" {
+		index["step_name"] = a.StepName
+	}
+	if a.Status != "" {
+		index["status"] = a.Status
+	}
+	return index
+}
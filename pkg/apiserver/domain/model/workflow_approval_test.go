@@ -0,0 +1,31 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/config"
+)
+
+func TestWorkflowApproval_EntityContract(t *testing.T) {
+	approval := &WorkflowApproval{
+		ID:       3,
+		TaskID:   "task-1",
+		StepName: "gate",
+		Status:   config.StatusWaitingApprove,
+	}
+
+	require.Equal(t, "min_workflow_approval", approval.TableName())
+	require.Equal(t, "workflow_approval", approval.ShortTableName())
+	require.Equal(t, "3", approval.PrimaryKey())
+
+	index := approval.Index()
+	require.Equal(t, "task-1", index["taskid"])
+	require.Equal(t, "gate", index["step_name"])
+	require.Equal(t, config.StatusWaitingApprove, index["status"])
+
+	registered := GetRegisterModels()
+	_, ok := registered[approval.TableName()]
+	require.True(t, ok, "expected model to be registered for auto-migration")
+}
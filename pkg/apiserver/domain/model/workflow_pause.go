@@ -0,0 +1,32 @@
+package model
+
+func init() {
+	RegisterModel(&WorkflowPauseRequest{})
+}
+
+// WorkflowPauseRequest 未生效的暂停请求；没有 Redis 时经数据库传递，任意实例上运行该任务的 Worker 都能读到
+type WorkflowPauseRequest struct {
+	TaskID string `gorm:"primaryKey;type:varchar(255)" json:"task_id"`
+	Reason string `json:"reason"`
+	BaseModel
+}
+
+func (p *WorkflowPauseRequest) PrimaryKey() string {
+	return p.TaskID
+}
+
+func (p *WorkflowPauseRequest) TableName() string {
+	return tableNamePrefix + "workflow_pause_request"
+}
+
+func (p *WorkflowPauseRequest) ShortTableName() string {
+	return "workflow_pause_request"
+}
+
+func (p *WorkflowPauseRequest) Index() map[string]interface{} {
+	index := make(map[string]interface{})
+	if p.TaskID != "" {
+		index["task_id"] = p.TaskID
+	}
+	return index
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkflowPauseRequest_EntityContract(t *testing.T) {
+	request := &WorkflowPauseRequest{TaskID: "task-1"}
+
+	require.Equal(t, "min_workflow_pause_request", request.TableName())
+	require.Equal(t, "workflow_pause_request", request.ShortTableName())
+	require.Equal(t, "task-1", request.PrimaryKey())
+	require.Equal(t, "task-1", request.Index()["task_id"])
+
+	registered := GetRegisterModels()
+	_, ok := registered[request.TableName()]
+	require.True(t, ok, "expected model to be registered for auto-migration")
+}
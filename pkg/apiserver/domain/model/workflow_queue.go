@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"kubemin-cli/pkg/apiserver/config"
 )
 
@@ -19,6 +21,32 @@ type WorkflowQueue struct {
 	TaskCreator         string                  `json:"task_creator,omitempty"`                //任务创建者
 	TaskRevoker         string                  `json:"task_revoker,omitempty"`                //任务取消者
 	Type                config.WorkflowTaskType `json:"type,omitempty"`                        //工作流类型
+	// Priority 调度优先级，数值越大越先派发；同优先级时按项目公平轮转
+	Priority int `gorm:"column:priority;default:0" json:"priority"`
+	// CompletedSteps 已成功完成的步骤执行，任务挂起后重新调度时跳过这些步骤
+	CompletedSteps []string `gorm:"serializer:json" json:"completed_steps,omitempty"`
+	// CompletedJobs 已成功结束的 Job（type/name），任务被重新领取后按执行计划跳过这些 Job
+	CompletedJobs []string `gorm:"column:completed_jobs;serializer:json" json:"completed_jobs,omitempty"`
+	// Revision 创建任务时工作流定义的摘要，用于判断失败重试时已完成的 Job 是否仍然有效
+	Revision string `json:"revision,omitempty"`
+	// RetryOf 从失败处重试时指向原任务
+	RetryOf string `gorm:"column:retry_of" json:"retry_of,omitempty"`
+	// SkipJobs 重试任务中沿用原任务结果、不再执行的 Job（type/name）
+	SkipJobs []string `gorm:"serializer:json" json:"skip_jobs,omitempty"`
+	// Inputs 执行工作流时传入的参数，供步骤条件表达式引用
+	Inputs map[string]interface{} `gorm:"serializer:json" json:"inputs,omitempty"`
+	// StepResults 已结束步骤的结果（completed 或 skipped），按步骤名记录
+	StepResults map[string]config.Status `gorm:"column:step_results;serializer:json" json:"step_results,omitempty"`
+	// SubmitTime 任务入队的时间（Unix 纳秒），创建后不再修改；CreateTime 在任务开始运行时会被刷新
+	SubmitTime int64 `gorm:"column:submit_time" json:"submit_time,omitempty"`
+	// Deadline 任务首次运行时确定的截止时间，等待审批或暂停的时间不计入，恢复时顺延
+	Deadline *time.Time `gorm:"column:deadline" json:"deadline,omitempty"`
+	// SuspendedAt 任务进入等待审批或暂停状态的时间，恢复后清空
+	SuspendedAt *time.Time `gorm:"column:suspended_at" json:"suspended_at,omitempty"`
+	// TimeoutStep 任务超时时正在执行的步骤
+	TimeoutStep string `gorm:"column:timeout_step" json:"timeout_step,omitempty"`
+	// CancelReason 任务被取消的原因，轮询数据库的取消后端据此通知运行中的 Job
+	CancelReason string `gorm:"column:cancel_reason" json:"cancel_reason,omitempty"`
 	BaseModel
 }
 
@@ -30,6 +58,15 @@ func (wq *WorkflowQueue) TableName() string {
 	return tableNamePrefix + "workflow_queue"
 }
 
+// SubmittedBefore reports whether the task was enqueued before other. CreateTime is refreshed
+// when a task starts running, so it only orders tasks enqueued before SubmitTime was recorded.
+func (wq *WorkflowQueue) SubmittedBefore(other *WorkflowQueue) bool {
+	if wq.SubmitTime != 0 && other.SubmitTime != 0 {
+		return wq.SubmitTime < other.SubmitTime
+	}
+	return wq.CreateTime.Before(other.CreateTime)
+}
+
 func (wq *WorkflowQueue) ShortTableName() string {
 	return "workflow_queue"
 }
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"kubemin-cli/pkg/apiserver/config"
+)
+
+func init() {
+	RegisterModel(&WorkflowSchedule{})
+}
+
+// WorkflowSchedule 按 cron 表达式定时执行应用工作流，由 leader 负责触发
+type WorkflowSchedule struct {
+	ID                string                           `gorm:"primaryKey;type:varchar(255)" json:"id"`
+	Name              string                           `json:"name"`
+	AppID             string                           `gorm:"column:app_id" json:"app_id"`
+	WorkflowID        string                           `gorm:"column:workflow_id" json:"workflow_id"`
+	Cron              string                           `json:"cron"`
+	TimeZone          string                           `gorm:"column:time_zone" json:"time_zone,omitempty"`                   //IANA 时区，为空时使用 UTC
+	ConcurrencyPolicy config.ScheduleConcurrencyPolicy `gorm:"column:concurrency_policy" json:"concurrency_policy,omitempty"` //allow, forbid, replace
+	Inputs            map[string]interface{}           `gorm:"serializer:json" json:"inputs,omitempty"`                       //每次执行传入的参数
+	Status            config.Status                    `json:"status"`                                                        //enabled, disabled
+	Creator           string                           `json:"creator,omitempty"`
+	LastRunTime       int64                            `gorm:"column:last_run_time" json:"last_run_time,omitempty"` //上次到点时间（Unix 秒）
+	NextRunTime       int64                            `gorm:"column:next_run_time" json:"next_run_time,omitempty"` //下次到点时间（Unix 秒），停用时为 0
+	LastTaskID        string                           `gorm:"column:last_task_id" json:"last_task_id,omitempty"`   //上次创建的任务
+	LastResult        string                           `gorm:"column:last_result" json:"last_result,omitempty"`     //上次到点的处理结果
+	BaseModel
+}
+
+func (s *WorkflowSchedule) PrimaryKey() string {
+	return s.ID
+}
+
+func (s *WorkflowSchedule) TableName() string {
+	return tableNamePrefix + "workflow_schedule"
+}
+
+func (s *WorkflowSchedule) ShortTableName() string {
+	return "workflow_schedule"
+}
+
+func (s *WorkflowSchedule) Index() map[string]interface{} {
+	index := make(map[string]interface{})
+	if s.ID != "" {
+		index["id"] = s.ID
+	}
+	if s.AppID != "" {
+		index["app_id"] = s.AppID
+	}
+	if s.WorkflowID != "" {
+		index["workflow_id"] = s.WorkflowID
+	}
+	if s.Status != "" {
+		index["status"] = s.Status
+	}
+	return index
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/config"
+)
+
+func TestWorkflowSchedule_EntityContract(t *testing.T) {
+	schedule := &WorkflowSchedule{
+		ID:         "sched-1",
+		AppID:      "app-1",
+		WorkflowID: "wf-1",
+		Status:     config.StatusEnabled,
+	}
+
+	require.Equal(t, "min_workflow_schedule", schedule.TableName())
+	require.Equal(t, "workflow_schedule", schedule.ShortTableName())
+	require.Equal(t, "sched-1", schedule.PrimaryKey())
+
+	index := schedule.Index()
+	require.Equal(t, "app-1", index["app_id"])
+	require.Equal(t, "wf-1", index["workflow_id"])
+	require.Equal(t, config.StatusEnabled, index["status"])
+
+	registered := GetRegisterModels()
+	_, ok := registered[schedule.TableName()]
+	require.True(t, ok, "expected model to be registered for auto-migration")
+}
@@ -0,0 +1,96 @@
+package model
+
+import (
+	"strconv"
+
+	"kubemin-cli/pkg/apiserver/config"
+)
+
+func init() {
+	RegisterModel(&WorkflowWebhook{}, &WebhookDelivery{})
+}
+
+// WorkflowWebhook 应用的入站 webhook 触发器，按映射规则从负载中提取镜像与版本后调用版本更新
+type WorkflowWebhook struct {
+	ID         string          `gorm:"primaryKey;type:varchar(255)" json:"id"`
+	Name       string          `json:"name"`
+	AppID      string          `gorm:"column:app_id" json:"app_id"`
+	WorkflowID string          `gorm:"column:workflow_id" json:"workflow_id,omitempty"` //为空时由版本更新执行应用的默认工作流
+	Secret     string          `json:"-"`                                               //HMAC 签名密钥
+	Mapping    *WebhookMapping `gorm:"serializer:json" json:"mapping"`
+	Status     config.Status   `json:"status"` //enabled, disabled
+	Creator    string          `json:"creator,omitempty"`
+	BaseModel
+}
+
+// WebhookMapping 负载字段到版本更新参数的映射，路径以 . 分隔，数组使用下标（如 event_data.resources.0.tag）
+type WebhookMapping struct {
+	Components  []string `json:"components"`             //需要更新镜像的组件
+	ImagePath   string   `json:"image_path,omitempty"`   //完整镜像地址的路径
+	TagPath     string   `json:"tag_path,omitempty"`     //镜像 tag 的路径，替换组件当前镜像的 tag
+	VersionPath string   `json:"version_path,omitempty"` //版本号的路径，为空时使用镜像 tag
+	Strategy    string   `json:"strategy,omitempty"`     //版本更新策略
+}
+
+func (w *WorkflowWebhook) PrimaryKey() string {
+	return w.ID
+}
+
+func (w *WorkflowWebhook) TableName() string {
+	return tableNamePrefix + "workflow_webhook"
+}
+
+func (w *WorkflowWebhook) ShortTableName() string {
+	return "workflow_webhook"
+}
+
+func (w *WorkflowWebhook) Index() map[string]interface{} {
+	index := make(map[string]interface{})
+	if w.ID != "" {
+		index["id"] = w.ID
+	}
+	if w.AppID != "" {
+		index["app_id"] = w.AppID
+	}
+	return index
+}
+
+// WebhookDelivery 记录一次 webhook 投递及其处理结果，用于排查触发是否创建了任务
+type WebhookDelivery struct {
+	ID        int                          `json:"id" gorm:"primaryKey"`
+	WebhookID string                       `gorm:"column:webhook_id" json:"webhook_id"`
+	AppID     string                       `gorm:"column:app_id" json:"app_id"`
+	Result    config.WebhookDeliveryResult `json:"result"`
+	Reason    string                       `json:"reason,omitempty"`
+	Version   string                       `json:"version,omitempty"`
+	TaskID    string                       `gorm:"column:taskid" json:"task_id,omitempty"`
+	Source    string                       `json:"source,omitempty"`                   //请求来源地址
+	Payload   string                       `gorm:"type:text" json:"payload,omitempty"` //截断后的请求体
+	BaseModel
+}
+
+func (d *WebhookDelivery) PrimaryKey() string {
+	return strconv.Itoa(d.ID)
+}
+
+func (d *WebhookDelivery) TableName() string {
+	return tableNamePrefix + "webhook_delivery"
+}
+
+func (d *WebhookDelivery) ShortTableName() string {
+	return "webhook_delivery"
+}
+
+func (d *WebhookDelivery) Index() map[string]interface{} {
+	index := make(map[string]interface{})
+	if d.WebhookID != "" {
+		index["webhook_id"] = d.WebhookID
+	}
+	if d.AppID != "" {
+		index["app_id"] = d.AppID
+	}
+	if d.Result != "" {
+		index["result"] = d.Result
+	}
+	return index
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/config"
+)
+
+func TestWorkflowWebhook_EntityContract(t *testing.T) {
+	webhook := &WorkflowWebhook{ID: "hook-1", AppID: "app-1"}
+	require.Equal(t, "min_workflow_webhook", webhook.TableName())
+	require.Equal(t, "workflow_webhook", webhook.ShortTableName())
+	require.Equal(t, "hook-1", webhook.PrimaryKey())
+	require.Equal(t, "app-1", webhook.Index()["app_id"])
+
+	delivery := &WebhookDelivery{ID: 7, WebhookID: "hook-1", Result: config.WebhookDeliveryIgnored}
+	require.Equal(t, "min_webhook_delivery", delivery.TableName())
+	require.Equal(t, "7", delivery.PrimaryKey())
+	require.Equal(t, "hook-1", delivery.Index()["webhook_id"])
+	require.Equal(t, config.WebhookDeliveryIgnored, delivery.Index()["result"])
+
+	registered := GetRegisterModels()
+	for _, name := range []string{webhook.TableName(), delivery.TableName()} {
+		_, ok := registered[name]
+		require.True(t, ok, "expected %s to be registered for auto-migration", name)
+	}
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+
+	"k8s.io/klog/v2"
+
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+)
+
+// WorkflowWebhookByID loads a webhook by its ID.
+func WorkflowWebhookByID(ctx context.Context, store datastore.DataStore, webhookID string) (*model.WorkflowWebhook, error) {
+	webhook := &model.WorkflowWebhook{ID: webhookID}
+	if err := store.Get(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// ListWorkflowWebhooks returns the webhooks of an application, oldest first.
+func ListWorkflowWebhooks(ctx context.Context, store datastore.DataStore, appID string) ([]*model.WorkflowWebhook, error) {
+	entities, err := store.List(ctx, &model.WorkflowWebhook{AppID: appID}, &datastore.ListOptions{
+		SortBy: []datastore.SortOption{{Key: "createtime", Order: datastore.SortOrderAscending}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*model.WorkflowWebhook, 0, len(entities))
+	for _, entity := range entities {
+		webhook, ok := entity.(*model.WorkflowWebhook)
+		if !ok {
+			klog.Warningf("unexpected workflow webhook entity type: %T", entity)
+			continue
+		}
+		list = append(list, webhook)
+	}
+	return list, nil
+}
+
+// ListWebhookDeliveries returns the most recent deliveries of a webhook, newest first.
+func ListWebhookDeliveries(ctx context.Context, store datastore.DataStore, webhookID string, limit int) ([]*model.WebhookDelivery, error) {
+	entities, err := store.List(ctx, &model.WebhookDelivery{WebhookID: webhookID}, &datastore.ListOptions{
+		Page:     1,
+		PageSize: limit,
+		SortBy:   []datastore.SortOption{{Key: "id", Order: datastore.SortOrderDescending}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*model.WebhookDelivery, 0, len(entities))
+	for _, entity := range entities {
+		delivery, ok := entity.(*model.WebhookDelivery)
+		if !ok {
+			klog.Warningf("unexpected webhook delivery entity type: %T", entity)
+			continue
+		}
+		list = append(list, delivery)
+	}
+	return list, nil
+}
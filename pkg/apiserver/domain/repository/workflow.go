@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"k8s.io/klog/v2"
 
@@ -306,6 +307,103 @@ func WaitingTasks(ctx context.Context, store datastore.DataStore) (list []*model
 	return
 }
 
+// ActiveAppTasks 应用下尚未结束的任务（等待、排队、运行、暂停或等待审批），按创建时间升序
+func ActiveAppTasks(ctx context.Context, store datastore.DataStore, appID string) (list []*model.WorkflowQueue, err error) {
+	tasks, err := store.List(ctx, &model.WorkflowQueue{AppID: appID}, &datastore.ListOptions{
+		SortBy: []datastore.SortOption{{Key: "createTime", Order: datastore.SortOrderAscending}},
+		FilterOptions: datastore.FilterOptions{In: []datastore.InQueryOption{{
+			Key: "status",
+			Values: []string{
+				string(config.StatusWaiting),
+				string(config.StatusQueued),
+				string(config.StatusRunning),
+				string(config.StatusPause),
+				string(config.StatusWaitingApprove),
+			},
+		}}},
+	})
+	if err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	for _, entity := range tasks {
+		task, ok := entity.(*model.WorkflowQueue)
+		if !ok {
+			klog.Warningf("unexpected workflow queue entity type: %T", entity)
+			continue
+		}
+		list = append(list, task)
+	}
+	return list, nil
+}
+
+// ListAppTasks 分页查询应用的任务历史，按创建时间倒序；workflowID 与 statuses 为空时不过滤
+func ListAppTasks(ctx context.Context, store datastore.DataStore, appID, workflowID string, statuses []string, page, pageSize int) ([]*model.WorkflowQueue, int64, error) {
+	var filter datastore.FilterOptions
+	if workflowID != "" {
+		filter.In = append(filter.In, datastore.InQueryOption{Key: "workflowId", Values: []string{workflowID}})
+	}
+	if len(statuses) > 0 {
+		filter.In = append(filter.In, datastore.InQueryOption{Key: "status", Values: statuses})
+	}
+	query := &model.WorkflowQueue{AppID: appID}
+	total, err := store.Count(ctx, query, &filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	tasks, err := store.List(ctx, query, &datastore.ListOptions{
+		FilterOptions: filter,
+		Page:          page,
+		PageSize:      pageSize,
+		SortBy:        []datastore.SortOption{{Key: "createTime", Order: datastore.SortOrderDescending}},
+	})
+	if err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, total, nil
+		}
+		return nil, 0, err
+	}
+	list := make([]*model.WorkflowQueue, 0, len(tasks))
+	for _, entity := range tasks {
+		task, ok := entity.(*model.WorkflowQueue)
+		if !ok {
+			klog.Warningf("unexpected workflow queue entity type: %T", entity)
+			continue
+		}
+		list = append(list, task)
+	}
+	return list, total, nil
+}
+
+// ListTaskJobs 返回任务的全部 Job 记录（含每次重试），按写入顺序；statuses 为空时不过滤
+func ListTaskJobs(ctx context.Context, store datastore.DataStore, taskID string, statuses []string) ([]*model.JobInfo, error) {
+	options := &datastore.ListOptions{
+		SortBy: []datastore.SortOption{{Key: "id", Order: datastore.SortOrderAscending}},
+	}
+	if len(statuses) > 0 {
+		options.In = []datastore.InQueryOption{{Key: "status", Values: statuses}}
+	}
+	entities, err := store.List(ctx, &model.JobInfo{TaskID: taskID}, options)
+	if err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	list := make([]*model.JobInfo, 0, len(entities))
+	for _, entity := range entities {
+		job, ok := entity.(*model.JobInfo)
+		if !ok {
+			klog.Warningf("unexpected job info entity type: %T", entity)
+			continue
+		}
+		list = append(list, job)
+	}
+	return list, nil
+}
+
 func UpdateTask(ctx context.Context, store datastore.DataStore, task *model.WorkflowQueue) error {
 	err := store.Put(ctx, task)
 	return err
@@ -381,3 +479,134 @@ func UpdateTaskStatus(ctx context.Context, store datastore.DataStore, taskID str
 	}
 	return store.CompareAndSwap(ctx, task, "status", from, updates)
 }
+
+// DeleteExecutionPlan removes the stored execution plan of a task; a missing plan is not an error.
+func DeleteExecutionPlan(ctx context.Context, store datastore.DataStore, taskID string) error {
+	err := store.Delete(ctx, &model.ExecutionPlan{TaskID: taskID})
+	if err != nil && !errors.Is(err, datastore.ErrRecordNotExist) {
+		return err
+	}
+	return nil
+}
+
+// SuspendTask moves a task into a suspended status (waiting for approval or paused) and
+// records when it was suspended, so the deadline can be extended once it resumes.
+func SuspendTask(ctx context.Context, store datastore.DataStore, taskID string, from, to config.Status) (bool, error) {
+	task := &model.WorkflowQueue{TaskID: taskID}
+	return store.CompareAndSwap(ctx, task, "status", from, map[string]interface{}{
+		"status":       to,
+		"suspended_at": time.Now(),
+	})
+}
+
+// ResumeTask puts a suspended task back to waiting. The task deadline is pushed back by
+// the time spent suspended, which does not count against the task timeout.
+func ResumeTask(ctx context.Context, store datastore.DataStore, task *model.WorkflowQueue) (bool, error) {
+	updates := map[string]interface{}{
+		"status":       config.StatusWaiting,
+		"suspended_at": nil,
+	}
+	if task.Deadline != nil && task.SuspendedAt != nil {
+		if suspended := time.Since(*task.SuspendedAt); suspended > 0 {
+			updates["deadline"] = task.Deadline.Add(suspended)
+		}
+	}
+	return store.CompareAndSwap(ctx, &model.WorkflowQueue{TaskID: task.TaskID}, "status", task.Status, updates)
+}
+
+// ListWorkflowApprovals returns the approvals of a task, optionally filtered by status, oldest first.
+func ListWorkflowApprovals(ctx context.Context, store datastore.DataStore, taskID string, status config.Status) ([]*model.WorkflowApproval, error) {
+	entities, err := store.List(ctx, &model.WorkflowApproval{TaskID: taskID, Status: status}, &datastore.ListOptions{
+		SortBy: []datastore.SortOption{{Key: "id", Order: datastore.SortOrderAscending}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var list []*model.WorkflowApproval
+	for _, entity := range entities {
+		approval, ok := entity.(*model.WorkflowApproval)
+		if !ok {
+			klog.Warningf("unexpected workflow approval entity type: %T", entity)
+			continue
+		}
+		list = append(list, approval)
+	}
+	return list, nil
+}
+
+// LatestWorkflowApproval returns the most recent approval of a step, or nil when the step has none.
+func LatestWorkflowApproval(ctx context.Context, store datastore.DataStore, taskID, stepName string) (*model.WorkflowApproval, error) {
+	entities, err := store.List(ctx, &model.WorkflowApproval{TaskID: taskID, StepName: stepName}, nil)
+	if err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var latest *model.WorkflowApproval
+	for _, entity := range entities {
+		approval, ok := entity.(*model.WorkflowApproval)
+		if !ok {
+			continue
+		}
+		if latest == nil || approval.ID > latest.ID {
+			latest = approval
+		}
+	}
+	return latest, nil
+}
+
+// CreateWorkflowSchedule persists a new workflow schedule.
+func CreateWorkflowSchedule(ctx context.Context, store datastore.DataStore, schedule *model.WorkflowSchedule) error {
+	return store.Add(ctx, schedule)
+}
+
+// WorkflowScheduleByID loads a workflow schedule by its ID.
+func WorkflowScheduleByID(ctx context.Context, store datastore.DataStore, scheduleID string) (*model.WorkflowSchedule, error) {
+	schedule := &model.WorkflowSchedule{ID: scheduleID}
+	if err := store.Get(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// ListWorkflowSchedules returns the schedules matching the index fields of query, oldest first.
+func ListWorkflowSchedules(ctx context.Context, store datastore.DataStore, query *model.WorkflowSchedule) ([]*model.WorkflowSchedule, error) {
+	entities, err := store.List(ctx, query, &datastore.ListOptions{
+		SortBy: []datastore.SortOption{{Key: "createtime", Order: datastore.SortOrderAscending}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*model.WorkflowSchedule, 0, len(entities))
+	for _, entity := range entities {
+		schedule, ok := entity.(*model.WorkflowSchedule)
+		if !ok {
+			klog.Warningf("unexpected workflow schedule entity type: %T", entity)
+			continue
+		}
+		list = append(list, schedule)
+	}
+	return list, nil
+}
+
+// ListJobDiagnostics 返回任务下所有失败 Job 采集的诊断信息
+func ListJobDiagnostics(ctx context.Context, store datastore.DataStore, taskID string) ([]*model.JobDiagnostics, error) {
+	entities, err := store.List(ctx, &model.JobDiagnostics{TaskID: taskID}, nil)
+	if err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	list := make([]*model.JobDiagnostics, 0, len(entities))
+	for _, entity := range entities {
+		diag, ok := entity.(*model.JobDiagnostics)
+		if !ok {
+			klog.Warningf("unexpected job diagnostics entity type: %T", entity)
+			continue
+		}
+		list = append(list, diag)
+	}
+	return list, nil
+}
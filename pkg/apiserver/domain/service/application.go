@@ -10,6 +10,7 @@ import (
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -29,6 +30,7 @@ import (
 	apisv1 "kubemin-cli/pkg/apiserver/interfaces/api/dto/v1"
 	"kubemin-cli/pkg/apiserver/utils"
 	"kubemin-cli/pkg/apiserver/utils/bcode"
+	wf "kubemin-cli/pkg/apiserver/workflow"
 	"kubemin-cli/pkg/apiserver/workflow/naming"
 )
 
@@ -83,6 +85,9 @@ func (c *applicationsServiceImpl) CreateApplications(ctx context.Context, req ap
 	if req.Version == "" {
 		req.Version = "1.0.0"
 	}
+	if !config.IsValidAppConcurrencyPolicy(req.ConcurrencyPolicy) {
+		return nil, bcode.ErrApplicationConcurrencyPolicy
+	}
 
 	var (
 		application *model.Applications
@@ -115,6 +120,9 @@ func (c *applicationsServiceImpl) CreateApplications(ctx context.Context, req ap
 	if application.Namespace == "" {
 		application.Namespace = config.DefaultNamespace
 	}
+	if req.ConcurrencyPolicy != "" || application.ConcurrencyPolicy == "" {
+		application.ConcurrencyPolicy = config.ParseAppConcurrencyPolicy(req.ConcurrencyPolicy)
+	}
 
 	//分解所有的组件
 	resolvedComponents, err := c.resolveComponents(ctx, application.Namespace, application.Name, req.Component)
@@ -190,7 +198,12 @@ func (c *applicationsServiceImpl) upsertDefaultWorkflow(ctx context.Context, sto
 	if len(req.WorkflowSteps) == 0 {
 		workflowBody = convertWorkflowStepByComponent(resolvedComponents)
 	} else {
-		workflowBody = convertWorkflowStepsFromRequest(req.WorkflowSteps)
+		steps := convertWorkflowStepsFromRequest(req.WorkflowSteps)
+		if err := validateWorkflowStepRules(steps); err != nil {
+			klog.Errorf("workflow step validation failed for app=%s: %v", app.ID, err)
+			return nil, err
+		}
+		workflowBody = steps
 	}
 
 	workflowStep, err := model.NewJSONStructByStruct(workflowBody)
@@ -663,9 +676,12 @@ func (c *applicationsServiceImpl) refreshExistingApplication(ctx context.Context
 func prepareComponents(appID, namespace string, reqComponents []apisv1.CreateComponentRequest) ([]*model.ApplicationComponent, error) {
 	components := make([]*model.ApplicationComponent, 0, len(reqComponents))
 	for _, reqComponent := range reqComponents {
-		if (reqComponent.ComponentType == config.ServerJob || reqComponent.ComponentType == config.StoreJob) && reqComponent.Image == "" {
+		if requiresImage(reqComponent.ComponentType) && reqComponent.Image == "" {
 			return nil, bcode.ErrComponentNotImageSet
 		}
+		if err := validateBatchJobProperties(reqComponent.ComponentType, reqComponent.Properties); err != nil {
+			return nil, err
+		}
 
 		reqComponent.Namespace = namespace
 		// 复制 ConvertComponent 逻辑，确保一致性
@@ -700,6 +716,50 @@ func prepareComponents(appID, namespace string, reqComponents []apisv1.CreateCom
 	return components, nil
 }
 
+// requiresImage reports whether a component type runs a container and therefore needs an image.
+func requiresImage(componentType config.JobType) bool {
+	switch componentType {
+	case config.ServerJob, config.StoreJob, config.DaemonJob, config.BatchJob, config.CronJob:
+		return true
+	}
+	return false
+}
+
+// validateBatchJobProperties checks the job settings of job and cronjob components.
+func validateBatchJobProperties(componentType config.JobType, properties apisv1.Properties) error {
+	if componentType != config.BatchJob && componentType != config.CronJob {
+		return nil
+	}
+	jobProps := properties.Job
+	if jobProps == nil {
+		if componentType == config.CronJob {
+			return bcode.ErrInvalidBatchJobConfig
+		}
+		return nil
+	}
+	switch jobProps.RestartPolicy {
+	case "", string(corev1.RestartPolicyNever), string(corev1.RestartPolicyOnFailure):
+	default:
+		return bcode.ErrInvalidBatchJobConfig
+	}
+	if componentType != config.CronJob {
+		return nil
+	}
+	switch jobProps.ConcurrencyPolicy {
+	case "", string(batchv1.AllowConcurrent), string(batchv1.ForbidConcurrent), string(batchv1.ReplaceConcurrent):
+	default:
+		return bcode.ErrInvalidBatchJobConfig
+	}
+	if strings.TrimSpace(jobProps.Schedule) == "" {
+		return bcode.ErrInvalidBatchJobConfig
+	}
+	if _, err := wf.ParseCron(jobProps.Schedule, jobProps.TimeZone); err != nil {
+		klog.Errorf("invalid cronjob schedule %q: %v", jobProps.Schedule, err)
+		return bcode.ErrInvalidBatchJobConfig
+	}
+	return nil
+}
+
 func convertWorkflowStepByComponent(components []apisv1.CreateComponentRequest) *model.WorkflowSteps {
 	workflowSteps := new(model.WorkflowSteps)
 	for _, component := range components {
@@ -720,9 +780,24 @@ func convertWorkflowStepsFromRequest(steps []apisv1.CreateWorkflowStepRequest) *
 	workflowSteps := new(model.WorkflowSteps)
 	for _, reqStep := range steps {
 		step := &model.WorkflowStep{
-			Name:         reqStep.Name,
-			WorkflowType: reqStep.WorkflowType,
-			Mode:         config.ParseWorkflowMode(reqStep.Mode),
+			Name:           reqStep.Name,
+			WorkflowType:   reqStep.WorkflowType,
+			Mode:           config.ParseWorkflowMode(reqStep.Mode),
+			DependsOn:      dedupeStrings(reqStep.DependsOn),
+			Condition:      strings.TrimSpace(reqStep.Condition),
+			TimeoutSeconds: reqStep.TimeoutSeconds,
+		}
+		if reqStep.Retry != nil {
+			step.Retry = &model.RetryPolicy{
+				Attempts:      reqStep.Retry.Attempts,
+				DelaySeconds:  reqStep.Retry.DelaySeconds,
+				BackoffFactor: reqStep.Retry.BackoffFactor,
+				MaxDelay:      reqStep.Retry.MaxDelay,
+			}
+		}
+		if reqStep.Spec != nil {
+			spec := model.StepSpec(*reqStep.Spec)
+			step.Spec = &spec
 		}
 		componentNames := mergeWorkflowComponents(reqStep.Components, reqStep.Properties.Policies)
 		if len(componentNames) > 0 {
@@ -834,6 +909,32 @@ func validateWorkflowComponentRefs(steps []apisv1.CreateWorkflowStepRequest, exi
 	return nil
 }
 
+// validateWorkflowStepRules rejects unknown dependencies, cycles and malformed approval
+// or built-in steps before the workflow is persisted.
+func validateWorkflowStepRules(steps *model.WorkflowSteps) error {
+	if steps == nil {
+		return nil
+	}
+	if err := wf.ValidateStepDependencies(steps.Steps); err != nil {
+		return fmt.Errorf("%w: %v", bcode.ErrWorkflowStepDependency, err)
+	}
+	if err := wf.ValidateApprovalSteps(steps.Steps); err != nil {
+		return fmt.Errorf("%w: %v", bcode.ErrWorkflowConfig, err)
+	}
+	if err := wf.ValidateBuiltinSteps(steps.Steps); err != nil {
+		return fmt.Errorf("%w: %v", bcode.ErrWorkflowConfig, err)
+	}
+	if err := wf.ValidateStepConditions(steps.Steps); err != nil {
+		return fmt.Errorf("%w: %v", bcode.ErrWorkflowStepCondition, err)
+	}
+	for _, step := range steps.Steps {
+		if step != nil && step.TimeoutSeconds < 0 {
+			return fmt.Errorf("%w: step %q", bcode.ErrWorkflowStepTimeout, step.Name)
+		}
+	}
+	return nil
+}
+
 func ensureComponentsExist(names []string, existing map[string]struct{}) error {
 	for _, name := range names {
 		lower := strings.ToLower(name)
@@ -990,6 +1091,54 @@ func (c *applicationsServiceImpl) deleteComponentResources(ctx context.Context,
 			c.deleteAdditionalObjects(ctx, componentPtr.Namespace, result.AdditionalObjects, reporter)
 		}
 		reporter.record("StatefulSet", statefulNS, statefulName, c.deleteStatefulSet(ctx, statefulNS, statefulName))
+	case config.DaemonJob:
+		result := job.GenerateDaemonSet(componentPtr, &props)
+		daemonNS := componentPtr.Namespace
+		daemonName := naming.DaemonSetName(component.Name, component.AppID)
+		if result != nil {
+			if ds, ok := result.Service.(*appsv1.DaemonSet); ok && ds != nil {
+				if ds.Namespace != "" {
+					daemonNS = ds.Namespace
+				}
+				if ds.Name != "" {
+					daemonName = ds.Name
+				}
+			}
+			c.deleteAdditionalObjects(ctx, componentPtr.Namespace, result.AdditionalObjects, reporter)
+		}
+		reporter.record("DaemonSet", daemonNS, daemonName, c.deleteDaemonSet(ctx, daemonNS, daemonName))
+	case config.BatchJob:
+		result := job.GenerateBatchJob(componentPtr, &props)
+		jobNS := componentPtr.Namespace
+		jobName := naming.BatchJobName(component.Name, component.AppID)
+		if result != nil {
+			if batchJob, ok := result.Service.(*batchv1.Job); ok && batchJob != nil {
+				if batchJob.Namespace != "" {
+					jobNS = batchJob.Namespace
+				}
+				if batchJob.Name != "" {
+					jobName = batchJob.Name
+				}
+			}
+			c.deleteAdditionalObjects(ctx, componentPtr.Namespace, result.AdditionalObjects, reporter)
+		}
+		reporter.record("Job", jobNS, jobName, c.deleteBatchJob(ctx, jobNS, jobName))
+	case config.CronJob:
+		result := job.GenerateCronJob(componentPtr, &props)
+		cronNS := componentPtr.Namespace
+		cronName := naming.CronJobName(component.Name, component.AppID)
+		if result != nil {
+			if cronJob, ok := result.Service.(*batchv1.CronJob); ok && cronJob != nil {
+				if cronJob.Namespace != "" {
+					cronNS = cronJob.Namespace
+				}
+				if cronJob.Name != "" {
+					cronName = cronJob.Name
+				}
+			}
+			c.deleteAdditionalObjects(ctx, componentPtr.Namespace, result.AdditionalObjects, reporter)
+		}
+		reporter.record("CronJob", cronNS, cronName, c.deleteCronJob(ctx, cronNS, cronName))
 	case config.ConfJob:
 		c.deleteConfigMapForComponent(ctx, componentPtr, &props, reporter)
 	case config.SecretJob:
@@ -1028,7 +1177,14 @@ func (c *applicationsServiceImpl) UpdateApplicationWorkflow(ctx context.Context,
 		return nil, err
 	}
 
+	if !config.IsValidRollbackMode(req.RollbackMode) {
+		return nil, bcode.ErrWorkflowRollbackMode
+	}
 	workflowSteps := convertWorkflowStepsFromRequest(req.Workflow)
+	if err := validateWorkflowStepRules(workflowSteps); err != nil {
+		klog.Errorf("workflow step validation failed for app=%s workflowId=%s: %v", appID, req.WorkflowID, err)
+		return nil, err
+	}
 	stepsStruct, err := model.NewJSONStructByStruct(workflowSteps)
 	if err != nil {
 		return nil, fmt.Errorf("marshal workflow steps: %w", err)
@@ -1076,6 +1232,7 @@ func (c *applicationsServiceImpl) UpdateApplicationWorkflow(ctx context.Context,
 			Description:  description,
 			WorkflowType: config.WorkflowTaskTypeWorkflow,
 			Status:       config.StatusCreated,
+			RollbackMode: config.ParseRollbackMode(req.RollbackMode),
 		}
 		target.Steps = stepsStruct
 		if err := c.WorkflowRepo.Create(ctx, target); err != nil {
@@ -1088,6 +1245,9 @@ func (c *applicationsServiceImpl) UpdateApplicationWorkflow(ctx context.Context,
 		if req.Alias != "" {
 			target.Alias = req.Alias
 		}
+		if req.RollbackMode != "" {
+			target.RollbackMode = config.ParseRollbackMode(req.RollbackMode)
+		}
 		target.Steps = stepsStruct
 		if err := c.WorkflowRepo.Update(ctx, target); err != nil {
 			return nil, err
@@ -1256,6 +1416,37 @@ func (c *applicationsServiceImpl) deleteStatefulSet(ctx context.Context, namespa
 	})
 }
 
+func (c *applicationsServiceImpl) deleteDaemonSet(ctx context.Context, namespace, name string) error {
+	if name == "" {
+		return nil
+	}
+	return c.deleteNamespaced(ctx, namespace, func(opCtx context.Context, ns string) error {
+		return c.KubeClient.AppsV1().DaemonSets(ns).Delete(opCtx, name, metav1.DeleteOptions{})
+	})
+}
+
+// deleteBatchJob removes the Job together with its pods; Kubernetes orphans them by default.
+func (c *applicationsServiceImpl) deleteBatchJob(ctx context.Context, namespace, name string) error {
+	if name == "" {
+		return nil
+	}
+	propagation := metav1.DeletePropagationBackground
+	return c.deleteNamespaced(ctx, namespace, func(opCtx context.Context, ns string) error {
+		return c.KubeClient.BatchV1().Jobs(ns).Delete(opCtx, name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+	})
+}
+
+// deleteCronJob removes the CronJob and, in the background, the Jobs it spawned.
+func (c *applicationsServiceImpl) deleteCronJob(ctx context.Context, namespace, name string) error {
+	if name == "" {
+		return nil
+	}
+	propagation := metav1.DeletePropagationBackground
+	return c.deleteNamespaced(ctx, namespace, func(opCtx context.Context, ns string) error {
+		return c.KubeClient.BatchV1().CronJobs(ns).Delete(opCtx, name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+	})
+}
+
 func (c *applicationsServiceImpl) deleteService(ctx context.Context, namespace, name string) error {
 	if name == "" {
 		return nil
@@ -1747,6 +1938,7 @@ func (c *applicationsServiceImpl) execWorkflow(ctx context.Context, workflow *mo
 		WorkflowDisplayName: workflow.Alias,
 		Type:                workflow.WorkflowType,
 		Status:              config.StatusWaiting,
+		SubmitTime:          time.Now().UnixNano(),
 	}
 
 	if err := c.WorkflowQueueRepo.Create(ctx, workflowTask); err != nil {
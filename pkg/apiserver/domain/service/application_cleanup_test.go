@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
@@ -67,6 +68,51 @@ func TestCleanupApplicationResourcesDeletesWorkload(t *testing.T) {
 	require.Empty(t, resp.FailedResources)
 }
 
+func TestCleanupApplicationResourcesDeletesBatchAndDaemonWorkloads(t *testing.T) {
+	app := &model.Applications{ID: "app-1", Name: "demo", Namespace: "default"}
+	cronProps, err := model.NewJSONStructByStruct(&model.Properties{
+		Job: &model.JobProperties{Schedule: "0 * * * *"},
+	})
+	require.NoError(t, err)
+	components := []*model.ApplicationComponent{
+		{Name: "migrate", AppID: app.ID, Namespace: "default", ComponentType: config.BatchJob, Image: "migrate:v1"},
+		{Name: "report", AppID: app.ID, Namespace: "default", ComponentType: config.CronJob, Image: "report:v1", Properties: cronProps},
+		{Name: "agent", AppID: app.ID, Namespace: "default", ComponentType: config.DaemonJob, Image: "agent:v1"},
+	}
+	store := &cleanupStore{
+		app:          app,
+		components:   components,
+		applications: map[string]*model.Applications{app.ID: app},
+	}
+
+	jobName := naming.BatchJobName("migrate", app.ID)
+	cronName := naming.CronJobName("report", app.ID)
+	daemonName := naming.DaemonSetName("agent", app.ID)
+	clientset := fake.NewSimpleClientset(
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: jobName, Namespace: "default"}},
+		&batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: cronName, Namespace: "default"}},
+		&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: daemonName, Namespace: "default"}},
+	)
+	svc := &applicationsServiceImpl{
+		KubeClient:    clientset,
+		AppRepo:       &mockCleanupAppRepo{store: store},
+		ComponentRepo: &mockCleanupComponentRepo{store: store},
+	}
+
+	resp, err := svc.CleanupApplicationResources(context.Background(), app.ID)
+	require.NoError(t, err)
+	require.Contains(t, resp.DeletedResources, "Job:default/"+jobName)
+	require.Contains(t, resp.DeletedResources, "CronJob:default/"+cronName)
+	require.Contains(t, resp.DeletedResources, "DaemonSet:default/"+daemonName)
+
+	jobs, err := clientset.BatchV1().Jobs("default").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, jobs.Items)
+	cronJobs, err := clientset.BatchV1().CronJobs("default").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, cronJobs.Items)
+}
+
 type cleanupStore struct {
 	app          *model.Applications
 	components   []*model.ApplicationComponent
@@ -9,6 +9,7 @@ import (
 	"kubemin-cli/pkg/apiserver/config"
 	"kubemin-cli/pkg/apiserver/domain/model"
 	apisv1 "kubemin-cli/pkg/apiserver/interfaces/api/dto/v1"
+	"kubemin-cli/pkg/apiserver/utils/bcode"
 )
 
 func TestCreateApplications_UpdatePreservesWorkflowID(t *testing.T) {
@@ -76,3 +77,9 @@ func TestCreateApplications_UpdatePreservesWorkflowID(t *testing.T) {
 	require.Len(t, decoded.Steps, 1)
 	require.Equal(t, "step1", decoded.Steps[0].Name)
 }
+
+func TestCreateApplications_RejectsUnknownConcurrencyPolicy(t *testing.T) {
+	svc := &applicationsServiceImpl{}
+	_, err := svc.CreateApplications(context.Background(), apisv1.CreateApplicationsRequest{Name: "demo", ConcurrencyPolicy: "parallel"})
+	require.ErrorIs(t, err, bcode.ErrApplicationConcurrencyPolicy)
+}
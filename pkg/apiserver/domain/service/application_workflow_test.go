@@ -462,3 +462,97 @@ func (s *inMemoryAppStore) CompareAndSwap(context.Context, datastore.Entity, str
 }
 
 var _ datastore.DataStore = (*inMemoryAppStore)(nil)
+
+func TestUpdateApplicationWorkflowPersistsStepDependencies(t *testing.T) {
+	store := newInMemoryAppStore()
+	store.apps["app-1"] = &model.Applications{ID: "app-1", Name: "DemoApp", Project: "proj-1"}
+	store.components["config"] = &model.ApplicationComponent{Name: "config", AppID: "app-1"}
+	store.components["api"] = &model.ApplicationComponent{Name: "api", AppID: "app-1"}
+	store.components["worker"] = &model.ApplicationComponent{Name: "worker", AppID: "app-1"}
+	svc := newMockServiceWithStore(store)
+
+	req := apisv1.UpdateApplicationWorkflowRequest{
+		Name: "dag-flow",
+		Workflow: []apisv1.CreateWorkflowStepRequest{
+			{Name: "config", Components: []string{"config"}},
+			{Name: "api", Components: []string{"api"}, DependsOn: []string{"config"}},
+			{Name: "worker", Components: []string{"worker"}, DependsOn: []string{"config", "config"}},
+		},
+	}
+
+	resp, err := svc.UpdateApplicationWorkflow(context.Background(), "app-1", req)
+	require.NoError(t, err)
+
+	steps := decodeWorkflowSteps(t, store.workflows[resp.WorkflowID].Steps)
+	require.Len(t, steps.Steps, 3)
+	require.Empty(t, steps.Steps[0].DependsOn)
+	require.Equal(t, []string{"config"}, steps.Steps[1].DependsOn)
+	require.Equal(t, []string{"config"}, steps.Steps[2].DependsOn)
+}
+
+func TestUpdateApplicationWorkflowRejectsDependencyCycle(t *testing.T) {
+	store := newInMemoryAppStore()
+	store.apps["app-1"] = &model.Applications{ID: "app-1", Name: "DemoApp", Project: "proj-1"}
+	store.components["api"] = &model.ApplicationComponent{Name: "api", AppID: "app-1"}
+	store.components["worker"] = &model.ApplicationComponent{Name: "worker", AppID: "app-1"}
+	svc := newMockServiceWithStore(store)
+
+	req := apisv1.UpdateApplicationWorkflowRequest{
+		Name: "cyclic-flow",
+		Workflow: []apisv1.CreateWorkflowStepRequest{
+			{Name: "api", Components: []string{"api"}, DependsOn: []string{"worker"}},
+			{Name: "worker", Components: []string{"worker"}, DependsOn: []string{"api"}},
+		},
+	}
+
+	_, err := svc.UpdateApplicationWorkflow(context.Background(), "app-1", req)
+	require.Error(t, err)
+	require.True(t, errors.Is(err, bcode.ErrWorkflowStepDependency))
+	require.Contains(t, err.Error(), "cycle")
+	require.Empty(t, store.workflows)
+}
+
+func TestUpdateApplicationWorkflowStoresStepCondition(t *testing.T) {
+	store := newInMemoryAppStore()
+	store.apps["app-1"] = &model.Applications{ID: "app-1", Name: "DemoApp", Project: "proj-1"}
+	store.components["api"] = &model.ApplicationComponent{Name: "api", AppID: "app-1"}
+	svc := newMockServiceWithStore(store)
+
+	req := apisv1.UpdateApplicationWorkflowRequest{
+		Name: "conditional-flow",
+		Workflow: []apisv1.CreateWorkflowStepRequest{
+			{Name: "api", Components: []string{"api"}, Condition: ` inputs.deploy == "true" `},
+		},
+	}
+
+	resp, err := svc.UpdateApplicationWorkflow(context.Background(), "app-1", req)
+	require.NoError(t, err)
+	steps := decodeWorkflowSteps(t, store.workflows[resp.WorkflowID].Steps)
+	require.Equal(t, `inputs.deploy == "true"`, steps.Steps[0].Condition)
+
+	req.Name = "broken-flow"
+	req.Workflow[0].Condition = "inputs.deploy =="
+	_, err = svc.UpdateApplicationWorkflow(context.Background(), "app-1", req)
+	require.ErrorIs(t, err, bcode.ErrWorkflowStepCondition)
+}
+
+func TestUpdateApplicationWorkflowStepTimeout(t *testing.T) {
+	store := newInMemoryAppStore()
+	store.apps["app-1"] = &model.Applications{ID: "app-1", Name: "DemoApp", Project: "proj-1"}
+	store.components["api"] = &model.ApplicationComponent{Name: "api", AppID: "app-1"}
+	svc := newMockServiceWithStore(store)
+
+	req := apisv1.UpdateApplicationWorkflowRequest{
+		Name:     "timed-flow",
+		Workflow: []apisv1.CreateWorkflowStepRequest{{Name: "api", Components: []string{"api"}, TimeoutSeconds: 300}},
+	}
+	resp, err := svc.UpdateApplicationWorkflow(context.Background(), "app-1", req)
+	require.NoError(t, err)
+	steps := decodeWorkflowSteps(t, store.workflows[resp.WorkflowID].Steps)
+	require.Equal(t, 300, steps.Steps[0].TimeoutSeconds)
+
+	req.Name = "negative-flow"
+	req.Workflow[0].TimeoutSeconds = -1
+	_, err = svc.UpdateApplicationWorkflow(context.Background(), "app-1", req)
+	require.ErrorIs(t, err, bcode.ErrWorkflowStepTimeout)
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+	"kubemin-cli/pkg/apiserver/utils/bcode"
+)
+
+// IdempotencyService 按 Idempotency-Key 对可重试的写请求去重，保存首次成功的响应用于重放
+type IdempotencyService interface {
+	// Begin 占用 scope 下的 key。返回非空 replay 时请求已经完成过，调用方直接返回 replay；
+	// 否则调用方执行请求，并在成功后调用 Complete、失败后调用 Release
+	Begin(ctx context.Context, scope, key string, request interface{}) (replay []byte, err error)
+	Complete(ctx context.Context, scope, key string, response interface{}) error
+	Release(ctx context.Context, scope, key string)
+}
+
+type idempotencyServiceImpl struct {
+	Store datastore.DataStore `inject:"datastore"`
+	Cfg   *config.Config      `inject:""`
+}
+
+// NewIdempotencyService new idempotency service
+func NewIdempotencyService() IdempotencyService {
+	return &idempotencyServiceImpl{}
+}
+
+func (s *idempotencyServiceImpl) Begin(ctx context.Context, scope, key string, request interface{}) ([]byte, error) {
+	if !validIdempotencyKey(key) {
+		return nil, bcode.ErrIdempotencyKeyInvalid
+	}
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("hash idempotent request: %w", err)
+	}
+	sum := sha256.Sum256(payload)
+	hash := hex.EncodeToString(sum[:])
+	now := time.Now()
+	record := &model.IdempotencyRecord{
+		ID:          idempotencyRecordID(scope, key),
+		Scope:       scope,
+		Key:         key,
+		RequestHash: hash,
+		Status:      config.IdempotencyPending,
+		ExpireTime:  now.Add(s.window()).Unix(),
+	}
+	// 第二次尝试用于覆盖过期或中断的旧记录
+	for attempt := 0; attempt < 2; attempt++ {
+		err := s.Store.Add(ctx, record)
+		if err == nil {
+			return nil, nil
+		}
+		if !errors.Is(err, datastore.ErrRecordExist) {
+			return nil, err
+		}
+		existing := &model.IdempotencyRecord{ID: record.ID}
+		if err := s.Store.Get(ctx, existing); err != nil {
+			if errors.Is(err, datastore.ErrRecordNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		if !idempotencyRecordStale(existing, now) {
+			switch {
+			case existing.RequestHash != hash:
+				return nil, bcode.ErrIdempotencyKeyConflict
+			case existing.Status == config.IdempotencyCompleted:
+				return []byte(existing.Response), nil
+			default:
+				return nil, bcode.ErrIdempotencyKeyInProgress
+			}
+		}
+		if err := s.Store.Delete(ctx, existing); err != nil && !errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, err
+		}
+	}
+	return nil, bcode.ErrIdempotencyKeyInProgress
+}
+
+func (s *idempotencyServiceImpl) Complete(ctx context.Context, scope, key string, response interface{}) error {
+	payload, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("marshal idempotent response: %w", err)
+	}
+	return s.Store.Put(ctx, &model.IdempotencyRecord{
+		ID:       idempotencyRecordID(scope, key),
+		Status:   config.IdempotencyCompleted,
+		Response: string(payload),
+	})
+}
+
+// Release 删除未完成的记录，失败的请求可以使用同一个 key 重试
+func (s *idempotencyServiceImpl) Release(ctx context.Context, scope, key string) {
+	err := s.Store.Delete(ctx, &model.IdempotencyRecord{ID: idempotencyRecordID(scope, key)})
+	if err != nil && !errors.Is(err, datastore.ErrRecordNotExist) {
+		klog.Warningf("release idempotency key of %s failed: %v", scope, err)
+	}
+}
+
+func (s *idempotencyServiceImpl) window() time.Duration {
+	if s.Cfg != nil && s.Cfg.IdempotencyWindow > 0 {
+		return s.Cfg.IdempotencyWindow
+	}
+	return config.DefaultIdempotencyWindow
+}
+
+func idempotencyRecordID(scope, key string) string {
+	sum := sha256.Sum256([]byte(scope + "\x00" + key))
+	return hex.EncodeToString(sum[:])
+}
+
+// idempotencyRecordStale 记录已过期，或首个请求处理中断（进程退出）后长时间未完成
+func idempotencyRecordStale(record *model.IdempotencyRecord, now time.Time) bool {
+	if now.Unix() >= record.ExpireTime {
+		return true
+	}
+	return record.Status == config.IdempotencyPending && now.Sub(record.UpdateTime) > config.IdempotencyPendingTimeout
+}
+
+func validIdempotencyKey(key string) bool {
+	if key == "" || len(key) > config.IdempotencyKeyMaxLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+	apis "kubemin-cli/pkg/apiserver/interfaces/api/dto/v1"
+	"kubemin-cli/pkg/apiserver/utils/bcode"
+)
+
+// idempotencyDataStore keeps idempotency records by primary key and rejects duplicate adds
+// like the SQL driver does.
+type idempotencyDataStore struct {
+	statusDataStore
+	records map[string]*model.IdempotencyRecord
+}
+
+func (s *idempotencyDataStore) Add(_ context.Context, entity datastore.Entity) error {
+	record := entity.(*model.IdempotencyRecord)
+	if _, exists := s.records[record.ID]; exists {
+		return datastore.ErrRecordExist
+	}
+	record.SetCreateTime(time.Now())
+	record.SetUpdateTime(time.Now())
+	copied := *record
+	s.records[record.ID] = &copied
+	return nil
+}
+
+func (s *idempotencyDataStore) Get(_ context.Context, entity datastore.Entity) error {
+	record := entity.(*model.IdempotencyRecord)
+	stored, ok := s.records[record.ID]
+	if !ok {
+		return datastore.ErrRecordNotExist
+	}
+	*record = *stored
+	return nil
+}
+
+func (s *idempotencyDataStore) Put(_ context.Context, entity datastore.Entity) error {
+	record := entity.(*model.IdempotencyRecord)
+	stored, ok := s.records[record.ID]
+	if !ok {
+		return datastore.ErrRecordNotExist
+	}
+	stored.Status, stored.Response = record.Status, record.Response
+	stored.SetUpdateTime(time.Now())
+	return nil
+}
+
+func (s *idempotencyDataStore) Delete(_ context.Context, entity datastore.Entity) error {
+	record := entity.(*model.IdempotencyRecord)
+	if _, ok := s.records[record.ID]; !ok {
+		return datastore.ErrRecordNotExist
+	}
+	delete(s.records, record.ID)
+	return nil
+}
+
+func newIdempotencyFixture() (*idempotencyServiceImpl, *idempotencyDataStore) {
+	store := &idempotencyDataStore{records: map[string]*model.IdempotencyRecord{}}
+	cfg := config.NewConfig()
+	cfg.IdempotencyWindow = time.Hour
+	return &idempotencyServiceImpl{Store: store, Cfg: cfg}, store
+}
+
+func TestIdempotencyReplaysCompletedResponse(t *testing.T) {
+	svc, _ := newIdempotencyFixture()
+	ctx := context.Background()
+	req := apis.ExecWorkflowRequest{WorkflowID: "wf-1"}
+
+	replay, err := svc.Begin(ctx, "workflow.exec:app-1", "ci-123", req)
+	require.NoError(t, err)
+	require.Nil(t, replay)
+
+	// A retry arriving while the first request is running must not execute it again.
+	_, err = svc.Begin(ctx, "workflow.exec:app-1", "ci-123", req)
+	require.ErrorIs(t, err, bcode.ErrIdempotencyKeyInProgress)
+
+	require.NoError(t, svc.Complete(ctx, "workflow.exec:app-1", "ci-123", &apis.ExecWorkflowResponse{TaskID: "task-1"}))
+	replay, err = svc.Begin(ctx, "workflow.exec:app-1", "ci-123", req)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"task_id":"task-1"}`, string(replay))
+
+	_, err = svc.Begin(ctx, "workflow.exec:app-1", "ci-123", apis.ExecWorkflowRequest{WorkflowID: "wf-2"})
+	require.ErrorIs(t, err, bcode.ErrIdempotencyKeyConflict)
+
+	// Keys are scoped: the same key on another application is a new request.
+	replay, err = svc.Begin(ctx, "workflow.exec:app-2", "ci-123", req)
+	require.NoError(t, err)
+	require.Nil(t, replay)
+}
+
+func TestIdempotencyReleaseAllowsRetry(t *testing.T) {
+	svc, store := newIdempotencyFixture()
+	ctx := context.Background()
+
+	_, err := svc.Begin(ctx, "applications.create", "key-1", map[string]string{"name": "demo"})
+	require.NoError(t, err)
+	svc.Release(ctx, "applications.create", "key-1")
+	require.Empty(t, store.records)
+
+	replay, err := svc.Begin(ctx, "applications.create", "key-1", map[string]string{"name": "demo"})
+	require.NoError(t, err)
+	require.Nil(t, replay)
+}
+
+func TestIdempotencyReclaimsExpiredAndAbandonedRecords(t *testing.T) {
+	svc, store := newIdempotencyFixture()
+	ctx := context.Background()
+	id := idempotencyRecordID("applications.create", "key-1")
+
+	store.records[id] = &model.IdempotencyRecord{
+		ID:          id,
+		RequestHash: "other",
+		Status:      config.IdempotencyCompleted,
+		ExpireTime:  time.Now().Add(-time.Minute).Unix(),
+	}
+	replay, err := svc.Begin(ctx, "applications.create", "key-1", "demo")
+	require.NoError(t, err)
+	require.Nil(t, replay)
+	require.Equal(t, config.IdempotencyPending, store.records[id].Status)
+
+	store.records[id].UpdateTime = time.Now().Add(-config.IdempotencyPendingTimeout - time.Second)
+	replay, err = svc.Begin(ctx, "applications.create", "key-1", "demo")
+	require.NoError(t, err)
+	require.Nil(t, replay)
+}
+
+func TestIdempotencyRejectsInvalidKey(t *testing.T) {
+	svc, _ := newIdempotencyFixture()
+	for _, key := range []string{"", "bad\nkey", string(make([]byte, config.IdempotencyKeyMaxLength+1))} {
+		_, err := svc.Begin(context.Background(), "applications.create", key, "demo")
+		require.ErrorIs(t, err, bcode.ErrIdempotencyKeyInvalid)
+	}
+}
@@ -9,10 +9,14 @@ func InitServiceBean(c config.Config) []interface{} {
 	applicationService := NewApplicationService()
 	workflowService := NewWorkflowService()
 	validationService := NewValidationService()
+	webhookService := NewWebhookService()
+	idempotencyService := NewIdempotencyService()
 
 	return []interface{}{
 		applicationService,
 		workflowService,
 		validationService,
+		webhookService,
+		idempotencyService,
 	}
 }
@@ -7,9 +7,11 @@ import (
 	"strings"
 
 	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
 	"kubemin-cli/pkg/apiserver/domain/repository"
 	"kubemin-cli/pkg/apiserver/domain/spec"
 	apisv1 "kubemin-cli/pkg/apiserver/interfaces/api/dto/v1"
+	wf "kubemin-cli/pkg/apiserver/workflow"
 )
 
 var (
@@ -41,6 +43,9 @@ var (
 		config.StoreJob:  true,
 		config.ConfJob:   true,
 		config.SecretJob: true,
+		config.DaemonJob: true,
+		config.BatchJob:  true,
+		config.CronJob:   true,
 	}
 
 	// Valid workflow modes
@@ -210,16 +215,25 @@ func (v *validationServiceImpl) validateComponent(comp apisv1.CreateComponentReq
 		errors = append(errors, apisv1.ValidationError{
 			Field:   fmt.Sprintf("%s.type", fieldPrefix),
 			Code:    apisv1.ErrCodeInvalidComponentType,
-			Message: fmt.Sprintf("invalid component type: %s, must be one of: webservice, store, config, secret", comp.ComponentType),
+			Message: fmt.Sprintf("invalid component type: %s, must be one of: webservice, store, daemon, config, secret, job, cronjob", comp.ComponentType),
 		})
 	}
 
-	// Validate image requirement for webservice and store types
-	if (comp.ComponentType == config.ServerJob || comp.ComponentType == config.StoreJob) && comp.Image == "" {
+	// Validate image requirement for workload types
+	if requiresImage(comp.ComponentType) && comp.Image == "" {
 		errors = append(errors, apisv1.ValidationError{
 			Field:   fmt.Sprintf("%s.image", fieldPrefix),
 			Code:    apisv1.ErrCodeMissingImage,
-			Message: "image is required for webservice and store component types",
+			Message: "image is required for webservice, store, daemon, job and cronjob component types",
+		})
+	}
+
+	// Validate job settings for job and cronjob types
+	if err := validateBatchJobProperties(comp.ComponentType, comp.Properties); err != nil {
+		errors = append(errors, apisv1.ValidationError{
+			Field:   fmt.Sprintf("%s.properties.job", fieldPrefix),
+			Code:    apisv1.ErrCodeInvalidJobConfig,
+			Message: "cronjob requires a valid schedule; restart_policy must be Never or OnFailure; concurrency_policy must be Allow, Forbid or Replace",
 		})
 	}
 
@@ -697,9 +711,74 @@ func (v *validationServiceImpl) validateWorkflowSteps(steps []apisv1.CreateWorkf
 			})
 		}
 
+		// Validate retry policy
+		if step.Retry != nil {
+			if step.Retry.Attempts < 1 || step.Retry.DelaySeconds < 0 || step.Retry.MaxDelay < 0 ||
+				(step.Retry.BackoffFactor != 0 && step.Retry.BackoffFactor < 1) {
+				errors = append(errors, apisv1.ValidationError{
+					Field:   fmt.Sprintf("%s.retry", stepField),
+					Code:    apisv1.ErrCodeInvalidRetryPolicy,
+					Message: "retry attempts must be >= 1, delays must be >= 0 and backoff_factor must be >= 1",
+				})
+			}
+		}
+
+		// Validate step timeout
+		if step.TimeoutSeconds < 0 {
+			errors = append(errors, apisv1.ValidationError{
+				Field:   fmt.Sprintf("%s.timeout_seconds", stepField),
+				Code:    apisv1.ErrCodeInvalidStepTimeout,
+				Message: "timeout_seconds must be >= 0",
+			})
+		}
+
+		// Validate condition expression
+		if strings.TrimSpace(step.Condition) != "" {
+			if err := wf.ValidateCondition(step.Condition); err != nil {
+				errors = append(errors, apisv1.ValidationError{
+					Field:   fmt.Sprintf("%s.condition", stepField),
+					Code:    apisv1.ErrCodeInvalidStepCondition,
+					Message: err.Error(),
+				})
+			}
+		}
+
 		// Collect all component references from step
 		allComponents := mergeWorkflowComponents(step.Components, step.Properties.Policies)
 
+		// Approval steps only gate the workflow and must not deploy anything
+		if step.WorkflowType == config.JobApproval {
+			if step.Name == "" || len(allComponents) > 0 || len(step.SubSteps) > 0 {
+				errors = append(errors, apisv1.ValidationError{
+					Field:   stepField,
+					Code:    apisv1.ErrCodeInvalidApprovalStep,
+					Message: "approval step must have a name and cannot contain components or substeps",
+				})
+			}
+			continue
+		}
+
+		// Built-in steps run their own job and must not deploy anything
+		if wf.IsBuiltinStepType(step.WorkflowType) {
+			var spec *model.StepSpec
+			if step.Spec != nil {
+				converted := model.StepSpec(*step.Spec)
+				spec = &converted
+			}
+			err := wf.ValidateStepSpec(step.WorkflowType, spec)
+			if err == nil && (step.Name == "" || len(allComponents) > 0 || len(step.SubSteps) > 0) {
+				err = fmt.Errorf("%s step must have a name and cannot contain components or substeps", step.WorkflowType)
+			}
+			if err != nil {
+				errors = append(errors, apisv1.ValidationError{
+					Field:   stepField,
+					Code:    apisv1.ErrCodeInvalidBuiltinStep,
+					Message: err.Error(),
+				})
+			}
+			continue
+		}
+
 		// Check if step has any components
 		if len(allComponents) == 0 && len(step.SubSteps) == 0 {
 			errors = append(errors, apisv1.ValidationError{
@@ -745,5 +824,18 @@ func (v *validationServiceImpl) validateWorkflowSteps(steps []apisv1.CreateWorkf
 		}
 	}
 
+	// Validate step dependencies (unknown steps and cycles)
+	modelSteps := make([]*model.WorkflowStep, 0, len(steps))
+	for _, step := range steps {
+		modelSteps = append(modelSteps, &model.WorkflowStep{Name: step.Name, DependsOn: step.DependsOn})
+	}
+	if err := wf.ValidateStepDependencies(modelSteps); err != nil {
+		errors = append(errors, apisv1.ValidationError{
+			Field:   fmt.Sprintf("%s.depends_on", fieldPrefix),
+			Code:    apisv1.ErrCodeInvalidStepDependency,
+			Message: err.Error(),
+		})
+	}
+
 	return errors
 }
@@ -146,6 +146,40 @@ func TestValidationService_TryApplication_MissingImage(t *testing.T) {
 	assert.True(t, found, "Expected missing image error")
 }
 
+func TestValidationService_TryApplication_CronJobSchedule(t *testing.T) {
+	svc := &validationServiceImpl{}
+	ctx := context.Background()
+
+	newReq := func(schedule string) apisv1.CreateApplicationsRequest {
+		return apisv1.CreateApplicationsRequest{
+			Name:      "my-app",
+			Namespace: "default",
+			Component: []apisv1.CreateComponentRequest{
+				{
+					Name:          "report",
+					ComponentType: config.CronJob,
+					Image:         "report:v1",
+					Properties:    apisv1.Properties{Job: &spec.JobProperties{Schedule: schedule}},
+				},
+			},
+		}
+	}
+
+	resp := svc.TryApplication(ctx, newReq("0 3 * * *"))
+	assert.True(t, resp.Valid, "Expected valid cronjob, got errors: %v", resp.Errors)
+
+	resp = svc.TryApplication(ctx, newReq("every night"))
+	assert.False(t, resp.Valid, "Expected invalid due to bad schedule")
+	found := false
+	for _, err := range resp.Errors {
+		if err.Code == apisv1.ErrCodeInvalidJobConfig {
+			found = true
+			break
+		}
+	}
+	assert.True(t, found, "Expected invalid job config error")
+}
+
 func TestValidationService_TryApplication_InvalidComponentType(t *testing.T) {
 	svc := &validationServiceImpl{}
 	ctx := context.Background()
@@ -1443,3 +1477,39 @@ func TestValidationService_TryWorkflow_ValidWorkflow(t *testing.T) {
 	}
 	assert.True(t, found, "Expected component not found error when appID is empty")
 }
+
+func TestValidationService_TryApplication_UnknownStepDependency(t *testing.T) {
+	svc := &validationServiceImpl{}
+	ctx := context.Background()
+
+	req := apisv1.CreateApplicationsRequest{
+		Name:      "my-app",
+		Namespace: "default",
+		Component: []apisv1.CreateComponentRequest{
+			{
+				Name:          "backend",
+				ComponentType: config.ServerJob,
+				Image:         "nginx:latest",
+			},
+		},
+		WorkflowSteps: []apisv1.CreateWorkflowStepRequest{
+			{
+				Name:       "deploy-step",
+				Components: []string{"backend"},
+				DependsOn:  []string{"missing-step"},
+			},
+		},
+	}
+
+	resp := svc.TryApplication(ctx, req)
+
+	assert.False(t, resp.Valid, "Expected invalid due to unknown step dependency")
+	found := false
+	for _, err := range resp.Errors {
+		if err.Code == apisv1.ErrCodeInvalidStepDependency {
+			found = true
+			break
+		}
+	}
+	assert.True(t, found, "Expected invalid step dependency error")
+}
@@ -0,0 +1,418 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/domain/repository"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+	apis "kubemin-cli/pkg/apiserver/interfaces/api/dto/v1"
+	"kubemin-cli/pkg/apiserver/utils"
+	"kubemin-cli/pkg/apiserver/utils/bcode"
+)
+
+// webhookDeliveryListLimit bounds the delivery log returned for a webhook.
+const webhookDeliveryListLimit = 50
+
+// WebhookService 管理应用的入站 webhook，并将签名通过的投递转换为版本更新
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, appID string, req apis.CreateWebhookRequest) (*model.WorkflowWebhook, error)
+	ListWebhooks(ctx context.Context, appID string) ([]*model.WorkflowWebhook, error)
+	GetWebhook(ctx context.Context, appID, webhookID string) (*model.WorkflowWebhook, error)
+	DeleteWebhook(ctx context.Context, appID, webhookID string) error
+	ListWebhookDeliveries(ctx context.Context, appID, webhookID string) ([]*model.WebhookDelivery, error)
+	TriggerWebhook(ctx context.Context, webhookID string, req apis.WebhookTriggerRequest) (*apis.WebhookTriggerResponse, error)
+}
+
+type webhookServiceImpl struct {
+	Store              datastore.DataStore              `inject:"datastore"`
+	AppRepo            repository.ApplicationRepository `inject:""`
+	ApplicationService ApplicationsService              `inject:""`
+	WorkflowService    WorkflowService                  `inject:""`
+}
+
+// NewWebhookService new webhook service
+func NewWebhookService() WebhookService {
+	return &webhookServiceImpl{}
+}
+
+// CreateWebhook 创建 webhook，未指定密钥时生成随机密钥
+func (s *webhookServiceImpl) CreateWebhook(ctx context.Context, appID string, req apis.CreateWebhookRequest) (*model.WorkflowWebhook, error) {
+	if _, err := s.AppRepo.FindByID(ctx, appID); err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, bcode.ErrApplicationNotExist
+		}
+		return nil, err
+	}
+	if req.WorkflowID != "" {
+		workflow, err := repository.WorkflowByID(ctx, s.Store, req.WorkflowID)
+		if err != nil {
+			if errors.Is(err, datastore.ErrRecordNotExist) {
+				return nil, bcode.ErrWorkflowNotExist
+			}
+			return nil, err
+		}
+		if workflow.AppID != appID {
+			return nil, bcode.ErrWorkflowNotExist
+		}
+	}
+	mapping := &model.WebhookMapping{
+		ImagePath:   strings.TrimSpace(req.Mapping.ImagePath),
+		TagPath:     strings.TrimSpace(req.Mapping.TagPath),
+		VersionPath: strings.TrimSpace(req.Mapping.VersionPath),
+		Strategy:    req.Mapping.Strategy,
+	}
+	for _, name := range req.Mapping.Components {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			mapping.Components = append(mapping.Components, name)
+		}
+	}
+	if len(mapping.Components) == 0 || (mapping.ImagePath == "" && mapping.TagPath == "") {
+		return nil, bcode.ErrWebhookConfig
+	}
+	secret := req.Secret
+	if secret == "" {
+		var err error
+		if secret, err = newWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+	webhook := &model.WorkflowWebhook{
+		ID:         utils.RandStringByNumLowercase(24),
+		Name:       req.Name,
+		AppID:      appID,
+		WorkflowID: req.WorkflowID,
+		Secret:     secret,
+		Mapping:    mapping,
+		Status:     config.StatusEnabled,
+		Creator:    req.User,
+	}
+	if req.Disabled {
+		webhook.Status = config.StatusDisabled
+	}
+	if err := s.Store.Add(ctx, webhook); err != nil {
+		return nil, err
+	}
+	klog.Infof("AUDIT: create webhook webhookID=%s appID=%s workflowID=%s components=%v user=%s",
+		webhook.ID, appID, webhook.WorkflowID, mapping.Components, req.User)
+	return webhook, nil
+}
+
+// ListWebhooks 列出应用的 webhook
+func (s *webhookServiceImpl) ListWebhooks(ctx context.Context, appID string) ([]*model.WorkflowWebhook, error) {
+	list, err := repository.ListWorkflowWebhooks(ctx, s.Store, appID)
+	if err != nil && !errors.Is(err, datastore.ErrRecordNotExist) {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetWebhook 查询应用下的单个 webhook
+func (s *webhookServiceImpl) GetWebhook(ctx context.Context, appID, webhookID string) (*model.WorkflowWebhook, error) {
+	webhook, err := repository.WorkflowWebhookByID(ctx, s.Store, webhookID)
+	if err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, bcode.ErrWebhookNotExist
+		}
+		return nil, err
+	}
+	if webhook.AppID != appID {
+		return nil, bcode.ErrWebhookNotExist
+	}
+	return webhook, nil
+}
+
+// DeleteWebhook 删除 webhook，投递记录保留
+func (s *webhookServiceImpl) DeleteWebhook(ctx context.Context, appID, webhookID string) error {
+	webhook, err := s.GetWebhook(ctx, appID, webhookID)
+	if err != nil {
+		return err
+	}
+	if err := s.Store.Delete(ctx, webhook); err != nil {
+		return err
+	}
+	klog.Infof("AUDIT: delete webhook webhookID=%s appID=%s", webhook.ID, appID)
+	return nil
+}
+
+// ListWebhookDeliveries 返回 webhook 最近的投递记录，最新的在前
+func (s *webhookServiceImpl) ListWebhookDeliveries(ctx context.Context, appID, webhookID string) ([]*model.WebhookDelivery, error) {
+	if _, err := s.GetWebhook(ctx, appID, webhookID); err != nil {
+		return nil, err
+	}
+	list, err := repository.ListWebhookDeliveries(ctx, s.Store, webhookID, webhookDeliveryListLimit)
+	if err != nil && !errors.Is(err, datastore.ErrRecordNotExist) {
+		return nil, err
+	}
+	return list, nil
+}
+
+// TriggerWebhook 校验签名并按映射从负载中提取镜像与版本，调用版本更新。
+// 每次投递（包括被拒绝或忽略的）都会记录，便于排查为何没有创建任务
+func (s *webhookServiceImpl) TriggerWebhook(ctx context.Context, webhookID string, req apis.WebhookTriggerRequest) (*apis.WebhookTriggerResponse, error) {
+	webhook, err := repository.WorkflowWebhookByID(ctx, s.Store, webhookID)
+	if err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, bcode.ErrWebhookNotExist
+		}
+		return nil, err
+	}
+	delivery := &model.WebhookDelivery{
+		WebhookID: webhook.ID,
+		AppID:     webhook.AppID,
+		Source:    req.Source,
+		Payload:   truncatePayload(req.Body),
+	}
+	if !verifyWebhookSignature(webhook, req) {
+		delivery.Result = config.WebhookDeliveryRejected
+		delivery.Reason = "signature is missing or invalid"
+		s.recordDelivery(ctx, delivery)
+		return nil, bcode.ErrWebhookSignature
+	}
+	var payload interface{}
+	if err := json.Unmarshal(req.Body, &payload); err != nil {
+		delivery.Result = config.WebhookDeliveryIgnored
+		delivery.Reason = fmt.Sprintf("payload is not valid JSON: %v", err)
+		s.recordDelivery(ctx, delivery)
+		return nil, bcode.ErrWebhookPayload
+	}
+	s.deliver(ctx, webhook, payload, delivery)
+	s.recordDelivery(ctx, delivery)
+	return &apis.WebhookTriggerResponse{
+		DeliveryID: delivery.ID,
+		Result:     string(delivery.Result),
+		Reason:     delivery.Reason,
+		Version:    delivery.Version,
+		TaskID:     delivery.TaskID,
+	}, nil
+}
+
+// deliver applies a verified payload and fills in the outcome of the delivery.
+func (s *webhookServiceImpl) deliver(ctx context.Context, webhook *model.WorkflowWebhook, payload interface{}, delivery *model.WebhookDelivery) {
+	if webhook.Status != config.StatusEnabled {
+		delivery.Result = config.WebhookDeliveryIgnored
+		delivery.Reason = "webhook is disabled"
+		return
+	}
+	if webhook.Mapping == nil {
+		delivery.Result = config.WebhookDeliveryIgnored
+		delivery.Reason = "webhook has no mapping"
+		return
+	}
+	update, reason, err := s.buildVersionUpdate(ctx, webhook, payload)
+	if err != nil {
+		delivery.Result = config.WebhookDeliveryFailed
+		delivery.Reason = err.Error()
+		return
+	}
+	if update == nil {
+		delivery.Result = config.WebhookDeliveryIgnored
+		delivery.Reason = reason
+		return
+	}
+	delivery.Version = update.Version
+
+	resp, err := s.ApplicationService.UpdateVersion(ctx, webhook.AppID, *update)
+	if err != nil {
+		delivery.Result = config.WebhookDeliveryFailed
+		delivery.Reason = fmt.Sprintf("update version: %v", err)
+		return
+	}
+	delivery.TaskID = resp.TaskID
+	if webhook.WorkflowID != "" && len(resp.UpdatedComponents) > 0 {
+		inputs := map[string]interface{}{"webhook": webhook.Name, "version": update.Version}
+		execResp, err := s.WorkflowService.ExecWorkflowTaskForApp(ctx, webhook.AppID, webhook.WorkflowID, inputs, 0)
+		if err != nil {
+			delivery.Result = config.WebhookDeliveryFailed
+			delivery.Reason = fmt.Sprintf("version %s applied but exec workflow %s failed: %v", update.Version, webhook.WorkflowID, err)
+			return
+		}
+		delivery.TaskID = execResp.TaskID
+	}
+	if delivery.TaskID == "" {
+		delivery.Result = config.WebhookDeliveryUpdated
+		delivery.Reason = "no component changed, workflow not started"
+		return
+	}
+	delivery.Result = config.WebhookDeliveryTriggered
+}
+
+// buildVersionUpdate maps the payload onto a version update request. A nil request with a
+// reason means the payload does not carry the mapped fields and the delivery is ignored.
+func (s *webhookServiceImpl) buildVersionUpdate(ctx context.Context, webhook *model.WorkflowWebhook, payload interface{}) (*apis.UpdateVersionRequest, string, error) {
+	mapping := webhook.Mapping
+	var image, tag string
+	if mapping.ImagePath != "" {
+		var ok bool
+		if image, ok = lookupPayloadPath(payload, mapping.ImagePath); !ok || image == "" {
+			return nil, fmt.Sprintf("no image at %q", mapping.ImagePath), nil
+		}
+		tag = imageTag(image)
+	} else {
+		var ok bool
+		if tag, ok = lookupPayloadPath(payload, mapping.TagPath); !ok || tag == "" {
+			return nil, fmt.Sprintf("no tag at %q", mapping.TagPath), nil
+		}
+	}
+	version := tag
+	if mapping.VersionPath != "" {
+		if v, ok := lookupPayloadPath(payload, mapping.VersionPath); ok && v != "" {
+			version = v
+		}
+	}
+	if version == "" {
+		return nil, "no version could be derived from the payload", nil
+	}
+
+	current := make(map[string]string)
+	if image == "" {
+		components, err := s.ApplicationService.ListApplicationComponents(ctx, webhook.AppID)
+		if err != nil {
+			return nil, "", fmt.Errorf("list components: %w", err)
+		}
+		for _, component := range components {
+			current[strings.ToLower(component.Name)] = component.Image
+		}
+	}
+	update := &apis.UpdateVersionRequest{
+		Version:     version,
+		Strategy:    mapping.Strategy,
+		Description: fmt.Sprintf("triggered by webhook %s", webhook.Name),
+	}
+	if webhook.WorkflowID != "" {
+		autoExec := false
+		update.AutoExec = &autoExec
+	}
+	for _, name := range mapping.Components {
+		componentImage := image
+		if componentImage == "" {
+			existing, ok := current[name]
+			if !ok {
+				return nil, fmt.Sprintf("component %s does not exist", name), nil
+			}
+			componentImage = replaceImageTag(existing, tag)
+		}
+		update.Components = append(update.Components, apis.ComponentUpdateSpec{Name: name, Image: componentImage})
+	}
+	return update, "", nil
+}
+
+func (s *webhookServiceImpl) recordDelivery(ctx context.Context, delivery *model.WebhookDelivery) {
+	if err := s.Store.Add(ctx, delivery); err != nil {
+		klog.Errorf("record delivery of webhook %s failed: %v", delivery.WebhookID, err)
+	}
+	klog.Infof("webhook delivery webhookID=%s appID=%s result=%s version=%s taskID=%s reason=%s",
+		delivery.WebhookID, delivery.AppID, delivery.Result, delivery.Version, delivery.TaskID, delivery.Reason)
+}
+
+// WebhookTriggerToken returns the token that authorises the signed trigger URL of a webhook.
+func WebhookTriggerToken(webhook *model.WorkflowWebhook) string {
+	return webhookHMAC(webhook.Secret, []byte(webhook.ID))
+}
+
+// verifyWebhookSignature accepts either an HMAC-SHA256 signature of the body or, for callers that
+// cannot sign requests, the token of the signed trigger URL.
+func verifyWebhookSignature(webhook *model.WorkflowWebhook, req apis.WebhookTriggerRequest) bool {
+	if webhook.Secret == "" {
+		return false
+	}
+	if signature := strings.TrimSpace(req.Signature); signature != "" {
+		expected := webhookHMAC(webhook.Secret, req.Body)
+		return hmac.Equal([]byte(strings.TrimPrefix(signature, "sha256=")), []byte(expected))
+	}
+	if req.Token != "" {
+		return hmac.Equal([]byte(req.Token), []byte(WebhookTriggerToken(webhook)))
+	}
+	return false
+}
+
+func webhookHMAC(secret string, data []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newWebhookSecret() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// lookupPayloadPath resolves a dot separated path in a decoded JSON document; numeric
+// segments index into arrays. Scalars are rendered as strings.
+func lookupPayloadPath(payload interface{}, path string) (string, bool) {
+	current := payload
+	for _, segment := range strings.Split(path, ".") {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			next, ok := node[segment]
+			if !ok {
+				return "", false
+			}
+			current = next
+		case []interface{}:
+			idx, err := strconv.Atoi(segment)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return "", false
+			}
+			current = node[idx]
+		default:
+			return "", false
+		}
+	}
+	switch v := current.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		return "", false
+	}
+}
+
+// imageTag returns the tag of an image reference, or "" when it has none.
+func imageTag(image string) string {
+	name := image
+	if at := strings.Index(name, "@"); at >= 0 {
+		name = name[:at]
+	}
+	if colon := strings.LastIndex(name, ":"); colon > strings.LastIndex(name, "/") {
+		return name[colon+1:]
+	}
+	return ""
+}
+
+// replaceImageTag swaps the tag (and drops any digest) of an image reference.
+func replaceImageTag(image, tag string) string {
+	name := image
+	if at := strings.Index(name, "@"); at >= 0 {
+		name = name[:at]
+	}
+	if colon := strings.LastIndex(name, ":"); colon > strings.LastIndex(name, "/") {
+		name = name[:colon]
+	}
+	return name + ":" + tag
+}
+
+func truncatePayload(body []byte) string {
+	if len(body) > config.WebhookPayloadLogLimit {
+		return string(body[:config.WebhookPayloadLogLimit])
+	}
+	return string(body)
+}
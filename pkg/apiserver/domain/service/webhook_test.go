@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+	apis "kubemin-cli/pkg/apiserver/interfaces/api/dto/v1"
+	"kubemin-cli/pkg/apiserver/utils/bcode"
+)
+
+// webhookDataStore serves a single webhook and records the deliveries written for it.
+type webhookDataStore struct {
+	statusDataStore
+	webhook    *model.WorkflowWebhook
+	deliveries []*model.WebhookDelivery
+}
+
+func (s *webhookDataStore) Add(_ context.Context, entity datastore.Entity) error {
+	if delivery, ok := entity.(*model.WebhookDelivery); ok {
+		delivery.ID = len(s.deliveries) + 1
+		s.deliveries = append(s.deliveries, delivery)
+	}
+	return nil
+}
+
+func (s *webhookDataStore) Get(ctx context.Context, entity datastore.Entity) error {
+	if v, ok := entity.(*model.WorkflowWebhook); ok {
+		if s.webhook != nil && v.ID == s.webhook.ID {
+			*v = *s.webhook
+			return nil
+		}
+		return datastore.ErrRecordNotExist
+	}
+	return s.statusDataStore.Get(ctx, entity)
+}
+
+// versionRecorder captures the version updates issued by webhook deliveries.
+type versionRecorder struct {
+	ApplicationsService
+	components []*model.ApplicationComponent
+	requests   []apis.UpdateVersionRequest
+}
+
+func (r *versionRecorder) ListApplicationComponents(context.Context, string) ([]*model.ApplicationComponent, error) {
+	return r.components, nil
+}
+
+func (r *versionRecorder) UpdateVersion(_ context.Context, appID string, req apis.UpdateVersionRequest) (*apis.UpdateVersionResponse, error) {
+	r.requests = append(r.requests, req)
+	return &apis.UpdateVersionResponse{AppID: appID, Version: req.Version, TaskID: "task-1", UpdatedComponents: []string{"web"}}, nil
+}
+
+func newWebhookFixture() (*webhookServiceImpl, *webhookDataStore, *versionRecorder) {
+	store := &webhookDataStore{webhook: &model.WorkflowWebhook{
+		ID:     "hook-1",
+		Name:   "registry",
+		AppID:  "app-1",
+		Secret: "s3cret",
+		Status: config.StatusEnabled,
+		Mapping: &model.WebhookMapping{
+			Components: []string{"web"},
+			TagPath:    "push_data.tag",
+		},
+	}}
+	apps := &versionRecorder{components: []*model.ApplicationComponent{{Name: "web", Image: "registry.local/org/web:v1@sha256:abc"}}}
+	return &webhookServiceImpl{Store: store, ApplicationService: apps}, store, apps
+}
+
+func TestTriggerWebhookUpdatesVersionFromTag(t *testing.T) {
+	svc, store, apps := newWebhookFixture()
+	body := []byte(`{"push_data":{"tag":"v2"},"repository":{"repo_name":"org/web"}}`)
+
+	resp, err := svc.TriggerWebhook(context.Background(), "hook-1", apis.WebhookTriggerRequest{
+		Body:      body,
+		Signature: "sha256=" + webhookHMAC("s3cret", body),
+	})
+	require.NoError(t, err)
+	require.Equal(t, string(config.WebhookDeliveryTriggered), resp.Result)
+	require.Equal(t, "task-1", resp.TaskID)
+	require.Equal(t, "v2", resp.Version)
+
+	require.Len(t, apps.requests, 1)
+	require.Equal(t, "v2", apps.requests[0].Version)
+	require.Nil(t, apps.requests[0].AutoExec)
+	require.Equal(t, []apis.ComponentUpdateSpec{{Name: "web", Image: "registry.local/org/web:v2"}}, apps.requests[0].Components)
+
+	require.Len(t, store.deliveries, 1)
+	require.Equal(t, config.WebhookDeliveryTriggered, store.deliveries[0].Result)
+	require.Equal(t, string(body), store.deliveries[0].Payload)
+}
+
+func TestTriggerWebhookAcceptsSignedURLToken(t *testing.T) {
+	svc, store, _ := newWebhookFixture()
+	resp, err := svc.TriggerWebhook(context.Background(), "hook-1", apis.WebhookTriggerRequest{
+		Body:  []byte(`{"push_data":{"tag":"v3"}}`),
+		Token: WebhookTriggerToken(store.webhook),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "v3", resp.Version)
+}
+
+func TestTriggerWebhookRecordsRejectedAndIgnoredDeliveries(t *testing.T) {
+	svc, store, apps := newWebhookFixture()
+
+	_, err := svc.TriggerWebhook(context.Background(), "hook-1", apis.WebhookTriggerRequest{
+		Body:      []byte(`{"push_data":{"tag":"v2"}}`),
+		Signature: "sha256=deadbeef",
+	})
+	require.ErrorIs(t, err, bcode.ErrWebhookSignature)
+
+	body := []byte(`{"ping":true}`)
+	resp, err := svc.TriggerWebhook(context.Background(), "hook-1", apis.WebhookTriggerRequest{
+		Body:      body,
+		Signature: "sha256=" + webhookHMAC("s3cret", body),
+	})
+	require.NoError(t, err)
+	require.Equal(t, string(config.WebhookDeliveryIgnored), resp.Result)
+	require.Contains(t, resp.Reason, "push_data.tag")
+
+	store.webhook.Status = config.StatusDisabled
+	body = []byte(`{"push_data":{"tag":"v2"}}`)
+	resp, err = svc.TriggerWebhook(context.Background(), "hook-1", apis.WebhookTriggerRequest{
+		Body:      body,
+		Signature: "sha256=" + webhookHMAC("s3cret", body),
+	})
+	require.NoError(t, err)
+	require.Equal(t, string(config.WebhookDeliveryIgnored), resp.Result)
+
+	require.Empty(t, apps.requests)
+	require.Len(t, store.deliveries, 3)
+	require.Equal(t, config.WebhookDeliveryRejected, store.deliveries[0].Result)
+}
+
+func TestLookupPayloadPath(t *testing.T) {
+	var payload interface{} = map[string]interface{}{
+		"event_data": map[string]interface{}{
+			"resources": []interface{}{map[string]interface{}{"tag": "1.4.0", "size": float64(42)}},
+		},
+	}
+	v, ok := lookupPayloadPath(payload, "event_data.resources.0.tag")
+	require.True(t, ok)
+	require.Equal(t, "1.4.0", v)
+	v, ok = lookupPayloadPath(payload, "event_data.resources.0.size")
+	require.True(t, ok)
+	require.Equal(t, "42", v)
+	_, ok = lookupPayloadPath(payload, "event_data.resources.1.tag")
+	require.False(t, ok)
+
+	require.Equal(t, "v2", imageTag("localhost:5000/org/web:v2"))
+	require.Equal(t, "", imageTag("localhost:5000/org/web"))
+	require.Equal(t, "localhost:5000/org/web:v3", replaceImageTag("localhost:5000/org/web", "v3"))
+}
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -14,12 +16,14 @@ import (
 	"kubemin-cli/pkg/apiserver/config"
 	"kubemin-cli/pkg/apiserver/domain/model"
 	"kubemin-cli/pkg/apiserver/domain/repository"
+	"kubemin-cli/pkg/apiserver/event/workflow/job"
 	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
 	apis "kubemin-cli/pkg/apiserver/interfaces/api/dto/v1"
 	"kubemin-cli/pkg/apiserver/utils"
 	"kubemin-cli/pkg/apiserver/utils/bcode"
 	"kubemin-cli/pkg/apiserver/utils/cache"
 	wf "kubemin-cli/pkg/apiserver/workflow"
+	"kubemin-cli/pkg/apiserver/workflow/progress"
 	"kubemin-cli/pkg/apiserver/workflow/signal"
 )
 
@@ -27,21 +31,40 @@ type WorkflowService interface {
 	ListApplicationWorkflow(ctx context.Context, app *model.Applications) error
 	CreateWorkflowTask(ctx context.Context, workflow apis.CreateWorkflowRequest) (*apis.CreateWorkflowResponse, error)
 	ExecWorkflowTask(ctx context.Context, workflowID string) (*apis.ExecWorkflowResponse, error)
-	ExecWorkflowTaskForApp(ctx context.Context, appID, workflowID string) (*apis.ExecWorkflowResponse, error)
+	ExecWorkflowTaskForApp(ctx context.Context, appID, workflowID string, inputs map[string]interface{}, priority int) (*apis.ExecWorkflowResponse, error)
 	WaitingTasks(ctx context.Context) ([]*model.WorkflowQueue, error)
 	UpdateTask(ctx context.Context, queue *model.WorkflowQueue) bool
 	TaskRunning(ctx context.Context) ([]*model.WorkflowQueue, error)
 	CancelWorkflowTask(ctx context.Context, userName, taskID, reason string) error
 	CancelWorkflowTaskForApp(ctx context.Context, appID, userName, taskID, reason string) error
+	RollbackWorkflowTaskForApp(ctx context.Context, appID, userName, taskID string) (*apis.RollbackWorkflowResponse, error)
+	RetryWorkflowTaskForApp(ctx context.Context, appID, userName, taskID string) (*apis.RetryWorkflowResponse, error)
+	PauseWorkflowTaskForApp(ctx context.Context, appID, userName, taskID, reason string) (*apis.PauseWorkflowResponse, error)
+	ResumeWorkflowTaskForApp(ctx context.Context, appID, userName, taskID string) (*apis.PauseWorkflowResponse, error)
+	DecideWorkflowApproval(ctx context.Context, appID, taskID string, approved bool, req apis.WorkflowApprovalRequest) (*apis.WorkflowApprovalResponse, error)
 	MarkTaskStatus(ctx context.Context, taskID string, from, to config.Status) (bool, error)
 	GetTaskStatus(ctx context.Context, taskID string) (*apis.TaskStatusResponse, error)
+	CreateWorkflowSchedule(ctx context.Context, appID string, req apis.CreateWorkflowScheduleRequest) (*model.WorkflowSchedule, error)
+	ListWorkflowSchedules(ctx context.Context, appID string) ([]*model.WorkflowSchedule, error)
+	GetWorkflowSchedule(ctx context.Context, appID, scheduleID string) (*model.WorkflowSchedule, error)
+	UpdateWorkflowSchedule(ctx context.Context, appID, scheduleID string, req apis.UpdateWorkflowScheduleRequest) (*model.WorkflowSchedule, error)
+	DeleteWorkflowSchedule(ctx context.Context, appID, scheduleID string) error
+	DryRunWorkflowForApp(ctx context.Context, appID string, req apis.DryRunWorkflowRequest) (*apis.DryRunWorkflowResponse, error)
+	PlanWorkflowForApp(ctx context.Context, appID, workflowID string) (*apis.WorkflowPlanResponse, error)
+	WatchTask(ctx context.Context, taskID string) (<-chan apis.TaskProgressEvent, error)
+	ListWorkflowTasks(ctx context.Context, appID string, query apis.ListWorkflowTasksQuery) (*apis.ListWorkflowTasksResponse, error)
+	ListTaskJobs(ctx context.Context, taskID string, statuses []string) (*apis.ListTaskJobsResponse, error)
+	FireDueSchedules(ctx context.Context, now time.Time) (int, error)
 }
 
 type workflowServiceImpl struct {
 	Store      datastore.DataStore  `inject:"datastore"`
 	KubeClient kubernetes.Interface `inject:"kubeClient"`
 	KubeConfig *rest.Config         `inject:"kubeConfig"`
-	Cache      cache.Cache         `inject:"cache"`
+	Cache      cache.Cache          `inject:"cache"`
+	DryRunner  WorkflowDryRunner    `inject:""`
+	Planner    WorkflowPlanner      `inject:""`
+	Progress   *progress.Broker     `inject:""`
 }
 
 // NewWorkflowService new workflow service
@@ -129,7 +152,11 @@ func (w *workflowServiceImpl) ExecWorkflowTask(ctx context.Context, workflowID s
 	if err != nil {
 		return nil, err
 	}
-	return w.enqueueWorkflowTask(ctx, workflow)
+	task, err := w.newWorkflowTask(ctx, workflow, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	return w.enqueueWorkflowTask(ctx, task)
 }
 
 func (w *workflowServiceImpl) GetTaskStatus(ctx context.Context, taskID string) (*apis.TaskStatusResponse, error) {
@@ -155,11 +182,8 @@ func (w *workflowServiceImpl) GetTaskStatus(ctx context.Context, taskID string)
 	if err != nil && !errors.Is(err, datastore.ErrRecordNotExist) {
 		klog.Errorf("list job info for task %s failed: %v", taskID, err)
 	} else {
-		for _, entity := range jobEntities {
-			j, ok := entity.(*model.JobInfo)
-			if !ok {
-				continue
-			}
+		diagnostics := w.taskDiagnostics(ctx, taskID)
+		for _, j := range latestJobAttempts(jobEntities) {
 			key := strings.ToLower(j.ServiceName)
 			agg, exists := componentAggregates[key]
 			if !exists {
@@ -170,26 +194,36 @@ func (w *workflowServiceImpl) GetTaskStatus(ctx context.Context, taskID string)
 					Error:     j.Error,
 					StartTime: j.StartTime,
 					EndTime:   j.EndTime,
+					Attempts:  j.Attempt,
 				}
 				componentAggregates[key] = agg
-				continue
-			}
-			// Aggregate: prefer the most severe status; capture first error message.
-			agg.Status = chooseAggStatus(agg.Status, j.Status)
-			if agg.Error == "" && j.Error != "" {
-				agg.Error = j.Error
-			}
-			if agg.StartTime == 0 || (j.StartTime != 0 && j.StartTime < agg.StartTime) {
-				agg.StartTime = j.StartTime
+			} else {
+				if j.Attempt > agg.Attempts {
+					agg.Attempts = j.Attempt
+				}
+				// Aggregate: prefer the most severe status; capture first error message.
+				agg.Status = chooseAggStatus(agg.Status, j.Status)
+				if agg.Error == "" && j.Error != "" {
+					agg.Error = j.Error
+				}
+				if agg.StartTime == 0 || (j.StartTime != 0 && j.StartTime < agg.StartTime) {
+					agg.StartTime = j.StartTime
+				}
+				if j.EndTime > agg.EndTime {
+					agg.EndTime = j.EndTime
+				}
 			}
-			if j.EndTime > agg.EndTime {
-				agg.EndTime = j.EndTime
+			if diag, ok := diagnostics[j.ID]; ok {
+				agg.Diagnostics = append(agg.Diagnostics, jobDiagnosticsToDTO(diag))
 			}
 		}
 	}
 
+	approvals, approvalStatuses := w.taskApprovals(ctx, taskID)
+
 	// Fill in missing components from workflow definition so the caller can see
 	// waiting/queued/cancelled components even before job records exist.
+	var stepStatuses []apis.StepTaskStatus
 	if workflow, wfErr := repository.WorkflowByID(ctx, w.Store, task.WorkflowID); wfErr == nil {
 		names := collectWorkflowComponentNames(workflow)
 		defaultStatus := defaultComponentStatus(task.Status)
@@ -203,6 +237,9 @@ func (w *workflowServiceImpl) GetTaskStatus(ctx context.Context, taskID string)
 				Status: defaultStatus,
 			}
 		}
+		if steps := parseWorkflowSteps(workflow); steps != nil {
+			stepStatuses = buildStepStatuses(steps.Steps, componentAggregates, approvals, task.StepResults)
+		}
 	} else if !errors.Is(wfErr, datastore.ErrRecordNotExist) {
 		klog.V(4).Infof("load workflow %s for task %s failed: %v", task.WorkflowID, taskID, wfErr)
 	}
@@ -212,7 +249,7 @@ func (w *workflowServiceImpl) GetTaskStatus(ctx context.Context, taskID string)
 		componentStatuses = append(componentStatuses, *cs)
 	}
 
-	return &apis.TaskStatusResponse{
+	resp := &apis.TaskStatusResponse{
 		TaskID:       task.TaskID,
 		Status:       string(task.Status),
 		WorkflowID:   task.WorkflowID,
@@ -220,7 +257,210 @@ func (w *workflowServiceImpl) GetTaskStatus(ctx context.Context, taskID string)
 		AppID:        task.AppID,
 		Type:         task.Type,
 		Components:   componentStatuses,
-	}, nil
+		Steps:        stepStatuses,
+		Approvals:    approvalStatuses,
+		TimeoutStep:  task.TimeoutStep,
+		Priority:     task.Priority,
+	}
+	if task.Deadline != nil {
+		resp.Deadline = task.Deadline.Unix()
+	}
+	if task.Status == config.StatusWaiting {
+		resp.QueuePosition = w.taskQueuePosition(ctx, task.TaskID)
+	}
+	return resp, nil
+}
+
+// taskQueuePosition reports where a waiting task stands in the dispatcher's priority and
+// fair-share order; 0 means the position is unknown.
+func (w *workflowServiceImpl) taskQueuePosition(ctx context.Context, taskID string) int {
+	waiting, err := repository.WaitingTasks(ctx, w.Store)
+	if err != nil {
+		klog.V(4).Infof("list waiting tasks for queue position of %s failed: %v", taskID, err)
+		return 0
+	}
+	active, err := repository.TaskRunning(ctx, w.Store)
+	if err != nil {
+		klog.V(4).Infof("list active tasks for queue position of %s failed: %v", taskID, err)
+		return 0
+	}
+	return wf.QueuePosition(waiting, wf.ActiveTasksByProject(active), taskID)
+}
+
+// taskApprovals loads the approvals of a task, returning the latest approval per step
+// (keyed by lower-cased step name) together with their API representation.
+func (w *workflowServiceImpl) taskApprovals(ctx context.Context, taskID string) (map[string]*model.WorkflowApproval, []apis.ApprovalTaskStatus) {
+	list, err := repository.ListWorkflowApprovals(ctx, w.Store, taskID, "")
+	if err != nil {
+		if !errors.Is(err, datastore.ErrRecordNotExist) {
+			klog.Errorf("list approvals for task %s failed: %v", taskID, err)
+		}
+		return nil, nil
+	}
+	latest := make(map[string]*model.WorkflowApproval, len(list))
+	statuses := make([]apis.ApprovalTaskStatus, 0, len(list))
+	for _, approval := range list {
+		latest[strings.ToLower(approval.StepName)] = approval
+		status := apis.ApprovalTaskStatus{
+			Step:       approval.StepName,
+			Status:     string(approval.Status),
+			Approver:   approval.Approver,
+			Comment:    approval.Comment,
+			DecideTime: approval.DecideTime,
+		}
+		if !approval.CreateTime.IsZero() {
+			status.CreateTime = approval.CreateTime.Unix()
+		}
+		statuses = append(statuses, status)
+	}
+	return latest, statuses
+}
+
+// taskDiagnostics 返回任务下采集的诊断信息，按 JobInfo ID 索引
+func (w *workflowServiceImpl) taskDiagnostics(ctx context.Context, taskID string) map[int]*model.JobDiagnostics {
+	list, err := repository.ListJobDiagnostics(ctx, w.Store, taskID)
+	if err != nil {
+		klog.Errorf("list job diagnostics for task %s failed: %v", taskID, err)
+		return nil
+	}
+	byJob := make(map[int]*model.JobDiagnostics, len(list))
+	for _, diag := range list {
+		byJob[diag.JobInfoID] = diag
+	}
+	return byJob
+}
+
+func jobDiagnosticsToDTO(diag *model.JobDiagnostics) apis.JobDiagnostics {
+	dto := apis.JobDiagnostics{
+		JobName:   diag.JobName,
+		Kind:      string(diag.Kind),
+		Namespace: diag.Namespace,
+		Name:      diag.Name,
+		Error:     diag.Error,
+	}
+	for _, pod := range diag.Pods {
+		item := apis.PodDiagnostics{
+			Name:       pod.Name,
+			Phase:      pod.Phase,
+			Reason:     pod.Reason,
+			Message:    pod.Message,
+			Conditions: pod.Conditions,
+		}
+		for _, c := range pod.Containers {
+			item.Containers = append(item.Containers, apis.ContainerDiagnostics(c))
+		}
+		dto.Pods = append(dto.Pods, item)
+	}
+	for _, ev := range diag.Events {
+		dto.Events = append(dto.Events, apis.EventDiagnostics(ev))
+	}
+	return dto
+}
+
+// latestJobAttempts keeps only the most recent attempt of every job so that a failed
+// attempt followed by a successful retry does not mark the component as failed.
+func latestJobAttempts(entities []datastore.Entity) []*model.JobInfo {
+	type jobKey struct{ name, jobType string }
+	index := make(map[jobKey]int)
+	var latest []*model.JobInfo
+	for _, entity := range entities {
+		j, ok := entity.(*model.JobInfo)
+		if !ok {
+			continue
+		}
+		key := jobKey{name: strings.ToLower(j.ServiceName), jobType: j.Type}
+		if i, exists := index[key]; exists {
+			if j.Attempt >= latest[i].Attempt {
+				latest[i] = j
+			}
+			continue
+		}
+		index[key] = len(latest)
+		latest = append(latest, j)
+	}
+	return latest
+}
+
+// buildStepStatuses renders the workflow step graph with the effective dependencies
+// of each step and a status aggregated from the components it deploys. Steps whose
+// condition was false are reported as skipped.
+func buildStepStatuses(steps []*model.WorkflowStep, components map[string]*apis.ComponentTaskStatus, approvals map[string]*model.WorkflowApproval, results map[string]config.Status) []apis.StepTaskStatus {
+	deps := wf.StepDependencies(steps)
+	result := make([]apis.StepTaskStatus, 0, len(steps))
+	for i, step := range steps {
+		if step == nil {
+			continue
+		}
+		name := step.Name
+		if name == "" {
+			name = fmt.Sprintf("step-%d", i+1)
+		}
+		key := strings.ToLower(step.Name)
+		stepStatus := apis.StepTaskStatus{
+			Name:      name,
+			DependsOn: deps[key],
+			Condition: step.Condition,
+		}
+		if wf.IsApprovalStep(step) {
+			stepStatus.Status = string(config.StatusWaiting)
+			if approval, ok := approvals[key]; ok {
+				stepStatus.Status = string(approval.Status)
+			}
+		} else if wf.IsBuiltinStep(step) {
+			// 内置步骤的任务记录以步骤名作为 service_name
+			stepStatus.Status = string(config.StatusWaiting)
+			if cs, ok := components[key]; ok {
+				stepStatus.Status = cs.Status
+			}
+		} else {
+			names := step.ComponentNames()
+			for _, sub := range step.SubSteps {
+				names = append(names, sub.ComponentNames()...)
+			}
+			statuses := make([]string, 0, len(names))
+			for _, n := range names {
+				if cs, ok := components[strings.ToLower(n)]; ok {
+					statuses = append(statuses, cs.Status)
+				}
+			}
+			stepStatus.Status = aggregateStepStatus(statuses)
+			stepStatus.Components = names
+		}
+		if result := results[key]; result == config.StatusSkipped || result == config.StatusTimeout {
+			stepStatus.Status = string(result)
+		}
+		result = append(result, stepStatus)
+	}
+	return result
+}
+
+// aggregateStepStatus derives a step status from its component statuses: any failure wins,
+// a partially finished step is running, and a step is completed only when every component is.
+func aggregateStepStatus(statuses []string) string {
+	if len(statuses) == 0 {
+		return string(config.StatusWaiting)
+	}
+	agg := statuses[0]
+	finished := 0
+	for _, status := range statuses {
+		agg = chooseAggStatus(agg, status)
+		switch config.Status(status) {
+		case config.StatusCompleted, config.StatusPassed, config.StatusSkipped:
+			finished++
+		}
+	}
+	if finished == len(statuses) {
+		return string(config.StatusCompleted)
+	}
+	if finished > 0 {
+		switch config.Status(agg) {
+		case config.StatusFailed, config.StatusTimeout, config.StatusReject, config.StatusCancelled:
+			return agg
+		default:
+			return string(config.StatusRunning)
+		}
+	}
+	return agg
 }
 
 // chooseAggStatus merges two statuses, preferring failure/timeouts over running, over waiting.
@@ -245,8 +485,8 @@ func chooseAggStatus(current, incoming string) string {
 	return current
 }
 
-// collectWorkflowComponentNames extracts all unique component names declared in a workflow.
-func collectWorkflowComponentNames(workflow *model.Workflow) []string {
+// parseWorkflowSteps decodes the persisted step definition of a workflow.
+func parseWorkflowSteps(workflow *model.Workflow) *model.WorkflowSteps {
 	if workflow == nil || workflow.Steps == nil {
 		return nil
 	}
@@ -260,6 +500,15 @@ func collectWorkflowComponentNames(workflow *model.Workflow) []string {
 		klog.Errorf("unmarshal workflow steps for %s failed: %v", workflow.ID, err)
 		return nil
 	}
+	return &steps
+}
+
+// collectWorkflowComponentNames extracts all unique component names declared in a workflow.
+func collectWorkflowComponentNames(workflow *model.Workflow) []string {
+	steps := parseWorkflowSteps(workflow)
+	if steps == nil {
+		return nil
+	}
 	seen := make(map[string]struct{})
 	var names []string
 	add := func(name string) {
@@ -274,6 +523,9 @@ func collectWorkflowComponentNames(workflow *model.Workflow) []string {
 		names = append(names, name)
 	}
 	for _, step := range steps.Steps {
+		if wf.IsApprovalStep(step) || wf.IsBuiltinStep(step) {
+			continue
+		}
 		for _, n := range step.ComponentNames() {
 			add(n)
 		}
@@ -299,7 +551,8 @@ func defaultComponentStatus(taskStatus config.Status) string {
 	}
 }
 
-func (w *workflowServiceImpl) ExecWorkflowTaskForApp(ctx context.Context, appID, workflowID string) (*apis.ExecWorkflowResponse, error) {
+// ExecWorkflowTaskForApp 执行应用的工作流，inputs 随任务保存，供步骤条件表达式引用；priority 决定派发顺序
+func (w *workflowServiceImpl) ExecWorkflowTaskForApp(ctx context.Context, appID, workflowID string, inputs map[string]interface{}, priority int) (*apis.ExecWorkflowResponse, error) {
 	workflow, err := repository.WorkflowByID(ctx, w.Store, workflowID)
 	if err != nil {
 		return nil, err
@@ -307,7 +560,35 @@ func (w *workflowServiceImpl) ExecWorkflowTaskForApp(ctx context.Context, appID,
 	if workflow.AppID == "" || workflow.AppID != appID {
 		return nil, bcode.ErrWorkflowNotExist
 	}
-	return w.enqueueWorkflowTask(ctx, workflow)
+	task, err := w.newWorkflowTask(ctx, workflow, inputs, priority)
+	if err != nil {
+		return nil, err
+	}
+	return w.enqueueWorkflowTask(ctx, task)
+}
+
+// checkAppConcurrency 应用的串行策略为 reject 时，存在未结束的任务则拒绝执行，并在错误中返回阻塞的任务ID。
+// 两个请求同时通过检查时，由派发时的串行控制兜底，后一个任务排队等待
+func (w *workflowServiceImpl) checkAppConcurrency(ctx context.Context, appID string) error {
+	app, err := repository.ApplicationByID(ctx, w.Store, appID)
+	if err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil
+		}
+		return err
+	}
+	if config.ParseAppConcurrencyPolicy(string(app.ConcurrencyPolicy)) != config.AppConcurrencyReject {
+		return nil
+	}
+	active, err := repository.ActiveAppTasks(ctx, w.Store, appID)
+	if err != nil {
+		return err
+	}
+	if len(active) > 0 {
+		klog.Infof("reject workflow execution for app %s: task %s is still %s", appID, active[0].TaskID, active[0].Status)
+		return bcode.ErrWorkflowTaskConflict.WithDetail("blocking_task_id", active[0].TaskID)
+	}
+	return nil
 }
 
 func (w *workflowServiceImpl) ListApplicationWorkflow(ctx context.Context, app *model.Applications) error {
@@ -369,30 +650,284 @@ func (w *workflowServiceImpl) cancelWorkflowTask(ctx context.Context, task *mode
 	klog.Infof("AUDIT: cancel workflow task taskID=%s workflowID=%s workflowName=%s user=%s reason=%s prevStatus=%s",
 		task.TaskID, task.WorkflowID, task.WorkflowName, userName, reason, task.Status)
 
+	if reason == "" {
+		reason = fmt.Sprintf("cancelled by %s", userName)
+	}
+	prevStatus := task.Status
 	task.TaskRevoker = userName
 	task.Status = config.StatusCancelled
+	task.CancelReason = reason
 
 	if err := repository.UpdateTask(ctx, w.Store, task); err != nil {
 		klog.Errorf("AUDIT: cancel workflow task failed taskID=%s user=%s error=%v", task.TaskID, userName, err)
 		return err
 	}
-	if reason == "" {
-		reason = fmt.Sprintf("cancelled by %s", userName)
-	}
 	if err := signal.Cancel(ctx, task.TaskID, reason); err != nil {
 		klog.Errorf("AUDIT: signal cancel failed taskID=%s user=%s error=%v", task.TaskID, userName, err)
 		return err
 	}
+	// A running task removes its own plan when the controller stops; a suspended or waiting
+	// task has no controller, so its plan is removed here.
+	if prevStatus != config.StatusRunning {
+		if err := repository.DeleteExecutionPlan(ctx, w.Store, task.TaskID); err != nil {
+			klog.Errorf("delete execution plan of cancelled task %s failed: %v", task.TaskID, err)
+		}
+	}
 
 	klog.Infof("AUDIT: cancel workflow task completed taskID=%s user=%s", task.TaskID, userName)
 	return nil
 }
 
-func (w *workflowServiceImpl) enqueueWorkflowTask(ctx context.Context, workflow *model.Workflow) (*apis.ExecWorkflowResponse, error) {
+// PauseWorkflowTaskForApp 暂停工作流任务。尚未派发的任务直接置为 pause；运行中的任务收到暂停信号后，
+// 在当前优先级分组执行完时记录位置并交还队列
+func (w *workflowServiceImpl) PauseWorkflowTaskForApp(ctx context.Context, appID, userName, taskID, reason string) (*apis.PauseWorkflowResponse, error) {
+	task, err := repository.TaskByID(ctx, w.Store, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.AppID == "" || task.AppID != appID {
+		return nil, bcode.ErrWorkflowNotExist
+	}
+	if reason == "" {
+		reason = fmt.Sprintf("paused by %s", userName)
+	}
+	klog.Infof("AUDIT: pause workflow task taskID=%s workflowID=%s workflowName=%s user=%s reason=%s prevStatus=%s",
+		task.TaskID, task.WorkflowID, task.WorkflowName, userName, reason, task.Status)
+
+	status := task.Status
+	switch task.Status {
+	case config.StatusPause:
+		return &apis.PauseWorkflowResponse{TaskID: task.TaskID, Status: string(config.StatusPause)}, nil
+	case config.StatusWaiting:
+		// Not dispatched yet: park it directly so the dispatcher never claims it.
+		paused, err := repository.SuspendTask(ctx, w.Store, task.TaskID, config.StatusWaiting, config.StatusPause)
+		if err != nil {
+			return nil, err
+		}
+		if paused {
+			klog.Infof("AUDIT: pause workflow task completed taskID=%s user=%s status=%s", task.TaskID, userName, config.StatusPause)
+			return &apis.PauseWorkflowResponse{TaskID: task.TaskID, Status: string(config.StatusPause)}, nil
+		}
+		// Claimed by a worker in the meantime; the signal below stops it.
+		status = config.StatusQueued
+	case config.StatusQueued, config.StatusRunning:
+	default:
+		return nil, bcode.ErrWorkflowPauseNotAllowed
+	}
+
+	if err := signal.Pause(ctx, task.TaskID, reason); err != nil {
+		klog.Errorf("AUDIT: signal pause failed taskID=%s user=%s error=%v", task.TaskID, userName, err)
+		return nil, err
+	}
+	klog.Infof("AUDIT: pause workflow task requested taskID=%s user=%s status=%s", task.TaskID, userName, status)
+	return &apis.PauseWorkflowResponse{TaskID: task.TaskID, Status: string(status)}, nil
+}
+
+// ResumeWorkflowTaskForApp 恢复已暂停的任务：清除暂停信号并将任务放回等待队列，
+// 再次派发时跳过已完成的优先级分组。对尚未生效的暂停请求，则只撤销该请求
+func (w *workflowServiceImpl) ResumeWorkflowTaskForApp(ctx context.Context, appID, userName, taskID string) (*apis.PauseWorkflowResponse, error) {
+	task, err := repository.TaskByID(ctx, w.Store, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.AppID == "" || task.AppID != appID {
+		return nil, bcode.ErrWorkflowNotExist
+	}
+	klog.Infof("AUDIT: resume workflow task taskID=%s workflowID=%s workflowName=%s user=%s prevStatus=%s",
+		task.TaskID, task.WorkflowID, task.WorkflowName, userName, task.Status)
+
+	switch task.Status {
+	case config.StatusPause:
+	case config.StatusQueued, config.StatusRunning:
+		requested, _, err := signal.PauseRequested(ctx, task.TaskID)
+		if err != nil {
+			return nil, err
+		}
+		if !requested {
+			return nil, bcode.ErrWorkflowNotPaused
+		}
+	default:
+		return nil, bcode.ErrWorkflowNotPaused
+	}
+
+	// Clear the request first so the re-dispatched task does not pause again immediately.
+	if err := signal.ClearPause(ctx, task.TaskID); err != nil {
+		klog.Errorf("AUDIT: clear pause signal failed taskID=%s user=%s error=%v", task.TaskID, userName, err)
+		return nil, err
+	}
+	status := task.Status
+	if task.Status == config.StatusPause {
+		resumed, err := repository.ResumeTask(ctx, w.Store, task)
+		if err != nil {
+			klog.Errorf("AUDIT: re-queue paused task failed taskID=%s user=%s error=%v", task.TaskID, userName, err)
+			return nil, err
+		}
+		if !resumed {
+			return nil, bcode.ErrWorkflowNotPaused
+		}
+		status = config.StatusWaiting
+	}
+	klog.Infof("AUDIT: resume workflow task completed taskID=%s user=%s status=%s", task.TaskID, userName, status)
+	return &apis.PauseWorkflowResponse{TaskID: task.TaskID, Status: string(status)}, nil
+}
+
+// RollbackWorkflowTaskForApp 手动回滚失败的任务：按倒序恢复任务执行前记录的资源快照
+func (w *workflowServiceImpl) RollbackWorkflowTaskForApp(ctx context.Context, appID, userName, taskID string) (*apis.RollbackWorkflowResponse, error) {
+	task, err := repository.TaskByID(ctx, w.Store, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.AppID == "" || task.AppID != appID {
+		return nil, bcode.ErrWorkflowNotExist
+	}
+	if task.Status != config.StatusFailed && task.Status != config.StatusRollbackFailed {
+		return nil, bcode.ErrWorkflowRollbackNotAllowed
+	}
+	snapshots, err := job.ListTaskSnapshots(ctx, w.Store, task.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) == 0 {
+		return nil, bcode.ErrWorkflowRollbackNotAllowed
+	}
+
+	klog.Infof("AUDIT: rollback workflow task taskID=%s workflowID=%s workflowName=%s user=%s prevStatus=%s resources=%d",
+		task.TaskID, task.WorkflowID, task.WorkflowName, userName, task.Status, len(snapshots))
+
+	from := task.Status
+	to := config.StatusRolledBack
+	if err := job.RestoreSnapshots(ctx, w.KubeClient, w.Store, snapshots); err != nil {
+		klog.Errorf("AUDIT: rollback workflow task failed taskID=%s user=%s error=%v", task.TaskID, userName, err)
+		to = config.StatusRollbackFailed
+	}
+	if _, err := repository.UpdateTaskStatus(ctx, w.Store, task.TaskID, from, to); err != nil {
+		return nil, err
+	}
+
+	klog.Infof("AUDIT: rollback workflow task completed taskID=%s user=%s status=%s", task.TaskID, userName, to)
+	return &apis.RollbackWorkflowResponse{TaskID: task.TaskID, Status: string(to), Resources: len(snapshots)}, nil
+}
+
+// DecideWorkflowApproval 批准或拒绝任务的待审批步骤。所有待审批步骤处理完（或任一步骤被拒绝）后，
+// 任务重新回到等待队列，由调度器再次派发并从审批步骤的下一步继续执行
+func (w *workflowServiceImpl) DecideWorkflowApproval(ctx context.Context, appID, taskID string, approved bool, req apis.WorkflowApprovalRequest) (*apis.WorkflowApprovalResponse, error) {
+	task, err := repository.TaskByID(ctx, w.Store, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.AppID == "" || task.AppID != appID {
+		return nil, bcode.ErrWorkflowNotExist
+	}
+	if task.Status != config.StatusWaitingApprove {
+		return nil, bcode.ErrWorkflowApprovalNotPending
+	}
+	pending, err := repository.ListWorkflowApprovals(ctx, w.Store, task.TaskID, config.StatusWaitingApprove)
+	if err != nil && !errors.Is(err, datastore.ErrRecordNotExist) {
+		return nil, err
+	}
+	target, err := selectPendingApproval(pending, req.Step)
+	if err != nil {
+		return nil, err
+	}
+
+	decision := config.StatusPassed
+	if !approved {
+		decision = config.StatusReject
+	}
+	klog.Infof("AUDIT: decide workflow approval taskID=%s workflowID=%s step=%s approver=%s decision=%s comment=%s",
+		task.TaskID, task.WorkflowID, target.StepName, req.Approver, decision, req.Comment)
+
+	// Only the first decision on a pending approval wins.
+	decided, err := w.Store.CompareAndSwap(ctx, target, "status", config.StatusWaitingApprove, map[string]interface{}{
+		"status":      decision,
+		"approver":    req.Approver,
+		"comment":     req.Comment,
+		"decide_time": time.Now().Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !decided {
+		return nil, bcode.ErrWorkflowApprovalNotPending
+	}
+
+	// Parallel gates may be decided concurrently, so whether any is left is checked after the
+	// decision; resuming is a CAS on the task status and only happens once.
+	remaining, err := repository.ListWorkflowApprovals(ctx, w.Store, task.TaskID, config.StatusWaitingApprove)
+	if err != nil && !errors.Is(err, datastore.ErrRecordNotExist) {
+		return nil, err
+	}
+	taskStatus := config.StatusWaitingApprove
+	if !approved || len(remaining) == 0 {
+		resumed, err := repository.ResumeTask(ctx, w.Store, task)
+		if err != nil {
+			klog.Errorf("AUDIT: re-queue approved task failed taskID=%s error=%v", task.TaskID, err)
+			return nil, err
+		}
+		if resumed {
+			taskStatus = config.StatusWaiting
+		}
+	}
+
+	klog.Infof("AUDIT: decide workflow approval completed taskID=%s step=%s decision=%s taskStatus=%s", task.TaskID, target.StepName, decision, taskStatus)
+	return &apis.WorkflowApprovalResponse{
+		TaskID:     task.TaskID,
+		Step:       target.StepName,
+		Status:     string(decision),
+		TaskStatus: string(taskStatus),
+	}, nil
+}
+
+// selectPendingApproval picks the approval addressed by step, or the only pending approval when step is empty.
+func selectPendingApproval(pending []*model.WorkflowApproval, step string) (*model.WorkflowApproval, error) {
+	if len(pending) == 0 {
+		return nil, bcode.ErrWorkflowApprovalNotPending
+	}
+	step = strings.TrimSpace(step)
+	if step == "" {
+		if len(pending) > 1 {
+			return nil, bcode.ErrWorkflowApprovalStepRequired
+		}
+		return pending[0], nil
+	}
+	for _, approval := range pending {
+		if strings.EqualFold(approval.StepName, step) {
+			return approval, nil
+		}
+	}
+	return nil, bcode.ErrWorkflowApprovalNotPending
+}
+
+// newWorkflowTask 构造执行工作流的新任务
+func (w *workflowServiceImpl) newWorkflowTask(ctx context.Context, workflow *model.Workflow, inputs map[string]interface{}, priority int) (*model.WorkflowQueue, error) {
 	if workflow == nil || workflow.Steps == nil {
 		return nil, bcode.ErrExecWorkflow
 	}
-	workflowTask := &model.WorkflowQueue{
+	if priority < 0 || priority > config.MaxWorkflowTaskPriority {
+		return nil, bcode.ErrWorkflowConfig
+	}
+	workflowTask := newWorkflowQueueTask(workflow)
+	workflowTask.Revision = w.workflowRevision(ctx, workflow)
+	workflowTask.Inputs = inputs
+	workflowTask.Priority = priority
+	return workflowTask, nil
+}
+
+// enqueueWorkflowTask 将新任务写入队列；所有新建任务的入口都经过这里，以遵守应用的串行策略
+func (w *workflowServiceImpl) enqueueWorkflowTask(ctx context.Context, workflowTask *model.WorkflowQueue) (*apis.ExecWorkflowResponse, error) {
+	if workflowTask.AppID != "" {
+		if err := w.checkAppConcurrency(ctx, workflowTask.AppID); err != nil {
+			return nil, err
+		}
+	}
+	if err := repository.CreateWorkflowQueue(ctx, w.Store, workflowTask); err != nil {
+		return nil, err
+	}
+	return &apis.ExecWorkflowResponse{TaskID: workflowTask.TaskID}, nil
+}
+
+func newWorkflowQueueTask(workflow *model.Workflow) *model.WorkflowQueue {
+	return &model.WorkflowQueue{
 		TaskID:              utils.RandStringByNumLowercase(24),
 		AppID:               workflow.AppID,
 		WorkflowID:          workflow.ID,
@@ -401,12 +936,96 @@ func (w *workflowServiceImpl) enqueueWorkflowTask(ctx context.Context, workflow
 		WorkflowDisplayName: workflow.Alias,
 		Type:                workflow.WorkflowType,
 		Status:              config.StatusWaiting,
+		SubmitTime:          time.Now().UnixNano(),
 	}
+}
 
-	if err := repository.CreateWorkflowQueue(ctx, w.Store, workflowTask); err != nil {
+// workflowRevision fingerprints the current workflow definition. An empty revision only
+// disables retry-from-failure for the task, so errors are logged instead of failing the run.
+func (w *workflowServiceImpl) workflowRevision(ctx context.Context, workflow *model.Workflow) string {
+	components, err := repository.FindComponentsByAppID(ctx, w.Store, workflow.AppID)
+	if err != nil && !errors.Is(err, datastore.ErrRecordNotExist) {
+		klog.Errorf("list components of workflow %s for revision failed: %v", workflow.ID, err)
+		return ""
+	}
+	revision, err := wf.Revision(workflow, components)
+	if err != nil {
+		klog.Errorf("compute revision of workflow %s failed: %v", workflow.ID, err)
+		return ""
+	}
+	return revision
+}
+
+// RetryWorkflowTaskForApp 从失败处重试：创建一个关联原任务的新任务；工作流修订版本未变化时，
+// 原任务中已成功的 Job 在新任务中直接标记为跳过，不会被重新部署
+func (w *workflowServiceImpl) RetryWorkflowTaskForApp(ctx context.Context, appID, userName, taskID string) (*apis.RetryWorkflowResponse, error) {
+	task, err := repository.TaskByID(ctx, w.Store, taskID)
+	if err != nil {
 		return nil, err
 	}
-	return &apis.ExecWorkflowResponse{TaskID: workflowTask.TaskID}, nil
+	if task.AppID == "" || task.AppID != appID {
+		return nil, bcode.ErrWorkflowNotExist
+	}
+	switch task.Status {
+	case config.StatusFailed, config.StatusTimeout:
+	default:
+		return nil, bcode.ErrWorkflowRetryNotAllowed
+	}
+	workflow, err := repository.WorkflowByID(ctx, w.Store, task.WorkflowID)
+	if err != nil {
+		return nil, err
+	}
+	if workflow.Steps == nil {
+		return nil, bcode.ErrExecWorkflow
+	}
+
+	retryTask := newWorkflowQueueTask(workflow)
+	retryTask.Revision = w.workflowRevision(ctx, workflow)
+	retryTask.RetryOf = task.TaskID
+	retryTask.Inputs = task.Inputs
+	retryTask.Priority = task.Priority
+	retryTask.TaskCreator = userName
+	if task.Revision != "" && task.Revision == retryTask.Revision {
+		skip, err := w.completedJobKeys(ctx, task.TaskID)
+		if err != nil {
+			return nil, err
+		}
+		retryTask.SkipJobs = skip
+	} else {
+		klog.Infof("workflow %s changed since task %s, retry re-runs every job", workflow.ID, task.TaskID)
+	}
+
+	klog.Infof("AUDIT: retry workflow task taskID=%s retryTaskID=%s workflowID=%s user=%s skippedJobs=%d",
+		task.TaskID, retryTask.TaskID, task.WorkflowID, userName, len(retryTask.SkipJobs))
+	if _, err := w.enqueueWorkflowTask(ctx, retryTask); err != nil {
+		klog.Errorf("AUDIT: retry workflow task failed taskID=%s user=%s error=%v", task.TaskID, userName, err)
+		return nil, err
+	}
+	return &apis.RetryWorkflowResponse{
+		TaskID:      retryTask.TaskID,
+		RetryOf:     task.TaskID,
+		SkippedJobs: len(retryTask.SkipJobs),
+	}, nil
+}
+
+// completedJobKeys returns the jobs whose latest attempt in the task succeeded or was skipped.
+func (w *workflowServiceImpl) completedJobKeys(ctx context.Context, taskID string) ([]string, error) {
+	entities, err := w.Store.List(ctx, &model.JobInfo{TaskID: taskID}, nil)
+	if err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var keys []string
+	for _, j := range latestJobAttempts(entities) {
+		switch config.Status(j.Status) {
+		case config.StatusCompleted, config.StatusSkipped:
+			keys = append(keys, wf.JobKey(j.Type, j.ServiceName))
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
 }
 
 func (w *workflowServiceImpl) rollbackWorkflowCreation(ctx context.Context, workflow *model.Workflow) {
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+	apisv1 "kubemin-cli/pkg/apiserver/interfaces/api/dto/v1"
+)
+
+// approvalDataStore serves pending approvals and records the updates applied to the task.
+type approvalDataStore struct {
+	statusDataStore
+	approvals   []*model.WorkflowApproval
+	taskUpdates []map[string]interface{}
+	// onDecide runs once an approval is decided, e.g. to decide another gate concurrently.
+	onDecide func()
+}
+
+func (s *approvalDataStore) List(ctx context.Context, query datastore.Entity, opts *datastore.ListOptions) ([]datastore.Entity, error) {
+	if q, ok := query.(*model.WorkflowApproval); ok {
+		var out []datastore.Entity
+		for _, approval := range s.approvals {
+			if approval.TaskID == q.TaskID && (q.Status == "" || approval.Status == q.Status) {
+				out = append(out, approval)
+			}
+		}
+		if len(out) == 0 {
+			return nil, datastore.ErrRecordNotExist
+		}
+		return out, nil
+	}
+	return s.statusDataStore.List(ctx, query, opts)
+}
+
+func (s *approvalDataStore) CompareAndSwap(_ context.Context, entity datastore.Entity, field string, value interface{}, updates map[string]interface{}) (bool, error) {
+	switch v := entity.(type) {
+	case *model.WorkflowApproval:
+		for _, approval := range s.approvals {
+			if approval.ID == v.ID && approval.Status == value {
+				approval.Status = updates["status"].(config.Status)
+				if s.onDecide != nil {
+					s.onDecide()
+				}
+				return true, nil
+			}
+		}
+		return false, nil
+	case *model.WorkflowQueue:
+		if v.TaskID != s.task.TaskID || field != "status" || s.task.Status != value {
+			return false, nil
+		}
+		s.taskUpdates = append(s.taskUpdates, updates)
+		s.task.Status = updates["status"].(config.Status)
+		return true, nil
+	}
+	return false, nil
+}
+
+func TestDecideWorkflowApprovalExtendsDeadlineBySuspendedTime(t *testing.T) {
+	// The task was suspended for approval an hour ago, with 10 minutes left on its deadline;
+	// the deadline has since passed on the wall clock.
+	suspendedAt := time.Now().Add(-time.Hour)
+	deadline := suspendedAt.Add(10 * time.Minute)
+	store := &approvalDataStore{
+		statusDataStore: statusDataStore{task: &model.WorkflowQueue{
+			TaskID:      "task-1",
+			AppID:       "app-1",
+			Status:      config.StatusWaitingApprove,
+			Deadline:    &deadline,
+			SuspendedAt: &suspendedAt,
+		}},
+		approvals: []*model.WorkflowApproval{{ID: 1, TaskID: "task-1", StepName: "gate", Status: config.StatusWaitingApprove}},
+	}
+	svc := &workflowServiceImpl{Store: store}
+
+	resp, err := svc.DecideWorkflowApproval(context.Background(), "app-1", "task-1", true, apisv1.WorkflowApprovalRequest{Approver: "alice"})
+	require.NoError(t, err)
+	require.Equal(t, string(config.StatusPassed), resp.Status)
+	require.Equal(t, string(config.StatusWaiting), resp.TaskStatus)
+
+	require.Len(t, store.taskUpdates, 1)
+	updates := store.taskUpdates[0]
+	require.Equal(t, config.StatusWaiting, updates["status"])
+	require.Contains(t, updates, "suspended_at")
+	require.Nil(t, updates["suspended_at"])
+	extended, ok := updates["deadline"].(time.Time)
+	require.True(t, ok, "deadline should be re-based on approval")
+	// The 10 minutes that were left when the task was suspended are still available.
+	require.WithinDuration(t, time.Now().Add(10*time.Minute), extended, 5*time.Second)
+}
+
+func TestDecideWorkflowApprovalWithoutDeadline(t *testing.T) {
+	suspendedAt := time.Now().Add(-time.Hour)
+	store := &approvalDataStore{
+		statusDataStore: statusDataStore{task: &model.WorkflowQueue{
+			TaskID:      "task-1",
+			AppID:       "app-1",
+			Status:      config.StatusWaitingApprove,
+			SuspendedAt: &suspendedAt,
+		}},
+		approvals: []*model.WorkflowApproval{{ID: 1, TaskID: "task-1", StepName: "gate", Status: config.StatusWaitingApprove}},
+	}
+	svc := &workflowServiceImpl{Store: store}
+
+	_, err := svc.DecideWorkflowApproval(context.Background(), "app-1", "task-1", true, apisv1.WorkflowApprovalRequest{Approver: "alice"})
+	require.NoError(t, err)
+	require.Len(t, store.taskUpdates, 1)
+	require.NotContains(t, store.taskUpdates[0], "deadline")
+}
+
+func TestDecideWorkflowApprovalResumesAfterConcurrentGates(t *testing.T) {
+	store := &approvalDataStore{
+		statusDataStore: statusDataStore{task: &model.WorkflowQueue{TaskID: "task-1", AppID: "app-1", Status: config.StatusWaitingApprove}},
+		approvals: []*model.WorkflowApproval{
+			{ID: 1, TaskID: "task-1", StepName: "gate-a", Status: config.StatusWaitingApprove},
+			{ID: 2, TaskID: "task-1", StepName: "gate-b", Status: config.StatusWaitingApprove},
+		},
+	}
+	svc := &workflowServiceImpl{Store: store}
+	// gate-b is approved by a concurrent request that also saw both gates pending.
+	store.onDecide = func() {
+		store.onDecide = nil
+		store.approvals[1].Status = config.StatusPassed
+	}
+
+	resp, err := svc.DecideWorkflowApproval(context.Background(), "app-1", "task-1", true, apisv1.WorkflowApprovalRequest{Step: "gate-a", Approver: "alice"})
+	require.NoError(t, err)
+	require.Equal(t, string(config.StatusWaiting), resp.TaskStatus)
+	require.Len(t, store.taskUpdates, 1)
+	require.Equal(t, config.StatusWaiting, store.task.Status)
+}
+
+func TestDecideWorkflowApprovalWaitsForOtherGates(t *testing.T) {
+	store := &approvalDataStore{
+		statusDataStore: statusDataStore{task: &model.WorkflowQueue{TaskID: "task-1", AppID: "app-1", Status: config.StatusWaitingApprove}},
+		approvals: []*model.WorkflowApproval{
+			{ID: 1, TaskID: "task-1", StepName: "gate-a", Status: config.StatusWaitingApprove},
+			{ID: 2, TaskID: "task-1", StepName: "gate-b", Status: config.StatusWaitingApprove},
+		},
+	}
+	svc := &workflowServiceImpl{Store: store}
+
+	resp, err := svc.DecideWorkflowApproval(context.Background(), "app-1", "task-1", true, apisv1.WorkflowApprovalRequest{Step: "gate-a", Approver: "alice"})
+	require.NoError(t, err)
+	require.Equal(t, string(config.StatusWaitingApprove), resp.TaskStatus)
+	require.Empty(t, store.taskUpdates)
+
+	resp, err = svc.DecideWorkflowApproval(context.Background(), "app-1", "task-1", true, apisv1.WorkflowApprovalRequest{Step: "gate-b", Approver: "bob"})
+	require.NoError(t, err)
+	require.Equal(t, string(config.StatusWaiting), resp.TaskStatus)
+	require.Len(t, store.taskUpdates, 1)
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/domain/repository"
+	apis "kubemin-cli/pkg/apiserver/interfaces/api/dto/v1"
+	"kubemin-cli/pkg/apiserver/utils/bcode"
+)
+
+// WorkflowDryRunner 按真实执行计划生成 Job，并以 DryRun=All 提交到 API Server
+type WorkflowDryRunner interface {
+	DryRunWorkflow(ctx context.Context, task *model.WorkflowQueue) ([]model.DryRunJobResult, error)
+}
+
+// DryRunWorkflowForApp 同步执行一次 dry-run，并记录为测试类型的任务。任务写入时已处于终态，
+// 不会被 worker 领取；每个 Job 的准入结果同时记录为 JobInfo
+func (w *workflowServiceImpl) DryRunWorkflowForApp(ctx context.Context, appID string, req apis.DryRunWorkflowRequest) (*apis.DryRunWorkflowResponse, error) {
+	workflow, err := w.appWorkflow(ctx, appID, req.WorkflowID)
+	if err != nil {
+		return nil, err
+	}
+	if w.DryRunner == nil {
+		return nil, bcode.ErrWorkflowDryRun
+	}
+
+	task := newWorkflowQueueTask(workflow)
+	task.Type = config.WorkflowTaskTypeTesting
+	task.Revision = w.workflowRevision(ctx, workflow)
+	task.Inputs = req.Inputs
+	task.CreateTime = time.Now()
+
+	start := time.Now().Unix()
+	results, err := w.DryRunner.DryRunWorkflow(ctx, task)
+	if err != nil {
+		klog.Errorf("dry-run workflow %s failed: %v", workflow.ID, err)
+		return nil, bcode.ErrWorkflowDryRun
+	}
+	end := time.Now().Unix()
+
+	resp := &apis.DryRunWorkflowResponse{
+		TaskID: task.TaskID,
+		Passed: true,
+		Jobs:   make([]apis.DryRunJobResult, 0, len(results)),
+	}
+	for _, result := range results {
+		if result.Status == config.StatusFailed {
+			resp.Passed = false
+		}
+		resp.Jobs = append(resp.Jobs, apis.DryRunJobResult{
+			Step:      result.Step,
+			Name:      result.Name,
+			Type:      result.JobType,
+			Kind:      string(result.Kind),
+			Namespace: result.Namespace,
+			Resource:  result.Resource,
+			Status:    string(result.Status),
+			Error:     result.Error,
+		})
+	}
+	task.Status = config.StatusCompleted
+	if !resp.Passed {
+		task.Status = config.StatusFailed
+	}
+	if err := repository.CreateWorkflowQueue(ctx, w.Store, task); err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		jobInfo := &model.JobInfo{
+			Type:        result.JobType,
+			WorkflowID:  task.WorkflowID,
+			ProductID:   task.ProjectID,
+			AppID:       task.AppID,
+			TaskID:      task.TaskID,
+			Status:      string(result.Status),
+			StartTime:   start,
+			EndTime:     end,
+			ServiceType: string(result.Kind),
+			ServiceName: result.Name,
+			Error:       result.Error,
+			Attempt:     1,
+		}
+		if err := w.Store.Add(ctx, jobInfo); err != nil {
+			klog.Errorf("record dry-run job %s of task %s failed: %v", result.Name, task.TaskID, err)
+		}
+	}
+	return resp, nil
+}
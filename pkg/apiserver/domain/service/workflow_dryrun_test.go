@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+	apis "kubemin-cli/pkg/apiserver/interfaces/api/dto/v1"
+	"kubemin-cli/pkg/apiserver/utils/bcode"
+)
+
+// dryRunDataStore serves a workflow and records the task and job infos written for it.
+type dryRunDataStore struct {
+	statusDataStore
+	tasks []*model.WorkflowQueue
+	jobs  []*model.JobInfo
+}
+
+func (s *dryRunDataStore) Add(_ context.Context, entity datastore.Entity) error {
+	switch v := entity.(type) {
+	case *model.WorkflowQueue:
+		s.tasks = append(s.tasks, v)
+	case *model.JobInfo:
+		s.jobs = append(s.jobs, v)
+	}
+	return nil
+}
+
+type stubDryRunner struct {
+	task    *model.WorkflowQueue
+	results []model.DryRunJobResult
+}
+
+func (r *stubDryRunner) DryRunWorkflow(_ context.Context, task *model.WorkflowQueue) ([]model.DryRunJobResult, error) {
+	r.task = task
+	return r.results, nil
+}
+
+func newDryRunFixture(t *testing.T) *dryRunDataStore {
+	t.Helper()
+	steps, err := model.NewJSONStructByStruct(&model.WorkflowSteps{
+		Steps: []*model.WorkflowStep{{Name: "config"}, {Name: "web"}},
+	})
+	require.NoError(t, err)
+	return &dryRunDataStore{statusDataStore: statusDataStore{
+		workflow: &model.Workflow{ID: "wf-1", AppID: "app-1", Name: "deploy", Steps: steps},
+	}}
+}
+
+func TestDryRunWorkflowRecordsTestingTask(t *testing.T) {
+	store := newDryRunFixture(t)
+	runner := &stubDryRunner{results: []model.DryRunJobResult{
+		{Step: "config", Name: "config", JobType: string(config.JobDeployConfigMap), Kind: config.ResourceConfigMap, Status: config.StatusCompleted},
+		{Step: "web", Name: "web", JobType: string(config.JobDeploy), Kind: config.ResourceDeployment, Status: config.StatusFailed, Error: "exceeded quota"},
+	}}
+	svc := &workflowServiceImpl{Store: store, DryRunner: runner}
+
+	resp, err := svc.DryRunWorkflowForApp(context.Background(), "app-1", apis.DryRunWorkflowRequest{WorkflowID: "wf-1"})
+	require.NoError(t, err)
+	require.False(t, resp.Passed)
+	require.Len(t, resp.Jobs, 2)
+	require.Equal(t, "exceeded quota", resp.Jobs[1].Error)
+	require.Equal(t, string(config.ResourceDeployment), resp.Jobs[1].Kind)
+
+	// The task is written once, already finished, so no worker ever claims it.
+	require.Len(t, store.tasks, 1)
+	task := store.tasks[0]
+	require.Equal(t, resp.TaskID, task.TaskID)
+	require.Equal(t, runner.task.TaskID, task.TaskID)
+	require.Equal(t, config.WorkflowTaskTypeTesting, task.Type)
+	require.Equal(t, config.StatusFailed, task.Status)
+
+	require.Len(t, store.jobs, 2)
+	require.Equal(t, task.TaskID, store.jobs[1].TaskID)
+	require.Equal(t, string(config.StatusFailed), store.jobs[1].Status)
+	require.Equal(t, "exceeded quota", store.jobs[1].Error)
+}
+
+func TestDryRunWorkflowPassesWhenAllJobsAccepted(t *testing.T) {
+	store := newDryRunFixture(t)
+	runner := &stubDryRunner{results: []model.DryRunJobResult{
+		{Step: "web", Name: "web", JobType: string(config.JobDeploy), Status: config.StatusCompleted},
+		{Step: "approve", Name: "approve", JobType: string(config.JobApproval), Status: config.StatusSkipped},
+	}}
+	svc := &workflowServiceImpl{Store: store, DryRunner: runner}
+
+	resp, err := svc.DryRunWorkflowForApp(context.Background(), "app-1", apis.DryRunWorkflowRequest{WorkflowID: "wf-1"})
+	require.NoError(t, err)
+	require.True(t, resp.Passed)
+	require.Equal(t, config.StatusCompleted, store.tasks[0].Status)
+
+	_, err = svc.DryRunWorkflowForApp(context.Background(), "other-app", apis.DryRunWorkflowRequest{WorkflowID: "wf-1"})
+	require.ErrorIs(t, err, bcode.ErrWorkflowNotExist)
+}
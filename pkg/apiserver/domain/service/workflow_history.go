@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/domain/repository"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+	apis "kubemin-cli/pkg/apiserver/interfaces/api/dto/v1"
+	"kubemin-cli/pkg/apiserver/utils/bcode"
+)
+
+// ListWorkflowTasks 分页返回应用的任务历史；指定 WorkflowID 时只返回该工作流的任务
+func (w *workflowServiceImpl) ListWorkflowTasks(ctx context.Context, appID string, query apis.ListWorkflowTasksQuery) (*apis.ListWorkflowTasksResponse, error) {
+	if query.WorkflowID != "" {
+		workflow, err := repository.WorkflowByID(ctx, w.Store, query.WorkflowID)
+		if err != nil {
+			if errors.Is(err, datastore.ErrRecordNotExist) {
+				return nil, bcode.ErrWorkflowNotExist
+			}
+			return nil, err
+		}
+		if workflow.AppID != appID {
+			return nil, bcode.ErrWorkflowNotExist
+		}
+	}
+	tasks, total, err := repository.ListAppTasks(ctx, w.Store, appID, query.WorkflowID, query.Status, query.Page, query.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	resp := &apis.ListWorkflowTasksResponse{
+		Tasks:    make([]apis.WorkflowTaskSummary, 0, len(tasks)),
+		Total:    total,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}
+	for _, task := range tasks {
+		resp.Tasks = append(resp.Tasks, summarizeTask(task))
+	}
+	return resp, nil
+}
+
+// ListTaskJobs 返回任务的全部 Job 执行记录，statuses 为空时不过滤
+func (w *workflowServiceImpl) ListTaskJobs(ctx context.Context, taskID string, statuses []string) (*apis.ListTaskJobsResponse, error) {
+	taskID = strings.TrimSpace(taskID)
+	if taskID == "" {
+		return nil, bcode.ErrWorkflowTaskNotExist
+	}
+	if _, err := repository.TaskByID(ctx, w.Store, taskID); err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, bcode.ErrWorkflowTaskNotExist
+		}
+		return nil, err
+	}
+	jobs, err := repository.ListTaskJobs(ctx, w.Store, taskID, statuses)
+	if err != nil {
+		return nil, err
+	}
+	resp := &apis.ListTaskJobsResponse{TaskID: taskID, Jobs: make([]apis.TaskJobDetail, 0, len(jobs))}
+	for _, job := range jobs {
+		detail := apis.TaskJobDetail{
+			ID:          job.ID,
+			Name:        job.ServiceName,
+			Type:        job.Type,
+			ServiceType: job.ServiceType,
+			Status:      job.Status,
+			Error:       job.Error,
+			Attempt:     job.Attempt,
+			StartTime:   job.StartTime,
+			EndTime:     job.EndTime,
+		}
+		if job.StartTime > 0 && job.EndTime >= job.StartTime {
+			detail.Duration = job.EndTime - job.StartTime
+		}
+		resp.Jobs = append(resp.Jobs, detail)
+	}
+	return resp, nil
+}
+
+// summarizeTask 任务开始运行时 CreateTime 被刷新为启动时间，结束后的最后一次更新即结束时间
+func summarizeTask(task *model.WorkflowQueue) apis.WorkflowTaskSummary {
+	summary := apis.WorkflowTaskSummary{
+		TaskID:       task.TaskID,
+		WorkflowID:   task.WorkflowID,
+		WorkflowName: task.WorkflowName,
+		Type:         task.Type,
+		Status:       string(task.Status),
+		TaskCreator:  task.TaskCreator,
+		TaskRevoker:  task.TaskRevoker,
+		Priority:     task.Priority,
+		RetryOf:      task.RetryOf,
+	}
+	if !task.CreateTime.IsZero() {
+		summary.StartTime = task.CreateTime.Unix()
+	}
+	if isTaskFinished(task.Status) && !task.UpdateTime.IsZero() {
+		summary.EndTime = task.UpdateTime.Unix()
+		if summary.StartTime > 0 && summary.EndTime >= summary.StartTime {
+			summary.Duration = summary.EndTime - summary.StartTime
+		}
+	}
+	return summary
+}
+
+func isTaskFinished(status config.Status) bool {
+	switch status {
+	case config.StatusCompleted, config.StatusFailed, config.StatusTimeout, config.StatusCancelled,
+		config.StatusReject, config.StatusRolledBack, config.StatusRollbackFailed:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+	apis "kubemin-cli/pkg/apiserver/interfaces/api/dto/v1"
+	"kubemin-cli/pkg/apiserver/utils/bcode"
+)
+
+// historyDataStore serves a fixed task page and records the list options it was queried with.
+type historyDataStore struct {
+	statusDataStore
+	tasks   []*model.WorkflowQueue
+	total   int64
+	options *datastore.ListOptions
+}
+
+func (s *historyDataStore) List(ctx context.Context, query datastore.Entity, opts *datastore.ListOptions) ([]datastore.Entity, error) {
+	if _, ok := query.(*model.WorkflowQueue); ok {
+		s.options = opts
+		out := make([]datastore.Entity, 0, len(s.tasks))
+		for _, task := range s.tasks {
+			out = append(out, task)
+		}
+		return out, nil
+	}
+	s.options = opts
+	return s.statusDataStore.List(ctx, query, opts)
+}
+
+func (s *historyDataStore) Count(context.Context, datastore.Entity, *datastore.FilterOptions) (int64, error) {
+	return s.total, nil
+}
+
+func TestListWorkflowTasksSummarizesDurations(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	store := &historyDataStore{
+		statusDataStore: statusDataStore{workflow: &model.Workflow{ID: "wf-1", AppID: "app-1"}},
+		tasks: []*model.WorkflowQueue{
+			{TaskID: "t-2", WorkflowID: "wf-1", Status: config.StatusRunning, TaskCreator: "alice",
+				BaseModel: model.BaseModel{CreateTime: start.Add(time.Hour), UpdateTime: start.Add(time.Hour + time.Minute)}},
+			{TaskID: "t-1", WorkflowID: "wf-1", Status: config.StatusCancelled, TaskRevoker: "bob",
+				BaseModel: model.BaseModel{CreateTime: start, UpdateTime: start.Add(90 * time.Second)}},
+		},
+		total: 12,
+	}
+	svc := &workflowServiceImpl{Store: store}
+
+	resp, err := svc.ListWorkflowTasks(context.Background(), "app-1", apis.ListWorkflowTasksQuery{
+		WorkflowID: "wf-1", Status: []string{"running", "cancelled"}, Page: 2, PageSize: 10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(12), resp.Total)
+	require.Equal(t, 2, store.options.Page)
+	require.Equal(t, 10, store.options.PageSize)
+	require.Len(t, store.options.In, 2)
+	require.Len(t, resp.Tasks, 2)
+
+	running := resp.Tasks[0]
+	require.Equal(t, "alice", running.TaskCreator)
+	require.Zero(t, running.EndTime, "unfinished tasks have no end time")
+	require.Zero(t, running.Duration)
+
+	cancelled := resp.Tasks[1]
+	require.Equal(t, "bob", cancelled.TaskRevoker)
+	require.Equal(t, start.Unix(), cancelled.StartTime)
+	require.Equal(t, int64(90), cancelled.Duration)
+
+	_, err = svc.ListWorkflowTasks(context.Background(), "app-2", apis.ListWorkflowTasksQuery{WorkflowID: "wf-1", Page: 1, PageSize: 10})
+	require.ErrorIs(t, err, bcode.ErrWorkflowNotExist)
+}
+
+func TestListTaskJobsReturnsEveryAttempt(t *testing.T) {
+	store := &historyDataStore{statusDataStore: statusDataStore{
+		task: &model.WorkflowQueue{TaskID: "task-1"},
+		jobs: []*model.JobInfo{
+			{ID: 1, TaskID: "task-1", ServiceName: "web", Type: string(config.JobDeploy), Status: string(config.StatusFailed), Error: "image pull backoff", Attempt: 1, StartTime: 100, EndTime: 160},
+			{ID: 2, TaskID: "task-1", ServiceName: "web", Type: string(config.JobDeploy), Status: string(config.StatusRunning), Attempt: 2, StartTime: 170},
+		},
+	}}
+	svc := &workflowServiceImpl{Store: store}
+
+	resp, err := svc.ListTaskJobs(context.Background(), "task-1", []string{"failed"})
+	require.NoError(t, err)
+	require.Equal(t, []datastore.InQueryOption{{Key: "status", Values: []string{"failed"}}}, store.options.In)
+	require.Len(t, resp.Jobs, 2)
+	require.Equal(t, "image pull backoff", resp.Jobs[0].Error)
+	require.Equal(t, int64(60), resp.Jobs[0].Duration)
+	require.Zero(t, resp.Jobs[1].Duration, "running jobs have no duration yet")
+
+	_, err = svc.ListTaskJobs(context.Background(), "missing", nil)
+	require.ErrorIs(t, err, bcode.ErrWorkflowTaskNotExist)
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"k8s.io/klog/v2"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/domain/repository"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+	apis "kubemin-cli/pkg/apiserver/interfaces/api/dto/v1"
+	"kubemin-cli/pkg/apiserver/utils/bcode"
+)
+
+// WorkflowPlanner 对比工作流计划生成的期望对象与集群中的线上状态
+type WorkflowPlanner interface {
+	PlanWorkflow(ctx context.Context, task *model.WorkflowQueue) ([]model.ResourcePlan, error)
+}
+
+// PlanWorkflowForApp 预览执行工作流会对每个资源做的变更，不创建任务也不修改集群
+func (w *workflowServiceImpl) PlanWorkflowForApp(ctx context.Context, appID, workflowID string) (*apis.WorkflowPlanResponse, error) {
+	workflow, err := w.appWorkflow(ctx, appID, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	if w.Planner == nil {
+		return nil, bcode.ErrWorkflowPlan
+	}
+	plans, err := w.Planner.PlanWorkflow(ctx, newWorkflowQueueTask(workflow))
+	if err != nil {
+		klog.Errorf("plan workflow %s failed: %v", workflow.ID, err)
+		return nil, bcode.ErrWorkflowPlan
+	}
+	resp := &apis.WorkflowPlanResponse{
+		WorkflowID: workflow.ID,
+		Summary: map[string]int{
+			string(config.PlanActionCreate): 0,
+			string(config.PlanActionUpdate): 0,
+			string(config.PlanActionNoop):   0,
+			string(config.PlanActionDelete): 0,
+		},
+		Resources: make([]apis.WorkflowPlanResource, 0, len(plans)),
+	}
+	for _, plan := range plans {
+		if plan.Action != "" {
+			resp.Summary[string(plan.Action)]++
+		}
+		resource := apis.WorkflowPlanResource{
+			Step:      plan.Step,
+			Job:       plan.Job,
+			Kind:      string(plan.Kind),
+			Namespace: plan.Namespace,
+			Name:      plan.Name,
+			Action:    string(plan.Action),
+			Error:     plan.Error,
+		}
+		for _, change := range plan.Changes {
+			resource.Changes = append(resource.Changes, apis.WorkflowPlanFieldChange{
+				Path:    change.Path,
+				Live:    change.Live,
+				Desired: change.Desired,
+			})
+		}
+		resp.Resources = append(resp.Resources, resource)
+	}
+	return resp, nil
+}
+
+// appWorkflow 读取属于应用的可执行工作流
+func (w *workflowServiceImpl) appWorkflow(ctx context.Context, appID, workflowID string) (*model.Workflow, error) {
+	workflow, err := repository.WorkflowByID(ctx, w.Store, workflowID)
+	if err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, bcode.ErrWorkflowNotExist
+		}
+		return nil, err
+	}
+	if workflow.AppID == "" || workflow.AppID != appID {
+		return nil, bcode.ErrWorkflowNotExist
+	}
+	if workflow.Steps == nil {
+		return nil, bcode.ErrExecWorkflow
+	}
+	return workflow, nil
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/utils/bcode"
+)
+
+type stubPlanner struct {
+	task  *model.WorkflowQueue
+	plans []model.ResourcePlan
+}
+
+func (p *stubPlanner) PlanWorkflow(_ context.Context, task *model.WorkflowQueue) ([]model.ResourcePlan, error) {
+	p.task = task
+	return p.plans, nil
+}
+
+func TestPlanWorkflowSummarisesActions(t *testing.T) {
+	store := newDryRunFixture(t)
+	planner := &stubPlanner{plans: []model.ResourcePlan{
+		{Step: "web", Kind: config.ResourceDeployment, Name: "web", Action: config.PlanActionUpdate,
+			Changes: []model.FieldChange{{Path: "spec.replicas", Live: int64(1), Desired: int64(3)}}},
+		{Step: "config", Kind: config.ResourceConfigMap, Name: "config", Action: config.PlanActionCreate},
+		{Kind: config.ResourceConfigMap, Name: "stale", Action: config.PlanActionDelete},
+	}}
+	svc := &workflowServiceImpl{Store: store, Planner: planner}
+
+	resp, err := svc.PlanWorkflowForApp(context.Background(), "app-1", "wf-1")
+	require.NoError(t, err)
+	require.Equal(t, "wf-1", planner.task.WorkflowID)
+	require.Equal(t, map[string]int{"create": 1, "update": 1, "no-op": 0, "delete": 1}, resp.Summary)
+	require.Len(t, resp.Resources, 3)
+	require.Equal(t, "spec.replicas", resp.Resources[0].Changes[0].Path)
+	// A plan is read-only: no task is recorded.
+	require.Empty(t, store.tasks)
+
+	_, err = svc.PlanWorkflowForApp(context.Background(), "other-app", "wf-1")
+	require.ErrorIs(t, err, bcode.ErrWorkflowNotExist)
+}
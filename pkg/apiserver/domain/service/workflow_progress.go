@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/repository"
+	apis "kubemin-cli/pkg/apiserver/interfaces/api/dto/v1"
+	"kubemin-cli/pkg/apiserver/utils/bcode"
+	"kubemin-cli/pkg/apiserver/workflow/progress"
+)
+
+const progressEventSnapshot = "snapshot"
+
+// progressResyncInterval 定期回读任务状态，覆盖不经过执行器的状态变化（例如排队中被取消）
+var progressResyncInterval = 15 * time.Second
+
+// WatchTask 先推送任务的当前状态，再推送之后的任务与 Job 状态变化。
+// 任务结束、订阅被关闭或 ctx 结束时关闭返回的通道
+func (w *workflowServiceImpl) WatchTask(ctx context.Context, taskID string) (<-chan apis.TaskProgressEvent, error) {
+	if w.Progress == nil {
+		return nil, bcode.ErrWorkflowProgressUnavailable
+	}
+	// 先订阅再读取快照，保证两者之间发生的变化不会丢失
+	events, unsubscribe, err := w.Progress.Subscribe(taskID)
+	if err != nil {
+		klog.Errorf("subscribe progress of task %s failed: %v", taskID, err)
+		return nil, bcode.ErrWorkflowProgressUnavailable
+	}
+	snapshot, err := w.GetTaskStatus(ctx, taskID)
+	if err != nil {
+		unsubscribe()
+		return nil, err
+	}
+
+	out := make(chan apis.TaskProgressEvent, 16)
+	go func() {
+		defer close(out)
+		defer unsubscribe()
+		send := func(event apis.TaskProgressEvent) bool {
+			if event.Timestamp == 0 {
+				event.Timestamp = time.Now().UnixMilli()
+			}
+			select {
+			case out <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		status := config.Status(snapshot.Status)
+		if !send(apis.TaskProgressEvent{Type: progressEventSnapshot, TaskID: taskID, Status: snapshot.Status, Snapshot: snapshot}) || isTaskFinished(status) {
+			return
+		}
+		ticker := time.NewTicker(progressResyncInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				if !send(progressEventToDTO(event)) {
+					return
+				}
+				if event.Type == progress.EventTask {
+					status = event.Status
+					if isTaskFinished(status) {
+						return
+					}
+				}
+			case <-ticker.C:
+				task, err := repository.TaskByID(ctx, w.Store, taskID)
+				if err != nil || task.Status == status {
+					continue
+				}
+				status = task.Status
+				if !send(apis.TaskProgressEvent{Type: string(progress.EventTask), TaskID: taskID, Status: string(status)}) || isTaskFinished(status) {
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+func progressEventToDTO(event progress.Event) apis.TaskProgressEvent {
+	dto := apis.TaskProgressEvent{
+		Type:      string(event.Type),
+		TaskID:    event.TaskID,
+		Status:    string(event.Status),
+		Timestamp: event.Timestamp,
+	}
+	if event.Job != nil {
+		dto.Job = &apis.TaskJobProgress{
+			Name:      event.Job.Name,
+			Type:      event.Job.Type,
+			Status:    string(event.Job.Status),
+			Error:     event.Job.Error,
+			Attempt:   event.Job.Attempt,
+			StartTime: event.Job.StartTime,
+			EndTime:   event.Job.EndTime,
+		}
+	}
+	return dto
+}
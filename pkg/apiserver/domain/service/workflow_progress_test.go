@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+	msg "kubemin-cli/pkg/apiserver/infrastructure/messaging"
+	apis "kubemin-cli/pkg/apiserver/interfaces/api/dto/v1"
+	"kubemin-cli/pkg/apiserver/utils/bcode"
+	"kubemin-cli/pkg/apiserver/workflow/progress"
+)
+
+func nextProgressEvent(t *testing.T, ch <-chan apis.TaskProgressEvent) (apis.TaskProgressEvent, bool) {
+	t.Helper()
+	select {
+	case event, ok := <-ch:
+		return event, ok
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for progress event")
+	}
+	return apis.TaskProgressEvent{}, false
+}
+
+func TestWatchTaskReplaysSnapshotThenStreamsChanges(t *testing.T) {
+	broker := progress.NewBroker(msg.NewLocalPubSub(), "events")
+	defer broker.Close()
+	store := &statusDataStore{task: &model.WorkflowQueue{TaskID: "task-1", Status: config.StatusRunning}}
+	svc := &workflowServiceImpl{Store: store, Progress: broker}
+
+	events, err := svc.WatchTask(context.Background(), "task-1")
+	require.NoError(t, err)
+	snapshot, ok := nextProgressEvent(t, events)
+	require.True(t, ok)
+	require.Equal(t, "snapshot", snapshot.Type)
+	require.Equal(t, "task-1", snapshot.Snapshot.TaskID)
+
+	ctx := context.Background()
+	broker.Publish(ctx, progress.Event{Type: progress.EventTask, TaskID: "task-2", Status: config.StatusFailed})
+	broker.Publish(ctx, progress.Event{Type: progress.EventJob, TaskID: "task-1", Status: config.StatusRunning,
+		Job: &progress.JobState{Name: "web", Type: string(config.JobDeploy), Status: config.StatusCompleted, Attempt: 1}})
+	broker.Publish(ctx, progress.Event{Type: progress.EventTask, TaskID: "task-1", Status: config.StatusCompleted})
+
+	jobEvent, _ := nextProgressEvent(t, events)
+	require.Equal(t, "job", jobEvent.Type)
+	require.Equal(t, string(config.StatusCompleted), jobEvent.Job.Status)
+	taskEvent, _ := nextProgressEvent(t, events)
+	require.Equal(t, string(config.StatusCompleted), taskEvent.Status)
+	_, ok = nextProgressEvent(t, events)
+	require.False(t, ok, "stream ends once the task finishes")
+}
+
+// flippingDataStore reports the task as running on the first read and cancelled afterwards,
+// as if it had been cancelled while queued without any executor publishing the change.
+type flippingDataStore struct {
+	statusDataStore
+	reads atomic.Int32
+}
+
+func (s *flippingDataStore) Get(_ context.Context, entity datastore.Entity) error {
+	task, ok := entity.(*model.WorkflowQueue)
+	if !ok || task.TaskID != "task-1" {
+		return datastore.ErrRecordNotExist
+	}
+	task.Status = config.StatusRunning
+	if s.reads.Add(1) > 1 {
+		task.Status = config.StatusCancelled
+	}
+	return nil
+}
+
+func TestWatchTaskResyncsStatusChangedOutsideExecutor(t *testing.T) {
+	previous := progressResyncInterval
+	progressResyncInterval = 20 * time.Millisecond
+	defer func() { progressResyncInterval = previous }()
+
+	broker := progress.NewBroker(msg.NewLocalPubSub(), "events")
+	defer broker.Close()
+	svc := &workflowServiceImpl{Store: &flippingDataStore{}, Progress: broker}
+
+	events, err := svc.WatchTask(context.Background(), "task-1")
+	require.NoError(t, err)
+	snapshot, _ := nextProgressEvent(t, events)
+	require.Equal(t, string(config.StatusRunning), snapshot.Status)
+	resynced, _ := nextProgressEvent(t, events)
+	require.Equal(t, string(config.StatusCancelled), resynced.Status)
+	_, ok := nextProgressEvent(t, events)
+	require.False(t, ok)
+
+	_, err = (&workflowServiceImpl{Store: &statusDataStore{}}).WatchTask(context.Background(), "task-1")
+	require.ErrorIs(t, err, bcode.ErrWorkflowProgressUnavailable)
+	_, err = svc.WatchTask(context.Background(), "missing")
+	require.ErrorIs(t, err, bcode.ErrWorkflowTaskNotExist)
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+	"kubemin-cli/pkg/apiserver/utils/bcode"
+	wf "kubemin-cli/pkg/apiserver/workflow"
+)
+
+// retryDataStore serves a failed task and records the tasks created from it.
+type retryDataStore struct {
+	statusDataStore
+	created []*model.WorkflowQueue
+}
+
+func (s *retryDataStore) Add(_ context.Context, entity datastore.Entity) error {
+	if task, ok := entity.(*model.WorkflowQueue); ok {
+		s.created = append(s.created, task)
+	}
+	return nil
+}
+
+func newRetryFixture(t *testing.T, revision string) *retryDataStore {
+	t.Helper()
+	steps, err := model.NewJSONStructByStruct(&model.WorkflowSteps{
+		Steps: []*model.WorkflowStep{{Name: "config"}, {Name: "web"}},
+	})
+	require.NoError(t, err)
+	workflow := &model.Workflow{ID: "wf-1", AppID: "app-1", Name: "deploy", Steps: steps}
+	if revision == "" {
+		revision, err = wf.Revision(workflow, nil)
+		require.NoError(t, err)
+	}
+	return &retryDataStore{statusDataStore: statusDataStore{
+		workflow: workflow,
+		task: &model.WorkflowQueue{
+			TaskID:     "task-1",
+			AppID:      "app-1",
+			WorkflowID: "wf-1",
+			Status:     config.StatusFailed,
+			Revision:   revision,
+			Priority:   5,
+		},
+		jobs: []*model.JobInfo{
+			{TaskID: "task-1", Type: string(config.JobDeployConfigMap), ServiceName: "config", Status: string(config.StatusCompleted), Attempt: 1},
+			{TaskID: "task-1", Type: string(config.JobDeploy), ServiceName: "web", Status: string(config.StatusCompleted), Attempt: 1},
+			{TaskID: "task-1", Type: string(config.JobDeploy), ServiceName: "web", Status: string(config.StatusFailed), Attempt: 2},
+		},
+	}}
+}
+
+func TestRetryWorkflowTaskSkipsCompletedJobs(t *testing.T) {
+	store := newRetryFixture(t, "")
+	svc := &workflowServiceImpl{Store: store}
+
+	resp, err := svc.RetryWorkflowTaskForApp(context.Background(), "app-1", "alice", "task-1")
+	require.NoError(t, err)
+	require.Equal(t, "task-1", resp.RetryOf)
+	require.Equal(t, 1, resp.SkippedJobs)
+
+	require.Len(t, store.created, 1)
+	retry := store.created[0]
+	require.Equal(t, resp.TaskID, retry.TaskID)
+	require.Equal(t, "task-1", retry.RetryOf)
+	require.Equal(t, config.StatusWaiting, retry.Status)
+	require.Equal(t, "alice", retry.TaskCreator)
+	require.Equal(t, store.task.Revision, retry.Revision)
+	require.Equal(t, 5, retry.Priority)
+	// Only the config job succeeded; the web job failed on its latest attempt.
+	require.Equal(t, []string{wf.JobKey(string(config.JobDeployConfigMap), "config")}, retry.SkipJobs)
+}
+
+func TestRetryWorkflowTaskRerunsEverythingAfterChange(t *testing.T) {
+	store := newRetryFixture(t, "outdated-revision")
+	svc := &workflowServiceImpl{Store: store}
+
+	resp, err := svc.RetryWorkflowTaskForApp(context.Background(), "app-1", "alice", "task-1")
+	require.NoError(t, err)
+	require.Zero(t, resp.SkippedJobs)
+	require.Empty(t, store.created[0].SkipJobs)
+}
+
+func TestRetryWorkflowTaskRejectsUnfailedTask(t *testing.T) {
+	store := newRetryFixture(t, "")
+	store.task.Status = config.StatusCompleted
+	svc := &workflowServiceImpl{Store: store}
+
+	_, err := svc.RetryWorkflowTaskForApp(context.Background(), "app-1", "alice", "task-1")
+	require.ErrorIs(t, err, bcode.ErrWorkflowRetryNotAllowed)
+
+	_, err = svc.RetryWorkflowTaskForApp(context.Background(), "other-app", "alice", "task-1")
+	require.ErrorIs(t, err, bcode.ErrWorkflowNotExist)
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/domain/repository"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+	apis "kubemin-cli/pkg/apiserver/interfaces/api/dto/v1"
+	"kubemin-cli/pkg/apiserver/utils"
+	"kubemin-cli/pkg/apiserver/utils/bcode"
+	wf "kubemin-cli/pkg/apiserver/workflow"
+)
+
+// CreateWorkflowSchedule 为应用的工作流创建定时执行，首次触发时间按 cron 与时区计算
+func (w *workflowServiceImpl) CreateWorkflowSchedule(ctx context.Context, appID string, req apis.CreateWorkflowScheduleRequest) (*model.WorkflowSchedule, error) {
+	workflow, err := repository.WorkflowByID(ctx, w.Store, req.WorkflowID)
+	if err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, bcode.ErrWorkflowNotExist
+		}
+		return nil, err
+	}
+	if workflow.AppID == "" || workflow.AppID != appID {
+		return nil, bcode.ErrWorkflowNotExist
+	}
+	if !config.IsValidScheduleConcurrencyPolicy(req.ConcurrencyPolicy) {
+		return nil, bcode.ErrWorkflowScheduleInvalid
+	}
+	schedule := &model.WorkflowSchedule{
+		ID:                utils.RandStringByNumLowercase(24),
+		Name:              req.Name,
+		AppID:             appID,
+		WorkflowID:        workflow.ID,
+		Cron:              strings.TrimSpace(req.Cron),
+		TimeZone:          strings.TrimSpace(req.TimeZone),
+		ConcurrencyPolicy: config.ParseScheduleConcurrencyPolicy(req.ConcurrencyPolicy),
+		Inputs:            req.Inputs,
+		Status:            config.StatusEnabled,
+		Creator:           req.User,
+	}
+	if req.Disabled {
+		schedule.Status = config.StatusDisabled
+	}
+	if err := refreshNextRunTime(schedule, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := repository.CreateWorkflowSchedule(ctx, w.Store, schedule); err != nil {
+		return nil, err
+	}
+	klog.Infof("AUDIT: create workflow schedule scheduleID=%s appID=%s workflowID=%s cron=%q timeZone=%s policy=%s user=%s",
+		schedule.ID, appID, workflow.ID, schedule.Cron, schedule.TimeZone, schedule.ConcurrencyPolicy, req.User)
+	return schedule, nil
+}
+
+// ListWorkflowSchedules 列出应用下的全部定时执行
+func (w *workflowServiceImpl) ListWorkflowSchedules(ctx context.Context, appID string) ([]*model.WorkflowSchedule, error) {
+	list, err := repository.ListWorkflowSchedules(ctx, w.Store, &model.WorkflowSchedule{AppID: appID})
+	if err != nil && !errors.Is(err, datastore.ErrRecordNotExist) {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetWorkflowSchedule 查询应用下的单个定时执行
+func (w *workflowServiceImpl) GetWorkflowSchedule(ctx context.Context, appID, scheduleID string) (*model.WorkflowSchedule, error) {
+	schedule, err := repository.WorkflowScheduleByID(ctx, w.Store, scheduleID)
+	if err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, bcode.ErrWorkflowScheduleNotExist
+		}
+		return nil, err
+	}
+	if schedule.AppID != appID {
+		return nil, bcode.ErrWorkflowScheduleNotExist
+	}
+	return schedule, nil
+}
+
+// UpdateWorkflowSchedule 修改定时执行；cron、时区或启停状态变化后重新计算下次触发时间
+func (w *workflowServiceImpl) UpdateWorkflowSchedule(ctx context.Context, appID, scheduleID string, req apis.UpdateWorkflowScheduleRequest) (*model.WorkflowSchedule, error) {
+	schedule, err := w.GetWorkflowSchedule(ctx, appID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if name := strings.TrimSpace(req.Name); name != "" {
+		schedule.Name = name
+	}
+	if cron := strings.TrimSpace(req.Cron); cron != "" {
+		schedule.Cron = cron
+	}
+	if tz := strings.TrimSpace(req.TimeZone); tz != "" {
+		schedule.TimeZone = tz
+	}
+	if req.ConcurrencyPolicy != "" {
+		if !config.IsValidScheduleConcurrencyPolicy(req.ConcurrencyPolicy) {
+			return nil, bcode.ErrWorkflowScheduleInvalid
+		}
+		schedule.ConcurrencyPolicy = config.ParseScheduleConcurrencyPolicy(req.ConcurrencyPolicy)
+	}
+	if req.Inputs != nil {
+		schedule.Inputs = req.Inputs
+	}
+	if req.Enabled != nil {
+		schedule.Status = config.StatusDisabled
+		if *req.Enabled {
+			schedule.Status = config.StatusEnabled
+		}
+	}
+	if err := refreshNextRunTime(schedule, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := w.Store.Put(ctx, schedule); err != nil {
+		return nil, err
+	}
+	klog.Infof("AUDIT: update workflow schedule scheduleID=%s appID=%s cron=%q timeZone=%s policy=%s status=%s",
+		schedule.ID, appID, schedule.Cron, schedule.TimeZone, schedule.ConcurrencyPolicy, schedule.Status)
+	return schedule, nil
+}
+
+// DeleteWorkflowSchedule 删除定时执行，已创建的任务不受影响
+func (w *workflowServiceImpl) DeleteWorkflowSchedule(ctx context.Context, appID, scheduleID string) error {
+	schedule, err := w.GetWorkflowSchedule(ctx, appID, scheduleID)
+	if err != nil {
+		return err
+	}
+	if err := w.Store.Delete(ctx, schedule); err != nil {
+		return err
+	}
+	klog.Infof("AUDIT: delete workflow schedule scheduleID=%s appID=%s workflowID=%s", schedule.ID, appID, schedule.WorkflowID)
+	return nil
+}
+
+// FireDueSchedules 触发所有已到点的定时执行，仅由 leader 调用。错过的多次触发只补执行一次
+func (w *workflowServiceImpl) FireDueSchedules(ctx context.Context, now time.Time) (int, error) {
+	schedules, err := repository.ListWorkflowSchedules(ctx, w.Store, &model.WorkflowSchedule{Status: config.StatusEnabled})
+	if err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	fired := 0
+	for _, schedule := range schedules {
+		if schedule.NextRunTime == 0 || schedule.NextRunTime > now.Unix() {
+			continue
+		}
+		if w.fireSchedule(ctx, schedule, now) {
+			fired++
+		}
+	}
+	return fired, nil
+}
+
+// fireSchedule claims one due activation and applies the concurrency policy. The claim is a
+// compare-and-swap on next_run_time so that a leader hand-over never fires the same run twice.
+func (w *workflowServiceImpl) fireSchedule(ctx context.Context, schedule *model.WorkflowSchedule, now time.Time) bool {
+	due := schedule.NextRunTime
+	valid := true
+	if err := refreshNextRunTime(schedule, now); err != nil {
+		// Stop firing instead of retrying a broken expression on every poll.
+		klog.Errorf("workflow schedule %s has an invalid cron %q, disabling it", schedule.ID, schedule.Cron)
+		schedule.NextRunTime = 0
+		valid = false
+	}
+	claimed, err := w.Store.CompareAndSwap(ctx, schedule, "next_run_time", due, map[string]interface{}{
+		"next_run_time": schedule.NextRunTime,
+		"last_run_time": due,
+	})
+	if err != nil {
+		klog.Errorf("claim workflow schedule %s failed: %v", schedule.ID, err)
+		return false
+	}
+	if !claimed || !valid {
+		return false
+	}
+	schedule.LastRunTime = due
+
+	result := w.runSchedule(ctx, schedule)
+	schedule.LastResult = result
+	if _, err := w.Store.CompareAndSwap(ctx, schedule, "last_run_time", due, map[string]interface{}{
+		"last_task_id": schedule.LastTaskID,
+		"last_result":  result,
+	}); err != nil {
+		klog.Errorf("record workflow schedule %s result failed: %v", schedule.ID, err)
+	}
+	klog.Infof("workflow schedule %s (%s) fired: %s", schedule.ID, schedule.Name, result)
+	return true
+}
+
+// runSchedule enqueues the scheduled workflow and returns a short description of the outcome.
+func (w *workflowServiceImpl) runSchedule(ctx context.Context, schedule *model.WorkflowSchedule) string {
+	if previous := w.activeScheduleTask(ctx, schedule); previous != nil {
+		switch config.ParseScheduleConcurrencyPolicy(string(schedule.ConcurrencyPolicy)) {
+		case config.ScheduleConcurrencyForbid:
+			return fmt.Sprintf("skipped: task %s is still %s", previous.TaskID, previous.Status)
+		case config.ScheduleConcurrencyReplace:
+			reason := fmt.Sprintf("replaced by workflow schedule %s", schedule.Name)
+			if err := w.cancelWorkflowTask(ctx, previous, scheduleTaskCreator(schedule), reason); err != nil {
+				return fmt.Sprintf("failed: cancel task %s: %v", previous.TaskID, err)
+			}
+		}
+	}
+	workflow, err := repository.WorkflowByID(ctx, w.Store, schedule.WorkflowID)
+	if err != nil {
+		return fmt.Sprintf("failed: load workflow %s: %v", schedule.WorkflowID, err)
+	}
+	task, err := w.newWorkflowTask(ctx, workflow, schedule.Inputs, 0)
+	if err != nil {
+		return fmt.Sprintf("failed: %v", err)
+	}
+	resp, err := w.enqueueWorkflowTask(ctx, task)
+	if err != nil {
+		var conflict *bcode.Bcode
+		if errors.As(err, &conflict) && errors.Is(err, bcode.ErrWorkflowTaskConflict) {
+			return fmt.Sprintf("skipped: application task %v is still active", conflict.Detail["blocking_task_id"])
+		}
+		return fmt.Sprintf("failed: %v", err)
+	}
+	schedule.LastTaskID = resp.TaskID
+	return fmt.Sprintf("created task %s", resp.TaskID)
+}
+
+// activeScheduleTask returns the task created by the previous activation when it has not finished yet.
+func (w *workflowServiceImpl) activeScheduleTask(ctx context.Context, schedule *model.WorkflowSchedule) *model.WorkflowQueue {
+	if schedule.LastTaskID == "" {
+		return nil
+	}
+	task, err := repository.TaskByID(ctx, w.Store, schedule.LastTaskID)
+	if err != nil {
+		if !errors.Is(err, datastore.ErrRecordNotExist) {
+			klog.Errorf("load last task %s of workflow schedule %s failed: %v", schedule.LastTaskID, schedule.ID, err)
+		}
+		return nil
+	}
+	switch task.Status {
+	case config.StatusWaiting, config.StatusQueued, config.StatusRunning, config.StatusPause, config.StatusWaitingApprove:
+		return task
+	default:
+		return nil
+	}
+}
+
+func scheduleTaskCreator(schedule *model.WorkflowSchedule) string {
+	return "schedule:" + schedule.Name
+}
+
+// refreshNextRunTime validates the cron expression and time zone and sets the next activation
+// after now; disabled schedules have no next activation.
+func refreshNextRunTime(schedule *model.WorkflowSchedule, now time.Time) error {
+	cron, err := wf.ParseCron(schedule.Cron, schedule.TimeZone)
+	if err != nil {
+		klog.V(4).Infof("invalid workflow schedule %s: %v", schedule.ID, err)
+		return bcode.ErrWorkflowScheduleInvalid
+	}
+	schedule.NextRunTime = 0
+	if schedule.Status != config.StatusEnabled {
+		return nil
+	}
+	if next := cron.Next(now); !next.IsZero() {
+		schedule.NextRunTime = next.Unix()
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+	apis "kubemin-cli/pkg/apiserver/interfaces/api/dto/v1"
+	"kubemin-cli/pkg/apiserver/utils/bcode"
+)
+
+// scheduleDataStore serves one workflow, one schedule and the previous task of that schedule,
+// recording the tasks enqueued and the column updates made through CompareAndSwap.
+type scheduleDataStore struct {
+	statusDataStore
+	schedule *model.WorkflowSchedule
+	created  []*model.WorkflowQueue
+	updates  []map[string]interface{}
+}
+
+func (s *scheduleDataStore) Add(_ context.Context, entity datastore.Entity) error {
+	switch v := entity.(type) {
+	case *model.WorkflowQueue:
+		s.created = append(s.created, v)
+	case *model.WorkflowSchedule:
+		s.schedule = v
+	}
+	return nil
+}
+
+func (s *scheduleDataStore) Get(ctx context.Context, entity datastore.Entity) error {
+	if v, ok := entity.(*model.WorkflowSchedule); ok {
+		if s.schedule != nil && v.ID == s.schedule.ID {
+			*v = *s.schedule
+			return nil
+		}
+		return datastore.ErrRecordNotExist
+	}
+	return s.statusDataStore.Get(ctx, entity)
+}
+
+func (s *scheduleDataStore) List(ctx context.Context, query datastore.Entity, opts *datastore.ListOptions) ([]datastore.Entity, error) {
+	if _, ok := query.(*model.WorkflowSchedule); ok {
+		if s.schedule == nil {
+			return nil, datastore.ErrRecordNotExist
+		}
+		copied := *s.schedule
+		return []datastore.Entity{&copied}, nil
+	}
+	return s.statusDataStore.List(ctx, query, opts)
+}
+
+func (s *scheduleDataStore) CompareAndSwap(_ context.Context, entity datastore.Entity, field string, value interface{}, updates map[string]interface{}) (bool, error) {
+	if _, ok := entity.(*model.WorkflowSchedule); !ok {
+		return true, nil
+	}
+	if field == "next_run_time" && s.schedule.NextRunTime != value.(int64) {
+		return false, nil
+	}
+	s.updates = append(s.updates, updates)
+	if next, ok := updates["next_run_time"].(int64); ok {
+		s.schedule.NextRunTime = next
+	}
+	return true, nil
+}
+
+func newScheduleFixture(t *testing.T, policy config.ScheduleConcurrencyPolicy, previous config.Status) *scheduleDataStore {
+	t.Helper()
+	steps, err := model.NewJSONStructByStruct(&model.WorkflowSteps{Steps: []*model.WorkflowStep{{Name: "web"}}})
+	require.NoError(t, err)
+	return &scheduleDataStore{
+		statusDataStore: statusDataStore{
+			workflow: &model.Workflow{ID: "wf-1", AppID: "app-1", Name: "deploy", Steps: steps},
+			task:     &model.WorkflowQueue{TaskID: "task-prev", AppID: "app-1", WorkflowID: "wf-1", Status: previous},
+		},
+		schedule: &model.WorkflowSchedule{
+			ID:                "sched-1",
+			Name:              "nightly",
+			AppID:             "app-1",
+			WorkflowID:        "wf-1",
+			Cron:              "0 2 * * *",
+			ConcurrencyPolicy: policy,
+			Inputs:            map[string]interface{}{"env": "prod"},
+			Status:            config.StatusEnabled,
+			NextRunTime:       time.Date(2026, 3, 14, 2, 0, 0, 0, time.UTC).Unix(),
+			LastTaskID:        "task-prev",
+		},
+	}
+}
+
+func TestFireDueSchedulesEnqueuesTaskAndAdvances(t *testing.T) {
+	store := newScheduleFixture(t, config.ScheduleConcurrencyForbid, config.StatusCompleted)
+	svc := &workflowServiceImpl{Store: store}
+	now := time.Date(2026, 3, 14, 2, 0, 20, 0, time.UTC)
+
+	fired, err := svc.FireDueSchedules(context.Background(), now)
+	require.NoError(t, err)
+	require.Equal(t, 1, fired)
+	require.Len(t, store.created, 1)
+	require.Equal(t, "wf-1", store.created[0].WorkflowID)
+	require.Equal(t, map[string]interface{}{"env": "prod"}, store.created[0].Inputs)
+	require.Equal(t, time.Date(2026, 3, 15, 2, 0, 0, 0, time.UTC).Unix(), store.schedule.NextRunTime)
+	require.Equal(t, store.created[0].TaskID, store.updates[1]["last_task_id"])
+
+	// The same activation is not fired twice.
+	fired, err = svc.FireDueSchedules(context.Background(), now)
+	require.NoError(t, err)
+	require.Zero(t, fired)
+	require.Len(t, store.created, 1)
+}
+
+func TestFireDueSchedulesConcurrencyPolicy(t *testing.T) {
+	now := time.Date(2026, 3, 14, 2, 0, 20, 0, time.UTC)
+
+	forbid := newScheduleFixture(t, config.ScheduleConcurrencyForbid, config.StatusRunning)
+	_, err := (&workflowServiceImpl{Store: forbid}).FireDueSchedules(context.Background(), now)
+	require.NoError(t, err)
+	require.Empty(t, forbid.created)
+	require.Contains(t, forbid.updates[1]["last_result"], "skipped")
+
+	allow := newScheduleFixture(t, config.ScheduleConcurrencyAllow, config.StatusRunning)
+	_, err = (&workflowServiceImpl{Store: allow}).FireDueSchedules(context.Background(), now)
+	require.NoError(t, err)
+	require.Len(t, allow.created, 1)
+
+	replace := newScheduleFixture(t, config.ScheduleConcurrencyReplace, config.StatusRunning)
+	_, err = (&workflowServiceImpl{Store: replace}).FireDueSchedules(context.Background(), now)
+	require.NoError(t, err)
+	require.Len(t, replace.created, 1)
+}
+
+func TestCreateWorkflowScheduleValidates(t *testing.T) {
+	store := newScheduleFixture(t, "", config.StatusCompleted)
+	store.schedule = nil
+	svc := &workflowServiceImpl{Store: store}
+	req := apis.CreateWorkflowScheduleRequest{Name: "nightly", WorkflowID: "wf-1", Cron: "0 2 * * *", TimeZone: "Asia/Shanghai"}
+
+	schedule, err := svc.CreateWorkflowSchedule(context.Background(), "app-1", req)
+	require.NoError(t, err)
+	require.Equal(t, config.ScheduleConcurrencyForbid, schedule.ConcurrencyPolicy)
+	require.Equal(t, config.StatusEnabled, schedule.Status)
+	require.NotZero(t, schedule.NextRunTime)
+
+	_, err = svc.CreateWorkflowSchedule(context.Background(), "other-app", req)
+	require.ErrorIs(t, err, bcode.ErrWorkflowNotExist)
+
+	for _, bad := range []apis.CreateWorkflowScheduleRequest{
+		{Name: "n", WorkflowID: "wf-1", Cron: "61 * * * *"},
+		{Name: "n", WorkflowID: "wf-1", Cron: "* * * * *", TimeZone: "Nowhere/City"},
+		{Name: "n", WorkflowID: "wf-1", Cron: "* * * * *", ConcurrencyPolicy: "queue"},
+	} {
+		_, err = svc.CreateWorkflowSchedule(context.Background(), "app-1", bad)
+		require.ErrorIs(t, err, bcode.ErrWorkflowScheduleInvalid)
+	}
+
+	disabled := req
+	disabled.Disabled = true
+	schedule, err = svc.CreateWorkflowSchedule(context.Background(), "app-1", disabled)
+	require.NoError(t, err)
+	require.Zero(t, schedule.NextRunTime)
+}
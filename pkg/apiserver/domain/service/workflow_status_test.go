@@ -3,12 +3,14 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"kubemin-cli/pkg/apiserver/config"
 	"kubemin-cli/pkg/apiserver/domain/model"
 	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+	"kubemin-cli/pkg/apiserver/utils/bcode"
 )
 
 func TestGetTaskStatusIncludesAllComponents(t *testing.T) {
@@ -148,3 +150,322 @@ func (s *statusDataStore) CompareAndSwap(context.Context, datastore.Entity, stri
 }
 
 var _ datastore.DataStore = (*statusDataStore)(nil)
+
+func TestGetTaskStatusReportsStepGraph(t *testing.T) {
+	steps := &model.WorkflowSteps{
+		Steps: []*model.WorkflowStep{
+			{Name: "config", Properties: []model.Policies{{Policies: []string{"config"}}}},
+			{Name: "api", Properties: []model.Policies{{Policies: []string{"api"}}}, DependsOn: []string{"config"}},
+			{Name: "worker", Properties: []model.Policies{{Policies: []string{"worker"}}}, DependsOn: []string{"config"}},
+		},
+	}
+	stepsStruct, err := model.NewJSONStructByStruct(steps)
+	require.NoError(t, err)
+
+	store := &statusDataStore{
+		task: &model.WorkflowQueue{TaskID: "task-1", WorkflowID: "wf-1", Status: config.StatusRunning},
+		workflow: &model.Workflow{
+			ID:    "wf-1",
+			Steps: stepsStruct,
+		},
+		jobs: []*model.JobInfo{
+			{TaskID: "task-1", ServiceName: "config", Status: string(config.StatusCompleted)},
+			{TaskID: "task-1", ServiceName: "api", Status: string(config.StatusRunning)},
+		},
+	}
+
+	svc := &workflowServiceImpl{Store: store}
+	resp, err := svc.GetTaskStatus(context.Background(), "task-1")
+	require.NoError(t, err)
+	require.Len(t, resp.Steps, 3)
+
+	require.Equal(t, "config", resp.Steps[0].Name)
+	require.Empty(t, resp.Steps[0].DependsOn)
+	require.Equal(t, string(config.StatusCompleted), resp.Steps[0].Status)
+
+	require.Equal(t, []string{"config"}, resp.Steps[1].DependsOn)
+	require.Equal(t, string(config.StatusRunning), resp.Steps[1].Status)
+
+	require.Equal(t, []string{"config"}, resp.Steps[2].DependsOn)
+	require.Equal(t, string(config.StatusWaiting), resp.Steps[2].Status)
+}
+
+func TestGetTaskStatusSequentialStepsChainImplicitly(t *testing.T) {
+	steps := &model.WorkflowSteps{
+		Steps: []*model.WorkflowStep{
+			{Name: "web"},
+			{Name: "db"},
+		},
+	}
+	stepsStruct, err := model.NewJSONStructByStruct(steps)
+	require.NoError(t, err)
+
+	store := &statusDataStore{
+		task:     &model.WorkflowQueue{TaskID: "task-1", WorkflowID: "wf-1", Status: config.StatusWaiting},
+		workflow: &model.Workflow{ID: "wf-1", Steps: stepsStruct},
+	}
+
+	svc := &workflowServiceImpl{Store: store}
+	resp, err := svc.GetTaskStatus(context.Background(), "task-1")
+	require.NoError(t, err)
+	require.Len(t, resp.Steps, 2)
+	require.Empty(t, resp.Steps[0].DependsOn)
+	require.Equal(t, []string{"web"}, resp.Steps[1].DependsOn)
+}
+
+func TestGetTaskStatusReportsSkippedSteps(t *testing.T) {
+	steps := &model.WorkflowSteps{
+		Steps: []*model.WorkflowStep{
+			{Name: "migrate", Condition: "inputs.migrate"},
+			{Name: "web"},
+		},
+	}
+	stepsStruct, err := model.NewJSONStructByStruct(steps)
+	require.NoError(t, err)
+
+	store := &statusDataStore{
+		task: &model.WorkflowQueue{
+			TaskID:      "task-1",
+			WorkflowID:  "wf-1",
+			Status:      config.StatusCompleted,
+			StepResults: map[string]config.Status{"migrate": config.StatusSkipped, "web": config.StatusCompleted},
+		},
+		workflow: &model.Workflow{ID: "wf-1", Steps: stepsStruct},
+		jobs: []*model.JobInfo{
+			{TaskID: "task-1", ServiceName: "migrate", Status: string(config.StatusSkipped)},
+			{TaskID: "task-1", ServiceName: "web", Status: string(config.StatusCompleted)},
+		},
+	}
+
+	svc := &workflowServiceImpl{Store: store}
+	resp, err := svc.GetTaskStatus(context.Background(), "task-1")
+	require.NoError(t, err)
+	require.Len(t, resp.Steps, 2)
+	require.Equal(t, string(config.StatusSkipped), resp.Steps[0].Status)
+	require.Equal(t, "inputs.migrate", resp.Steps[0].Condition)
+	require.Equal(t, string(config.StatusCompleted), resp.Steps[1].Status)
+}
+
+func TestGetTaskStatusReportsBuiltinSteps(t *testing.T) {
+	steps := &model.WorkflowSteps{
+		Steps: []*model.WorkflowStep{
+			{Name: "web"},
+			{Name: "health", WorkflowType: config.JobHTTPCheck, Spec: &model.StepSpec{URL: "http://web/healthz"}},
+			{Name: "announce", WorkflowType: config.JobNotify, Spec: &model.StepSpec{URL: "http://hooks/x"}},
+		},
+	}
+	stepsStruct, err := model.NewJSONStructByStruct(steps)
+	require.NoError(t, err)
+
+	store := &statusDataStore{
+		task:     &model.WorkflowQueue{TaskID: "task-1", WorkflowID: "wf-1", Status: config.StatusRunning},
+		workflow: &model.Workflow{ID: "wf-1", Steps: stepsStruct},
+		jobs: []*model.JobInfo{
+			{TaskID: "task-1", ServiceName: "web", Status: string(config.StatusCompleted)},
+			{TaskID: "task-1", ServiceName: "health", Type: string(config.JobHTTPCheck), Status: string(config.StatusRunning)},
+		},
+	}
+
+	svc := &workflowServiceImpl{Store: store}
+	resp, err := svc.GetTaskStatus(context.Background(), "task-1")
+	require.NoError(t, err)
+	require.Len(t, resp.Steps, 3)
+	require.Equal(t, string(config.StatusRunning), resp.Steps[1].Status)
+	require.Empty(t, resp.Steps[1].Components)
+	require.Equal(t, string(config.StatusWaiting), resp.Steps[2].Status)
+	// Built-in steps that have not run yet are not listed as components.
+	require.Len(t, resp.Components, 2)
+}
+
+func TestGetTaskStatusReportsTimeout(t *testing.T) {
+	steps := &model.WorkflowSteps{Steps: []*model.WorkflowStep{{Name: "web", TimeoutSeconds: 60}}}
+	stepsStruct, err := model.NewJSONStructByStruct(steps)
+	require.NoError(t, err)
+
+	deadline := time.Unix(1700000000, 0)
+	store := &statusDataStore{
+		task: &model.WorkflowQueue{
+			TaskID:      "task-1",
+			WorkflowID:  "wf-1",
+			Status:      config.StatusTimeout,
+			Deadline:    &deadline,
+			TimeoutStep: "web",
+			StepResults: map[string]config.Status{"web": config.StatusTimeout},
+		},
+		workflow: &model.Workflow{ID: "wf-1", Steps: stepsStruct},
+	}
+
+	svc := &workflowServiceImpl{Store: store}
+	resp, err := svc.GetTaskStatus(context.Background(), "task-1")
+	require.NoError(t, err)
+	require.Equal(t, int64(1700000000), resp.Deadline)
+	require.Equal(t, "web", resp.TimeoutStep)
+	require.Len(t, resp.Steps, 1)
+	require.Equal(t, string(config.StatusTimeout), resp.Steps[0].Status)
+}
+
+func TestGetTaskStatusUsesLatestJobAttempt(t *testing.T) {
+	steps := &model.WorkflowSteps{Steps: []*model.WorkflowStep{{Name: "web"}}}
+	stepsStruct, err := model.NewJSONStructByStruct(steps)
+	require.NoError(t, err)
+
+	store := &statusDataStore{
+		task:     &model.WorkflowQueue{TaskID: "task-1", WorkflowID: "wf-1", Status: config.StatusCompleted},
+		workflow: &model.Workflow{ID: "wf-1", Steps: stepsStruct},
+		jobs: []*model.JobInfo{
+			{TaskID: "task-1", ServiceName: "web", Type: string(config.JobDeploy), Status: string(config.StatusFailed), Error: "conflict", Attempt: 1},
+			{TaskID: "task-1", ServiceName: "web", Type: string(config.JobDeploy), Status: string(config.StatusCompleted), Attempt: 2},
+			{TaskID: "task-1", ServiceName: "web", Type: string(config.JobDeployService), Status: string(config.StatusCompleted), Attempt: 1},
+		},
+	}
+
+	svc := &workflowServiceImpl{Store: store}
+	resp, err := svc.GetTaskStatus(context.Background(), "task-1")
+	require.NoError(t, err)
+	require.Len(t, resp.Components, 1)
+	require.Equal(t, string(config.StatusCompleted), resp.Components[0].Status)
+	require.Empty(t, resp.Components[0].Error)
+	require.Equal(t, 2, resp.Components[0].Attempts)
+}
+
+func TestSelectPendingApproval(t *testing.T) {
+	_, err := selectPendingApproval(nil, "")
+	require.ErrorIs(t, err, bcode.ErrWorkflowApprovalNotPending)
+
+	gate := &model.WorkflowApproval{ID: 1, StepName: "gate"}
+	selected, err := selectPendingApproval([]*model.WorkflowApproval{gate}, "")
+	require.NoError(t, err)
+	require.Same(t, gate, selected)
+
+	prod := &model.WorkflowApproval{ID: 2, StepName: "prod-gate"}
+	_, err = selectPendingApproval([]*model.WorkflowApproval{gate, prod}, "")
+	require.ErrorIs(t, err, bcode.ErrWorkflowApprovalStepRequired)
+	selected, err = selectPendingApproval([]*model.WorkflowApproval{gate, prod}, "Prod-Gate")
+	require.NoError(t, err)
+	require.Same(t, prod, selected)
+	_, err = selectPendingApproval([]*model.WorkflowApproval{gate, prod}, "missing")
+	require.ErrorIs(t, err, bcode.ErrWorkflowApprovalNotPending)
+}
+
+// queueDataStore serves a fixed set of workflow tasks for the waiting and active task queries.
+type queueDataStore struct {
+	statusDataStore
+	queue []*model.WorkflowQueue
+}
+
+func (s *queueDataStore) List(ctx context.Context, query datastore.Entity, opts *datastore.ListOptions) ([]datastore.Entity, error) {
+	q, ok := query.(*model.WorkflowQueue)
+	if !ok {
+		return s.statusDataStore.List(ctx, query, opts)
+	}
+	var out []datastore.Entity
+	for _, task := range s.queue {
+		if q.Status == "" || task.Status == q.Status {
+			out = append(out, task)
+		}
+	}
+	return out, nil
+}
+
+func TestGetTaskStatusReportsQueuePosition(t *testing.T) {
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	newTask := func(id, project string, priority int, status config.Status, offset time.Duration) *model.WorkflowQueue {
+		task := &model.WorkflowQueue{TaskID: id, ProjectID: project, Priority: priority, Status: status}
+		task.CreateTime = base.Add(offset)
+		return task
+	}
+	target := newTask("task-mine", "quiet", 0, config.StatusWaiting, 3*time.Second)
+	store := &queueDataStore{
+		statusDataStore: statusDataStore{task: target},
+		queue: []*model.WorkflowQueue{
+			newTask("busy-running", "busy", 0, config.StatusRunning, 0),
+			newTask("busy-1", "busy", 0, config.StatusWaiting, time.Second),
+			newTask("busy-2", "busy", 0, config.StatusWaiting, 2*time.Second),
+			target,
+			newTask("urgent", "other", 50, config.StatusWaiting, 4*time.Second),
+		},
+	}
+
+	resp, err := (&workflowServiceImpl{Store: store}).GetTaskStatus(context.Background(), "task-mine")
+	require.NoError(t, err)
+	// The urgent task goes first and busy already runs a task, so the quiet project is next.
+	require.Equal(t, 2, resp.QueuePosition)
+	require.Zero(t, resp.Priority)
+}
+
+func TestEnqueueWorkflowTaskPriority(t *testing.T) {
+	steps, err := model.NewJSONStructByStruct(&model.WorkflowSteps{Steps: []*model.WorkflowStep{{Name: "web"}}})
+	require.NoError(t, err)
+	store := &scheduleDataStore{}
+	svc := &workflowServiceImpl{Store: store}
+	workflow := &model.Workflow{ID: "wf-1", AppID: "app-1", ProjectID: "p1", Steps: steps}
+
+	task, err := svc.newWorkflowTask(context.Background(), workflow, nil, 30)
+	require.NoError(t, err)
+	_, err = svc.enqueueWorkflowTask(context.Background(), task)
+	require.NoError(t, err)
+	require.Len(t, store.created, 1)
+	require.Equal(t, 30, store.created[0].Priority)
+
+	_, err = svc.newWorkflowTask(context.Background(), workflow, nil, config.MaxWorkflowTaskPriority+1)
+	require.ErrorIs(t, err, bcode.ErrWorkflowConfig)
+}
+
+type diagnosticsDataStore struct {
+	statusDataStore
+	diagnostics []*model.JobDiagnostics
+}
+
+func (s *diagnosticsDataStore) List(ctx context.Context, query datastore.Entity, opts *datastore.ListOptions) ([]datastore.Entity, error) {
+	if diagQuery, ok := query.(*model.JobDiagnostics); ok {
+		var out []datastore.Entity
+		for _, diag := range s.diagnostics {
+			if diag.TaskID == diagQuery.TaskID {
+				out = append(out, diag)
+			}
+		}
+		return out, nil
+	}
+	return s.statusDataStore.List(ctx, query, opts)
+}
+
+func TestGetTaskStatusAttachesDiagnosticsOfLatestAttempt(t *testing.T) {
+	store := &diagnosticsDataStore{
+		statusDataStore: statusDataStore{
+			task: &model.WorkflowQueue{TaskID: "task-1", WorkflowID: "wf-1", Status: config.StatusFailed},
+			jobs: []*model.JobInfo{
+				{ID: 1, TaskID: "task-1", ServiceName: "web", Type: string(config.JobDeploy), Status: string(config.StatusTimeout), Attempt: 1},
+				{ID: 2, TaskID: "task-1", ServiceName: "web", Type: string(config.JobDeploy), Status: string(config.StatusTimeout), Attempt: 2},
+			},
+		},
+		diagnostics: []*model.JobDiagnostics{
+			{JobInfoID: 1, TaskID: "task-1", JobName: "web", Error: "first attempt"},
+			{
+				JobInfoID: 2,
+				TaskID:    "task-1",
+				JobName:   "web",
+				Kind:      config.ResourceDeployment,
+				Name:      "web-app-1",
+				Pods: []model.PodDiagnostic{{
+					Name:       "web-0",
+					Phase:      "Running",
+					Containers: []model.ContainerDiagnostic{{Name: "web", WaitingReason: "CrashLoopBackOff", Logs: "panic: boom"}},
+				}},
+				Events: []model.EventDiagnostic{{Object: "Pod/web-0", Type: "Warning", Reason: "BackOff"}},
+			},
+		},
+	}
+
+	svc := &workflowServiceImpl{Store: store}
+	resp, err := svc.GetTaskStatus(context.Background(), "task-1")
+	require.NoError(t, err)
+	require.Len(t, resp.Components, 1)
+
+	diagnostics := resp.Components[0].Diagnostics
+	require.Len(t, diagnostics, 1)
+	require.Equal(t, string(config.ResourceDeployment), diagnostics[0].Kind)
+	require.Equal(t, "web-app-1", diagnostics[0].Name)
+	require.Equal(t, "CrashLoopBackOff", diagnostics[0].Pods[0].Containers[0].WaitingReason)
+	require.Equal(t, "panic: boom", diagnostics[0].Pods[0].Containers[0].Logs)
+	require.Equal(t, "BackOff", diagnostics[0].Events[0].Reason)
+}
@@ -7,8 +7,11 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
 	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
 	apis "kubemin-cli/pkg/apiserver/interfaces/api/dto/v1"
+	"kubemin-cli/pkg/apiserver/utils/bcode"
 )
 
 type failingDataStore struct {
@@ -53,3 +56,111 @@ func TestCreateWorkflowTaskPropagatesStoreError(t *testing.T) {
 // compile-time check that failingDataStore satisfies the interface
 var _ datastore.DataStore = (*failingDataStore)(nil)
 var _ WorkflowService = (*workflowServiceImpl)(nil)
+
+// appTaskDataStore serves one application together with its unfinished workflow tasks.
+type appTaskDataStore struct {
+	queueDataStore
+	app *model.Applications
+}
+
+func (s *appTaskDataStore) Get(ctx context.Context, entity datastore.Entity) error {
+	if app, ok := entity.(*model.Applications); ok && s.app != nil && app.ID == s.app.ID {
+		*app = *s.app
+		return nil
+	}
+	return s.queueDataStore.Get(ctx, entity)
+}
+
+// newRejectFixture serves an application under the reject policy with one running task.
+func newRejectFixture(t *testing.T) *appTaskDataStore {
+	t.Helper()
+	steps, err := model.NewJSONStructByStruct(&model.WorkflowSteps{Steps: []*model.WorkflowStep{{Name: "web"}}})
+	require.NoError(t, err)
+	return &appTaskDataStore{
+		queueDataStore: queueDataStore{
+			statusDataStore: statusDataStore{workflow: &model.Workflow{ID: "wf-1", AppID: "app-1", Steps: steps}},
+			queue:           []*model.WorkflowQueue{{TaskID: "task-running", AppID: "app-1", Status: config.StatusRunning}},
+		},
+		app: &model.Applications{ID: "app-1", ConcurrencyPolicy: config.AppConcurrencyReject},
+	}
+}
+
+func requireBlockedBy(t *testing.T, err error, taskID string) {
+	t.Helper()
+	require.ErrorIs(t, err, bcode.ErrWorkflowTaskConflict)
+	var conflict *bcode.Bcode
+	require.True(t, errors.As(err, &conflict))
+	require.Equal(t, taskID, conflict.Detail["blocking_task_id"])
+	require.Nil(t, bcode.ErrWorkflowTaskConflict.Detail)
+}
+
+func TestExecWorkflowTaskForAppRejectsWhileTaskActive(t *testing.T) {
+	store := newRejectFixture(t)
+	svc := &workflowServiceImpl{Store: store}
+
+	_, err := svc.ExecWorkflowTaskForApp(context.Background(), "app-1", "wf-1", nil, 0)
+	requireBlockedBy(t, err, "task-running")
+
+	// The default queue policy accepts the request and lets the dispatcher serialize it.
+	store.app.ConcurrencyPolicy = ""
+	resp, err := svc.ExecWorkflowTaskForApp(context.Background(), "app-1", "wf-1", nil, 0)
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.TaskID)
+}
+
+func TestExecWorkflowTaskRejectsWhileTaskActive(t *testing.T) {
+	svc := &workflowServiceImpl{Store: newRejectFixture(t)}
+
+	_, err := svc.ExecWorkflowTask(context.Background(), "wf-1")
+	requireBlockedBy(t, err, "task-running")
+}
+
+func TestRetryWorkflowTaskRejectsWhileTaskActive(t *testing.T) {
+	store := newRejectFixture(t)
+	store.task = &model.WorkflowQueue{TaskID: "task-failed", AppID: "app-1", WorkflowID: "wf-1", Status: config.StatusFailed}
+	svc := &workflowServiceImpl{Store: store}
+
+	_, err := svc.RetryWorkflowTaskForApp(context.Background(), "app-1", "alice", "task-failed")
+	requireBlockedBy(t, err, "task-running")
+}
+
+func TestRunScheduleSkipsWhileAppTaskActive(t *testing.T) {
+	svc := &workflowServiceImpl{Store: newRejectFixture(t)}
+	schedule := &model.WorkflowSchedule{ID: "sched-1", Name: "nightly", AppID: "app-1", WorkflowID: "wf-1"}
+
+	outcome := svc.runSchedule(context.Background(), schedule)
+	require.Equal(t, "skipped: application task task-running is still active", outcome)
+	require.Empty(t, schedule.LastTaskID)
+}
+
+// planDataStore records the execution plans deleted from the store.
+type planDataStore struct {
+	statusDataStore
+	deletedPlans []string
+}
+
+func (s *planDataStore) Delete(_ context.Context, entity datastore.Entity) error {
+	if plan, ok := entity.(*model.ExecutionPlan); ok {
+		s.deletedPlans = append(s.deletedPlans, plan.TaskID)
+	}
+	return nil
+}
+
+func TestCancelSuspendedTaskDeletesExecutionPlan(t *testing.T) {
+	for _, status := range []config.Status{config.StatusPause, config.StatusWaitingApprove} {
+		store := &planDataStore{statusDataStore: statusDataStore{
+			task: &model.WorkflowQueue{TaskID: "task-1", AppID: "app-1", Status: status},
+		}}
+		svc := &workflowServiceImpl{Store: store}
+
+		require.NoError(t, svc.CancelWorkflowTaskForApp(context.Background(), "app-1", "alice", "task-1", ""))
+		require.Equal(t, []string{"task-1"}, store.deletedPlans, status)
+	}
+
+	// A running task's controller deletes the plan itself once it stops.
+	store := &planDataStore{statusDataStore: statusDataStore{
+		task: &model.WorkflowQueue{TaskID: "task-1", AppID: "app-1", Status: config.StatusRunning},
+	}}
+	require.NoError(t, (&workflowServiceImpl{Store: store}).CancelWorkflowTask(context.Background(), "alice", "task-1", ""))
+	require.Empty(t, store.deletedPlans)
+}
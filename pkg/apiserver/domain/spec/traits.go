@@ -92,6 +92,30 @@ type Properties struct {
 	Secret  map[string]string `json:"secret"`
 	Command []string          `json:"command"`
 	Labels  map[string]string `json:"labels"`
+	// Job 批处理组件（job、cronjob）的运行参数，其他组件类型忽略
+	Job *JobProperties `json:"job,omitempty"`
+}
+
+// JobProperties configures the batch workloads generated for job and cronjob components.
+type JobProperties struct {
+	// BackoffLimit 失败重试次数，为空时使用 Kubernetes 默认值（6）
+	BackoffLimit *int32 `json:"backoff_limit,omitempty"`
+	// ActiveDeadlineSeconds 单次执行的时限
+	ActiveDeadlineSeconds *int64 `json:"active_deadline_seconds,omitempty"`
+	// TTLSecondsAfterFinished 执行结束后保留 Job 的时长
+	TTLSecondsAfterFinished *int32 `json:"ttl_seconds_after_finished,omitempty"`
+	// RestartPolicy Never 或 OnFailure，默认 Never
+	RestartPolicy string `json:"restart_policy,omitempty"`
+	// Schedule cronjob 的五段式 cron 表达式
+	Schedule string `json:"schedule,omitempty"`
+	// TimeZone cronjob 的时区，为空时使用 kube-controller-manager 的时区
+	TimeZone string `json:"time_zone,omitempty"`
+	// ConcurrencyPolicy cronjob 的并发策略：Allow、Forbid、Replace，默认 Forbid
+	ConcurrencyPolicy string `json:"concurrency_policy,omitempty"`
+	Suspend           bool   `json:"suspend,omitempty"`
+	// SuccessfulJobsHistoryLimit / FailedJobsHistoryLimit cronjob 保留的历史 Job 数
+	SuccessfulJobsHistoryLimit *int32 `json:"successful_jobs_history_limit,omitempty"`
+	FailedJobsHistoryLimit     *int32 `json:"failed_jobs_history_limit,omitempty"`
 }
 
 type Ports struct {
@@ -0,0 +1,115 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/domain/repository"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+	"kubemin-cli/pkg/apiserver/infrastructure/locker"
+	"kubemin-cli/pkg/apiserver/utils/cache"
+)
+
+const appLockPrefix = "kubemin-app"
+
+// admitTask enforces the application concurrency policy before a waiting task is claimed.
+// The check and the claim run under a per-application lock so that replicas never claim
+// two tasks of the same application at once. The returned release must be called after the claim.
+func (w *Workflow) admitTask(ctx context.Context, task *model.WorkflowQueue) (func(), bool) {
+	if task.AppID == "" {
+		return func() {}, true
+	}
+	mutex := w.appLockerInstance().NewMutex(task.AppID, locker.WithTTL(config.TaskStateTransitionTimeout*2))
+	if err := mutex.TryLock(ctx); err != nil {
+		klog.V(4).Infof("application %s is locked by another dispatcher, task %s keeps waiting: %v", task.AppID, task.TaskID, err)
+		return nil, false
+	}
+	release := func() {
+		unlockCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := mutex.Unlock(unlockCtx); err != nil {
+			klog.Warningf("failed to release application lock %s: %v", mutex.Key(), err)
+		}
+	}
+	if !w.applyAppConcurrency(ctx, task) {
+		release()
+		return nil, false
+	}
+	return release, true
+}
+
+// applyAppConcurrency reports whether task may be claimed now. Under queue and reject the task
+// waits while another task of the application is dispatched; under supersede the newest task
+// wins and every older unfinished task is cancelled.
+func (w *Workflow) applyAppConcurrency(ctx context.Context, task *model.WorkflowQueue) bool {
+	policy := config.AppConcurrencyQueue
+	app, err := repository.ApplicationByID(ctx, w.Store, task.AppID)
+	switch {
+	case err == nil:
+		policy = config.ParseAppConcurrencyPolicy(string(app.ConcurrencyPolicy))
+	case !errors.Is(err, datastore.ErrRecordNotExist):
+		klog.Errorf("load application %s of task %s failed: %v", task.AppID, task.TaskID, err)
+		return false
+	}
+	active, err := repository.ActiveAppTasks(ctx, w.Store, task.AppID)
+	if err != nil {
+		klog.Errorf("list active tasks of application %s failed: %v", task.AppID, err)
+		return false
+	}
+
+	var older []*model.WorkflowQueue
+	for _, other := range active {
+		if other.TaskID == task.TaskID {
+			continue
+		}
+		if policy == config.AppConcurrencySupersede {
+			if task.SubmittedBefore(other) {
+				w.supersedeTask(ctx, task, other.TaskID)
+				return false
+			}
+			older = append(older, other)
+			continue
+		}
+		if other.Status != config.StatusWaiting {
+			klog.V(4).Infof("task %s waits for task %s (%s) of application %s", task.TaskID, other.TaskID, other.Status, task.AppID)
+			return false
+		}
+	}
+	for _, other := range older {
+		w.supersedeTask(ctx, other, task.TaskID)
+	}
+	return true
+}
+
+func (w *Workflow) supersedeTask(ctx context.Context, task *model.WorkflowQueue, newer string) {
+	reason := fmt.Sprintf("superseded by task %s", newer)
+	if err := w.WorkflowService.CancelWorkflowTask(ctx, config.DefaultTaskRevoker, task.TaskID, reason); err != nil {
+		klog.Errorf("cancel superseded task %s failed: %v", task.TaskID, err)
+	}
+}
+
+func (w *Workflow) appLockerInstance() locker.Locker {
+	w.appLockerOnce.Do(func() {
+		if w.appLocker == nil {
+			w.appLocker = newAppLocker()
+		}
+	})
+	return w.appLocker
+}
+
+func newAppLocker() locker.Locker {
+	if redisClient := cache.GetGlobalRedisClient(); redisClient != nil {
+		redisLocker, err := locker.New(locker.Config{Type: locker.TypeRedis, RedisClient: redisClient, Prefix: appLockPrefix})
+		if err == nil {
+			return redisLocker
+		}
+		klog.Warningf("failed to init redis application locker, falling back to memory: %v", err)
+	}
+	return locker.NewMemoryLocker(appLockPrefix)
+}
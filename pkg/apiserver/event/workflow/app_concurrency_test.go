@@ -0,0 +1,114 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+	"kubemin-cli/pkg/apiserver/infrastructure/locker"
+)
+
+// appConcurrencyStore serves one application and its unfinished tasks.
+type appConcurrencyStore struct {
+	workflowAckTestStore
+	app   *model.Applications
+	tasks []*model.WorkflowQueue
+}
+
+func (s *appConcurrencyStore) Get(ctx context.Context, entity datastore.Entity) error {
+	if app, ok := entity.(*model.Applications); ok {
+		*app = *s.app
+		return nil
+	}
+	return s.workflowAckTestStore.Get(ctx, entity)
+}
+
+func (s *appConcurrencyStore) List(ctx context.Context, query datastore.Entity, opts *datastore.ListOptions) ([]datastore.Entity, error) {
+	if _, ok := query.(*model.WorkflowQueue); ok {
+		out := make([]datastore.Entity, 0, len(s.tasks))
+		for _, task := range s.tasks {
+			out = append(out, task)
+		}
+		return out, nil
+	}
+	return s.workflowAckTestStore.List(ctx, query, opts)
+}
+
+func newAppConcurrencyFixture(policy config.AppConcurrencyPolicy) (*Workflow, *stubWorkflowService, []*model.WorkflowQueue) {
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	tasks := []*model.WorkflowQueue{
+		{TaskID: "task-old", AppID: "app-1", Status: config.StatusRunning},
+		{TaskID: "task-mid", AppID: "app-1", Status: config.StatusWaiting},
+		{TaskID: "task-new", AppID: "app-1", Status: config.StatusWaiting},
+	}
+	for i, task := range tasks {
+		task.CreateTime = base.Add(time.Duration(i) * time.Minute)
+		task.SubmitTime = task.CreateTime.UnixNano()
+	}
+	svc := &stubWorkflowService{}
+	w := &Workflow{
+		Store:           &appConcurrencyStore{app: &model.Applications{ID: "app-1", ConcurrencyPolicy: policy}, tasks: tasks},
+		WorkflowService: svc,
+		appLocker:       locker.NewMemoryLocker(appLockPrefix),
+	}
+	return w, svc, tasks
+}
+
+func TestAdmitTaskQueuesBehindActiveTask(t *testing.T) {
+	w, svc, tasks := newAppConcurrencyFixture(config.AppConcurrencyQueue)
+	_, admitted := w.admitTask(context.Background(), tasks[1])
+	require.False(t, admitted)
+	require.Empty(t, svc.cancelled)
+
+	// Once the running task is gone, the waiting tasks are claimed one at a time.
+	tasks[0].Status = config.StatusCompleted
+	w.Store.(*appConcurrencyStore).tasks = tasks[1:]
+	release, admitted := w.admitTask(context.Background(), tasks[1])
+	require.True(t, admitted)
+	release()
+}
+
+func TestAdmitTaskSupersedesOlderTasks(t *testing.T) {
+	w, svc, tasks := newAppConcurrencyFixture(config.AppConcurrencySupersede)
+
+	// An older task is cancelled instead of being claimed when a newer one is waiting.
+	_, admitted := w.admitTask(context.Background(), tasks[1])
+	require.False(t, admitted)
+	require.Equal(t, []string{"task-mid"}, svc.cancelled)
+
+	svc.cancelled = nil
+	release, admitted := w.admitTask(context.Background(), tasks[2])
+	require.True(t, admitted)
+	release()
+	require.Equal(t, []string{"task-old", "task-mid"}, svc.cancelled)
+}
+
+func TestAdmitTaskSupersedesBySubmitTime(t *testing.T) {
+	w, svc, tasks := newAppConcurrencyFixture(config.AppConcurrencySupersede)
+	// The running task's CreateTime is refreshed when it starts, after the others were submitted.
+	tasks[0].CreateTime = tasks[2].CreateTime.Add(time.Minute)
+
+	release, admitted := w.admitTask(context.Background(), tasks[2])
+	require.True(t, admitted)
+	release()
+	require.Equal(t, []string{"task-old", "task-mid"}, svc.cancelled)
+}
+
+func TestAdmitTaskWaitsForApplicationLock(t *testing.T) {
+	w, _, tasks := newAppConcurrencyFixture(config.AppConcurrencySupersede)
+	held := w.appLocker.NewMutex("app-1")
+	require.NoError(t, held.TryLock(context.Background()))
+
+	_, admitted := w.admitTask(context.Background(), tasks[2])
+	require.False(t, admitted)
+
+	require.NoError(t, held.Unlock(context.Background()))
+	release, admitted := w.admitTask(context.Background(), tasks[2])
+	require.True(t, admitted)
+	release()
+}
@@ -0,0 +1,151 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/domain/repository"
+	"kubemin-cli/pkg/apiserver/event/workflow/job"
+)
+
+// errWorkflowSuspended is returned when the task reached an approval gate that has not
+// been decided yet. It stops scheduling new steps without being treated as a failure.
+var errWorkflowSuspended = errors.New("workflow suspended waiting for approval")
+
+// executionKey identifies a step execution inside a task so completed executions
+// can be skipped when a suspended task is dispatched again.
+func executionKey(exec StepExecution) string {
+	if exec.Step == "" || strings.EqualFold(exec.Step, exec.Name) {
+		return strings.ToLower(exec.Name)
+	}
+	return strings.ToLower(exec.Step + "/" + exec.Name)
+}
+
+// runTrackedStep runs one step execution unless an earlier dispatch of the task already
+// completed it or its condition is false, and records the result on the task afterwards.
+func (w *WorkflowCtl) runTrackedStep(ctx context.Context, exec StepExecution, seqLimit int) error {
+	key := executionKey(exec)
+	if w.isStepCompleted(key) {
+		klog.FromContext(ctx).Info("Skipping workflow step completed before suspension", "step", exec.Step, "execution", exec.Name)
+		return nil
+	}
+	run, err := w.evaluateStepCondition(ctx, exec)
+	if err != nil {
+		return err
+	}
+	if !run {
+		w.skipStepExecution(ctx, exec)
+		w.markStepCompleted(key)
+		return nil
+	}
+	stepCtx, cancel := w.withStepDeadline(ctx, exec)
+	defer cancel()
+	if err = stepCtx.Err(); err == nil {
+		if exec.Approval {
+			err = w.checkApproval(stepCtx, exec)
+		} else {
+			err = w.runStepExecution(stepCtx, exec, seqLimit)
+		}
+	}
+	if err != nil {
+		return w.stepTimedOut(stepCtx, exec, err)
+	}
+	w.recordStepResult(exec, config.StatusCompleted)
+	w.markStepCompleted(key)
+	return nil
+}
+
+func (w *WorkflowCtl) isStepCompleted(key string) bool {
+	task := w.snapshotTask()
+	for _, done := range task.CompletedSteps {
+		if done == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *WorkflowCtl) markStepCompleted(key string) {
+	w.mutateTask(func(task *model.WorkflowQueue) {
+		task.CompletedSteps = append(task.CompletedSteps, key)
+	})
+	if w.ack != nil {
+		w.ack()
+	}
+}
+
+// checkApproval 检查审批步骤：首次到达时创建待审批记录并挂起任务；已批准则继续；已拒绝则以 reject 结束任务
+func (w *WorkflowCtl) checkApproval(ctx context.Context, exec StepExecution) error {
+	logger := klog.FromContext(ctx)
+	task := w.snapshotTask()
+	approval, err := repository.LatestWorkflowApproval(ctx, w.Store, task.TaskID, exec.Step)
+	if err != nil {
+		return fmt.Errorf("load approval for step %s: %w", exec.Step, err)
+	}
+	if approval == nil {
+		approval = &model.WorkflowApproval{
+			TaskID:     task.TaskID,
+			AppID:      task.AppID,
+			WorkflowID: task.WorkflowID,
+			StepName:   exec.Step,
+			Status:     config.StatusWaitingApprove,
+		}
+		if err := w.Store.Add(ctx, approval); err != nil {
+			return fmt.Errorf("create approval for step %s: %w", exec.Step, err)
+		}
+		logger.Info("Workflow waiting for approval", "step", exec.Step)
+		return errWorkflowSuspended
+	}
+	switch approval.Status {
+	case config.StatusPassed:
+		logger.Info("Approval step passed", "step", exec.Step, "approver", approval.Approver)
+		return nil
+	case config.StatusReject:
+		return job.NewStatusError(config.StatusReject, fmt.Errorf("approval step %s rejected by %s: %s", exec.Step, approval.Approver, approval.Comment))
+	default:
+		logger.Info("Workflow still waiting for approval", "step", exec.Step)
+		return errWorkflowSuspended
+	}
+}
+
+// suspend hands the task back to the queue: the task is persisted with the given status
+// (waiting for approval or paused) and the worker returns, releasing its goroutine and
+// workflow slot. The approve, reject or resume endpoint moves the task back to waiting so
+// it is dispatched again.
+func (w *WorkflowCtl) suspend(ctx context.Context, status config.Status) {
+	logger := klog.FromContext(ctx)
+	w.setStatus(status)
+	// Completed steps were already persisted by ack as each step finished.
+	taskID := w.snapshotTask().TaskID
+	persist := repository.UpdateTaskStatus
+	if isWorkflowSuspended(status) {
+		// Time spent suspended does not count against the task deadline.
+		persist = repository.SuspendTask
+	}
+	swapped, err := persist(context.WithoutCancel(ctx), w.Store, taskID, config.StatusRunning, status)
+	if err != nil {
+		logger.Error(err, "Failed to persist workflow suspension", "status", status)
+		return
+	}
+	if !swapped {
+		logger.Info("Workflow task status changed externally before suspension", "status", status)
+	}
+}
+
+// isWorkflowSuspended reports whether the task was handed back to the queue and waits for
+// an external decision before it is dispatched again.
+func isWorkflowSuspended(status config.Status) bool {
+	return status == config.StatusWaitingApprove || status == config.StatusPause
+}
+
+// isTaskHandedBack reports whether the worker gave the task up without finishing it: the
+// task is suspended, or was put back to waiting because the worker drained.
+func isTaskHandedBack(status config.Status) bool {
+	return isWorkflowSuspended(status) || status == config.StatusWaiting
+}
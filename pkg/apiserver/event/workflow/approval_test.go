@@ -0,0 +1,117 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/event/workflow/job"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+)
+
+// approvalStore keeps approval records in memory and records task status swaps.
+type approvalStore struct {
+	fakeDataStore
+	approvals   []*model.WorkflowApproval
+	statusSwaps []config.Status
+	suspendedAt []time.Time
+}
+
+func (s *approvalStore) Add(_ context.Context, entity datastore.Entity) error {
+	approval, ok := entity.(*model.WorkflowApproval)
+	if !ok {
+		return errors.New("unexpected entity")
+	}
+	approval.ID = len(s.approvals) + 1
+	s.approvals = append(s.approvals, approval)
+	return nil
+}
+
+func (s *approvalStore) List(_ context.Context, query datastore.Entity, _ *datastore.ListOptions) ([]datastore.Entity, error) {
+	q, ok := query.(*model.WorkflowApproval)
+	if !ok {
+		return nil, nil
+	}
+	var entities []datastore.Entity
+	for _, approval := range s.approvals {
+		if approval.TaskID == q.TaskID && (q.StepName == "" || approval.StepName == q.StepName) {
+			entities = append(entities, approval)
+		}
+	}
+	return entities, nil
+}
+
+func (s *approvalStore) CompareAndSwap(_ context.Context, _ datastore.Entity, _ string, _ interface{}, updates map[string]interface{}) (bool, error) {
+	s.statusSwaps = append(s.statusSwaps, updates["status"].(config.Status))
+	if at, ok := updates["suspended_at"].(time.Time); ok {
+		s.suspendedAt = append(s.suspendedAt, at)
+	}
+	return true, nil
+}
+
+func TestRunTrackedStepSuspendsOnPendingApproval(t *testing.T) {
+	store := &approvalStore{}
+	ctl := &WorkflowCtl{
+		workflowTask: &model.WorkflowQueue{TaskID: "task-1", AppID: "app-1", WorkflowID: "wf-1", Status: config.StatusRunning},
+		Store:        store,
+	}
+	gate := StepExecution{Name: "gate", Step: "gate", Mode: config.WorkflowModeStepByStep, Approval: true}
+
+	err := ctl.runTrackedStep(context.Background(), gate, 1)
+	require.ErrorIs(t, err, errWorkflowSuspended)
+	require.Len(t, store.approvals, 1)
+	require.Equal(t, config.StatusWaitingApprove, store.approvals[0].Status)
+	require.Equal(t, "app-1", store.approvals[0].AppID)
+
+	// A second dispatch before the decision reuses the pending record.
+	err = ctl.runTrackedStep(context.Background(), gate, 1)
+	require.ErrorIs(t, err, errWorkflowSuspended)
+	require.Len(t, store.approvals, 1)
+	require.Empty(t, ctl.snapshotTask().CompletedSteps)
+
+	ctl.suspend(context.Background(), config.StatusWaitingApprove)
+	require.Equal(t, []config.Status{config.StatusWaitingApprove}, store.statusSwaps)
+	require.Len(t, store.suspendedAt, 1, "suspension time is recorded so the deadline can be extended")
+	require.Equal(t, config.StatusWaitingApprove, ctl.snapshotTask().Status)
+}
+
+func TestRunTrackedStepResumesAfterDecision(t *testing.T) {
+	store := &approvalStore{approvals: []*model.WorkflowApproval{
+		{ID: 1, TaskID: "task-1", StepName: "gate", Status: config.StatusPassed, Approver: "alice"},
+		{ID: 2, TaskID: "task-2", StepName: "gate", Status: config.StatusReject, Approver: "bob"},
+	}}
+	gate := StepExecution{Name: "gate", Step: "gate", Mode: config.WorkflowModeStepByStep, Approval: true}
+
+	approved := &WorkflowCtl{workflowTask: &model.WorkflowQueue{TaskID: "task-1", Status: config.StatusRunning}, Store: store}
+	require.NoError(t, approved.runTrackedStep(context.Background(), gate, 1))
+	require.Equal(t, []string{"gate"}, approved.snapshotTask().CompletedSteps)
+
+	rejected := &WorkflowCtl{workflowTask: &model.WorkflowQueue{TaskID: "task-2", Status: config.StatusRunning}, Store: store}
+	err := rejected.runTrackedStep(context.Background(), gate, 1)
+	var statusErr *job.StatusError
+	require.True(t, errors.As(err, &statusErr))
+	require.Equal(t, config.StatusReject, statusErr.Status)
+}
+
+func TestRunTrackedStepSkipsCompletedSteps(t *testing.T) {
+	ctl := &WorkflowCtl{workflowTask: &model.WorkflowQueue{
+		TaskID:         "task-1",
+		Status:         config.StatusRunning,
+		CompletedSteps: []string{"gate", "backend/api"},
+	}}
+
+	// Neither execution touches the store, which is nil here.
+	require.NoError(t, ctl.runTrackedStep(context.Background(), StepExecution{Name: "gate", Step: "gate", Approval: true}, 1))
+	require.NoError(t, ctl.runTrackedStep(context.Background(), StepExecution{Name: "api", Step: "backend"}, 1))
+}
+
+func TestExecutionKey(t *testing.T) {
+	require.Equal(t, "gate", executionKey(StepExecution{Name: "Gate", Step: "gate"}))
+	require.Equal(t, "web", executionKey(StepExecution{Name: "web"}))
+	require.Equal(t, "backend/api", executionKey(StepExecution{Name: "api", Step: "Backend"}))
+}
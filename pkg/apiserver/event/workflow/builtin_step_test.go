@@ -0,0 +1,45 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+func TestGenerateJobTasksBuildsBuiltinSteps(t *testing.T) {
+	steps := &model.WorkflowSteps{
+		Steps: []*model.WorkflowStep{
+			{Name: "settle", WorkflowType: config.JobWait, Spec: &model.StepSpec{DurationSeconds: 30}},
+			{Name: "health", WorkflowType: config.JobHTTPCheck, TimeoutSeconds: 120, Spec: &model.StepSpec{URL: "http://api/healthz"}},
+			{Name: "announce", WorkflowType: config.JobNotify, DependsOn: []string{"health"}, Spec: &model.StepSpec{URL: "http://hooks/x"}},
+		},
+	}
+	stepsJSON, err := model.NewJSONStructByStruct(steps)
+	require.NoError(t, err)
+	store := &fakeDataStore{workflow: &model.Workflow{ID: "wf-1", Steps: stepsJSON}}
+	task := &model.WorkflowQueue{TaskID: "task-1", WorkflowID: "wf-1", AppID: "app-1", WorkflowName: "test-workflow"}
+
+	executions := GenerateJobTasks(context.Background(), task, store, int64(config.DefaultJobTaskTimeout))
+	require.Len(t, executions, 3)
+
+	wait := executions[0].Jobs[config.JobPriorityNormal]
+	require.Len(t, wait, 1)
+	require.Equal(t, "settle", wait[0].Name)
+	require.Equal(t, string(config.JobWait), wait[0].JobType)
+	require.Equal(t, &model.StepSpec{DurationSeconds: 30}, wait[0].JobInfo)
+	require.Zero(t, wait[0].Timeout)
+
+	check := executions[1].Jobs[config.JobPriorityNormal]
+	require.Len(t, check, 1)
+	require.EqualValues(t, 120, check[0].Timeout)
+	require.Equal(t, "task-1", check[0].TaskID)
+
+	notify := executions[2].Jobs[config.JobPriorityNormal]
+	require.Len(t, notify, 1)
+	require.EqualValues(t, config.DefaultBuiltinStepTimeout.Seconds(), notify[0].Timeout)
+	require.Equal(t, []string{"health"}, executions[2].DependsOn)
+}
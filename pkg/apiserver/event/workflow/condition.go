@@ -0,0 +1,132 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/domain/repository"
+	"kubemin-cli/pkg/apiserver/event/workflow/job"
+	wf "kubemin-cli/pkg/apiserver/workflow"
+)
+
+// stepResultKey is the name under which a step's result is recorded and referenced as steps.<name>.
+func stepResultKey(exec StepExecution) string {
+	if exec.Step != "" {
+		return strings.ToLower(exec.Step)
+	}
+	return strings.ToLower(exec.Name)
+}
+
+// evaluateStepCondition 在步骤执行前对其条件求值。同一步骤展开的多个执行共享结果：
+// 步骤一旦被跳过，后续执行直接跳过，不再重新求值
+func (w *WorkflowCtl) evaluateStepCondition(ctx context.Context, exec StepExecution) (bool, error) {
+	if strings.TrimSpace(exec.Condition) == "" {
+		return true, nil
+	}
+	task := w.snapshotTask()
+	if task.StepResults[stepResultKey(exec)] == config.StatusSkipped {
+		return false, nil
+	}
+	vars, err := w.conditionVars(ctx)
+	if err != nil {
+		return false, fmt.Errorf("prepare condition of step %s: %w", exec.Step, err)
+	}
+	run, err := wf.EvaluateCondition(exec.Condition, vars)
+	if err != nil {
+		return false, fmt.Errorf("evaluate condition of step %s: %w", exec.Step, err)
+	}
+	klog.FromContext(ctx).Info("Evaluated workflow step condition", "step", exec.Step, "condition", exec.Condition, "result", run)
+	return run, nil
+}
+
+// conditionVars exposes the exec-time inputs, the application components and the
+// results of the steps finished so far to condition expressions.
+func (w *WorkflowCtl) conditionVars(ctx context.Context) (map[string]interface{}, error) {
+	task := w.snapshotTask()
+	components, err := w.loadComponentVars(ctx, task.AppID)
+	if err != nil {
+		return nil, err
+	}
+	inputs := make(map[string]interface{}, len(task.Inputs))
+	for k, v := range task.Inputs {
+		inputs[k] = v
+	}
+	steps := make(map[string]interface{}, len(task.StepResults))
+	for name, status := range task.StepResults {
+		steps[name] = map[string]interface{}{"status": string(status)}
+	}
+	return map[string]interface{}{
+		wf.ConditionInputs:     inputs,
+		wf.ConditionComponents: components,
+		wf.ConditionSteps:      steps,
+	}, nil
+}
+
+// loadComponentVars lists the application components once per run.
+func (w *WorkflowCtl) loadComponentVars(ctx context.Context, appID string) (map[string]interface{}, error) {
+	w.componentVarsOnce.Do(func() {
+		if w.Store == nil || appID == "" {
+			w.componentVars = map[string]interface{}{}
+			return
+		}
+		components, err := repository.FindComponentsByAppID(ctx, w.Store, appID)
+		if err != nil {
+			w.componentVarsErr = err
+			return
+		}
+		vars := make(map[string]interface{}, len(components))
+		for _, component := range components {
+			vars[component.Name] = map[string]interface{}{
+				"name":       component.Name,
+				"namespace":  component.Namespace,
+				"image":      component.Image,
+				"replicas":   component.Replicas,
+				"type":       string(component.ComponentType),
+				"properties": component.Properties,
+				"traits":     component.Traits,
+			}
+		}
+		w.componentVars = vars
+	})
+	return w.componentVars, w.componentVarsErr
+}
+
+// skipStepExecution records every job of a step whose condition is false as skipped,
+// without applying anything to the cluster.
+func (w *WorkflowCtl) skipStepExecution(ctx context.Context, exec StepExecution) {
+	klog.FromContext(ctx).Info("Skipping workflow step, condition is false", "step", exec.Step, "execution", exec.Name)
+	for _, priority := range sortedPriorities(exec.Jobs) {
+		jobs := exec.Jobs[priority]
+		if len(jobs) == 0 {
+			continue
+		}
+		for _, task := range jobs {
+			task.Status = config.StatusSkipped
+		}
+		job.RunJobs(ctx, jobs, len(jobs), w.Client, w.Store, w.ack, false)
+	}
+	w.recordStepResult(exec, config.StatusSkipped)
+}
+
+// recordStepResult stores the step outcome on the task; a skipped step stays skipped.
+// The map is replaced rather than mutated because task snapshots share it while ack persists them.
+func (w *WorkflowCtl) recordStepResult(exec StepExecution, status config.Status) {
+	key := stepResultKey(exec)
+	w.mutateTask(func(task *model.WorkflowQueue) {
+		current := task.StepResults[key]
+		if current == config.StatusSkipped || current == status {
+			return
+		}
+		results := make(map[string]config.Status, len(task.StepResults)+1)
+		for name, result := range task.StepResults {
+			results[name] = result
+		}
+		results[key] = status
+		task.StepResults = results
+	})
+}
@@ -0,0 +1,84 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+func TestRunTrackedStepSkipsWhenConditionFalse(t *testing.T) {
+	store := &fakeDataStore{components: []*model.ApplicationComponent{
+		{Name: "web", AppID: "app-1", Replicas: 1, ComponentType: config.ServerJob},
+	}}
+	ctl := &WorkflowCtl{
+		workflowTask: &model.WorkflowQueue{
+			TaskID: "task-cond-1",
+			AppID:  "app-1",
+			Status: config.StatusRunning,
+			Inputs: map[string]interface{}{"migrate": false},
+		},
+		Store: store,
+	}
+	migrate := &model.JobTask{Name: "migrate", Status: config.StatusQueued}
+	exec := StepExecution{
+		Name:      "migrate",
+		Step:      "migrate",
+		Mode:      config.WorkflowModeStepByStep,
+		Condition: "inputs.migrate || components.web.replicas > 1",
+		Jobs:      map[int][]*model.JobTask{config.JobPriorityNormal: {migrate}},
+	}
+
+	require.NoError(t, ctl.runTrackedStep(context.Background(), exec, 1))
+	require.Equal(t, config.StatusSkipped, migrate.Status)
+
+	task := ctl.snapshotTask()
+	require.Equal(t, config.StatusSkipped, task.StepResults["migrate"])
+	require.True(t, ctl.isStepCompleted(executionKey(exec)))
+
+	// Later steps can refer to the skipped one.
+	next := StepExecution{Name: "notify", Step: "notify", Mode: config.WorkflowModeStepByStep, Condition: `steps.migrate.status == "skipped"`}
+	require.NoError(t, ctl.runTrackedStep(context.Background(), next, 1))
+	require.Equal(t, config.StatusCompleted, ctl.snapshotTask().StepResults["notify"])
+}
+
+func TestRunTrackedStepFailsOnBrokenCondition(t *testing.T) {
+	ctl := &WorkflowCtl{workflowTask: &model.WorkflowQueue{
+		TaskID: "task-cond-2",
+		Status: config.StatusRunning,
+		Inputs: map[string]interface{}{"env": "prod"},
+	}}
+	exec := StepExecution{Name: "web", Step: "web", Mode: config.WorkflowModeStepByStep, Condition: "inputs.env > 1"}
+
+	require.ErrorContains(t, ctl.runTrackedStep(context.Background(), exec, 1), "evaluate condition of step web")
+	require.Empty(t, ctl.snapshotTask().StepResults)
+}
+
+func TestGenerateJobTasksCarriesCondition(t *testing.T) {
+	steps := &model.WorkflowSteps{
+		Steps: []*model.WorkflowStep{
+			{Name: "config", Condition: `inputs.env == "prod"`},
+		},
+	}
+	stepsJSON, err := model.NewJSONStructByStruct(steps)
+	require.NoError(t, err)
+	configProps, err := model.NewJSONStructByStruct(model.Properties{
+		Conf: map[string]string{"config": "value"},
+	})
+	require.NoError(t, err)
+
+	store := &fakeDataStore{
+		workflow: &model.Workflow{ID: "wf-1", Steps: stepsJSON},
+		components: []*model.ApplicationComponent{
+			{Name: "config", AppID: "app-1", Namespace: "default", ComponentType: config.ConfJob, Properties: configProps},
+		},
+	}
+	task := &model.WorkflowQueue{WorkflowID: "wf-1", AppID: "app-1", WorkflowName: "test-workflow"}
+
+	executions := GenerateJobTasks(context.Background(), task, store, int64(config.DefaultJobTaskTimeout))
+	require.Len(t, executions, 1)
+	require.Equal(t, `inputs.env == "prod"`, executions[0].Condition)
+}
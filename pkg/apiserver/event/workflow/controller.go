@@ -2,6 +2,7 @@ package workflow
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -15,8 +16,13 @@ import (
 
 	"kubemin-cli/pkg/apiserver/config"
 	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/domain/repository"
 	"kubemin-cli/pkg/apiserver/event/workflow/job"
 	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+	wf "kubemin-cli/pkg/apiserver/workflow"
+	"kubemin-cli/pkg/apiserver/workflow/drain"
+	"kubemin-cli/pkg/apiserver/workflow/progress"
+	"kubemin-cli/pkg/apiserver/workflow/signal"
 )
 
 type WorkflowCtl struct {
@@ -24,9 +30,23 @@ type WorkflowCtl struct {
 	workflowTaskMutex        sync.RWMutex
 	Client                   kubernetes.Interface
 	Store                    datastore.DataStore
+	Progress                 *progress.Broker // 发布任务与 Job 的状态变化，为空时不发布
 	prefix                   string
 	ack                      func()
 	defaultJobTimeoutSeconds int64
+	jobRetry                 jobRetrySettings
+	taskTimeout              time.Duration
+	stepTimeout              time.Duration
+	// stepDeadlines holds the deadline of each step once its first execution started.
+	stepDeadlines      map[string]time.Time
+	stepDeadlinesMutex sync.Mutex
+	// componentVars caches the components referenced by step conditions for one run.
+	componentVars     map[string]interface{}
+	componentVarsErr  error
+	componentVarsOnce sync.Once
+	// publishedStatus is the last task status sent to Progress.
+	publishedStatus      config.Status
+	publishedStatusMutex sync.Mutex
 	// ctx holds the workflow execution context for use in callbacks like updateWorkflowTask.
 	// This avoids using context.Background() which would break tracing and cancellation.
 	ctx context.Context
@@ -39,6 +59,9 @@ func NewWorkflowController(workflowTask *model.WorkflowQueue, client kubernetes.
 		Client:                   client,
 		prefix:                   fmt.Sprintf("workflowctl-%s-%s", workflowTask.WorkflowName, workflowTask.TaskID),
 		defaultJobTimeoutSeconds: resolveDefaultJobTimeout(cfg),
+		jobRetry:                 resolveJobRetrySettings(cfg),
+		taskTimeout:              resolveTaskTimeout(cfg),
+		stepTimeout:              resolveStepTimeout(cfg),
 	}
 	ctl.ack = ctl.updateWorkflowTask
 	return ctl
@@ -47,8 +70,10 @@ func NewWorkflowController(workflowTask *model.WorkflowQueue, client kubernetes.
 // 更改工作流的状态或信息
 func (w *WorkflowCtl) updateWorkflowTask() {
 	taskSnapshot := w.snapshotTask()
+	w.publishTask(&taskSnapshot)
 	// 如果当前的task状态为：通过，暂停，超时，拒绝；则不处理，直接返回
-	if isWorkflowTerminal(taskSnapshot.Status) {
+	// 等待审批、已暂停或因实例排空交还的任务已回到队列，可能已被其他 Worker 领取，这里同样不再覆盖
+	if isWorkflowTerminal(taskSnapshot.Status) || isTaskHandedBack(taskSnapshot.Status) {
 		klog.Infof("workflow %s, task %s, status %s: task already done, skipping update", taskSnapshot.WorkflowName, taskSnapshot.TaskID, taskSnapshot.Status)
 		return
 	}
@@ -82,6 +107,21 @@ func (w *WorkflowCtl) Run(ctx context.Context, concurrency int) error {
 	)
 	ctx = klog.NewContext(ctx, logger)
 	ctx = job.WithTaskMetadata(ctx, taskMeta.TaskID)
+	// wait 步骤的 until 表达式与步骤条件使用相同的变量
+	ctx = job.WithConditionVars(ctx, w.conditionVars)
+	ctx = job.WithJobObserver(ctx, w.observeJob)
+
+	// 开启回滚时，在每个资源首次被修改前记录其线上状态
+	rollbackMode := w.resolveRollbackMode(ctx, taskMeta.WorkflowID)
+	var recorderErr error
+	if rollbackMode != config.RollbackModeNone {
+		recorder, err := job.NewSnapshotRecorder(ctx, w.Store, taskMeta.TaskID, taskMeta.AppID)
+		if err != nil {
+			recorderErr = fmt.Errorf("load resource snapshots of task %s: %w", taskMeta.TaskID, err)
+		} else {
+			ctx = job.WithSnapshotRecorder(ctx, recorder)
+		}
+	}
 
 	// Store context for use in callbacks (e.g., updateWorkflowTask)
 	w.ctx = ctx
@@ -90,55 +130,89 @@ func (w *WorkflowCtl) Run(ctx context.Context, concurrency int) error {
 	w.mutateTask(func(task *model.WorkflowQueue) {
 		task.Status = config.StatusRunning
 		task.CreateTime = time.Now()
+		w.initTaskDeadline(task)
 	})
 	w.ack()
 	logger.Info("Starting workflow", "status", w.snapshotTask().Status)
 
 	defer func() {
-		logger.Info("Finished workflow", "status", w.snapshotTask().Status)
+		status := w.snapshotTask().Status
+		logger.Info("Finished workflow", "status", status)
 		w.ack()
+		if status == config.StatusCompleted || status == config.StatusCancelled || isWorkflowTerminal(status) {
+			deleteExecutionPlan(context.WithoutCancel(ctx), w.Store, taskMeta.TaskID)
+		}
+		if !isTaskHandedBack(status) {
+			// A pause requested after the last bucket started has nothing left to stop.
+			if err := signal.ClearPause(context.WithoutCancel(ctx), taskMeta.TaskID); err != nil {
+				logger.Error(err, "Failed to clear workflow pause request")
+			}
+		}
 	}()
 
-	ctx, cancel := context.WithCancel(ctx)
+	ctx, cancel := w.withTaskDeadline(ctx)
 	defer cancel()
 
+	if recorderErr != nil {
+		// Without the earlier snapshots a resumed run would capture already-changed state.
+		logger.Error(recorderErr, "Failed to prepare snapshot recorder")
+		span.SetStatus(codes.Error, "Workflow failed")
+		span.RecordError(recorderErr)
+		w.handleWorkflowFailure(ctx, rollbackMode, config.StatusFailed)
+		return recorderErr
+	}
+
 	taskForGeneration := w.snapshotTask()
-	stepExecutions := GenerateJobTasks(ctx, &taskForGeneration, w.Store, w.defaultJobTimeoutSeconds)
+	stepExecutions := w.planStepExecutions(ctx, &taskForGeneration)
+	w.jobRetry.apply(stepExecutions)
+	if skipped := skipRetriedJobs(stepExecutions, taskForGeneration.SkipJobs); skipped > 0 {
+		logger.Info("Reusing jobs completed by the original task", "retryOf", taskForGeneration.RetryOf, "skipped", skipped)
+	}
+	if resumed := resumeCompletedJobs(stepExecutions, taskForGeneration.CompletedJobs); resumed > 0 {
+		logger.Info("Skipping jobs completed before the task was picked up again", "resumed", resumed)
+	}
 	seqLimit := 1
 	if concurrency > 0 {
 		seqLimit = concurrency
 	}
 
-	for _, stepExec := range stepExecutions {
-		if stepExec.Jobs == nil {
-			continue
-		}
-		priorities := sortedPriorities(stepExec.Jobs)
-		for _, priority := range priorities {
-			tasksInPriority := stepExec.Jobs[priority]
-			if len(tasksInPriority) == 0 {
-				continue
-			}
-			stepConcurrency := determineStepConcurrency(stepExec.Mode, len(tasksInPriority), seqLimit)
-			// Fix: StepByStep mode should stop on first failure (stopOnFailure=true)
-			// Parallel mode continues all jobs even if some fail (stopOnFailure=false)
-			stopOnFailure := !stepExec.Mode.IsParallel()
-			logger.Info("Executing workflow step", "workflowName", workflowName, "step", stepExec.Name, "mode", stepExec.Mode, "priority", priority, "jobCount", len(tasksInPriority), "concurrency", stepConcurrency, "stopOnFailure", stopOnFailure)
-
-			job.RunJobs(ctx, tasksInPriority, stepConcurrency, w.Client, w.Store, w.ack, stopOnFailure)
-
-			for _, task := range tasksInPriority {
-				if !isJobSuccessStatus(task.Status) {
-					err := fmt.Errorf("workflow %s failed at job %s (status=%s)", workflowName, task.Name, task.Status)
-					logger.Error(err, "Workflow failed at job, aborting.", "step", stepExec.Name, "priority", priority, "jobName", task.Name, "jobStatus", task.Status)
-					w.setStatus(config.StatusFailed)
-					span.SetStatus(codes.Error, "Workflow failed")
-					span.RecordError(err)
-					return err
-				}
+	var runErr error
+	if hasStepDependencies(stepExecutions) {
+		runErr = w.runStepGraph(ctx, stepExecutions, seqLimit)
+	} else {
+		for _, stepExec := range stepExecutions {
+			if runErr = w.runTrackedStep(ctx, stepExec, seqLimit); runErr != nil {
+				break
 			}
 		}
-		logger.Info("Workflow step completed successfully", "workflowName", workflowName, "step", stepExec.Name)
+	}
+	if errors.Is(runErr, errWorkflowSuspended) {
+		suspendStatus := config.StatusWaitingApprove
+		if errors.Is(runErr, errWorkflowPaused) {
+			suspendStatus = config.StatusPause
+		}
+		span.SetStatus(codes.Ok, "Workflow suspended")
+		w.suspend(ctx, suspendStatus)
+		return nil
+	}
+	if runErr != nil && errors.Is(context.Cause(ctx), drain.ErrDrained) {
+		// Completed jobs are checkpointed; the next worker resumes at the interrupted one.
+		logger.Info("Worker drained, handing workflow task back to the queue", "error", runErr)
+		span.SetStatus(codes.Ok, "Workflow handed back")
+		w.suspend(ctx, config.StatusWaiting)
+		return nil
+	}
+	if runErr != nil {
+		span.SetStatus(codes.Error, "Workflow failed")
+		span.RecordError(runErr)
+		failStatus := config.StatusFailed
+		if statusErr, ok := job.ExtractStatusError(runErr); ok && statusErr.Status == config.StatusReject {
+			failStatus = config.StatusReject
+		} else if errors.Is(runErr, errWorkflowTimeout) {
+			failStatus = config.StatusTimeout
+		}
+		w.handleWorkflowFailure(ctx, rollbackMode, failStatus)
+		return runErr
 	}
 
 	span.SetStatus(codes.Ok, "Workflow completed successfully")
@@ -146,6 +220,80 @@ func (w *WorkflowCtl) Run(ctx context.Context, concurrency int) error {
 	return nil
 }
 
+// runStepExecution runs the priority buckets of one step execution in order and
+// returns an error as soon as a bucket contains a job that did not succeed. Buckets
+// finished by an earlier dispatch are skipped, and a pending pause request stops the
+// execution before the next bucket starts.
+func (w *WorkflowCtl) runStepExecution(ctx context.Context, stepExec StepExecution, seqLimit int) error {
+	if stepExec.Jobs == nil {
+		return nil
+	}
+	logger := klog.FromContext(ctx)
+	workflowName := w.snapshotTask().WorkflowName
+	priorities := sortedPriorities(stepExec.Jobs)
+	for _, priority := range priorities {
+		tasksInPriority := stepExec.Jobs[priority]
+		if len(tasksInPriority) == 0 {
+			continue
+		}
+		key := bucketKey(stepExec, priority)
+		if w.isStepCompleted(key) {
+			logger.Info("Skipping priority bucket completed before pause", "step", stepExec.Name, "priority", priority)
+			continue
+		}
+		if err := w.checkPause(ctx); err != nil {
+			return err
+		}
+		stepConcurrency := determineStepConcurrency(stepExec.Mode, len(tasksInPriority), seqLimit)
+		// Fix: StepByStep mode should stop on first failure (stopOnFailure=true)
+		// Parallel mode continues all jobs even if some fail (stopOnFailure=false)
+		stopOnFailure := !stepExec.Mode.IsParallel()
+		logger.Info("Executing workflow step", "workflowName", workflowName, "step", stepExec.Name, "mode", stepExec.Mode, "priority", priority, "jobCount", len(tasksInPriority), "concurrency", stepConcurrency, "stopOnFailure", stopOnFailure)
+
+		job.RunJobs(ctx, tasksInPriority, stepConcurrency, w.Client, w.Store, w.ack, stopOnFailure)
+
+		for _, task := range tasksInPriority {
+			if !isJobSuccessStatus(task.Status) {
+				err := fmt.Errorf("workflow %s failed at job %s (status=%s)", workflowName, task.Name, task.Status)
+				logger.Error(err, "Workflow failed at job, aborting.", "step", stepExec.Name, "priority", priority, "jobName", task.Name, "jobStatus", task.Status)
+				return err
+			}
+		}
+		w.markStepCompleted(key)
+	}
+	logger.Info("Workflow step completed successfully", "workflowName", workflowName, "step", stepExec.Name)
+	return nil
+}
+
+// skipRetriedJobs marks the jobs a retried task reuses from the original task as skipped,
+// so they are recorded for the new task without being applied again.
+func skipRetriedJobs(executions []StepExecution, skipJobs []string) int {
+	return markJobs(executions, skipJobs, config.StatusSkipped)
+}
+
+// markJobs sets status on the jobs whose type/name key is listed in keys and returns how many matched.
+func markJobs(executions []StepExecution, keys []string, status config.Status) int {
+	if len(keys) == 0 {
+		return 0
+	}
+	set := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		set[key] = struct{}{}
+	}
+	marked := 0
+	for _, exec := range executions {
+		for _, jobs := range exec.Jobs {
+			for _, task := range jobs {
+				if _, ok := set[wf.JobKey(task.JobType, task.Name)]; ok {
+					task.Status = status
+					marked++
+				}
+			}
+		}
+	}
+	return marked
+}
+
 func isJobSuccessStatus(status config.Status) bool {
 	return status == config.StatusCompleted || status == config.StatusSkipped || status == config.StatusPassed
 }
@@ -159,6 +307,61 @@ func (w *WorkflowCtl) updateWorkflowStatus(ctx context.Context) {
 	}
 }
 
+// handleWorkflowFailure 持久化失败状态（failed、timeout 或 reject）；自动回滚模式下按倒序恢复任务修改过的资源，并以回滚结果作为最终状态。
+// 若任务状态已被外部修改（例如被取消），则保留该状态且不自动回滚。
+func (w *WorkflowCtl) handleWorkflowFailure(ctx context.Context, mode config.RollbackMode, status config.Status) {
+	logger := klog.FromContext(ctx)
+	w.setStatus(status)
+	taskID := w.snapshotTask().TaskID
+	// The workflow context may already be cancelled; persisting and restoring must still happen.
+	ctx = context.WithoutCancel(ctx)
+
+	swapped, err := repository.UpdateTaskStatus(ctx, w.Store, taskID, config.StatusRunning, status)
+	if err != nil {
+		logger.Error(err, "Failed to persist workflow failure")
+		return
+	}
+	if !swapped {
+		logger.Info("Workflow task status changed externally, skipping rollback")
+		// Keep the in-memory status in line with the stored one (e.g. cancelled) for the final ack.
+		if current, err := repository.TaskByID(ctx, w.Store, taskID); err == nil {
+			w.setStatus(current.Status)
+		}
+		return
+	}
+	if mode != config.RollbackModeAutomatic {
+		return
+	}
+
+	finalStatus := config.StatusRolledBack
+	snapshots, err := job.ListTaskSnapshots(ctx, w.Store, taskID)
+	if err == nil {
+		logger.Info("Rolling back workflow resources", "snapshots", len(snapshots))
+		err = job.RestoreSnapshots(ctx, w.Client, w.Store, snapshots)
+	}
+	if err != nil {
+		logger.Error(err, "Workflow rollback failed")
+		finalStatus = config.StatusRollbackFailed
+	}
+	w.setStatus(finalStatus)
+	if _, err := repository.UpdateTaskStatus(ctx, w.Store, taskID, status, finalStatus); err != nil {
+		logger.Error(err, "Failed to persist workflow rollback status", "status", finalStatus)
+	}
+}
+
+// resolveRollbackMode reads the rollback mode configured on the workflow, defaulting to none.
+func (w *WorkflowCtl) resolveRollbackMode(ctx context.Context, workflowID string) config.RollbackMode {
+	if workflowID == "" || w.Store == nil {
+		return config.RollbackModeNone
+	}
+	workflow := &model.Workflow{ID: workflowID}
+	if err := w.Store.Get(ctx, workflow); err != nil {
+		klog.FromContext(ctx).Error(err, "Failed to load workflow rollback mode", "workflowID", workflowID)
+		return config.RollbackModeNone
+	}
+	return config.ParseRollbackMode(string(workflow.RollbackMode))
+}
+
 func resolveDefaultJobTimeout(cfg *config.Config) int64 {
 	if cfg != nil && cfg.Workflow.DefaultJobTimeout > 0 {
 		seconds := int64(cfg.Workflow.DefaultJobTimeout / time.Second)
@@ -187,9 +390,60 @@ func (w *WorkflowCtl) setStatus(status config.Status) {
 	})
 }
 
+// publishTask 任务状态发生变化时发布任务事件
+func (w *WorkflowCtl) publishTask(task *model.WorkflowQueue) {
+	if w.Progress == nil {
+		return
+	}
+	w.publishedStatusMutex.Lock()
+	changed := task.Status != w.publishedStatus
+	w.publishedStatus = task.Status
+	w.publishedStatusMutex.Unlock()
+	if !changed {
+		return
+	}
+	w.Progress.Publish(w.callbackContext(), progress.Event{
+		Type:       progress.EventTask,
+		TaskID:     task.TaskID,
+		WorkflowID: task.WorkflowID,
+		AppID:      task.AppID,
+		Status:     task.Status,
+	})
+}
+
+// publishJob 作为 Job 观察者，在 Job 状态变化时发布 Job 事件
+func (w *WorkflowCtl) publishJob(jobTask *model.JobTask) {
+	task := w.snapshotTask()
+	w.Progress.Publish(w.callbackContext(), progress.Event{
+		Type:       progress.EventJob,
+		TaskID:     task.TaskID,
+		WorkflowID: task.WorkflowID,
+		AppID:      task.AppID,
+		Status:     task.Status,
+		Job: &progress.JobState{
+			Name:      jobTask.Name,
+			Type:      jobTask.JobType,
+			Status:    jobTask.Status,
+			Error:     jobTask.Error,
+			Attempt:   jobTask.Attempt(),
+			StartTime: jobTask.StartTime,
+			EndTime:   jobTask.EndTime,
+		},
+	})
+}
+
+func (w *WorkflowCtl) callbackContext() context.Context {
+	if w.ctx != nil {
+		return w.ctx
+	}
+	return context.Background()
+}
+
 func isWorkflowTerminal(status config.Status) bool {
 	return status == config.StatusPassed ||
 		status == config.StatusFailed ||
 		status == config.StatusTimeout ||
-		status == config.StatusReject
+		status == config.StatusReject ||
+		status == config.StatusRolledBack ||
+		status == config.StatusRollbackFailed
 }
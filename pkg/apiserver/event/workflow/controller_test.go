@@ -1,12 +1,18 @@
 package workflow
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 
 	"kubemin-cli/pkg/apiserver/config"
 	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
 )
 
 func TestStopOnFailureLogicForStepModes(t *testing.T) {
@@ -90,6 +96,8 @@ func TestIsWorkflowTerminal(t *testing.T) {
 		{config.StatusFailed, true},
 		{config.StatusTimeout, true},
 		{config.StatusReject, true},
+		{config.StatusRolledBack, true},
+		{config.StatusRollbackFailed, true},
 		{config.StatusRunning, false},
 		{config.StatusQueued, false},
 		{config.StatusWaiting, false},
@@ -122,3 +130,116 @@ func TestIsJobSuccessStatus(t *testing.T) {
 		})
 	}
 }
+
+// rollbackStore records task status transitions and serves the snapshots of a task.
+type rollbackStore struct {
+	fakeDataStore
+	rollbackMode config.RollbackMode
+	snapshots    []*model.ResourceSnapshot
+	statusSwaps  []config.Status
+	rejectSwap   bool
+}
+
+func (s *rollbackStore) Get(_ context.Context, entity datastore.Entity) error {
+	if wf, ok := entity.(*model.Workflow); ok {
+		wf.RollbackMode = s.rollbackMode
+		return nil
+	}
+	return datastore.ErrRecordNotExist
+}
+
+func (s *rollbackStore) List(_ context.Context, query datastore.Entity, _ *datastore.ListOptions) ([]datastore.Entity, error) {
+	if _, ok := query.(*model.ResourceSnapshot); !ok {
+		return nil, nil
+	}
+	entities := make([]datastore.Entity, 0, len(s.snapshots))
+	for _, snapshot := range s.snapshots {
+		entities = append(entities, snapshot)
+	}
+	return entities, nil
+}
+
+func (s *rollbackStore) CompareAndSwap(_ context.Context, _ datastore.Entity, _ string, _ interface{}, updates map[string]interface{}) (bool, error) {
+	if s.rejectSwap {
+		return false, nil
+	}
+	s.statusSwaps = append(s.statusSwaps, updates["status"].(config.Status))
+	return true, nil
+}
+
+func TestHandleWorkflowFailureRollsBackAutomatically(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app-conf", Namespace: "default"}})
+	store := &rollbackStore{
+		rollbackMode: config.RollbackModeAutomatic,
+		snapshots: []*model.ResourceSnapshot{
+			{ID: 1, TaskID: "task-1", Kind: config.ResourceConfigMap, Namespace: "default", Name: "app-conf", Sequence: 1},
+		},
+	}
+	ctl := &WorkflowCtl{
+		workflowTask: &model.WorkflowQueue{TaskID: "task-1", WorkflowID: "wf-1", Status: config.StatusRunning},
+		Client:       client,
+		Store:        store,
+	}
+
+	mode := ctl.resolveRollbackMode(context.Background(), "wf-1")
+	require.Equal(t, config.RollbackModeAutomatic, mode)
+	ctl.handleWorkflowFailure(context.Background(), mode, config.StatusFailed)
+
+	require.Equal(t, []config.Status{config.StatusFailed, config.StatusRolledBack}, store.statusSwaps)
+	require.Equal(t, config.StatusRolledBack, ctl.snapshotTask().Status)
+	_, err := client.CoreV1().ConfigMaps("default").Get(context.Background(), "app-conf", metav1.GetOptions{})
+	require.True(t, k8serrors.IsNotFound(err), "configmap created by the task should be removed")
+}
+
+func TestHandleWorkflowFailureManualModeKeepsFailedStatus(t *testing.T) {
+	store := &rollbackStore{rollbackMode: config.RollbackModeManual}
+	ctl := &WorkflowCtl{
+		workflowTask: &model.WorkflowQueue{TaskID: "task-2", Status: config.StatusRunning},
+		Client:       fake.NewSimpleClientset(),
+		Store:        store,
+	}
+
+	ctl.handleWorkflowFailure(context.Background(), config.RollbackModeManual, config.StatusFailed)
+
+	require.Equal(t, []config.Status{config.StatusFailed}, store.statusSwaps)
+	require.Equal(t, config.StatusFailed, ctl.snapshotTask().Status)
+}
+
+func TestHandleWorkflowFailureSkipsRollbackWhenTaskChanged(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app-conf", Namespace: "default"}})
+	store := &rollbackStore{
+		rejectSwap: true,
+		snapshots: []*model.ResourceSnapshot{
+			{ID: 1, TaskID: "task-3", Kind: config.ResourceConfigMap, Namespace: "default", Name: "app-conf", Sequence: 1},
+		},
+	}
+	ctl := &WorkflowCtl{
+		workflowTask: &model.WorkflowQueue{TaskID: "task-3", Status: config.StatusRunning},
+		Client:       client,
+		Store:        store,
+	}
+
+	ctl.handleWorkflowFailure(context.Background(), config.RollbackModeAutomatic, config.StatusFailed)
+
+	_, err := client.CoreV1().ConfigMaps("default").Get(context.Background(), "app-conf", metav1.GetOptions{})
+	require.NoError(t, err, "cancelled tasks are not rolled back automatically")
+}
+
+func TestSkipRetriedJobs(t *testing.T) {
+	conf := &model.JobTask{Name: "config", JobType: string(config.JobDeployConfigMap), Status: config.StatusQueued}
+	web := &model.JobTask{Name: "Web", JobType: string(config.JobDeploy), Status: config.StatusQueued}
+	svc := &model.JobTask{Name: "web", JobType: string(config.JobDeployService), Status: config.StatusQueued}
+	executions := []StepExecution{
+		{Name: "config", Jobs: map[int][]*model.JobTask{config.JobPriorityMaxHigh: {conf}}},
+		{Name: "web", Jobs: map[int][]*model.JobTask{config.JobPriorityNormal: {web}, config.JobPriorityLow: {svc}}},
+		{Name: "gate", Approval: true},
+	}
+
+	skipped := skipRetriedJobs(executions, []string{"configmap_deploy/config", "deploy/web"})
+
+	require.Equal(t, 2, skipped)
+	require.Equal(t, config.StatusSkipped, conf.Status)
+	require.Equal(t, config.StatusSkipped, web.Status)
+	require.Equal(t, config.StatusQueued, svc.Status, "jobs of another type are not reused")
+	require.Zero(t, skipRetriedJobs(executions, nil))
+}
@@ -0,0 +1,111 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	msg "kubemin-cli/pkg/apiserver/infrastructure/messaging"
+)
+
+// deadLetterQueue records dead letters and serves a fixed set of outstanding messages.
+type deadLetterQueue struct {
+	msg.NoopQueue
+	deadLetters   []msg.Message
+	reasons       []string
+	deadLetterErr error
+	outstanding   []msg.Message
+}
+
+func (q *deadLetterQueue) DeadLetter(_ context.Context, m msg.Message, reason string) error {
+	if q.deadLetterErr != nil {
+		return q.deadLetterErr
+	}
+	q.deadLetters = append(q.deadLetters, m)
+	q.reasons = append(q.reasons, reason)
+	return nil
+}
+
+func (q *deadLetterQueue) Outstanding(context.Context, string) ([]msg.Message, error) {
+	return q.outstanding, nil
+}
+
+func dispatchMessage(t *testing.T, taskID string, deliveries int64) msg.Message {
+	payload, err := MarshalTaskDispatch(TaskDispatch{TaskID: taskID, WorkflowID: "wf-1"})
+	require.NoError(t, err)
+	return msg.Message{ID: "1-0", Payload: payload, Deliveries: deliveries}
+}
+
+func TestProcessDispatchMessageDeadLettersUndecodablePayload(t *testing.T) {
+	w := newWorkflowForAckTests(true)
+	q := &deadLetterQueue{}
+	w.Queue = q
+
+	ack, _ := w.processDispatchMessage(context.Background(), msg.Message{ID: "1-0", Payload: []byte("oops")})
+	require.True(t, ack)
+	require.Len(t, q.deadLetters, 1)
+	require.Contains(t, q.reasons[0], "decode dispatch")
+
+	q.deadLetterErr = errors.New("redis down")
+	ack, _ = w.processDispatchMessage(context.Background(), msg.Message{ID: "2-0", Payload: []byte("oops")})
+	require.False(t, ack, "a message that cannot be dead-lettered stays pending")
+}
+
+func TestProcessDispatchMessageDeadLettersMissingTask(t *testing.T) {
+	w := newWorkflowForAckTests(true)
+	q := &deadLetterQueue{}
+	w.Queue = q
+	w.Store.(*workflowAckTestStore).task = nil
+
+	ack, taskID := w.processDispatchMessage(context.Background(), dispatchMessage(t, "task-1", 1))
+	require.True(t, ack)
+	require.Equal(t, "task-1", taskID)
+	require.Equal(t, []string{"task task-1 does not exist"}, q.reasons)
+}
+
+func TestProcessDispatchMessageLeavesPendingOnStoreError(t *testing.T) {
+	w := newWorkflowForAckTests(true)
+	q := &deadLetterQueue{}
+	w.Queue = q
+	w.Cfg = &config.Config{Workflow: config.WorkflowRuntimeConfig{WorkerMaxDeliveries: 3}}
+	w.Store.(*workflowAckTestStore).getErr = errors.New("connection refused")
+
+	ack, _ := w.processDispatchMessage(context.Background(), dispatchMessage(t, "task-1", 3))
+	require.False(t, ack)
+	require.Empty(t, q.deadLetters)
+
+	ack, _ = w.processDispatchMessage(context.Background(), dispatchMessage(t, "task-1", 4))
+	require.True(t, ack)
+	require.Equal(t, []string{"delivered 4 times, limit is 3"}, q.reasons)
+}
+
+func TestProcessDispatchMessageSkipsTaskNotQueued(t *testing.T) {
+	w := newWorkflowForAckTests(true)
+	svc := w.WorkflowService.(*stubWorkflowService)
+	w.Store.(*workflowAckTestStore).task.Status = config.StatusRunning
+
+	ack, taskID := w.processDispatchMessage(context.Background(), dispatchMessage(t, "task-1", 2))
+	require.True(t, ack)
+	require.Equal(t, "task-1", taskID)
+	require.Zero(t, svc.updates, "the task is not run again")
+}
+
+func TestReconcileQueuedTasksRequeuesTasksWithoutMessage(t *testing.T) {
+	stale := time.Now().Add(-time.Hour)
+	svc := &stubWorkflowService{running: []*model.WorkflowQueue{
+		{TaskID: "lost", Status: config.StatusQueued, BaseModel: model.BaseModel{UpdateTime: stale}},
+		{TaskID: "in-stream", Status: config.StatusQueued, BaseModel: model.BaseModel{UpdateTime: stale}},
+		{TaskID: "just-queued", Status: config.StatusQueued, BaseModel: model.BaseModel{UpdateTime: time.Now()}},
+		{TaskID: "running", Status: config.StatusRunning, BaseModel: model.BaseModel{UpdateTime: stale}},
+	}}
+	q := &deadLetterQueue{outstanding: []msg.Message{dispatchMessage(t, "in-stream", 1)}}
+	w := &Workflow{WorkflowService: svc, Queue: q, Cfg: config.NewConfig()}
+
+	require.Equal(t, 1, w.reconcileQueuedTasks(context.Background()))
+	require.Equal(t, []config.Status{config.StatusWaiting}, svc.marked)
+}
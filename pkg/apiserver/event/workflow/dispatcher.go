@@ -12,7 +12,9 @@ import (
 	"kubemin-cli/pkg/apiserver/config"
 	"kubemin-cli/pkg/apiserver/domain/model"
 	"kubemin-cli/pkg/apiserver/domain/repository"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
 	msg "kubemin-cli/pkg/apiserver/infrastructure/messaging"
+	wf "kubemin-cli/pkg/apiserver/workflow"
 )
 
 // Note: Worker resilience constants are defined in config/consts.go:
@@ -48,6 +50,10 @@ func (w *Workflow) WorkflowTaskSender(ctx context.Context) {
 		case <-ticker.C:
 		}
 
+		if w.draining() {
+			continue
+		}
+
 		waitingTasks, err := w.waitingTasks(ctx)
 		if err != nil {
 			if errors.Is(err, context.Canceled) || ctx.Err() != nil {
@@ -59,7 +65,7 @@ func (w *Workflow) WorkflowTaskSender(ctx context.Context) {
 		if len(waitingTasks) == 0 {
 			continue
 		}
-		for _, task := range waitingTasks {
+		for _, task := range w.dispatchOrder(ctx, waitingTasks) {
 			if ctx.Err() != nil {
 				return
 			}
@@ -93,7 +99,7 @@ func (w *Workflow) Dispatcher(ctx context.Context) {
 		if len(waitingTasks) == 0 {
 			continue
 		}
-		for _, task := range waitingTasks {
+		for _, task := range w.dispatchOrder(ctx, waitingTasks) {
 			if ctx.Err() != nil {
 				return
 			}
@@ -153,6 +159,10 @@ func (w *Workflow) StartWorker(ctx context.Context, errChan chan error) {
 		case <-ctx.Done():
 			klog.Info("worker shutting down due to context cancellation")
 			return
+		case <-w.drainStarted():
+			// Unread messages stay in the stream for the other workers.
+			klog.Info("worker draining, stopped reading new messages")
+			return
 		case <-staleTicker.C:
 			mags, err := w.Queue.AutoClaim(ctx, group, consumer, w.workerAutoClaimMinIdle(), w.workerAutoClaimCount())
 			if err != nil {
@@ -217,8 +227,40 @@ func (w *Workflow) StartWorker(ctx context.Context, errChan chan error) {
 	}
 }
 
+// dispatchOrder orders waiting tasks by priority and per-project fair share and drops the tasks
+// of projects that already reached the per-project cap. The global workflowLimiter still bounds
+// how many of the dispatched tasks run at the same time.
+func (w *Workflow) dispatchOrder(ctx context.Context, waiting []*model.WorkflowQueue) []*model.WorkflowQueue {
+	var active map[string]int
+	if tasks, err := w.activeTasks(ctx); err != nil {
+		klog.Warningf("list active workflow tasks failed, dispatching without fair share: %v", err)
+	} else {
+		active = wf.ActiveTasksByProject(tasks)
+	}
+	ordered := wf.FairShareOrder(waiting, active)
+	limit := w.maxConcurrentPerProject()
+	if limit <= 0 || active == nil {
+		return ordered
+	}
+	selected := make([]*model.WorkflowQueue, 0, len(ordered))
+	for _, task := range ordered {
+		if active[task.ProjectID] >= limit {
+			klog.V(4).Infof("task %s held back: project %q reached %d concurrent tasks", task.TaskID, task.ProjectID, limit)
+			continue
+		}
+		active[task.ProjectID]++
+		selected = append(selected, task)
+	}
+	return selected
+}
+
 func (w *Workflow) claimAndProcessTask(ctx context.Context, task *model.WorkflowQueue, processor func(context.Context, *model.WorkflowQueue) error) {
+	release, admitted := w.admitTask(ctx, task)
+	if !admitted {
+		return
+	}
 	claimed, err := w.markTaskStatus(ctx, task.TaskID, config.StatusWaiting, config.StatusQueued)
+	release()
 	if err != nil {
 		if errors.Is(err, context.Canceled) || ctx.Err() != nil {
 			return
@@ -304,21 +346,32 @@ func (w *Workflow) reportWorkerError(err error) {
 	w.reportTaskError(err)
 }
 
-// processDispatchMessage processes a single dispatch message.
-// This is a "pass/fail" system - no retries. Failures are logged and the message is acknowledged.
-// Task state is tracked in the database, so operators can see failed tasks there.
+// processDispatchMessage processes a single dispatch message and reports whether it can be acked.
+// Task state is tracked in the database, so a task that fails to run is visible there. A message
+// whose task cannot be loaded stays pending for another delivery; messages that can never be
+// processed, or were delivered too often, are moved to the dead-letter stream.
 func (w *Workflow) processDispatchMessage(ctx context.Context, m msg.Message) (bool, string) {
 	td, err := UnmarshalTaskDispatch(m.Payload)
 	if err != nil {
-		// Parse error: log and ack to prevent blocking
-		klog.Errorf("decode dispatch failed: %v, payload: %s", err, string(m.Payload))
-		return true, ""
+		return w.deadLetter(ctx, m, "", fmt.Errorf("decode dispatch: %w", err)), ""
+	}
+	if limit := w.workerMaxDeliveries(); limit > 0 && m.Deliveries > int64(limit) {
+		return w.deadLetter(ctx, m, td.TaskID, fmt.Errorf("delivered %d times, limit is %d", m.Deliveries, limit)), td.TaskID
 	}
 
 	task, err := repository.TaskByID(ctx, w.Store, td.TaskID)
 	if err != nil {
-		// DB error: log and ack
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return w.deadLetter(ctx, m, td.TaskID, fmt.Errorf("task %s does not exist", td.TaskID)), td.TaskID
+		}
 		klog.Errorf("load task %s failed: %v", td.TaskID, err)
+		return false, td.TaskID
+	}
+
+	// Only a task claimed by the dispatcher runs; a redelivered or replayed message of a task
+	// that already started or went back to waiting is dropped.
+	if task.Status != config.StatusQueued {
+		klog.Infof("skip dispatch of task %s in status %s", td.TaskID, task.Status)
 		return true, td.TaskID
 	}
 
@@ -330,6 +383,17 @@ func (w *Workflow) processDispatchMessage(ctx context.Context, m msg.Message) (b
 	return true, td.TaskID
 }
 
+// deadLetter moves a message to the dead-letter stream and reports whether it can be acked;
+// a message that cannot be written there stays pending.
+func (w *Workflow) deadLetter(ctx context.Context, m msg.Message, taskID string, reason error) bool {
+	if err := w.Queue.DeadLetter(ctx, m, reason.Error()); err != nil {
+		klog.Errorf("dead-letter message id=%s task=%s failed: %v", m.ID, taskID, err)
+		return false
+	}
+	klog.Warningf("moved message id=%s task=%s to dead letters: %v", m.ID, taskID, reason)
+	return true
+}
+
 func (w *Workflow) dispatchTopic() string {
 	prefix := ""
 	if w.Cfg != nil {
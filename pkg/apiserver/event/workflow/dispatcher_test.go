@@ -9,10 +9,12 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
 	msg "kubemin-cli/pkg/apiserver/infrastructure/messaging"
 )
 
 type fakeAckQueue struct {
+	msg.NoopQueue
 	ackErr   error
 	ackCalls []ackRequest
 }
@@ -130,3 +132,37 @@ func TestReportWorkerErrorIgnoresNil(t *testing.T) {
 	wf.reportWorkerError(nil)
 	require.Len(t, wf.errChan, 0)
 }
+
+func TestDispatchOrderAppliesProjectCap(t *testing.T) {
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	waitingTask := func(id, project string, offset time.Duration) *model.WorkflowQueue {
+		task := &model.WorkflowQueue{TaskID: id, ProjectID: project, Status: config.StatusWaiting}
+		task.CreateTime = base.Add(offset)
+		return task
+	}
+	cfg := config.NewConfig()
+	cfg.Workflow.MaxConcurrentPerProject = 2
+	w := &Workflow{
+		Cfg: cfg,
+		WorkflowService: &stubWorkflowService{running: []*model.WorkflowQueue{
+			{TaskID: "busy-running", ProjectID: "busy", Status: config.StatusRunning},
+		}},
+	}
+	waiting := []*model.WorkflowQueue{
+		waitingTask("busy-1", "busy", 0),
+		waitingTask("busy-2", "busy", time.Second),
+		waitingTask("quiet-1", "quiet", 2*time.Second),
+		waitingTask("quiet-2", "quiet", 3*time.Second),
+		waitingTask("quiet-3", "quiet", 4*time.Second),
+	}
+
+	var ids []string
+	for _, task := range w.dispatchOrder(context.Background(), waiting) {
+		ids = append(ids, task.TaskID)
+	}
+	require.Equal(t, []string{"quiet-1", "busy-1", "quiet-2"}, ids)
+
+	// Without a cap every waiting task is dispatched, still in fair-share order.
+	cfg.Workflow.MaxConcurrentPerProject = 0
+	require.Len(t, w.dispatchOrder(context.Background(), waiting), len(waiting))
+}
@@ -0,0 +1,50 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+	"kubemin-cli/pkg/apiserver/workflow/drain"
+)
+
+// drainStore keeps execution plans and records task status swaps.
+type drainStore struct {
+	planStore
+	statusSwaps []config.Status
+}
+
+func (s *drainStore) CompareAndSwap(_ context.Context, _ datastore.Entity, _ string, _ interface{}, updates map[string]interface{}) (bool, error) {
+	s.statusSwaps = append(s.statusSwaps, updates["status"].(config.Status))
+	return true, nil
+}
+
+func TestRunHandsBackDrainedTask(t *testing.T) {
+	store := &drainStore{planStore: *newPlanStore(t, "config-a")}
+	task := &model.WorkflowQueue{TaskID: "task-1", AppID: "app-1", WorkflowID: "wf-1", WorkflowName: "demo"}
+	ctl := NewWorkflowController(task, nil, store, config.NewConfig())
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(drain.ErrDrained)
+	require.NoError(t, ctl.Run(ctx, 1))
+
+	require.Equal(t, config.StatusWaiting, ctl.snapshotTask().Status)
+	require.Equal(t, []config.Status{config.StatusWaiting}, store.statusSwaps)
+	require.Contains(t, store.plans, "task-1", "the next worker resumes from the stored plan")
+}
+
+func TestRunWorkflowTaskHandsBackWhileDraining(t *testing.T) {
+	svc := &stubWorkflowService{}
+	controller := drain.NewController(config.DefaultWorkerDrainGracePeriod)
+	controller.Begin()
+	w := &Workflow{WorkflowService: svc, Drain: controller}
+
+	w.runWorkflowTask(context.Background(), &model.WorkflowQueue{TaskID: "task-1"}, 1)
+
+	require.Equal(t, []config.Status{config.StatusWaiting}, svc.marked)
+	require.Empty(t, controller.Status().InFlight)
+}
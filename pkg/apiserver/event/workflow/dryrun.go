@@ -0,0 +1,49 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/event/workflow/job"
+)
+
+// DryRunWorkflow 生成与真实执行相同的 Job 计划，并将每个对象以 DryRun=All 提交到 API Server，
+// 返回逐个 Job 的准入结果。步骤条件不求值，所有步骤都会被校验；审批与内置步骤不写集群，记为跳过
+func (w *Workflow) DryRunWorkflow(ctx context.Context, task *model.WorkflowQueue) ([]model.DryRunJobResult, error) {
+	if task == nil {
+		return nil, fmt.Errorf("task is nil")
+	}
+	if w.KubeClient == nil {
+		return nil, fmt.Errorf("kube client is nil")
+	}
+	executions := GenerateJobTasks(ctx, task, w.Store, resolveDefaultJobTimeout(w.Cfg))
+	var results []model.DryRunJobResult
+	for _, execution := range executions {
+		step := execution.Step
+		if step == "" {
+			step = execution.Name
+		}
+		if execution.Approval {
+			results = append(results, model.DryRunJobResult{
+				Step:    step,
+				Name:    execution.Name,
+				JobType: string(config.JobApproval),
+				Status:  config.StatusSkipped,
+			})
+			continue
+		}
+		for _, priority := range sortedPriorities(execution.Jobs) {
+			for _, jobTask := range execution.Jobs[priority] {
+				if jobTask == nil {
+					continue
+				}
+				result := job.DryRunJob(ctx, w.KubeClient, jobTask)
+				result.Step = step
+				results = append(results, result)
+			}
+		}
+	}
+	return results, nil
+}
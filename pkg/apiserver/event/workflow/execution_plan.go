@@ -0,0 +1,195 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/klog/v2"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/domain/repository"
+	"kubemin-cli/pkg/apiserver/event/workflow/job"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+	wf "kubemin-cli/pkg/apiserver/workflow"
+)
+
+// planStepExecutions returns the step executions of the task. A task that already ran
+// on some worker continues with the plan stored on its first run, so jobs keep the
+// objects they were generated with even if the workflow or components changed since.
+// Otherwise the executions are generated and stored before any job starts.
+func (w *WorkflowCtl) planStepExecutions(ctx context.Context, task *model.WorkflowQueue) []StepExecution {
+	logger := klog.FromContext(ctx)
+	executions, err := loadExecutionPlan(ctx, w.Store, task.TaskID)
+	if err != nil {
+		logger.Error(err, "Failed to load execution plan, regenerating")
+	}
+	if executions != nil {
+		logger.Info("Resuming workflow from stored execution plan", "completedJobs", len(task.CompletedJobs))
+		return executions
+	}
+	executions = GenerateJobTasks(ctx, task, w.Store, w.defaultJobTimeoutSeconds)
+	if len(executions) == 0 {
+		return executions
+	}
+	if err := saveExecutionPlan(ctx, w.Store, task.TaskID, executions); err != nil {
+		logger.Error(err, "Failed to save execution plan, a resumed task will regenerate its jobs")
+	}
+	return executions
+}
+
+// saveExecutionPlan stores executions as the plan of taskID.
+func saveExecutionPlan(ctx context.Context, store datastore.DataStore, taskID string, executions []StepExecution) error {
+	plan := &model.ExecutionPlan{
+		TaskID:  taskID,
+		Version: config.ExecutionPlanVersion,
+		Steps:   make([]model.PlannedStep, 0, len(executions)),
+	}
+	for _, exec := range executions {
+		step := model.PlannedStep{
+			Name:      exec.Name,
+			Mode:      exec.Mode,
+			Step:      exec.Step,
+			DependsOn: exec.DependsOn,
+			Retry:     exec.Retry,
+			Approval:  exec.Approval,
+			Condition: exec.Condition,
+			Timeout:   exec.Timeout,
+		}
+		if exec.Jobs != nil {
+			step.Jobs = make(map[int][]*model.PlannedJob, len(exec.Jobs))
+		}
+		for priority, jobs := range exec.Jobs {
+			planned := make([]*model.PlannedJob, 0, len(jobs))
+			for _, jobTask := range jobs {
+				kind, info, err := job.EncodeJobInfo(jobTask.JobInfo)
+				if err != nil {
+					return fmt.Errorf("job %s: %w", jobTask.Name, err)
+				}
+				planned = append(planned, &model.PlannedJob{
+					Name:       jobTask.Name,
+					Namespace:  jobTask.Namespace,
+					WorkflowID: jobTask.WorkflowID,
+					ProjectID:  jobTask.ProjectID,
+					AppID:      jobTask.AppID,
+					JobType:    jobTask.JobType,
+					Status:     jobTask.Status,
+					Timeout:    jobTask.Timeout,
+					InfoKind:   kind,
+					Info:       info,
+				})
+			}
+			step.Jobs[priority] = planned
+		}
+		plan.Steps = append(plan.Steps, step)
+	}
+	return store.Add(ctx, plan)
+}
+
+// loadExecutionPlan restores the stored plan of taskID. It returns nil executions when
+// there is no usable plan; plans written in an older format are removed.
+func loadExecutionPlan(ctx context.Context, store datastore.DataStore, taskID string) ([]StepExecution, error) {
+	plan := &model.ExecutionPlan{TaskID: taskID}
+	if err := store.Get(ctx, plan); err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	executions, err := decodeExecutionPlan(plan)
+	if err == nil && plan.Version != config.ExecutionPlanVersion {
+		err = fmt.Errorf("execution plan version %d is not supported", plan.Version)
+	}
+	if err != nil {
+		deleteExecutionPlan(ctx, store, taskID)
+		return nil, err
+	}
+	return executions, nil
+}
+
+func decodeExecutionPlan(plan *model.ExecutionPlan) ([]StepExecution, error) {
+	executions := make([]StepExecution, 0, len(plan.Steps))
+	for _, step := range plan.Steps {
+		exec := StepExecution{
+			Name:      step.Name,
+			Mode:      step.Mode,
+			Step:      step.Step,
+			DependsOn: step.DependsOn,
+			Retry:     step.Retry,
+			Approval:  step.Approval,
+			Condition: step.Condition,
+			Timeout:   step.Timeout,
+		}
+		if step.Jobs != nil {
+			exec.Jobs = make(map[int][]*model.JobTask, len(step.Jobs))
+		}
+		for priority, planned := range step.Jobs {
+			jobs := make([]*model.JobTask, 0, len(planned))
+			for _, p := range planned {
+				info, err := job.DecodeJobInfo(p.InfoKind, p.Info)
+				if err != nil {
+					return nil, fmt.Errorf("job %s: %w", p.Name, err)
+				}
+				jobs = append(jobs, &model.JobTask{
+					Name:       p.Name,
+					Namespace:  p.Namespace,
+					WorkflowID: p.WorkflowID,
+					ProjectID:  p.ProjectID,
+					AppID:      p.AppID,
+					TaskID:     plan.TaskID,
+					JobInfo:    info,
+					JobType:    p.JobType,
+					Status:     p.Status,
+					Timeout:    p.Timeout,
+				})
+			}
+			exec.Jobs[priority] = jobs
+		}
+		executions = append(executions, exec)
+	}
+	return executions, nil
+}
+
+// deleteExecutionPlan removes the plan of a task that will not be resumed any more.
+func deleteExecutionPlan(ctx context.Context, store datastore.DataStore, taskID string) {
+	if err := repository.DeleteExecutionPlan(ctx, store, taskID); err != nil {
+		klog.FromContext(ctx).Error(err, "Failed to delete execution plan", "taskID", taskID)
+	}
+}
+
+// resumeCompletedJobs marks the jobs an earlier run of the task already finished as
+// passed; they are neither applied again nor recorded a second time.
+func resumeCompletedJobs(executions []StepExecution, completedJobs []string) int {
+	return markJobs(executions, completedJobs, config.StatusPassed)
+}
+
+// checkpointJob records a job that finished successfully on the task, so a worker that
+// picks the task up again continues after it.
+func (w *WorkflowCtl) checkpointJob(jobTask *model.JobTask) {
+	if jobTask.Status != config.StatusCompleted && jobTask.Status != config.StatusSkipped {
+		return
+	}
+	key := wf.JobKey(jobTask.JobType, jobTask.Name)
+	added := false
+	w.mutateTask(func(task *model.WorkflowQueue) {
+		for _, done := range task.CompletedJobs {
+			if done == key {
+				return
+			}
+		}
+		task.CompletedJobs = append(task.CompletedJobs, key)
+		added = true
+	})
+	if added && w.ack != nil {
+		w.ack()
+	}
+}
+
+// observeJob 作为 Job 观察者，记录 Job 完成的检查点并发布 Job 事件
+func (w *WorkflowCtl) observeJob(jobTask *model.JobTask) {
+	w.checkpointJob(jobTask)
+	if w.Progress != nil {
+		w.publishJob(jobTask)
+	}
+}
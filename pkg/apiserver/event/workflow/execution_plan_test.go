@@ -0,0 +1,143 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+	wf "kubemin-cli/pkg/apiserver/workflow"
+)
+
+// planStore keeps execution plans by task and records the job infos written by jobs.
+type planStore struct {
+	fakeDataStore
+	plans    map[string]*model.ExecutionPlan
+	jobInfos []*model.JobInfo
+}
+
+func (s *planStore) Add(_ context.Context, entity datastore.Entity) error {
+	switch e := entity.(type) {
+	case *model.ExecutionPlan:
+		if _, exists := s.plans[e.TaskID]; exists {
+			return datastore.ErrRecordExist
+		}
+		copied := *e
+		s.plans[e.TaskID] = &copied
+	case *model.JobInfo:
+		s.jobInfos = append(s.jobInfos, e)
+	}
+	return nil
+}
+
+func (s *planStore) Get(ctx context.Context, entity datastore.Entity) error {
+	if e, ok := entity.(*model.ExecutionPlan); ok {
+		stored, exists := s.plans[e.TaskID]
+		if !exists {
+			return datastore.ErrRecordNotExist
+		}
+		*e = *stored
+		return nil
+	}
+	return s.fakeDataStore.Get(ctx, entity)
+}
+
+func (s *planStore) Put(context.Context, datastore.Entity) error { return nil }
+
+func (s *planStore) Delete(_ context.Context, entity datastore.Entity) error {
+	e := entity.(*model.ExecutionPlan)
+	if _, exists := s.plans[e.TaskID]; !exists {
+		return datastore.ErrRecordNotExist
+	}
+	delete(s.plans, e.TaskID)
+	return nil
+}
+
+func newPlanStore(t *testing.T, components ...string) *planStore {
+	steps := &model.WorkflowSteps{}
+	store := &planStore{plans: map[string]*model.ExecutionPlan{}}
+	for _, name := range components {
+		props, err := model.NewJSONStructByStruct(model.Properties{Conf: map[string]string{"name": name}})
+		require.NoError(t, err)
+		store.components = append(store.components, &model.ApplicationComponent{
+			Name: name, AppID: "app-1", Namespace: "default", ComponentType: config.ConfJob, Properties: props,
+		})
+		steps.Steps = append(steps.Steps, &model.WorkflowStep{Name: name})
+	}
+	stepsJSON, err := model.NewJSONStructByStruct(steps)
+	require.NoError(t, err)
+	store.workflow = &model.Workflow{ID: "wf-1", Steps: stepsJSON}
+	return store
+}
+
+func TestExecutionPlanRoundTrip(t *testing.T) {
+	store := newPlanStore(t, "config-a", "config-b")
+	task := &model.WorkflowQueue{TaskID: "task-1", AppID: "app-1", WorkflowID: "wf-1", WorkflowName: "demo"}
+	executions := GenerateJobTasks(context.Background(), task, store, int64(config.DefaultJobTaskTimeout))
+	require.Len(t, executions, 2)
+
+	require.NoError(t, saveExecutionPlan(context.Background(), store, task.TaskID, executions))
+	loaded, err := loadExecutionPlan(context.Background(), store, task.TaskID)
+	require.NoError(t, err)
+	require.Equal(t, executions, loaded)
+}
+
+func TestLoadExecutionPlanDiscardsOtherVersions(t *testing.T) {
+	store := newPlanStore(t)
+	store.plans["task-1"] = &model.ExecutionPlan{TaskID: "task-1", Version: config.ExecutionPlanVersion + 1}
+
+	executions, err := loadExecutionPlan(context.Background(), store, "task-1")
+	require.Error(t, err)
+	require.Nil(t, executions)
+	require.Empty(t, store.plans)
+
+	executions, err = loadExecutionPlan(context.Background(), store, "task-1")
+	require.NoError(t, err)
+	require.Nil(t, executions)
+}
+
+func TestRunResumesAtFirstUnfinishedJob(t *testing.T) {
+	store := newPlanStore(t, "config-a", "config-b")
+	task := &model.WorkflowQueue{TaskID: "task-1", AppID: "app-1", WorkflowID: "wf-1", WorkflowName: "demo"}
+	executions := GenerateJobTasks(context.Background(), task, store, int64(config.DefaultJobTaskTimeout))
+	require.NoError(t, saveExecutionPlan(context.Background(), store, task.TaskID, executions))
+	first := executions[0].Jobs[config.JobPriorityMaxHigh][0]
+	second := executions[1].Jobs[config.JobPriorityMaxHigh][0]
+
+	// The first job finished before the worker crashed; the workflow was edited since.
+	store.workflow = &model.Workflow{ID: "wf-1"}
+	task.Status = config.StatusWaiting
+	task.CompletedJobs = []string{wf.JobKey(first.JobType, first.Name)}
+	client := fake.NewSimpleClientset()
+	ctl := NewWorkflowController(task, client, store, config.NewConfig())
+
+	require.NoError(t, ctl.Run(context.Background(), 1))
+
+	snapshot := ctl.snapshotTask()
+	require.Equal(t, config.StatusCompleted, snapshot.Status)
+	require.Equal(t, []string{wf.JobKey(first.JobType, first.Name), wf.JobKey(second.JobType, second.Name)}, snapshot.CompletedJobs)
+	require.Len(t, store.jobInfos, 1)
+	require.Equal(t, second.Name, store.jobInfos[0].ServiceName)
+	configMaps, err := client.CoreV1().ConfigMaps("default").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, configMaps.Items, 1)
+	require.Empty(t, store.plans, "the plan of a finished task is removed")
+}
+
+func TestPlanStepExecutionsReusesStoredPlan(t *testing.T) {
+	store := newPlanStore(t, "config-a")
+	task := &model.WorkflowQueue{TaskID: "task-1", AppID: "app-1", WorkflowID: "wf-1", WorkflowName: "demo"}
+	ctl := NewWorkflowController(task, nil, store, config.NewConfig())
+
+	generated := ctl.planStepExecutions(context.Background(), task)
+	require.Len(t, generated, 1)
+	require.Contains(t, store.plans, "task-1")
+
+	store.workflow = &model.Workflow{ID: "wf-1"}
+	require.Equal(t, generated, ctl.planStepExecutions(context.Background(), task))
+}
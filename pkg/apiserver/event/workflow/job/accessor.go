@@ -0,0 +1,211 @@
+package job
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+
+	"kubemin-cli/pkg/apiserver/config"
+)
+
+var dryRunAll = []string{metav1.DryRunAll}
+
+// applyTarget reads and submits one kind of object the way its JobCtl applies it.
+type applyTarget interface {
+	get(ctx context.Context, client kubernetes.Interface, namespace, name string) (metav1.Object, error)
+	list(ctx context.Context, client kubernetes.Interface, namespace string, opts metav1.ListOptions) ([]metav1.Object, error)
+	// prepare carries the fields the real job keeps from the live object onto the desired one.
+	prepare(existing, desired metav1.Object)
+	dryRun(ctx context.Context, client kubernetes.Interface, obj metav1.Object) error
+}
+
+// listableClient adds List to the typed client subset used for rollback.
+type listableClient[T metav1.Object, L runtime.Object] interface {
+	typedResourceClient[T]
+	List(ctx context.Context, opts metav1.ListOptions) (L, error)
+}
+
+// applyAccessor mirrors the create-or-update decision of the matching JobCtl.
+type applyAccessor[T metav1.Object, L runtime.Object] struct {
+	client func(client kubernetes.Interface, namespace string) listableClient[T, L]
+	// prepareUpdate carries fields the real job keeps from the live object.
+	prepareUpdate func(existing, desired T)
+	// replace marks kinds the real job deletes and recreates (batch Jobs).
+	replace bool
+	// createOnly marks kinds the real job refuses to touch once they exist.
+	createOnly bool
+}
+
+func (a applyAccessor[T, L]) get(ctx context.Context, client kubernetes.Interface, namespace, name string) (metav1.Object, error) {
+	return a.client(client, namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+func (a applyAccessor[T, L]) list(ctx context.Context, client kubernetes.Interface, namespace string, opts metav1.ListOptions) ([]metav1.Object, error) {
+	list, err := a.client(client, namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]metav1.Object, 0, len(items))
+	for _, item := range items {
+		obj, err := meta.Accessor(item)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+func (a applyAccessor[T, L]) prepare(existing, desired metav1.Object) {
+	if a.prepareUpdate == nil {
+		return
+	}
+	e, eok := existing.(T)
+	d, dok := desired.(T)
+	if eok && dok {
+		a.prepareUpdate(e, d)
+	}
+}
+
+// dryRun submits the object with DryRun=All so admission runs and nothing is stored.
+func (a applyAccessor[T, L]) dryRun(ctx context.Context, client kubernetes.Interface, obj metav1.Object) error {
+	desired, ok := obj.(T)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", obj)
+	}
+	cli := a.client(client, desired.GetNamespace())
+	existing, err := cli.Get(ctx, desired.GetName(), metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		_, err = cli.Create(ctx, desired, metav1.CreateOptions{DryRun: dryRunAll})
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("get existing object: %w", err)
+	}
+	if a.createOnly {
+		return fmt.Errorf("%s/%s already exists", desired.GetNamespace(), desired.GetName())
+	}
+	if a.replace {
+		if err := cli.Delete(ctx, desired.GetName(), metav1.DeleteOptions{DryRun: dryRunAll}); err != nil {
+			return fmt.Errorf("delete previous object: %w", err)
+		}
+		// The old object is still there, so validate the new one under a generated name.
+		desired.SetGenerateName(desired.GetName() + "-")
+		desired.SetName("")
+		desired.SetResourceVersion("")
+		_, err = cli.Create(ctx, desired, metav1.CreateOptions{DryRun: dryRunAll})
+		return err
+	}
+	a.prepare(existing, desired)
+	desired.SetResourceVersion(existing.GetResourceVersion())
+	_, err = cli.Update(ctx, desired, metav1.UpdateOptions{DryRun: dryRunAll})
+	return err
+}
+
+var applyAccessors = map[config.ResourceKind]applyTarget{
+	config.ResourceDeployment: applyAccessor[*appsv1.Deployment, *appsv1.DeploymentList]{
+		client: func(c kubernetes.Interface, ns string) listableClient[*appsv1.Deployment, *appsv1.DeploymentList] {
+			return c.AppsV1().Deployments(ns)
+		},
+		prepareUpdate: func(existing, desired *appsv1.Deployment) {
+			desired.Spec.Selector = existing.Spec.Selector
+			desired.Spec.Template.Labels = existing.Spec.Template.Labels
+		},
+	},
+	config.ResourceStatefulSet: applyAccessor[*appsv1.StatefulSet, *appsv1.StatefulSetList]{
+		client: func(c kubernetes.Interface, ns string) listableClient[*appsv1.StatefulSet, *appsv1.StatefulSetList] {
+			return c.AppsV1().StatefulSets(ns)
+		},
+		createOnly: true,
+	},
+	config.ResourceDaemonSet: applyAccessor[*appsv1.DaemonSet, *appsv1.DaemonSetList]{
+		client: func(c kubernetes.Interface, ns string) listableClient[*appsv1.DaemonSet, *appsv1.DaemonSetList] {
+			return c.AppsV1().DaemonSets(ns)
+		},
+		prepareUpdate: func(existing, desired *appsv1.DaemonSet) {
+			desired.Spec.Selector = existing.Spec.Selector
+			desired.Spec.Template.Labels = existing.Spec.Template.Labels
+		},
+	},
+	config.ResourceJob: applyAccessor[*batchv1.Job, *batchv1.JobList]{
+		client: func(c kubernetes.Interface, ns string) listableClient[*batchv1.Job, *batchv1.JobList] {
+			return c.BatchV1().Jobs(ns)
+		},
+		replace: true,
+	},
+	config.ResourceCronJob: applyAccessor[*batchv1.CronJob, *batchv1.CronJobList]{
+		client: func(c kubernetes.Interface, ns string) listableClient[*batchv1.CronJob, *batchv1.CronJobList] {
+			return c.BatchV1().CronJobs(ns)
+		},
+	},
+	config.ResourceService: applyAccessor[*corev1.Service, *corev1.ServiceList]{
+		client: func(c kubernetes.Interface, ns string) listableClient[*corev1.Service, *corev1.ServiceList] {
+			return c.CoreV1().Services(ns)
+		},
+		prepareUpdate: carryServiceFields,
+	},
+	config.ResourceConfigMap: applyAccessor[*corev1.ConfigMap, *corev1.ConfigMapList]{
+		client: func(c kubernetes.Interface, ns string) listableClient[*corev1.ConfigMap, *corev1.ConfigMapList] {
+			return c.CoreV1().ConfigMaps(ns)
+		},
+	},
+	config.ResourceSecret: applyAccessor[*corev1.Secret, *corev1.SecretList]{
+		client: func(c kubernetes.Interface, ns string) listableClient[*corev1.Secret, *corev1.SecretList] {
+			return c.CoreV1().Secrets(ns)
+		},
+	},
+	config.ResourcePVC: applyAccessor[*corev1.PersistentVolumeClaim, *corev1.PersistentVolumeClaimList]{
+		client: func(c kubernetes.Interface, ns string) listableClient[*corev1.PersistentVolumeClaim, *corev1.PersistentVolumeClaimList] {
+			return c.CoreV1().PersistentVolumeClaims(ns)
+		},
+	},
+	config.ResourceIngress: applyAccessor[*networkingv1.Ingress, *networkingv1.IngressList]{
+		client: func(c kubernetes.Interface, ns string) listableClient[*networkingv1.Ingress, *networkingv1.IngressList] {
+			return c.NetworkingV1().Ingresses(ns)
+		},
+	},
+	config.ResourceServiceAccount: applyAccessor[*corev1.ServiceAccount, *corev1.ServiceAccountList]{
+		client: func(c kubernetes.Interface, ns string) listableClient[*corev1.ServiceAccount, *corev1.ServiceAccountList] {
+			return c.CoreV1().ServiceAccounts(ns)
+		},
+	},
+	config.ResourceRole: applyAccessor[*rbacv1.Role, *rbacv1.RoleList]{
+		client: func(c kubernetes.Interface, ns string) listableClient[*rbacv1.Role, *rbacv1.RoleList] {
+			return c.RbacV1().Roles(ns)
+		},
+	},
+	config.ResourceRoleBinding: applyAccessor[*rbacv1.RoleBinding, *rbacv1.RoleBindingList]{
+		client: func(c kubernetes.Interface, ns string) listableClient[*rbacv1.RoleBinding, *rbacv1.RoleBindingList] {
+			return c.RbacV1().RoleBindings(ns)
+		},
+	},
+	config.ResourceClusterRole: applyAccessor[*rbacv1.ClusterRole, *rbacv1.ClusterRoleList]{
+		client: func(c kubernetes.Interface, _ string) listableClient[*rbacv1.ClusterRole, *rbacv1.ClusterRoleList] {
+			return c.RbacV1().ClusterRoles()
+		},
+	},
+	config.ResourceClusterRoleBinding: applyAccessor[*rbacv1.ClusterRoleBinding, *rbacv1.ClusterRoleBindingList]{
+		client: func(c kubernetes.Interface, _ string) listableClient[*rbacv1.ClusterRoleBinding, *rbacv1.ClusterRoleBindingList] {
+			return c.RbacV1().ClusterRoleBindings()
+		},
+	},
+}
+
+// isClusterScoped reports whether objects of the kind live outside any namespace.
+func isClusterScoped(kind config.ResourceKind) bool {
+	return kind == config.ResourceClusterRole || kind == config.ResourceClusterRoleBinding
+}
@@ -0,0 +1,316 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+)
+
+// diagnosticsLogLimitBytes caps the log excerpt stored per container.
+const diagnosticsLogLimitBytes = 16 * 1024
+
+// shouldCollectDiagnostics reports whether a wait failure is worth diagnosing; cancelled
+// jobs were stopped on purpose and say nothing about the workload.
+func shouldCollectDiagnostics(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if statusErr, ok := ExtractStatusError(err); ok && statusErr.Status == config.StatusCancelled {
+		return false
+	}
+	return true
+}
+
+// collectWorkloadDiagnostics captures the state of a workload that failed to become ready:
+// the phase and container states of its pods, the last log lines of failing containers and
+// recent Kubernetes Events of the workload, its ReplicaSets and pods. Collection is best
+// effort; problems are recorded in Error instead of failing the job a second time.
+func collectWorkloadDiagnostics(ctx context.Context, client kubernetes.Interface, job *model.JobTask, kind config.ResourceKind, name string) *model.JobDiagnostics {
+	namespace := job.Namespace
+	diag := &model.JobDiagnostics{
+		TaskID:    job.TaskID,
+		AppID:     job.AppID,
+		JobName:   job.Name,
+		Kind:      kind,
+		Namespace: namespace,
+		Name:      name,
+	}
+	// The job context is usually done after a timeout; collection runs on its own deadline.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), config.DiagnosticsTimeout)
+	defer cancel()
+
+	var errs []string
+	objects := []corev1.ObjectReference{{Kind: workloadKindName(kind), Name: name}}
+	selector, err := workloadSelector(ctx, client, kind, namespace, name)
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("get %s: %v", kind, err))
+	}
+	if selector != "" {
+		if kind == config.ResourceDeployment {
+			objects = append(objects, ownedReplicaSets(ctx, client, namespace, name, selector)...)
+		}
+		pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("list pods: %v", err))
+		} else {
+			for _, pod := range selectDiagnosticPods(pods.Items) {
+				diag.Pods = append(diag.Pods, diagnosePod(ctx, client, pod))
+				objects = append(objects, corev1.ObjectReference{Kind: "Pod", Name: pod.Name})
+			}
+		}
+	}
+	events, err := recentEvents(ctx, client, namespace, objects)
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("list events: %v", err))
+	}
+	diag.Events = events
+	diag.Error = strings.Join(errs, "; ")
+	return diag
+}
+
+// saveDiagnostics stores diag linked to the JobInfo row it explains.
+func saveDiagnostics(ctx context.Context, store datastore.DataStore, diag *model.JobDiagnostics, jobInfoID int) error {
+	if diag == nil {
+		return nil
+	}
+	diag.JobInfoID = jobInfoID
+	if err := store.Add(ctx, diag); err != nil {
+		return fmt.Errorf("save diagnostics of job %s: %w", diag.JobName, err)
+	}
+	return nil
+}
+
+func workloadKindName(kind config.ResourceKind) string {
+	switch kind {
+	case config.ResourceDeployment:
+		return "Deployment"
+	case config.ResourceStatefulSet:
+		return "StatefulSet"
+	case config.ResourceDaemonSet:
+		return "DaemonSet"
+	}
+	return string(kind)
+}
+
+// workloadSelector returns the pod label selector of the live workload.
+func workloadSelector(ctx context.Context, client kubernetes.Interface, kind config.ResourceKind, namespace, name string) (string, error) {
+	var selector *metav1.LabelSelector
+	switch kind {
+	case config.ResourceDeployment:
+		obj, err := client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		selector = obj.Spec.Selector
+	case config.ResourceStatefulSet:
+		obj, err := client.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		selector = obj.Spec.Selector
+	case config.ResourceDaemonSet:
+		obj, err := client.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		selector = obj.Spec.Selector
+	default:
+		return "", fmt.Errorf("unsupported workload kind %s", kind)
+	}
+	if selector == nil {
+		return "", nil
+	}
+	parsed, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return "", err
+	}
+	return parsed.String(), nil
+}
+
+// ownedReplicaSets lists the ReplicaSets of a Deployment; pod creation failures such as
+// exceeded quotas are reported as Events on them rather than on the Deployment.
+func ownedReplicaSets(ctx context.Context, client kubernetes.Interface, namespace, deployment, selector string) []corev1.ObjectReference {
+	list, err := client.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		klog.V(4).Infof("list replicasets of deployment %s/%s for diagnostics failed: %v", namespace, deployment, err)
+		return nil
+	}
+	var refs []corev1.ObjectReference
+	for _, rs := range list.Items {
+		for _, owner := range rs.OwnerReferences {
+			if owner.Kind == "Deployment" && owner.Name == deployment {
+				refs = append(refs, corev1.ObjectReference{Kind: "ReplicaSet", Name: rs.Name})
+				break
+			}
+		}
+	}
+	return refs
+}
+
+// selectDiagnosticPods keeps at most config.DiagnosticsMaxPods pods, unhealthy ones first.
+func selectDiagnosticPods(pods []corev1.Pod) []corev1.Pod {
+	sort.SliceStable(pods, func(i, j int) bool {
+		hi, hj := podHealthy(&pods[i]), podHealthy(&pods[j])
+		if hi != hj {
+			return !hi
+		}
+		return pods[i].Name < pods[j].Name
+	})
+	if len(pods) > config.DiagnosticsMaxPods {
+		pods = pods[:config.DiagnosticsMaxPods]
+	}
+	return pods
+}
+
+func podHealthy(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning && pod.Status.Phase != corev1.PodSucceeded {
+		return false
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if !cs.Ready {
+			return false
+		}
+	}
+	return true
+}
+
+func diagnosePod(ctx context.Context, client kubernetes.Interface, pod corev1.Pod) model.PodDiagnostic {
+	result := model.PodDiagnostic{
+		Name:    pod.Name,
+		Phase:   string(pod.Status.Phase),
+		Reason:  pod.Status.Reason,
+		Message: pod.Status.Message,
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Status == corev1.ConditionTrue {
+			continue
+		}
+		line := string(cond.Type)
+		if cond.Reason != "" {
+			line += ": " + cond.Reason
+		}
+		if cond.Message != "" {
+			line += " - " + cond.Message
+		}
+		result.Conditions = append(result.Conditions, line)
+	}
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, cs := range statuses {
+		container := model.ContainerDiagnostic{
+			Name:         cs.Name,
+			Ready:        cs.Ready,
+			RestartCount: cs.RestartCount,
+		}
+		if w := cs.State.Waiting; w != nil {
+			container.WaitingReason, container.WaitingMessage = w.Reason, w.Message
+		}
+		terminated := cs.State.Terminated
+		if terminated == nil {
+			terminated = cs.LastTerminationState.Terminated
+		}
+		if terminated != nil {
+			container.TerminatedReason, container.TerminatedMessage = terminated.Reason, terminated.Message
+			container.ExitCode = terminated.ExitCode
+		}
+		if !cs.Ready && (cs.State.Waiting != nil || terminated != nil) {
+			// A restarting container has nothing useful in its current instance yet.
+			previous := cs.State.Terminated == nil && cs.LastTerminationState.Terminated != nil
+			container.Logs = containerLogTail(ctx, client, pod.Namespace, pod.Name, cs.Name, previous)
+		}
+		result.Containers = append(result.Containers, container)
+	}
+	return result
+}
+
+func containerLogTail(ctx context.Context, client kubernetes.Interface, namespace, pod, container string, previous bool) string {
+	tail := int64(config.DiagnosticsLogTailLines)
+	limit := int64(diagnosticsLogLimitBytes)
+	stream, err := client.CoreV1().Pods(namespace).GetLogs(pod, &corev1.PodLogOptions{
+		Container:  container,
+		TailLines:  &tail,
+		LimitBytes: &limit,
+		Previous:   previous,
+	}).Stream(ctx)
+	if err != nil {
+		// Containers that never started (e.g. image pull failures) have no logs.
+		klog.V(4).Infof("read logs of %s/%s[%s] for diagnostics failed: %v", namespace, pod, container, err)
+		return ""
+	}
+	defer stream.Close()
+	data, err := io.ReadAll(io.LimitReader(stream, limit))
+	if err != nil {
+		klog.V(4).Infof("read logs of %s/%s[%s] for diagnostics failed: %v", namespace, pod, container, err)
+	}
+	return string(data)
+}
+
+// recentEvents returns the latest config.DiagnosticsMaxEvents events of the given objects
+// in chronological order.
+func recentEvents(ctx context.Context, client kubernetes.Interface, namespace string, objects []corev1.ObjectReference) ([]model.EventDiagnostic, error) {
+	type timedEvent struct {
+		event model.EventDiagnostic
+		at    time.Time
+	}
+	var collected []timedEvent
+	for _, obj := range objects {
+		list, err := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+			FieldSelector: fields.Set{"involvedObject.name": obj.Name, "involvedObject.kind": obj.Kind}.String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, ev := range list.Items {
+			if ev.InvolvedObject.Name != obj.Name || ev.InvolvedObject.Kind != obj.Kind {
+				continue
+			}
+			item := timedEvent{
+				event: model.EventDiagnostic{
+					Object:  obj.Kind + "/" + obj.Name,
+					Type:    ev.Type,
+					Reason:  ev.Reason,
+					Message: ev.Message,
+					Count:   ev.Count,
+				},
+				at: eventTime(&ev),
+			}
+			if !item.at.IsZero() {
+				item.event.LastSeen = item.at.Unix()
+			}
+			collected = append(collected, item)
+		}
+	}
+	sort.SliceStable(collected, func(i, j int) bool { return collected[i].at.Before(collected[j].at) })
+	if len(collected) > config.DiagnosticsMaxEvents {
+		collected = collected[len(collected)-config.DiagnosticsMaxEvents:]
+	}
+	events := make([]model.EventDiagnostic, 0, len(collected))
+	for _, item := range collected {
+		events = append(events, item.event)
+	}
+	return events, nil
+}
+
+func eventTime(ev *corev1.Event) time.Time {
+	switch {
+	case !ev.LastTimestamp.IsZero():
+		return ev.LastTimestamp.Time
+	case !ev.EventTime.IsZero():
+		return ev.EventTime.Time
+	default:
+		return ev.FirstTimestamp.Time
+	}
+}
@@ -0,0 +1,191 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+func crashingPod(name string, labels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodScheduled, Status: corev1.ConditionTrue},
+				{Type: corev1.PodReady, Status: corev1.ConditionFalse, Reason: "ContainersNotReady"},
+			},
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "web",
+				RestartCount: 4,
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+					Reason:  "CrashLoopBackOff",
+					Message: "back-off 1m20s restarting failed container",
+				}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+					Reason:   "Error",
+					ExitCode: 1,
+				}},
+			}},
+		},
+	}
+}
+
+func diagnosticsEvent(name, kind, object, reason string, at time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: kind, Name: object, Namespace: "default"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         reason,
+		Message:        reason + " happened",
+		Count:          1,
+		LastTimestamp:  metav1.NewTime(at),
+	}
+}
+
+func TestCollectWorkloadDiagnostics_Deployment(t *testing.T) {
+	labels := map[string]string{"app": "web"}
+	now := time.Now().Truncate(time.Second)
+	client := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}},
+		},
+		&appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "web-7d9f",
+				Namespace:       "default",
+				Labels:          labels,
+				OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "web"}},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-a", Namespace: "default", Labels: labels},
+			Status: corev1.PodStatus{
+				Phase:             corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "web", Ready: true}},
+			},
+		},
+		crashingPod("web-b", labels),
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default", Labels: map[string]string{"app": "other"}}},
+		diagnosticsEvent("ev-1", "ReplicaSet", "web-7d9f", "FailedCreate", now.Add(-3*time.Minute)),
+		diagnosticsEvent("ev-2", "Pod", "web-b", "BackOff", now.Add(-time.Minute)),
+		diagnosticsEvent("ev-3", "Deployment", "web", "ProgressDeadlineExceeded", now.Add(-2*time.Minute)),
+		diagnosticsEvent("ev-4", "Pod", "other", "Unrelated", now),
+	)
+	job := &model.JobTask{Name: "web", Namespace: "default", AppID: "app-1", TaskID: "task-1"}
+
+	diag := collectWorkloadDiagnostics(context.Background(), client, job, config.ResourceDeployment, "web")
+
+	require.Empty(t, diag.Error)
+	require.Equal(t, "task-1", diag.TaskID)
+	require.Equal(t, config.ResourceDeployment, diag.Kind)
+	require.Len(t, diag.Pods, 2)
+	// Unhealthy pods come first.
+	failing := diag.Pods[0]
+	require.Equal(t, "web-b", failing.Name)
+	require.Equal(t, []string{"Ready: ContainersNotReady"}, failing.Conditions)
+	require.Len(t, failing.Containers, 1)
+	container := failing.Containers[0]
+	require.Equal(t, "CrashLoopBackOff", container.WaitingReason)
+	require.Equal(t, "Error", container.TerminatedReason)
+	require.EqualValues(t, 1, container.ExitCode)
+	require.EqualValues(t, 4, container.RestartCount)
+	require.Equal(t, "fake logs", container.Logs)
+	require.Equal(t, "web-a", diag.Pods[1].Name)
+	require.Empty(t, diag.Pods[1].Containers[0].Logs)
+
+	reasons := make([]string, 0, len(diag.Events))
+	for _, ev := range diag.Events {
+		reasons = append(reasons, ev.Reason)
+	}
+	require.Equal(t, []string{"FailedCreate", "ProgressDeadlineExceeded", "BackOff"}, reasons)
+	require.Equal(t, "ReplicaSet/web-7d9f", diag.Events[0].Object)
+	require.Equal(t, now.Add(-3*time.Minute).Unix(), diag.Events[0].LastSeen)
+}
+
+func TestCollectWorkloadDiagnostics_LimitsPodsAndEvents(t *testing.T) {
+	labels := map[string]string{"app": "web"}
+	now := time.Now()
+	objects := []runtime.Object{&appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+		Spec:       appsv1.StatefulSetSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}},
+	}}
+	for i := 0; i < config.DiagnosticsMaxPods+2; i++ {
+		objects = append(objects, crashingPod(fmt.Sprintf("db-%d", i), labels))
+	}
+	for i := 0; i < config.DiagnosticsMaxEvents+5; i++ {
+		objects = append(objects, diagnosticsEvent(fmt.Sprintf("ev-%d", i), "StatefulSet", "db", fmt.Sprintf("R%d", i), now.Add(time.Duration(i)*time.Second)))
+	}
+	client := fake.NewSimpleClientset(objects...)
+
+	diag := collectWorkloadDiagnostics(context.Background(), client, &model.JobTask{Name: "db", Namespace: "default"}, config.ResourceStatefulSet, "db")
+
+	require.Len(t, diag.Pods, config.DiagnosticsMaxPods)
+	require.Len(t, diag.Events, config.DiagnosticsMaxEvents)
+	// The newest events are kept.
+	require.Equal(t, fmt.Sprintf("R%d", config.DiagnosticsMaxEvents+4), diag.Events[len(diag.Events)-1].Reason)
+}
+
+func TestCollectWorkloadDiagnostics_MissingWorkload(t *testing.T) {
+	diag := collectWorkloadDiagnostics(context.Background(), fake.NewSimpleClientset(), &model.JobTask{Name: "web", Namespace: "default"}, config.ResourceDaemonSet, "web")
+
+	require.Contains(t, diag.Error, "not found")
+	require.Empty(t, diag.Pods)
+}
+
+func TestShouldCollectDiagnostics(t *testing.T) {
+	require.False(t, shouldCollectDiagnostics(nil))
+	require.False(t, shouldCollectDiagnostics(context.Canceled))
+	require.False(t, shouldCollectDiagnostics(NewStatusError(config.StatusCancelled, errors.New("cancelled"))))
+	require.True(t, shouldCollectDiagnostics(NewStatusError(config.StatusTimeout, errors.New("timeout"))))
+	require.True(t, shouldCollectDiagnostics(errors.New("boom")))
+}
+
+func TestDeployJobCtl_TimeoutSavesDiagnostics(t *testing.T) {
+	component := newWorkloadComponent(t, config.ServerJob)
+	component.Name = "web"
+	result := GenerateWebService(component, &model.Properties{})
+	require.NotNil(t, result)
+	deployment := result.Service.(*appsv1.Deployment)
+	client := fake.NewSimpleClientset(crashingPod("web-0", deployment.Spec.Selector.MatchLabels))
+
+	jobTask := &model.JobTask{
+		Name:      component.Name,
+		Namespace: "default",
+		AppID:     "app-1",
+		TaskID:    "task-1",
+		JobType:   string(config.JobDeploy),
+		JobInfo:   deployment,
+		Timeout:   1,
+	}
+	store := &jobInfoStore{}
+	ctl := NewDeployJobCtl(jobTask, client, store, func() {})
+
+	err := ctl.Run(context.Background())
+	require.Error(t, err)
+	require.Equal(t, config.StatusTimeout, jobTask.Status)
+	require.NoError(t, ctl.SaveInfo(context.Background()))
+
+	require.Len(t, store.added, 2)
+	_, ok := store.added[0].(*model.JobInfo)
+	require.True(t, ok)
+	diag, ok := store.added[1].(*model.JobDiagnostics)
+	require.True(t, ok)
+	require.Equal(t, "task-1", diag.TaskID)
+	require.Equal(t, deployment.Name, diag.Name)
+	require.Len(t, diag.Pods, 1)
+	require.Equal(t, "CrashLoopBackOff", diag.Pods[0].Containers[0].WaitingReason)
+}
@@ -0,0 +1,149 @@
+package job
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	applyv1 "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+// desiredObject resolves the object a job would write, applying the same naming and
+// namespace defaults as its JobCtl. A nil object means the job writes nothing.
+func desiredObject(job *model.JobTask) (config.ResourceKind, metav1.Object, error) {
+	var (
+		kind config.ResourceKind
+		obj  metav1.Object
+		ok   bool
+		err  error
+	)
+	switch job.JobType {
+	case string(config.JobDeploy):
+		var deploy *appsv1.Deployment
+		if deploy, ok = job.JobInfo.(*appsv1.Deployment); ok {
+			deploy.Name = buildWebServiceName(job.Name, job.AppID)
+		}
+		kind, obj = config.ResourceDeployment, deploy
+	case string(config.JobDeployStore):
+		var statefulSet *appsv1.StatefulSet
+		if statefulSet, ok = job.JobInfo.(*appsv1.StatefulSet); ok {
+			statefulSet.Name = buildStoreSeverName(job.Name, job.AppID)
+		}
+		kind, obj = config.ResourceStatefulSet, statefulSet
+	case string(config.JobDeployDaemonSet):
+		var daemonSet *appsv1.DaemonSet
+		if daemonSet, ok = job.JobInfo.(*appsv1.DaemonSet); ok {
+			daemonSet.Name = buildDaemonSetName(job.Name, job.AppID)
+		}
+		kind, obj = config.ResourceDaemonSet, daemonSet
+	case string(config.JobDeployBatchJob):
+		kind = config.ResourceJob
+		obj, ok = job.JobInfo.(*batchv1.Job)
+	case string(config.JobDeployCronJob):
+		kind = config.ResourceCronJob
+		obj, ok = job.JobInfo.(*batchv1.CronJob)
+	case string(config.JobDeployService):
+		var svc *applyv1.ServiceApplyConfiguration
+		if svc, ok = job.JobInfo.(*applyv1.ServiceApplyConfiguration); ok && svc != nil {
+			obj = buildCoreService(svc)
+		}
+		kind = config.ResourceService
+	case string(config.JobDeployConfigMap):
+		kind, ok = config.ResourceConfigMap, true
+		if obj, err = configMapFromJobInfo(job.JobInfo); err != nil {
+			return kind, nil, err
+		}
+	case string(config.JobDeploySecret):
+		kind, ok = config.ResourceSecret, true
+		if obj, err = secretFromJobInfo(job.JobInfo); err != nil {
+			return kind, nil, err
+		}
+	case string(config.JobDeployPVC):
+		kind = config.ResourcePVC
+		obj, ok = job.JobInfo.(*corev1.PersistentVolumeClaim)
+	case string(config.JobDeployIngress):
+		kind = config.ResourceIngress
+		obj, ok = job.JobInfo.(*networkingv1.Ingress)
+	case string(config.JobDeployServiceAccount):
+		kind = config.ResourceServiceAccount
+		obj, ok = job.JobInfo.(*corev1.ServiceAccount)
+	case string(config.JobDeployRole):
+		kind = config.ResourceRole
+		obj, ok = job.JobInfo.(*rbacv1.Role)
+	case string(config.JobDeployRoleBinding):
+		kind = config.ResourceRoleBinding
+		obj, ok = job.JobInfo.(*rbacv1.RoleBinding)
+	case string(config.JobDeployClusterRole):
+		kind = config.ResourceClusterRole
+		obj, ok = job.JobInfo.(*rbacv1.ClusterRole)
+	case string(config.JobDeployClusterRoleBinding):
+		kind = config.ResourceClusterRoleBinding
+		obj, ok = job.JobInfo.(*rbacv1.ClusterRoleBinding)
+	case string(config.JobApproval), string(config.JobWait), string(config.JobHTTPCheck), string(config.JobNotify):
+		return "", nil, nil
+	default:
+		return "", nil, fmt.Errorf("unknown job type: %s", job.JobType)
+	}
+	if !ok {
+		return kind, nil, fmt.Errorf("%s job info conversion type failure: %T", job.JobType, job.JobInfo)
+	}
+	if obj.GetNamespace() == "" && !isClusterScoped(kind) {
+		obj.SetNamespace(job.Namespace)
+	}
+	return kind, obj, nil
+}
+
+// DryRunJob submits the object a job would write with DryRun=All and reports whether
+// the API server would accept it. Jobs that write nothing to the cluster are skipped.
+func DryRunJob(ctx context.Context, client kubernetes.Interface, job *model.JobTask) model.DryRunJobResult {
+	result := model.DryRunJobResult{
+		Name:      job.Name,
+		JobType:   job.JobType,
+		Namespace: job.Namespace,
+		Status:    config.StatusCompleted,
+	}
+	fail := func(err error) model.DryRunJobResult {
+		result.Status = config.StatusFailed
+		result.Error = err.Error()
+		return result
+	}
+	if job.Status == config.StatusSkipped {
+		result.Status = config.StatusSkipped
+		return result
+	}
+	kind, obj, err := desiredObject(job)
+	result.Kind = kind
+	if err != nil {
+		return fail(err)
+	}
+	if obj == nil {
+		result.Status = config.StatusSkipped
+		return result
+	}
+	result.Namespace = obj.GetNamespace()
+	result.Resource = obj.GetName()
+	if _, strategy := shareInfoFromLabels(obj.GetLabels()); strategy == config.ShareStrategyIgnore {
+		result.Status = config.StatusSkipped
+		return result
+	}
+	if client == nil {
+		return fail(fmt.Errorf("client is nil"))
+	}
+	accessor, ok := applyAccessors[kind]
+	if !ok {
+		return fail(fmt.Errorf("dry-run unsupported for %s", kind))
+	}
+	if err := accessor.dryRun(ctx, client, obj); err != nil {
+		return fail(err)
+	}
+	return result
+}
@@ -0,0 +1,123 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+// dryRunRecorder intercepts writes like an API server honouring DryRun=All: it checks
+// the option is set, optionally rejects the object, and never persists anything.
+type dryRunRecorder struct {
+	t       *testing.T
+	verbs   []string
+	objects []runtime.Object
+	reject  string
+}
+
+func (r *dryRunRecorder) install(client *fake.Clientset) {
+	client.PrependReactor("*", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		var dryRun []string
+		var obj runtime.Object
+		switch a := action.(type) {
+		case k8stesting.CreateActionImpl:
+			dryRun, obj = a.CreateOptions.DryRun, a.Object
+		case k8stesting.UpdateActionImpl:
+			dryRun, obj = a.UpdateOptions.DryRun, a.Object
+		case k8stesting.DeleteActionImpl:
+			dryRun = a.DeleteOptions.DryRun
+		default:
+			return false, nil, nil
+		}
+		require.Equal(r.t, []string{metav1.DryRunAll}, dryRun, "%s must be a dry-run", action.GetVerb())
+		r.verbs = append(r.verbs, action.GetVerb()+"/"+action.GetResource().Resource)
+		r.objects = append(r.objects, obj)
+		if r.reject != "" && action.GetResource().Resource == r.reject {
+			return true, nil, k8serrors.NewForbidden(schema.GroupResource{Resource: r.reject}, "", errors.New("exceeded quota: compute-resources"))
+		}
+		return true, obj, nil
+	})
+}
+
+func TestDryRunJobCreatesAndUpdatesWithoutPersisting(t *testing.T) {
+	existing := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "default", ResourceVersion: "7"}}
+	client := fake.NewSimpleClientset(existing)
+	recorder := &dryRunRecorder{t: t}
+	recorder.install(client)
+
+	deploy := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default"}}
+	result := DryRunJob(context.Background(), client, &model.JobTask{
+		Name: "web", AppID: "app-1", Namespace: "default", JobType: string(config.JobDeploy), JobInfo: deploy,
+	})
+	require.Equal(t, config.StatusCompleted, result.Status, result.Error)
+	require.Equal(t, config.ResourceDeployment, result.Kind)
+	require.Equal(t, buildWebServiceName("web", "app-1"), result.Resource)
+
+	result = DryRunJob(context.Background(), client, &model.JobTask{
+		Name: "app-config", Namespace: "default", JobType: string(config.JobDeployConfigMap),
+		JobInfo: &model.ConfigMapInput{Name: "app-config", Data: map[string]string{"k": "v"}},
+	})
+	require.Equal(t, config.StatusCompleted, result.Status, result.Error)
+
+	require.Equal(t, []string{"create/deployments", "update/configmaps"}, recorder.verbs)
+	updated := recorder.objects[1].(*corev1.ConfigMap)
+	require.Equal(t, "7", updated.ResourceVersion)
+
+	_, err := client.AppsV1().Deployments("default").Get(context.Background(), result.Resource, metav1.GetOptions{})
+	require.True(t, k8serrors.IsNotFound(err))
+}
+
+func TestDryRunJobReportsAdmissionError(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	recorder := &dryRunRecorder{t: t, reject: "deployments"}
+	recorder.install(client)
+
+	result := DryRunJob(context.Background(), client, &model.JobTask{
+		Name: "web", AppID: "app-1", Namespace: "default", JobType: string(config.JobDeploy), JobInfo: &appsv1.Deployment{},
+	})
+	require.Equal(t, config.StatusFailed, result.Status)
+	require.Contains(t, result.Error, "exceeded quota")
+	require.Equal(t, "default", result.Namespace)
+}
+
+func TestDryRunJobReplacesExistingBatchJob(t *testing.T) {
+	jobTask, batchJob := newBatchJobTask(t)
+	client := fake.NewSimpleClientset(batchJob.DeepCopy())
+	recorder := &dryRunRecorder{t: t}
+	recorder.install(client)
+
+	result := DryRunJob(context.Background(), client, jobTask)
+	require.Equal(t, config.StatusCompleted, result.Status, result.Error)
+	require.Equal(t, []string{"delete/jobs", "create/jobs"}, recorder.verbs)
+	replacement := recorder.objects[1].(*batchv1.Job)
+	require.Equal(t, result.Resource+"-", replacement.GenerateName)
+}
+
+func TestDryRunJobSkipsJobsWithoutObjects(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	recorder := &dryRunRecorder{t: t}
+	recorder.install(client)
+
+	result := DryRunJob(context.Background(), client, &model.JobTask{
+		Name: "settle", JobType: string(config.JobWait), JobInfo: &model.StepSpec{DurationSeconds: 5},
+	})
+	require.Equal(t, config.StatusSkipped, result.Status)
+	require.Empty(t, recorder.verbs)
+
+	result = DryRunJob(context.Background(), client, &model.JobTask{Name: "x", JobType: "unknown"})
+	require.Equal(t, config.StatusFailed, result.Status)
+}
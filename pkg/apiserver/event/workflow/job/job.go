@@ -36,6 +36,8 @@ type GenerateServiceResult struct {
 
 type taskIDKey struct{}
 
+type jobObserverKey struct{}
+
 // StatusError wraps an error with an explicit job status for persistence.
 type StatusError struct {
 	Status config.Status
@@ -80,6 +82,40 @@ func TaskIDFromContext(ctx context.Context) string {
 	return ""
 }
 
+// WithJobObserver registers a callback invoked whenever a job run from ctx changes status.
+func WithJobObserver(ctx context.Context, observer func(job *model.JobTask)) context.Context {
+	if observer == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, jobObserverKey{}, observer)
+}
+
+// observeJob wraps ack so the observer registered in ctx, if any, is notified after
+// each acknowledged status or attempt change of job.
+func observeJob(ctx context.Context, job *model.JobTask, ack func()) func() {
+	observer, _ := ctx.Value(jobObserverKey{}).(func(job *model.JobTask))
+	if observer == nil {
+		return ack
+	}
+	var (
+		mu          sync.Mutex
+		lastStatus  config.Status
+		lastAttempt int
+	)
+	return func() {
+		if ack != nil {
+			ack()
+		}
+		mu.Lock()
+		changed := job.Status != lastStatus || job.Attempt() != lastAttempt
+		lastStatus, lastAttempt = job.Status, job.Attempt()
+		mu.Unlock()
+		if changed {
+			observer(job)
+		}
+	}
+}
+
 func initJobCtl(job *model.JobTask, client kubernetes.Interface, store datastore.DataStore, ack func()) JobCtl {
 	if store == nil {
 		klog.Errorf("initJobCtl store is nil")
@@ -102,6 +138,12 @@ func initJobCtl(job *model.JobTask, client kubernetes.Interface, store datastore
 		jobCtl = NewDeployServiceJobCtl(job, client, store, ack)
 	case string(config.JobDeployStore):
 		jobCtl = NewDeployStatefulSetJobCtl(job, client, store, ack)
+	case string(config.JobDeployDaemonSet):
+		jobCtl = NewDeployDaemonSetJobCtl(job, client, store, ack)
+	case string(config.JobDeployBatchJob):
+		jobCtl = NewDeployBatchJobCtl(job, client, store, ack)
+	case string(config.JobDeployCronJob):
+		jobCtl = NewDeployCronJobCtl(job, client, store, ack)
 	case string(config.JobDeployPVC):
 		jobCtl = NewDeployPVCJobCtl(job, client, store, ack)
 	case string(config.JobDeployConfigMap):
@@ -120,6 +162,12 @@ func initJobCtl(job *model.JobTask, client kubernetes.Interface, store datastore
 		jobCtl = NewDeployClusterRoleJobCtl(job, client, store, ack)
 	case string(config.JobDeployClusterRoleBinding):
 		jobCtl = NewDeployClusterRoleBindingJobCtl(job, client, store, ack)
+	case string(config.JobWait):
+		jobCtl = NewWaitJobCtl(job, store, ack)
+	case string(config.JobHTTPCheck):
+		jobCtl = NewHTTPCheckJobCtl(job, store, ack)
+	case string(config.JobNotify):
+		jobCtl = NewNotifyJobCtl(job, store, ack)
 	default:
 		klog.Errorf("unknown job type: %s", job.JobType)
 		return nil
@@ -165,6 +213,7 @@ func runJob(ctx context.Context, job *model.JobTask, client kubernetes.Interface
 	)
 	ctx = klog.NewContext(ctx, logger)
 	ctx = WithCleanupTracker(ctx)
+	ack = observeJob(ctx, job, ack)
 
 	var (
 		watcher  *signal.CancelWatcher
@@ -223,6 +272,27 @@ func runJob(ctx context.Context, job *model.JobTask, client kubernetes.Interface
 		}
 		return
 	}
+	for {
+		err := runJobAttempt(ctx, jobCtx, span, job, client, store, ack)
+		if !shouldRetryJob(jobCtx, job, err) {
+			return
+		}
+		delay := retryDelay(job.RetryPolicy, job.RetryCount+1)
+		logger.Info("Retrying job after transient failure", "attempt", job.Attempt(), "maxAttempts", job.RetryPolicy.Attempts, "delay", delay, "error", job.Error)
+		select {
+		case <-jobCtx.Done():
+			return
+		case <-time.After(delay):
+		}
+		job.RetryCount++
+		job.EndTime = 0
+	}
+}
+
+// runJobAttempt executes the job controller once and persists the attempt in JobInfo.
+// It returns the error reported by the controller so the caller can decide whether to retry.
+func runJobAttempt(ctx, jobCtx context.Context, span trace.Span, job *model.JobTask, client kubernetes.Interface, store datastore.DataStore, ack func()) (runErr error) {
+	logger := klog.FromContext(ctx)
 	job.Status = config.StatusPrepare
 	job.Error = ""
 	job.StartTime = time.Now().Unix()
@@ -230,9 +300,9 @@ func runJob(ctx context.Context, job *model.JobTask, client kubernetes.Interface
 
 	if store == nil {
 		klog.Error("start job store is nil")
-		return
+		return nil
 	}
-	logger.Info("Starting job", "jobType", job.JobType, "status", job.Status)
+	logger.Info("Starting job", "jobType", job.JobType, "status", job.Status, "attempt", job.Attempt())
 	jobCtl := initJobCtl(job, client, store, ack)
 	if jobCtl == nil {
 		errMsg := fmt.Sprintf("failed to initialize job controller for job: %s", job.Name)
@@ -243,7 +313,7 @@ func runJob(ctx context.Context, job *model.JobTask, client kubernetes.Interface
 		span.SetStatus(codes.Error, "Failed to initialize job controller")
 		span.RecordError(errors.New(errMsg))
 		ack()
-		return
+		return nil
 	}
 
 	cleaned := false
@@ -260,6 +330,7 @@ func runJob(ctx context.Context, job *model.JobTask, client kubernetes.Interface
 			job.Error = errMsg
 			span.SetStatus(codes.Error, "Panic in job execution")
 			span.RecordError(errors.New(errMsg))
+			runErr = nil
 		}
 		job.EndTime = time.Now().Unix()
 		if job.Error != "" {
@@ -269,12 +340,18 @@ func runJob(ctx context.Context, job *model.JobTask, client kubernetes.Interface
 		}
 		ack()
 		logger.Info("Updating job info in db...")
-		if err := jobCtl.SaveInfo(jobCtx); err != nil {
+		// The job context is done once a task or step deadline passes; the attempt must still be recorded.
+		if err := jobCtl.SaveInfo(context.WithoutCancel(jobCtx)); err != nil {
 			logger.Error(err, "Failed to update job info in db")
 		}
 	}()
 
-	if err := jobCtl.Run(jobCtx); err != nil {
+	err := captureResourceSnapshot(jobCtx, client, job)
+	if err == nil {
+		err = jobCtl.Run(jobCtx)
+	}
+	if err != nil {
+		runErr = err
 		if !cleaned {
 			jobCtl.Clean(jobCtx)
 			cleaned = true
@@ -292,6 +369,8 @@ func runJob(ctx context.Context, job *model.JobTask, client kubernetes.Interface
 				job.Error = reason
 			}
 			job.Status = config.StatusCancelled
+		} else if errors.Is(err, context.DeadlineExceeded) {
+			job.Status = config.StatusTimeout
 		} else if job.Status != config.StatusFailed && job.Status != config.StatusCancelled && job.Status != config.StatusTimeout {
 			job.Status = config.StatusFailed
 		}
@@ -302,6 +381,7 @@ func runJob(ctx context.Context, job *model.JobTask, client kubernetes.Interface
 	if !cleaned && jobStatusFailed(job.Status) {
 		jobCtl.Clean(jobCtx)
 	}
+	return runErr
 }
 
 func jobStatusFailed(status config.Status) bool {
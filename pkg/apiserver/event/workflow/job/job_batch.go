@@ -0,0 +1,461 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+	"kubemin-cli/pkg/apiserver/infrastructure/informer"
+	"kubemin-cli/pkg/apiserver/utils"
+	traitsPlu "kubemin-cli/pkg/apiserver/workflow/traits"
+)
+
+// batchDeleteGrace bounds how long a rerun waits for the previous Job to disappear.
+const batchDeleteGrace = 60 * time.Second
+
+// DeployBatchJobCtl 部署一次性批处理 Job 并等待其执行结束。Job 的 Pod 模板不可变，
+// 重新执行时先删除旧 Job 再创建
+type DeployBatchJobCtl struct {
+	job    *model.JobTask
+	client kubernetes.Interface
+	store  datastore.DataStore
+	ack    func()
+	// keep 为 true 表示 Job 已运行并自行失败，保留它以便排查，不在 Clean 中删除
+	keep bool
+}
+
+func NewDeployBatchJobCtl(job *model.JobTask, client kubernetes.Interface, store datastore.DataStore, ack func()) *DeployBatchJobCtl {
+	if job == nil {
+		klog.Errorf("DeployBatchJobCtl: job is nil")
+		return nil
+	}
+	return &DeployBatchJobCtl{
+		job:    job,
+		client: client,
+		store:  store,
+		ack:    ack,
+	}
+}
+
+func (c *DeployBatchJobCtl) Clean(ctx context.Context) {
+	if c.client == nil || c.keep {
+		return
+	}
+	refs := resourcesForCleanup(ctx, config.ResourceJob)
+	if len(refs) == 0 {
+		return
+	}
+	cleanupCtx, cancel := context.WithTimeout(context.Background(), config.DeleteTimeout)
+	defer cancel()
+	for _, ref := range refs {
+		if !ref.Created {
+			continue
+		}
+		ns := ref.Namespace
+		if ns == "" {
+			ns = c.job.Namespace
+		}
+		if err := deleteBatchJob(cleanupCtx, c.client, ns, ref.Name); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				klog.Errorf("failed to delete job %s/%s during cleanup: %v", ns, ref.Name, err)
+			}
+		} else {
+			klog.Infof("deleted job %s/%s after job failure", ns, ref.Name)
+		}
+	}
+}
+
+func (c *DeployBatchJobCtl) SaveInfo(ctx context.Context) error {
+	jobInfo := model.JobInfo{
+		Type:        c.job.JobType,
+		WorkflowID:  c.job.WorkflowID,
+		ProductID:   c.job.ProjectID,
+		AppID:       c.job.AppID,
+		TaskID:      c.job.TaskID,
+		Status:      string(c.job.Status),
+		StartTime:   c.job.StartTime,
+		EndTime:     c.job.EndTime,
+		Error:       c.job.Error,
+		ServiceName: c.job.Name,
+		Attempt:     c.job.Attempt(),
+	}
+	return c.store.Add(ctx, &jobInfo)
+}
+
+func (c *DeployBatchJobCtl) Run(ctx context.Context) error {
+	c.job.Status = config.StatusRunning
+	c.job.Error = ""
+	c.ack()
+
+	batchJob, ok := c.job.JobInfo.(*batchv1.Job)
+	if !ok {
+		err := fmt.Errorf("batch job JobInfo conversion type failure: %T", c.job.JobInfo)
+		c.job.Status = config.StatusFailed
+		c.job.Error = err.Error()
+		return err
+	}
+	if batchJob.Namespace == "" {
+		batchJob.Namespace = c.job.Namespace
+	}
+	if err := c.apply(ctx, batchJob); err != nil {
+		c.job.Status = config.StatusFailed
+		c.job.Error = err.Error()
+		return err
+	}
+	if err := c.wait(ctx, batchJob); err != nil {
+		c.job.Error = err.Error()
+		if statusErr, ok := ExtractStatusError(err); ok {
+			c.job.Status = statusErr.Status
+		} else {
+			c.job.Status = config.StatusFailed
+		}
+		c.keep = c.job.Status == config.StatusFailed
+		return err
+	}
+	c.job.Status = config.StatusCompleted
+	c.job.Error = ""
+	return nil
+}
+
+// apply replaces any previous run of the Job and creates it afresh.
+func (c *DeployBatchJobCtl) apply(ctx context.Context, batchJob *batchv1.Job) error {
+	logger := klog.FromContext(ctx)
+	cli := c.client.BatchV1().Jobs(batchJob.Namespace)
+	if _, err := cli.Get(ctx, batchJob.Name, metav1.GetOptions{}); err == nil {
+		logger.Info("Replacing previous run of job", "namespace", batchJob.Namespace, "name", batchJob.Name)
+		if err := deleteBatchJob(ctx, c.client, batchJob.Namespace, batchJob.Name); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("delete previous job %q failed: %w", batchJob.Name, err)
+		}
+		if err := waitBatchJobGone(ctx, c.client, batchJob.Namespace, batchJob.Name); err != nil {
+			return err
+		}
+	} else if !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("get job %q failed: %w", batchJob.Name, err)
+	}
+	if _, err := cli.Create(ctx, batchJob, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("create job %q failed: %w", batchJob.Name, err)
+	}
+	MarkResourceCreated(ctx, config.ResourceJob, batchJob.Namespace, batchJob.Name)
+	logger.Info("Job created", "namespace", batchJob.Namespace, "name", batchJob.Name)
+	return nil
+}
+
+// wait blocks until the Job completes or fails, preferring informer events over polling.
+func (c *DeployBatchJobCtl) wait(ctx context.Context, batchJob *batchv1.Job) error {
+	timeout := c.timeout(batchJob)
+	if waiter := GetGlobalWaiter(); waiter != nil {
+		klog.V(4).Infof("Using informer-based wait for job %s/%s", batchJob.Namespace, batchJob.Name)
+		err := waiter.WaitForJobComplete(ctx, batchJob.Namespace, batchJob.Name, timeout)
+		if err != nil {
+			var we *informer.WaitError
+			if errors.As(err, &we) {
+				return NewStatusError(we.Status, we.Err)
+			}
+			return err
+		}
+		return nil
+	}
+	klog.V(4).Infof("Waiter not initialized, falling back to polling for job %s/%s", batchJob.Namespace, batchJob.Name)
+	return c.waitPolling(ctx, batchJob, timeout)
+}
+
+func (c *DeployBatchJobCtl) waitPolling(ctx context.Context, batchJob *batchv1.Job, timeout time.Duration) error {
+	deadline := time.After(timeout)
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return NewStatusError(config.StatusCancelled, fmt.Errorf("job %s cancelled: %w", batchJob.Name, ctx.Err()))
+		case <-deadline:
+			return NewStatusError(config.StatusTimeout, fmt.Errorf("wait job %s timeout", batchJob.Name))
+		case <-ticker.C:
+			current, err := c.client.BatchV1().Jobs(batchJob.Namespace).Get(ctx, batchJob.Name, metav1.GetOptions{})
+			if err != nil {
+				if k8serrors.IsNotFound(err) {
+					return NewStatusError(config.StatusFailed, fmt.Errorf("job %s/%s was deleted", batchJob.Namespace, batchJob.Name))
+				}
+				return fmt.Errorf("wait job %s error: %w", batchJob.Name, err)
+			}
+			status := informer.ExtractJobStatus(current)
+			switch {
+			case status.Complete:
+				return nil
+			case status.FailedDone:
+				return NewStatusError(config.StatusFailed, fmt.Errorf("job %s/%s failed: %s", batchJob.Namespace, batchJob.Name, status.Message))
+			}
+		}
+	}
+}
+
+// timeout uses the job task timeout, extended to cover the Job's own activeDeadlineSeconds.
+func (c *DeployBatchJobCtl) timeout(batchJob *batchv1.Job) time.Duration {
+	if c.job.Timeout == 0 {
+		c.job.Timeout = config.DeployTimeout
+	}
+	timeout := time.Duration(c.job.Timeout) * time.Second
+	if deadline := batchJob.Spec.ActiveDeadlineSeconds; deadline != nil {
+		if jobDeadline := time.Duration(*deadline)*time.Second + time.Minute; jobDeadline > timeout {
+			timeout = jobDeadline
+		}
+	}
+	return timeout
+}
+
+// deleteBatchJob removes a Job together with its pods.
+func deleteBatchJob(ctx context.Context, client kubernetes.Interface, namespace, name string) error {
+	propagation := metav1.DeletePropagationBackground
+	return client.BatchV1().Jobs(namespace).Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+}
+
+func waitBatchJobGone(ctx context.Context, client kubernetes.Interface, namespace, name string) error {
+	waitCtx, cancel := context.WithTimeout(ctx, batchDeleteGrace)
+	defer cancel()
+	for {
+		if _, err := client.BatchV1().Jobs(namespace).Get(waitCtx, name, metav1.GetOptions{}); k8serrors.IsNotFound(err) {
+			return nil
+		}
+		if err := sleepContext(waitCtx, time.Second); err != nil {
+			return fmt.Errorf("previous job %s/%s still exists: %w", namespace, name, err)
+		}
+	}
+}
+
+// DeployCronJobCtl 创建或更新 CronJob；CronJob 仅登记调度，不等待任何一次执行
+type DeployCronJobCtl struct {
+	job    *model.JobTask
+	client kubernetes.Interface
+	store  datastore.DataStore
+	ack    func()
+}
+
+func NewDeployCronJobCtl(job *model.JobTask, client kubernetes.Interface, store datastore.DataStore, ack func()) *DeployCronJobCtl {
+	if job == nil {
+		klog.Errorf("DeployCronJobCtl: job is nil")
+		return nil
+	}
+	return &DeployCronJobCtl{
+		job:    job,
+		client: client,
+		store:  store,
+		ack:    ack,
+	}
+}
+
+func (c *DeployCronJobCtl) Clean(ctx context.Context) {
+	if c.client == nil {
+		return
+	}
+	refs := resourcesForCleanup(ctx, config.ResourceCronJob)
+	if len(refs) == 0 {
+		return
+	}
+	cleanupCtx, cancel := context.WithTimeout(context.Background(), config.DeleteTimeout)
+	defer cancel()
+	for _, ref := range refs {
+		if !ref.Created {
+			continue
+		}
+		ns := ref.Namespace
+		if ns == "" {
+			ns = c.job.Namespace
+		}
+		if err := c.client.BatchV1().CronJobs(ns).Delete(cleanupCtx, ref.Name, metav1.DeleteOptions{}); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				klog.Errorf("failed to delete cronjob %s/%s during cleanup: %v", ns, ref.Name, err)
+			}
+		} else {
+			klog.Infof("deleted cronjob %s/%s after job failure", ns, ref.Name)
+		}
+	}
+}
+
+func (c *DeployCronJobCtl) SaveInfo(ctx context.Context) error {
+	jobInfo := model.JobInfo{
+		Type:        c.job.JobType,
+		WorkflowID:  c.job.WorkflowID,
+		ProductID:   c.job.ProjectID,
+		AppID:       c.job.AppID,
+		TaskID:      c.job.TaskID,
+		Status:      string(c.job.Status),
+		StartTime:   c.job.StartTime,
+		EndTime:     c.job.EndTime,
+		Error:       c.job.Error,
+		ServiceName: c.job.Name,
+		Attempt:     c.job.Attempt(),
+	}
+	return c.store.Add(ctx, &jobInfo)
+}
+
+func (c *DeployCronJobCtl) Run(ctx context.Context) error {
+	c.job.Status = config.StatusRunning
+	c.job.Error = ""
+	c.ack()
+	if err := c.run(ctx); err != nil {
+		klog.FromContext(ctx).Error(err, "DeployCronJob run error")
+		c.job.Status = config.StatusFailed
+		c.job.Error = err.Error()
+		return err
+	}
+	c.job.Status = config.StatusCompleted
+	c.job.Error = ""
+	return nil
+}
+
+func (c *DeployCronJobCtl) run(ctx context.Context) error {
+	if c.client == nil {
+		return fmt.Errorf("client is nil")
+	}
+	cronJob, ok := c.job.JobInfo.(*batchv1.CronJob)
+	if !ok {
+		return fmt.Errorf("cronjob JobInfo conversion type failure: %T", c.job.JobInfo)
+	}
+	if cronJob.Namespace == "" {
+		cronJob.Namespace = c.job.Namespace
+	}
+	logger := klog.FromContext(ctx)
+	cli := c.client.BatchV1().CronJobs(cronJob.Namespace)
+	existing, err := cli.Get(ctx, cronJob.Name, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		cronJob.ResourceVersion = existing.ResourceVersion
+		if _, err := cli.Update(ctx, cronJob, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("update cronjob %q failed: %w", cronJob.Name, err)
+		}
+		markResourceObserved(ctx, config.ResourceCronJob, cronJob.Namespace, cronJob.Name)
+		logger.Info("CronJob updated", "namespace", cronJob.Namespace, "name", cronJob.Name)
+	case k8serrors.IsNotFound(err):
+		if _, err := cli.Create(ctx, cronJob, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("create cronjob %q failed: %w", cronJob.Name, err)
+		}
+		MarkResourceCreated(ctx, config.ResourceCronJob, cronJob.Namespace, cronJob.Name)
+		logger.Info("CronJob created", "namespace", cronJob.Namespace, "name", cronJob.Name)
+	default:
+		return fmt.Errorf("get cronjob %q failed: %w", cronJob.Name, err)
+	}
+	return nil
+}
+
+// GenerateBatchJob builds the Job of a job component with all component traits applied.
+func GenerateBatchJob(component *model.ApplicationComponent, properties *model.Properties) *GenerateServiceResult {
+	labels := BuildLabels(component, properties)
+	batchJob := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      buildBatchJobName(component.Name, component.AppID),
+			Namespace: component.Namespace,
+			Labels:    labels, // Job 自身的 labels，供 Informer 过滤和状态同步
+		},
+		Spec: batchJobSpec(component, properties, labels),
+	}
+	additionalObjects, err := traitsPlu.ApplyTraits(component, batchJob)
+	if err != nil {
+		klog.Errorf("Job Info %s Traits Error:%s", color.WhiteString(component.Namespace+"/"+component.Name), err)
+		return nil
+	}
+	return &GenerateServiceResult{
+		Service:           batchJob,
+		AdditionalObjects: additionalObjects,
+	}
+}
+
+// GenerateCronJob builds the CronJob of a cronjob component with all component traits applied.
+// Jobs spawned by the CronJob carry the component labels so their runs are synced as component status.
+func GenerateCronJob(component *model.ApplicationComponent, properties *model.Properties) *GenerateServiceResult {
+	labels := BuildLabels(component, properties)
+	jobProps := batchJobProperties(properties)
+	concurrency := batchv1.ForbidConcurrent
+	if jobProps.ConcurrencyPolicy != "" {
+		concurrency = batchv1.ConcurrencyPolicy(jobProps.ConcurrencyPolicy)
+	}
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      buildCronJobName(component.Name, component.AppID),
+			Namespace: component.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:                   jobProps.Schedule,
+			ConcurrencyPolicy:          concurrency,
+			Suspend:                    pointer.Bool(jobProps.Suspend),
+			SuccessfulJobsHistoryLimit: jobProps.SuccessfulJobsHistoryLimit,
+			FailedJobsHistoryLimit:     jobProps.FailedJobsHistoryLimit,
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       batchJobSpec(component, properties, labels),
+			},
+		},
+	}
+	if jobProps.TimeZone != "" {
+		cronJob.Spec.TimeZone = pointer.String(jobProps.TimeZone)
+	}
+	additionalObjects, err := traitsPlu.ApplyTraits(component, cronJob)
+	if err != nil {
+		klog.Errorf("CronJob Info %s Traits Error:%s", color.WhiteString(component.Namespace+"/"+component.Name), err)
+		return nil
+	}
+	return &GenerateServiceResult{
+		Service:           cronJob,
+		AdditionalObjects: additionalObjects,
+	}
+}
+
+func batchJobProperties(properties *model.Properties) model.JobProperties {
+	if properties == nil || properties.Job == nil {
+		return model.JobProperties{}
+	}
+	return *properties.Job
+}
+
+// batchJobSpec builds the Job spec shared by job and cronjob components. The selector is left
+// to the Job controller, which generates a unique one per Job.
+func batchJobSpec(component *model.ApplicationComponent, properties *model.Properties, labels map[string]string) batchv1.JobSpec {
+	jobProps := batchJobProperties(properties)
+	restartPolicy := corev1.RestartPolicyNever
+	if strings.EqualFold(jobProps.RestartPolicy, string(corev1.RestartPolicyOnFailure)) {
+		restartPolicy = corev1.RestartPolicyOnFailure
+	}
+	var envs []corev1.EnvVar
+	var command []string
+	if properties != nil {
+		for k, v := range properties.Env {
+			envs = append(envs, corev1.EnvVar{Name: k, Value: v})
+		}
+		command = properties.Command
+	}
+	return batchv1.JobSpec{
+		BackoffLimit:            jobProps.BackoffLimit,
+		ActiveDeadlineSeconds:   jobProps.ActiveDeadlineSeconds,
+		TTLSecondsAfterFinished: jobProps.TTLSecondsAfterFinished,
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: labels,
+			},
+			Spec: corev1.PodSpec{
+				RestartPolicy: restartPolicy,
+				Containers: []corev1.Container{
+					{
+						Name:            utils.NormalizeLowerStrip(component.Name),
+						Image:           component.Image,
+						Command:         command,
+						Env:             envs,
+						ImagePullPolicy: corev1.PullIfNotPresent,
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,175 @@
+package job
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	traitsPlu "kubemin-cli/pkg/apiserver/workflow/traits"
+)
+
+// registerTraitsOnce mirrors server startup; registering a processor twice is fatal.
+var registerTraitsOnce sync.Once
+
+func newWorkloadComponent(t *testing.T, componentType config.JobType) *model.ApplicationComponent {
+	registerTraitsOnce.Do(traitsPlu.RegisterAllProcessors)
+	traits, err := model.NewJSONStructByStruct(&model.Traits{
+		EnvFrom: []model.EnvFromSourceSpec{{Type: "secret", SourceName: "db-creds"}},
+	})
+	require.NoError(t, err)
+	return &model.ApplicationComponent{
+		ID:            3,
+		Name:          "migrate",
+		AppID:         "app-1",
+		Namespace:     "default",
+		Image:         "migrate:v1",
+		ComponentType: componentType,
+		Traits:        traits,
+	}
+}
+
+func TestGenerateBatchJobAppliesTraits(t *testing.T) {
+	backoff := int32(2)
+	component := newWorkloadComponent(t, config.BatchJob)
+	result := GenerateBatchJob(component, &model.Properties{
+		Command: []string{"./migrate", "up"},
+		Job:     &model.JobProperties{BackoffLimit: &backoff},
+	})
+	require.NotNil(t, result)
+
+	batchJob, ok := result.Service.(*batchv1.Job)
+	require.True(t, ok)
+	require.Equal(t, buildBatchJobName("migrate", "app-1"), batchJob.Name)
+	require.Equal(t, &backoff, batchJob.Spec.BackoffLimit)
+	require.Nil(t, batchJob.Spec.Selector)
+
+	podSpec := batchJob.Spec.Template.Spec
+	require.Equal(t, corev1.RestartPolicyNever, podSpec.RestartPolicy)
+	require.Len(t, podSpec.Containers, 1)
+	require.Equal(t, []string{"./migrate", "up"}, podSpec.Containers[0].Command)
+	require.Len(t, podSpec.Containers[0].EnvFrom, 1)
+	require.Equal(t, "db-creds", podSpec.Containers[0].EnvFrom[0].SecretRef.Name)
+	require.Equal(t, "app-1", batchJob.Spec.Template.Labels[config.LabelAppID])
+}
+
+func TestGenerateCronJobAppliesTraits(t *testing.T) {
+	component := newWorkloadComponent(t, config.CronJob)
+	result := GenerateCronJob(component, &model.Properties{
+		Job: &model.JobProperties{Schedule: "*/5 * * * *", TimeZone: "Asia/Shanghai", RestartPolicy: "OnFailure"},
+	})
+	require.NotNil(t, result)
+
+	cronJob, ok := result.Service.(*batchv1.CronJob)
+	require.True(t, ok)
+	require.Equal(t, buildCronJobName("migrate", "app-1"), cronJob.Name)
+	require.Equal(t, "*/5 * * * *", cronJob.Spec.Schedule)
+	require.Equal(t, batchv1.ForbidConcurrent, cronJob.Spec.ConcurrencyPolicy)
+	require.Equal(t, "Asia/Shanghai", *cronJob.Spec.TimeZone)
+	require.Equal(t, "app-1", cronJob.Spec.JobTemplate.Labels[config.LabelAppID])
+
+	podSpec := cronJob.Spec.JobTemplate.Spec.Template.Spec
+	require.Equal(t, corev1.RestartPolicyOnFailure, podSpec.RestartPolicy)
+	require.Len(t, podSpec.Containers[0].EnvFrom, 1)
+}
+
+// finishJobWhenCreated marks the Job finished once the controller has created it.
+func finishJobWhenCreated(t *testing.T, client kubernetes.Interface, namespace, name string, condition batchv1.JobConditionType) {
+	t.Helper()
+	go func() {
+		for i := 0; i < 100; i++ {
+			current, err := client.BatchV1().Jobs(namespace).Get(context.Background(), name, metav1.GetOptions{})
+			if err == nil {
+				current.Status.Conditions = []batchv1.JobCondition{{Type: condition, Status: corev1.ConditionTrue, Message: "backoff limit reached"}}
+				_, _ = client.BatchV1().Jobs(namespace).UpdateStatus(context.Background(), current, metav1.UpdateOptions{})
+				return
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}()
+}
+
+func newBatchJobTask(t *testing.T) (*model.JobTask, *batchv1.Job) {
+	result := GenerateBatchJob(newWorkloadComponent(t, config.BatchJob), &model.Properties{})
+	require.NotNil(t, result)
+	batchJob := result.Service.(*batchv1.Job)
+	return &model.JobTask{
+		Name:      "migrate",
+		Namespace: "default",
+		AppID:     "app-1",
+		TaskID:    "task-1",
+		JobType:   string(config.JobDeployBatchJob),
+		JobInfo:   batchJob,
+		Status:    config.StatusQueued,
+		Timeout:   30,
+	}, batchJob
+}
+
+func TestRunJob_BatchJobWaitsForCompletion(t *testing.T) {
+	jobTask, batchJob := newBatchJobTask(t)
+	previous := batchJob.DeepCopy()
+	client := fake.NewSimpleClientset(previous)
+	finishJobWhenCreated(t, client, "default", batchJob.Name, batchv1.JobComplete)
+
+	store := &jobInfoStore{}
+	runJob(context.Background(), jobTask, client, store, func() {})
+
+	require.Equal(t, config.StatusCompleted, jobTask.Status)
+	info, ok := store.lastAdded.(*model.JobInfo)
+	require.True(t, ok)
+	require.Equal(t, string(config.JobDeployBatchJob), info.Type)
+}
+
+func TestRunJob_BatchJobFailureKeepsJob(t *testing.T) {
+	jobTask, batchJob := newBatchJobTask(t)
+	client := fake.NewSimpleClientset()
+	finishJobWhenCreated(t, client, "default", batchJob.Name, batchv1.JobFailed)
+
+	runJob(context.Background(), jobTask, client, &jobInfoStore{}, func() {})
+
+	require.Equal(t, config.StatusFailed, jobTask.Status)
+	require.Contains(t, jobTask.Error, "backoff limit reached")
+	_, err := client.BatchV1().Jobs("default").Get(context.Background(), batchJob.Name, metav1.GetOptions{})
+	require.NoError(t, err, "failed job should be kept for inspection")
+}
+
+func TestRunJob_CronJobCreatesAndUpdates(t *testing.T) {
+	result := GenerateCronJob(newWorkloadComponent(t, config.CronJob), &model.Properties{
+		Job: &model.JobProperties{Schedule: "0 * * * *"},
+	})
+	require.NotNil(t, result)
+	cronJob := result.Service.(*batchv1.CronJob)
+	client := fake.NewSimpleClientset()
+
+	newTask := func(job *batchv1.CronJob) *model.JobTask {
+		return &model.JobTask{
+			Name:      "migrate",
+			Namespace: "default",
+			JobType:   string(config.JobDeployCronJob),
+			JobInfo:   job,
+			Status:    config.StatusQueued,
+		}
+	}
+	created := newTask(cronJob.DeepCopy())
+	runJob(context.Background(), created, client, &jobInfoStore{}, func() {})
+	require.Equal(t, config.StatusCompleted, created.Status)
+
+	changed := cronJob.DeepCopy()
+	changed.Spec.Schedule = "30 * * * *"
+	updated := newTask(changed)
+	runJob(context.Background(), updated, client, &jobInfoStore{}, func() {})
+	require.Equal(t, config.StatusCompleted, updated.Status)
+
+	current, err := client.BatchV1().CronJobs("default").Get(context.Background(), cronJob.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "30 * * * *", current.Spec.Schedule)
+}
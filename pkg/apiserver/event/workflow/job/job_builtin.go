@@ -0,0 +1,91 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+)
+
+// builtinJob holds what the built-in step controllers (wait、http-check、notify) share:
+// they touch no cluster resources and are recorded under the step name.
+type builtinJob struct {
+	job   *model.JobTask
+	store datastore.DataStore
+	ack   func()
+}
+
+// Clean is a no-op: built-in steps create nothing that needs to be rolled back.
+func (b *builtinJob) Clean(ctx context.Context) {}
+
+func (b *builtinJob) SaveInfo(ctx context.Context) error {
+	jobInfo := model.JobInfo{
+		Type:        b.job.JobType,
+		WorkflowID:  b.job.WorkflowID,
+		ProductID:   b.job.ProjectID,
+		AppID:       b.job.AppID,
+		TaskID:      b.job.TaskID,
+		Status:      string(b.job.Status),
+		StartTime:   b.job.StartTime,
+		EndTime:     b.job.EndTime,
+		Error:       b.job.Error,
+		ServiceName: b.job.Name,
+		Attempt:     b.job.Attempt(),
+	}
+	return b.store.Add(ctx, &jobInfo)
+}
+
+// run marks the job running, bounds it by the job timeout and records the outcome.
+func (b *builtinJob) run(ctx context.Context, name string, fn func(ctx context.Context, spec *model.StepSpec) error) error {
+	logger := klog.FromContext(ctx)
+	b.job.Status = config.StatusRunning
+	b.job.Error = ""
+	b.ack()
+
+	spec, ok := b.job.JobInfo.(*model.StepSpec)
+	if !ok || spec == nil {
+		err := fmt.Errorf("unsupported %s jobInfo type: %T", b.job.JobType, b.job.JobInfo)
+		b.job.Status = config.StatusFailed
+		b.job.Error = err.Error()
+		return err
+	}
+	if b.job.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(b.job.Timeout)*time.Second)
+		defer cancel()
+	}
+	if err := fn(ctx, spec); err != nil {
+		logger.Error(err, name+" run error")
+		b.job.Status = config.StatusFailed
+		b.job.Error = err.Error()
+		return err
+	}
+	b.job.Status = config.StatusCompleted
+	b.job.Error = ""
+	return nil
+}
+
+// pollInterval returns the configured poll interval or the default one.
+func pollInterval(spec *model.StepSpec) time.Duration {
+	if spec.IntervalSeconds > 0 {
+		return time.Duration(spec.IntervalSeconds) * time.Second
+	}
+	return config.DefaultBuiltinStepPollInterval
+}
+
+// sleepContext waits for d, returning early with the context error when ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
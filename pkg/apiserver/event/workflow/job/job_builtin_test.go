@@ -0,0 +1,109 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+func newBuiltinJob(jobType config.JobType, spec *model.StepSpec) *model.JobTask {
+	return &model.JobTask{
+		Name:       "check",
+		WorkflowID: "wf-1",
+		AppID:      "app-1",
+		TaskID:     "task-1",
+		JobType:    string(jobType),
+		JobInfo:    spec,
+		Status:     config.StatusQueued,
+	}
+}
+
+func TestRunJob_HTTPCheckPollsUntilExpectedBody(t *testing.T) {
+	var probes int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&probes, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	store := &jobInfoStore{}
+	jobTask := newBuiltinJob(config.JobHTTPCheck, &model.StepSpec{URL: server.URL, ExpectBody: `"ok"`, IntervalSeconds: 1})
+	runJob(context.Background(), jobTask, fake.NewSimpleClientset(), store, func() {})
+
+	require.Equal(t, config.StatusCompleted, jobTask.Status)
+	require.EqualValues(t, 3, atomic.LoadInt32(&probes))
+	info, ok := store.lastAdded.(*model.JobInfo)
+	require.True(t, ok)
+	require.Equal(t, string(config.JobHTTPCheck), info.Type)
+	require.Equal(t, "check", info.ServiceName)
+}
+
+func TestRunJob_HTTPCheckTimesOut(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	store := &jobInfoStore{}
+	jobTask := newBuiltinJob(config.JobHTTPCheck, &model.StepSpec{URL: server.URL, IntervalSeconds: 1})
+	jobTask.Timeout = 1
+	runJob(context.Background(), jobTask, fake.NewSimpleClientset(), store, func() {})
+
+	require.Equal(t, config.StatusTimeout, jobTask.Status)
+	require.Contains(t, jobTask.Error, "status 500")
+}
+
+func TestRunJob_NotifyPostsTaskPayload(t *testing.T) {
+	var received notifyPayload
+	var contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	jobTask := newBuiltinJob(config.JobNotify, &model.StepSpec{URL: server.URL})
+	runJob(context.Background(), jobTask, fake.NewSimpleClientset(), &jobInfoStore{}, func() {})
+
+	require.Equal(t, config.StatusCompleted, jobTask.Status)
+	require.Equal(t, "application/json", contentType)
+	require.Equal(t, "task-1", received.TaskID)
+	require.Equal(t, "check", received.Step)
+
+	rejected := newBuiltinJob(config.JobNotify, &model.StepSpec{URL: server.URL, ExpectStatus: []int{http.StatusOK}})
+	runJob(context.Background(), rejected, fake.NewSimpleClientset(), &jobInfoStore{}, func() {})
+	require.Equal(t, config.StatusFailed, rejected.Status)
+	require.Contains(t, rejected.Error, "status 202")
+}
+
+func TestRunJob_WaitUntilCondition(t *testing.T) {
+	var calls int32
+	ctx := WithConditionVars(context.Background(), func(context.Context) (map[string]interface{}, error) {
+		ready := atomic.AddInt32(&calls, 1) >= 2
+		return map[string]interface{}{"inputs": map[string]interface{}{"ready": ready}}, nil
+	})
+
+	jobTask := newBuiltinJob(config.JobWait, &model.StepSpec{Until: "inputs.ready == true", IntervalSeconds: 1})
+	runJob(ctx, jobTask, fake.NewSimpleClientset(), &jobInfoStore{}, func() {})
+	require.Equal(t, config.StatusCompleted, jobTask.Status)
+	require.EqualValues(t, 2, atomic.LoadInt32(&calls))
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	fixed := newBuiltinJob(config.JobWait, &model.StepSpec{DurationSeconds: 60})
+	runJob(cancelled, fixed, fake.NewSimpleClientset(), &jobInfoStore{}, func() {})
+	require.Equal(t, config.StatusCancelled, fixed.Status)
+}
@@ -78,6 +78,7 @@ func (c *DeployConfigMapJobCtl) SaveInfo(ctx context.Context) error {
 		EndTime:     c.job.EndTime,
 		Error:       c.job.Error,
 		ServiceName: c.job.Name,
+		Attempt:     c.job.Attempt(),
 	}
 	err := c.store.Add(ctx, &jobInfo)
 	if err != nil {
@@ -111,15 +112,25 @@ func (c *DeployConfigMapJobCtl) run(ctx context.Context) error {
 		return fmt.Errorf("client is nil")
 	}
 
-	// Compatible with two types of input parameters：ConfigMapInput、corev1.ConfigMap
-	var cm *corev1.ConfigMap
-	switch v := c.job.JobInfo.(type) {
+	cm, err := configMapFromJobInfo(c.job.JobInfo)
+	if err != nil {
+		return err
+	}
+	if cm.Namespace == "" {
+		cm.Namespace = c.job.Namespace
+	}
+	return c.deployConfigMap(ctx, cm)
+}
+
+// configMapFromJobInfo is compatible with two types of input parameters：ConfigMapInput、corev1.ConfigMap
+func configMapFromJobInfo(jobInfo interface{}) (*corev1.ConfigMap, error) {
+	switch v := jobInfo.(type) {
 	case *model.ConfigMapInput:
 		conf, err := v.GenerateConf()
 		if err != nil {
-			return fmt.Errorf("invalid ConfigMap spec: %w", err)
+			return nil, fmt.Errorf("invalid ConfigMap spec: %w", err)
 		}
-		cm = &corev1.ConfigMap{
+		return &corev1.ConfigMap{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:        conf.Name,
 				Namespace:   conf.Namespace,
@@ -127,24 +138,12 @@ func (c *DeployConfigMapJobCtl) run(ctx context.Context) error {
 				Annotations: conf.Annotations,
 			},
 			Data: conf.Data,
-		}
+		}, nil
 	case *corev1.ConfigMap:
-		return c.deployExistingConfigMap(ctx, v)
+		return v, nil
 	default:
-		return fmt.Errorf("unsupported configmap jobInfo type: %T", c.job.JobInfo)
+		return nil, fmt.Errorf("unsupported configmap jobInfo type: %T", jobInfo)
 	}
-
-	if cm.Namespace == "" {
-		cm.Namespace = c.job.Namespace
-	}
-	return c.deployConfigMap(ctx, cm)
-}
-
-func (c *DeployConfigMapJobCtl) deployExistingConfigMap(ctx context.Context, cm *corev1.ConfigMap) error {
-	if cm.Namespace == "" {
-		cm.Namespace = c.job.Namespace
-	}
-	return c.deployConfigMap(ctx, cm)
 }
 
 func (c *DeployConfigMapJobCtl) deployConfigMap(ctx context.Context, cm *corev1.ConfigMap) error {
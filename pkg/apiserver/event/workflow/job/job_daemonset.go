@@ -0,0 +1,356 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+	"kubemin-cli/pkg/apiserver/infrastructure/informer"
+	"kubemin-cli/pkg/apiserver/utils"
+	traitsPlu "kubemin-cli/pkg/apiserver/workflow/traits"
+)
+
+// DeployDaemonSetJobCtl 部署守护服务组件，并等待 DaemonSet 在所有目标节点上滚动完成
+type DeployDaemonSetJobCtl struct {
+	namespace string
+	job       *model.JobTask
+	client    kubernetes.Interface
+	store     datastore.DataStore
+	ack       func()
+	// diagnostics 等待就绪失败时采集的现场信息，随 JobInfo 一起保存
+	diagnostics *model.JobDiagnostics
+}
+
+func NewDeployDaemonSetJobCtl(job *model.JobTask, client kubernetes.Interface, store datastore.DataStore, ack func()) *DeployDaemonSetJobCtl {
+	if job == nil {
+		klog.Errorf("DeployDaemonSetJobCtl: job is nil")
+		return nil
+	}
+	return &DeployDaemonSetJobCtl{
+		namespace: job.Namespace,
+		job:       job,
+		client:    client,
+		store:     store,
+		ack:       ack,
+	}
+}
+
+func (c *DeployDaemonSetJobCtl) Clean(ctx context.Context) {
+	if c.client == nil {
+		return
+	}
+	refs := resourcesForCleanup(ctx, config.ResourceDaemonSet)
+	if len(refs) == 0 {
+		return
+	}
+	cleanupCtx, cancel := context.WithTimeout(context.Background(), config.DeleteTimeout)
+	defer cancel()
+	for _, ref := range refs {
+		if !ref.Created {
+			continue
+		}
+		ns := ref.Namespace
+		if ns == "" {
+			ns = c.namespace
+		}
+		if err := c.client.AppsV1().DaemonSets(ns).Delete(cleanupCtx, ref.Name, metav1.DeleteOptions{}); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				klog.Errorf("failed to delete daemonset %s/%s during cleanup: %v", ns, ref.Name, err)
+			}
+		} else {
+			klog.Infof("deleted daemonset %s/%s after job failure", ns, ref.Name)
+		}
+	}
+}
+
+// SaveInfo  创建Job的详情信息
+func (c *DeployDaemonSetJobCtl) SaveInfo(ctx context.Context) error {
+	jobInfo := model.JobInfo{
+		Type:        c.job.JobType,
+		WorkflowID:  c.job.WorkflowID,
+		ProductID:   c.job.ProjectID,
+		AppID:       c.job.AppID,
+		TaskID:      c.job.TaskID,
+		Status:      string(c.job.Status),
+		StartTime:   c.job.StartTime,
+		EndTime:     c.job.EndTime,
+		Error:       c.job.Error,
+		ServiceName: c.job.Name,
+		Attempt:     c.job.Attempt(),
+	}
+	if err := c.store.Add(ctx, &jobInfo); err != nil {
+		return err
+	}
+	return saveDiagnostics(ctx, c.store, c.diagnostics, jobInfo.ID)
+}
+
+func (c *DeployDaemonSetJobCtl) Run(ctx context.Context) error {
+	c.job.Status = config.StatusRunning
+	c.job.Error = ""
+	c.ack() // 通知工作流开始运行
+
+	if err := c.run(ctx); err != nil {
+		c.job.Error = err.Error()
+		if statusErr, ok := ExtractStatusError(err); ok {
+			c.job.Status = statusErr.Status
+		} else {
+			c.job.Status = config.StatusFailed
+		}
+		return err
+	}
+
+	if c.job.Status == config.StatusSkipped {
+		c.job.Error = ""
+		return nil
+	}
+
+	if err := c.wait(ctx); err != nil {
+		c.job.Error = err.Error()
+		if statusErr, ok := ExtractStatusError(err); ok {
+			c.job.Status = statusErr.Status
+		} else {
+			c.job.Status = config.StatusFailed
+		}
+		if shouldCollectDiagnostics(err) {
+			c.diagnostics = collectWorkloadDiagnostics(ctx, c.client, c.job, config.ResourceDaemonSet, buildDaemonSetName(c.job.Name, c.job.AppID))
+		}
+		return err
+	}
+
+	c.job.Status = config.StatusCompleted
+	c.job.Error = ""
+	return nil
+}
+
+func (c *DeployDaemonSetJobCtl) run(ctx context.Context) error {
+	if c.client == nil {
+		return fmt.Errorf("client is nil")
+	}
+	daemonSet, ok := c.job.JobInfo.(*appsv1.DaemonSet)
+	if !ok {
+		return fmt.Errorf("deploy Job Job.Info Conversion type failure")
+	}
+	daemonSet.Name = buildDaemonSetName(c.job.Name, c.job.AppID)
+	if daemonSet.Namespace == "" {
+		daemonSet.Namespace = c.namespace
+	}
+
+	shareName, shareStrategy := shareInfoFromLabels(daemonSet.Labels)
+	unlock, skipped, err := resolveSharedResource(ctx, shareName, shareStrategy, config.ResourceDaemonSet, func(ctx context.Context, opts metav1.ListOptions) (int, error) {
+		list, err := c.client.AppsV1().DaemonSets(daemonSet.Namespace).List(ctx, opts)
+		if err != nil {
+			return 0, err
+		}
+		return len(list.Items), nil
+	})
+	if err != nil {
+		return fmt.Errorf("resolve shared daemonsets failed: %w", err)
+	}
+	if unlock != nil {
+		defer unlock()
+	}
+	if skipped {
+		if shareStrategy == config.ShareStrategyIgnore {
+			klog.Infof("DaemonSet %q marked as shared ignore; skipping", daemonSet.Name)
+		} else {
+			klog.Infof("DaemonSet %q already exists and is shared; skipping", daemonSet.Name)
+		}
+		c.job.Status = config.StatusSkipped
+		c.job.Error = ""
+		c.ack()
+		return nil
+	}
+
+	current, err := c.client.AppsV1().DaemonSets(daemonSet.Namespace).Get(ctx, daemonSet.Name, metav1.GetOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to check daemonset %s/%s existence: %w", daemonSet.Namespace, daemonSet.Name, err)
+	}
+	if err == nil {
+		// selector 不可变，沿用已有 DaemonSet 的 selector 与 Pod 标签
+		daemonSet.Spec.Selector = current.Spec.Selector
+		daemonSet.Spec.Template.Labels = current.Spec.Template.Labels
+		updated, err := c.applyDaemonSet(ctx, daemonSet)
+		if err != nil {
+			klog.Errorf("failed to update daemonset %q: %v", daemonSet.Name, err)
+			return err
+		}
+		markResourceObserved(ctx, config.ResourceDaemonSet, daemonSet.Namespace, daemonSet.Name)
+		klog.Infof("DaemonSet %q updated successfully.", updated.Name)
+		return nil
+	}
+
+	result, err := c.client.AppsV1().DaemonSets(daemonSet.Namespace).Create(ctx, daemonSet, metav1.CreateOptions{})
+	if err != nil {
+		klog.Errorf("failed to create daemonset %q namespace: %q : %v", daemonSet.Name, daemonSet.Namespace, err)
+		return err
+	}
+	MarkResourceCreated(ctx, config.ResourceDaemonSet, daemonSet.Namespace, daemonSet.Name)
+	klog.Infof("JobTask Deploy Successfully %q.\n", result.GetObjectMeta().GetName())
+	return nil
+}
+
+// applyDaemonSet updates an existing DaemonSet through server-side apply, like ApplyDeployment.
+func (c *DeployDaemonSetJobCtl) applyDaemonSet(ctx context.Context, daemonSet *appsv1.DaemonSet) (*appsv1.DaemonSet, error) {
+	daemonSet.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   "apps",
+		Version: "v1",
+		Kind:    "DaemonSet",
+	})
+	cleanObjectMeta(&daemonSet.ObjectMeta)
+
+	patchBytes, err := json.Marshal(daemonSet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal daemonset: %w", err)
+	}
+	result, err := c.client.AppsV1().DaemonSets(daemonSet.Namespace).Patch(ctx,
+		daemonSet.Name,
+		types.ApplyPatchType,
+		patchBytes,
+		metav1.PatchOptions{
+			FieldManager: config.LabelCli,
+			Force:        pointer.Bool(true),
+		})
+	if err != nil {
+		return nil, fmt.Errorf("apply daemonset failed: %w", err)
+	}
+	return result, nil
+}
+
+func (c *DeployDaemonSetJobCtl) wait(ctx context.Context) error {
+	targetName := buildDaemonSetName(c.job.Name, c.job.AppID)
+	timeout := time.Duration(c.timeout()) * time.Second
+
+	// 优先使用 Informer 事件驱动
+	waiter := GetGlobalWaiter()
+	if waiter != nil {
+		klog.V(4).Infof("Using informer-based wait for daemonset %s/%s", c.job.Namespace, targetName)
+		err := waiter.WaitForDaemonSetReady(ctx, c.job.Namespace, targetName, timeout)
+		if err != nil {
+			var we *informer.WaitError
+			if errors.As(err, &we) {
+				return NewStatusError(we.Status, we.Err)
+			}
+			return err
+		}
+		return nil
+	}
+
+	// Fallback: 如果 Informer 未初始化，使用轮询方式
+	klog.V(4).Infof("Waiter not initialized, falling back to polling for daemonset %s/%s", c.job.Namespace, targetName)
+	return c.waitPolling(ctx)
+}
+
+// waitPolling 使用轮询方式等待 DaemonSet 就绪（作为 fallback）
+func (c *DeployDaemonSetJobCtl) waitPolling(ctx context.Context) error {
+	timeout := time.After(time.Duration(c.timeout()) * time.Second)
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	targetName := buildDaemonSetName(c.job.Name, c.job.AppID)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return NewStatusError(config.StatusCancelled, fmt.Errorf("daemonset %s cancelled: %w", targetName, ctx.Err()))
+		case <-timeout:
+			klog.Infof("timeout waiting for job %s", targetName)
+			return NewStatusError(config.StatusTimeout, fmt.Errorf("wait daemonset %s timeout", targetName))
+		case <-ticker.C:
+			daemonSet, err := c.client.AppsV1().DaemonSets(c.job.Namespace).Get(ctx, targetName, metav1.GetOptions{})
+			if err != nil {
+				if k8serrors.IsNotFound(err) {
+					continue
+				}
+				return fmt.Errorf("wait daemonset %s error: %w", targetName, err)
+			}
+			status := informer.ExtractDaemonSetStatus(daemonSet)
+			klog.Infof("daemonset: %s, Desired: %d, Updated: %d, Ready: %d", status.Name, status.Desired, status.Updated, status.ReadyReplicas)
+			if status.Ready {
+				return nil
+			}
+		}
+	}
+}
+
+func (c *DeployDaemonSetJobCtl) timeout() int64 {
+	if c.job.Timeout == 0 {
+		c.job.Timeout = config.DeployTimeout
+	}
+	return c.job.Timeout
+}
+
+// GenerateDaemonSet builds the DaemonSet of a daemon component; replicas are ignored since
+// the DaemonSet controller runs one pod on every eligible node.
+func GenerateDaemonSet(component *model.ApplicationComponent, properties *model.Properties) *GenerateServiceResult {
+	daemonSetName := buildDaemonSetName(component.Name, component.AppID)
+	containerName := utils.NormalizeLowerStrip(component.Name)
+	var ContainerPort []corev1.ContainerPort
+	for _, v := range properties.Ports {
+		ContainerPort = append(ContainerPort, corev1.ContainerPort{
+			ContainerPort: v.Port,
+		})
+	}
+
+	var envs []corev1.EnvVar
+	for k, v := range properties.Env {
+		envs = append(envs, corev1.EnvVar{Name: k, Value: v})
+	}
+
+	labels := BuildLabels(component, properties)
+
+	daemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      daemonSetName,
+			Namespace: component.Namespace,
+			Labels:    labels, // 设置 DaemonSet 自身的 labels，供 Informer 过滤和状态同步
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:            containerName,
+							Image:           component.Image,
+							Ports:           ContainerPort,
+							Env:             envs,
+							Command:         properties.Command,
+							ImagePullPolicy: corev1.PullIfNotPresent,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	additionalObjects, err := traitsPlu.ApplyTraits(component, daemonSet)
+	if err != nil {
+		klog.Errorf("DaemonSet Info %s Traits Error:%s", color.WhiteString(component.Namespace+"/"+component.Name), err)
+		return nil
+	}
+	return &GenerateServiceResult{
+		Service:           daemonSet,
+		AdditionalObjects: additionalObjects,
+	}
+}
@@ -0,0 +1,71 @@
+package job
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+func TestGenerateDaemonSetAppliesTraits(t *testing.T) {
+	component := newWorkloadComponent(t, config.DaemonJob)
+	component.Name = "log-agent"
+	component.Replicas = 3
+	result := GenerateDaemonSet(component, &model.Properties{Ports: []model.Ports{{Port: 9100}}})
+	require.NotNil(t, result)
+
+	daemonSet, ok := result.Service.(*appsv1.DaemonSet)
+	require.True(t, ok)
+	require.Equal(t, buildDaemonSetName("log-agent", "app-1"), daemonSet.Name)
+	require.Equal(t, daemonSet.Spec.Template.Labels, daemonSet.Spec.Selector.MatchLabels)
+
+	container := daemonSet.Spec.Template.Spec.Containers[0]
+	require.Equal(t, "log-agent", container.Name)
+	require.EqualValues(t, 9100, container.Ports[0].ContainerPort)
+	require.Len(t, container.EnvFrom, 1)
+}
+
+func TestRunJob_DaemonSetWaitsForRollout(t *testing.T) {
+	component := newWorkloadComponent(t, config.DaemonJob)
+	result := GenerateDaemonSet(component, &model.Properties{})
+	require.NotNil(t, result)
+	daemonSet := result.Service.(*appsv1.DaemonSet)
+	client := fake.NewSimpleClientset()
+
+	// Simulate the DaemonSet controller rolling out to two nodes.
+	go func() {
+		for i := 0; i < 100; i++ {
+			current, err := client.AppsV1().DaemonSets("default").Get(context.Background(), daemonSet.Name, metav1.GetOptions{})
+			if err == nil {
+				current.Status = appsv1.DaemonSetStatus{DesiredNumberScheduled: 2, UpdatedNumberScheduled: 2, NumberReady: 2, ObservedGeneration: current.Generation}
+				_, _ = client.AppsV1().DaemonSets("default").UpdateStatus(context.Background(), current, metav1.UpdateOptions{})
+				return
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}()
+
+	jobTask := &model.JobTask{
+		Name:      component.Name,
+		Namespace: "default",
+		AppID:     "app-1",
+		JobType:   string(config.JobDeployDaemonSet),
+		JobInfo:   daemonSet,
+		Status:    config.StatusQueued,
+		Timeout:   30,
+	}
+	store := &jobInfoStore{}
+	runJob(context.Background(), jobTask, client, store, func() {})
+
+	require.Equal(t, config.StatusCompleted, jobTask.Status)
+	info, ok := store.lastAdded.(*model.JobInfo)
+	require.True(t, ok)
+	require.Equal(t, string(config.JobDeployDaemonSet), info.Type)
+}
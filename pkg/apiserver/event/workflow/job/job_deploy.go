@@ -35,6 +35,8 @@ type DeployJobCtl struct {
 	client    kubernetes.Interface
 	store     datastore.DataStore
 	ack       func()
+	// diagnostics 等待就绪失败时采集的现场信息，随 JobInfo 一起保存
+	diagnostics *model.JobDiagnostics
 }
 
 func NewDeployJobCtl(job *model.JobTask, client kubernetes.Interface, store datastore.DataStore, ack func()) *DeployJobCtl {
@@ -90,12 +92,13 @@ func (c *DeployJobCtl) SaveInfo(ctx context.Context) error {
 		EndTime:     c.job.EndTime,
 		Error:       c.job.Error,
 		ServiceName: c.job.Name,
+		Attempt:     c.job.Attempt(),
 	}
 	err := c.store.Add(ctx, &jobInfo)
 	if err != nil {
 		return err
 	}
-	return nil
+	return saveDiagnostics(ctx, c.store, c.diagnostics, jobInfo.ID)
 }
 
 func (c *DeployJobCtl) Run(ctx context.Context) error {
@@ -125,6 +128,9 @@ func (c *DeployJobCtl) Run(ctx context.Context) error {
 		} else {
 			c.job.Status = config.StatusFailed
 		}
+		if shouldCollectDiagnostics(err) {
+			c.diagnostics = collectWorkloadDiagnostics(ctx, c.client, c.job, config.ResourceDeployment, buildWebServiceName(c.job.Name, c.job.AppID))
+		}
 		return err
 	}
 
@@ -0,0 +1,113 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+)
+
+// maxCheckBodyBytes bounds how much of a response body is read when matching expect_body.
+const maxCheckBodyBytes = 1 << 20
+
+// HTTPCheckJobCtl 轮询 URL，直到返回期望的状态码（默认 2xx）且响应体包含 expect_body
+type HTTPCheckJobCtl struct {
+	builtinJob
+	client *http.Client
+}
+
+func NewHTTPCheckJobCtl(job *model.JobTask, store datastore.DataStore, ack func()) *HTTPCheckJobCtl {
+	if job == nil {
+		klog.Errorf("HTTPCheckJobCtl: job is nil")
+		return nil
+	}
+	return &HTTPCheckJobCtl{
+		builtinJob: builtinJob{job: job, store: store, ack: ack},
+		client:     &http.Client{Timeout: config.BuiltinStepRequestTimeout},
+	}
+}
+
+func (c *HTTPCheckJobCtl) Run(ctx context.Context) error {
+	return c.run(ctx, "HTTPCheckJob", c.check)
+}
+
+func (c *HTTPCheckJobCtl) check(ctx context.Context, spec *model.StepSpec) error {
+	logger := klog.FromContext(ctx)
+	interval := pollInterval(spec)
+	method := strings.ToUpper(spec.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	last := "no response"
+	for attempt := 1; ; attempt++ {
+		result, ok := c.probe(ctx, method, spec)
+		if ok {
+			logger.Info("HTTP check passed", "url", spec.URL, "probes", attempt)
+			return nil
+		}
+		// A probe cut short by the deadline says nothing about the endpoint; keep the previous result.
+		if ctx.Err() == nil {
+			last = result
+			logger.V(4).Info("HTTP check not ready", "url", spec.URL, "result", result)
+			if err := sleepContext(ctx, interval); err == nil {
+				continue
+			}
+		}
+		return fmt.Errorf("http check %s not passed after %d probes (last: %s): %w", spec.URL, attempt, last, ctx.Err())
+	}
+}
+
+// probe sends one request and reports whether the response matches the expectation,
+// describing the response otherwise.
+func (c *HTTPCheckJobCtl) probe(ctx context.Context, method string, spec *model.StepSpec) (string, bool) {
+	var body io.Reader
+	if spec.Body != "" {
+		body = strings.NewReader(spec.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, spec.URL, body)
+	if err != nil {
+		return err.Error(), false
+	}
+	for k, v := range spec.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err.Error(), false
+	}
+	defer resp.Body.Close()
+	if !statusExpected(resp.StatusCode, spec.ExpectStatus) {
+		return fmt.Sprintf("status %d", resp.StatusCode), false
+	}
+	if spec.ExpectBody == "" {
+		return "", true
+	}
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxCheckBodyBytes))
+	if err != nil {
+		return fmt.Sprintf("read body: %v", err), false
+	}
+	if !strings.Contains(string(content), spec.ExpectBody) {
+		return fmt.Sprintf("status %d, body does not contain %q", resp.StatusCode, spec.ExpectBody), false
+	}
+	return "", true
+}
+
+// statusExpected accepts any 2xx status when no explicit codes are configured.
+func statusExpected(code int, expected []int) bool {
+	if len(expected) == 0 {
+		return code >= 200 && code < 300
+	}
+	for _, want := range expected {
+		if code == want {
+			return true
+		}
+	}
+	return false
+}
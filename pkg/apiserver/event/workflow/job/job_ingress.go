@@ -79,6 +79,7 @@ func (c *DeployIngressJobCtl) SaveInfo(ctx context.Context) error {
 		EndTime:     c.job.EndTime,
 		Error:       c.job.Error,
 		ServiceName: c.job.Name,
+		Attempt:     c.job.Attempt(),
 	}
 	return c.store.Add(ctx, &jobInfo)
 }
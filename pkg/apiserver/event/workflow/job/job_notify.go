@@ -0,0 +1,89 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+)
+
+// NotifyJobCtl 向 URL 投递一次通知；body 为空时发送任务信息，非 2xx（或不在 expect_status 中）视为失败
+type NotifyJobCtl struct {
+	builtinJob
+	client *http.Client
+}
+
+// notifyPayload is sent when the step does not configure its own body.
+type notifyPayload struct {
+	Step       string `json:"step"`
+	TaskID     string `json:"task_id"`
+	WorkflowID string `json:"workflow_id"`
+	AppID      string `json:"app_id"`
+	ProjectID  string `json:"project_id,omitempty"`
+	Time       int64  `json:"time"`
+}
+
+func NewNotifyJobCtl(job *model.JobTask, store datastore.DataStore, ack func()) *NotifyJobCtl {
+	if job == nil {
+		klog.Errorf("NotifyJobCtl: job is nil")
+		return nil
+	}
+	return &NotifyJobCtl{
+		builtinJob: builtinJob{job: job, store: store, ack: ack},
+		client:     &http.Client{Timeout: config.BuiltinStepRequestTimeout},
+	}
+}
+
+func (c *NotifyJobCtl) Run(ctx context.Context) error {
+	return c.run(ctx, "NotifyJob", c.notify)
+}
+
+func (c *NotifyJobCtl) notify(ctx context.Context, spec *model.StepSpec) error {
+	body := spec.Body
+	if body == "" {
+		payload, err := json.Marshal(notifyPayload{
+			Step:       c.job.Name,
+			TaskID:     c.job.TaskID,
+			WorkflowID: c.job.WorkflowID,
+			AppID:      c.job.AppID,
+			ProjectID:  c.job.ProjectID,
+			Time:       time.Now().Unix(),
+		})
+		if err != nil {
+			return fmt.Errorf("marshal notify payload: %w", err)
+		}
+		body = string(payload)
+	}
+	method := strings.ToUpper(spec.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+	req, err := http.NewRequestWithContext(ctx, method, spec.URL, strings.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build notify request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range spec.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("notify %s: %w", spec.URL, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxCheckBodyBytes))
+	if !statusExpected(resp.StatusCode, spec.ExpectStatus) {
+		return fmt.Errorf("notify %s returned status %d", spec.URL, resp.StatusCode)
+	}
+	klog.FromContext(ctx).Info("Notification delivered", "url", spec.URL, "status", resp.StatusCode)
+	return nil
+}
@@ -78,6 +78,7 @@ func (c *DeployPVCJobCtl) SaveInfo(ctx context.Context) error {
 		EndTime:     c.job.EndTime,
 		Error:       c.job.Error,
 		ServiceName: c.job.Name,
+		Attempt:     c.job.Attempt(),
 	}
 	err := c.store.Add(ctx, &jobInfo)
 	if err != nil {
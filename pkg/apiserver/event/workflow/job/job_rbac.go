@@ -76,6 +76,7 @@ func (c *DeployServiceAccountJobCtl) SaveInfo(ctx context.Context) error {
 		EndTime:     c.job.EndTime,
 		Error:       c.job.Error,
 		ServiceName: c.job.Name,
+		Attempt:     c.job.Attempt(),
 	}
 	return c.store.Add(ctx, &jobInfo)
 }
@@ -232,6 +233,7 @@ func (c *DeployRoleJobCtl) SaveInfo(ctx context.Context) error {
 		EndTime:     c.job.EndTime,
 		Error:       c.job.Error,
 		ServiceName: c.job.Name,
+		Attempt:     c.job.Attempt(),
 	}
 	return c.store.Add(ctx, &jobInfo)
 }
@@ -385,6 +387,7 @@ func (c *DeployRoleBindingJobCtl) SaveInfo(ctx context.Context) error {
 		EndTime:     c.job.EndTime,
 		Error:       c.job.Error,
 		ServiceName: c.job.Name,
+		Attempt:     c.job.Attempt(),
 	}
 	return c.store.Add(ctx, &jobInfo)
 }
@@ -534,6 +537,7 @@ func (c *DeployClusterRoleJobCtl) SaveInfo(ctx context.Context) error {
 		EndTime:     c.job.EndTime,
 		Error:       c.job.Error,
 		ServiceName: c.job.Name,
+		Attempt:     c.job.Attempt(),
 	}
 	return c.store.Add(ctx, &jobInfo)
 }
@@ -680,6 +684,7 @@ func (c *DeployClusterRoleBindingJobCtl) SaveInfo(ctx context.Context) error {
 		EndTime:     c.job.EndTime,
 		Error:       c.job.Error,
 		ServiceName: c.job.Name,
+		Attempt:     c.job.Attempt(),
 	}
 	return c.store.Add(ctx, &jobInfo)
 }
@@ -5,7 +5,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 
 	"kubemin-cli/pkg/apiserver/config"
 	"kubemin-cli/pkg/apiserver/domain/model"
@@ -15,11 +19,13 @@ import (
 type jobInfoStore struct {
 	addCount  int
 	lastAdded datastore.Entity
+	added     []datastore.Entity
 }
 
 func (s *jobInfoStore) Add(_ context.Context, entity datastore.Entity) error {
 	s.addCount++
 	s.lastAdded = entity
+	s.added = append(s.added, entity)
 	return nil
 }
 
@@ -68,3 +74,92 @@ func TestRunJob_SkippedWritesJobInfo(t *testing.T) {
 	require.NotZero(t, info.StartTime)
 	require.NotZero(t, info.EndTime)
 }
+
+func newConflictingConfigMapClient(failures int) *fake.Clientset {
+	client := fake.NewSimpleClientset()
+	remaining := failures
+	client.PrependReactor("create", "configmaps", func(k8stesting.Action) (bool, runtime.Object, error) {
+		if remaining > 0 {
+			remaining--
+			return true, nil, k8serrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "demo", nil)
+		}
+		return false, nil, nil
+	})
+	return client
+}
+
+func newConfigMapJobTask(policy *model.RetryPolicy) *model.JobTask {
+	return &model.JobTask{
+		Name:        "demo",
+		Namespace:   "default",
+		WorkflowID:  "wf-1",
+		AppID:       "app-1",
+		TaskID:      "task-1",
+		JobType:     string(config.JobDeployConfigMap),
+		JobInfo:     &model.ConfigMapInput{Name: "demo", Namespace: "default", Data: map[string]string{"k": "v"}},
+		Status:      config.StatusQueued,
+		RetryPolicy: policy,
+	}
+}
+
+func TestRunJob_RetriesTransientFailure(t *testing.T) {
+	store := &jobInfoStore{}
+	jobTask := newConfigMapJobTask(&model.RetryPolicy{Attempts: 3})
+
+	runJob(context.Background(), jobTask, newConflictingConfigMapClient(1), store, func() {})
+
+	require.Equal(t, config.StatusCompleted, jobTask.Status)
+	require.Equal(t, 1, jobTask.RetryCount)
+	require.Len(t, store.added, 2)
+	first := store.added[0].(*model.JobInfo)
+	require.Equal(t, 1, first.Attempt)
+	require.Equal(t, string(config.StatusFailed), first.Status)
+	second := store.added[1].(*model.JobInfo)
+	require.Equal(t, 2, second.Attempt)
+	require.Equal(t, string(config.StatusCompleted), second.Status)
+}
+
+func TestRunJob_StopsAfterMaxAttempts(t *testing.T) {
+	store := &jobInfoStore{}
+	jobTask := newConfigMapJobTask(&model.RetryPolicy{Attempts: 2})
+
+	runJob(context.Background(), jobTask, newConflictingConfigMapClient(5), store, func() {})
+
+	require.Equal(t, config.StatusFailed, jobTask.Status)
+	require.Equal(t, 1, jobTask.RetryCount)
+	require.Len(t, store.added, 2)
+}
+
+func TestRunJob_WithoutPolicyRunsOnce(t *testing.T) {
+	store := &jobInfoStore{}
+	jobTask := newConfigMapJobTask(nil)
+
+	runJob(context.Background(), jobTask, newConflictingConfigMapClient(1), store, func() {})
+
+	require.Equal(t, config.StatusFailed, jobTask.Status)
+	require.Zero(t, jobTask.RetryCount)
+	require.Len(t, store.added, 1)
+}
+
+func TestRunJob_NotifiesObserverOnTransitions(t *testing.T) {
+	type transition struct {
+		status  config.Status
+		attempt int
+	}
+	var seen []transition
+	ctx := WithJobObserver(context.Background(), func(job *model.JobTask) {
+		seen = append(seen, transition{job.Status, job.Attempt()})
+	})
+	jobTask := newConfigMapJobTask(&model.RetryPolicy{Attempts: 2})
+
+	runJob(ctx, jobTask, newConflictingConfigMapClient(1), &jobInfoStore{}, func() {})
+
+	require.Equal(t, []transition{
+		{config.StatusPrepare, 1},
+		{config.StatusRunning, 1},
+		{config.StatusFailed, 1},
+		{config.StatusPrepare, 2},
+		{config.StatusRunning, 2},
+		{config.StatusCompleted, 2},
+	}, seen)
+}
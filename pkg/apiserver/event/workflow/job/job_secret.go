@@ -83,6 +83,7 @@ func (c *DeploySecretJobCtl) SaveInfo(ctx context.Context) error {
 		EndTime:     c.job.EndTime,
 		Error:       c.job.Error,
 		ServiceName: c.job.Name,
+		Attempt:     c.job.Attempt(),
 	}
 	return c.store.Add(ctx, &jobInfo)
 }
@@ -113,53 +114,14 @@ func (c *DeploySecretJobCtl) run(ctx context.Context) error {
 		return fmt.Errorf("client is nil")
 	}
 
-	var secret *corev1.Secret
-	switch v := c.job.JobInfo.(type) {
-	case *corev1.Secret:
-		secret = v
-	case *model.SecretInput:
-		st := corev1.SecretTypeOpaque
-		if v.Type != "" {
-			st = corev1.SecretType(v.Type)
-		}
-		stringData := map[string]string{}
-		if v.URL != "" {
-			body, err := utils.ReadFileFromURLSimple(v.URL)
-			if err != nil {
-				return fmt.Errorf("fetch secret url failed: %w", err)
-			}
-			fileName := v.FileName
-			if fileName == "" {
-				fileName = model.ExtractFileNameFromURLForSecret(v.URL)
-			}
-			stringData[fileName] = string(body)
-		}
-		for k, val := range v.Data {
-			stringData[k] = val
-		}
-		secret = &corev1.Secret{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:        v.Name,
-				Namespace:   v.Namespace,
-				Labels:      v.Labels,
-				Annotations: v.Annotations,
-			},
-			Type:       st,
-			StringData: stringData,
-		}
-	default:
-		return fmt.Errorf("job info is not *corev1.Secret")
+	secret, err := secretFromJobInfo(c.job.JobInfo)
+	if err != nil {
+		return err
 	}
-
 	if secret.Namespace == "" {
 		secret.Namespace = c.job.Namespace
 	}
 
-	// Default to Opaque if not set
-	if string(secret.Type) == "" {
-		secret.Type = corev1.SecretTypeOpaque
-	}
-
 	cli := c.client.CoreV1().Secrets(secret.Namespace)
 
 	shareName, shareStrategy := shareInfoFromLabels(secret.Labels)
@@ -289,3 +251,49 @@ func GenerateSecret(component *model.ApplicationComponent, properties *model.Pro
 		Data:      data,
 	}
 }
+
+// secretFromJobInfo converts the job payload into the Secret to deploy.
+func secretFromJobInfo(jobInfo interface{}) (*corev1.Secret, error) {
+	var secret *corev1.Secret
+	switch v := jobInfo.(type) {
+	case *corev1.Secret:
+		secret = v
+	case *model.SecretInput:
+		st := corev1.SecretTypeOpaque
+		if v.Type != "" {
+			st = corev1.SecretType(v.Type)
+		}
+		stringData := map[string]string{}
+		if v.URL != "" {
+			body, err := utils.ReadFileFromURLSimple(v.URL)
+			if err != nil {
+				return nil, fmt.Errorf("fetch secret url failed: %w", err)
+			}
+			fileName := v.FileName
+			if fileName == "" {
+				fileName = model.ExtractFileNameFromURLForSecret(v.URL)
+			}
+			stringData[fileName] = string(body)
+		}
+		for k, val := range v.Data {
+			stringData[k] = val
+		}
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        v.Name,
+				Namespace:   v.Namespace,
+				Labels:      v.Labels,
+				Annotations: v.Annotations,
+			},
+			Type:       st,
+			StringData: stringData,
+		}, nil
+	default:
+		return nil, fmt.Errorf("job info is not *corev1.Secret")
+	}
+	// Default to Opaque if not set
+	if string(secret.Type) == "" {
+		secret.Type = corev1.SecretTypeOpaque
+	}
+	return secret, nil
+}
@@ -83,6 +83,7 @@ func (c *DeployServiceJobCtl) SaveInfo(ctx context.Context) error {
 		EndTime:     c.job.EndTime,
 		Error:       c.job.Error,
 		ServiceName: c.job.Name,
+		Attempt:     c.job.Attempt(),
 	}
 	err := c.store.Add(ctx, &jobInfo)
 	if err != nil {
@@ -286,6 +287,46 @@ func GenerateService(component *model.ApplicationComponent, properties *model.Pr
 }
 
 func (c *DeployServiceJobCtl) ApplyService(ctx context.Context, svc *applyv1.ServiceApplyConfiguration) (*corev1.Service, error) {
+	coreService := buildCoreService(svc)
+
+	// 检查 service 是否存在并获取现有 service 信息
+	existingService, err := c.client.CoreV1().Services(coreService.Namespace).Get(ctx, coreService.Name, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			// 如果不存在，则创建
+			appliedSvc, err := c.client.CoreV1().Services(coreService.Namespace).Create(ctx, coreService, metav1.CreateOptions{})
+			if err != nil {
+				klog.Errorf("TmpCreate failed: %v", err)
+				return nil, fmt.Errorf("create service failed: %w", err)
+			}
+			klog.InfoS("Service created", "namespace", appliedSvc.Namespace, "name", appliedSvc.Name)
+			return appliedSvc, nil
+		}
+		return nil, fmt.Errorf("failed to check service existence: %w", err)
+	}
+
+	// ✅ 复制必要字段 - 修复 Service 更新问题
+	if existingService != nil {
+		carryServiceFields(existingService, coreService)
+
+		klog.Infof("Copying necessary fields from existing service %s/%s: ResourceVersion=%s, ClusterIP=%s",
+			existingService.Namespace, existingService.Name,
+			existingService.ResourceVersion, existingService.Spec.ClusterIP)
+	}
+
+	// 如果存在，则更新
+	appliedSvc, err := c.client.CoreV1().Services(coreService.Namespace).Update(ctx, coreService, metav1.UpdateOptions{})
+	if err != nil {
+		klog.Errorf("Update failed: %v", err)
+		return nil, fmt.Errorf("update service failed: %w", err)
+	}
+
+	klog.Infof("Service updated: %s/%s", appliedSvc.Namespace, appliedSvc.Name)
+	return appliedSvc, nil
+}
+
+// buildCoreService converts the generated apply configuration into a typed Service.
+func buildCoreService(svc *applyv1.ServiceApplyConfiguration) *corev1.Service {
 	// 处理可能为 nil 的字段
 	var serviceType corev1.ServiceType = corev1.ServiceTypeClusterIP // 默认值
 	if svc.Spec.Type != nil {
@@ -331,94 +372,65 @@ func (c *DeployServiceJobCtl) ApplyService(ctx context.Context, svc *applyv1.Ser
 			Protocol:   protocol,
 		}
 	}
+	return coreService
+}
 
-	// 检查 service 是否存在并获取现有 service 信息
-	existingService, err := c.client.CoreV1().Services(coreService.Namespace).Get(ctx, coreService.Name, metav1.GetOptions{})
-	if err != nil {
-		if k8serrors.IsNotFound(err) {
-			// 如果不存在，则创建
-			appliedSvc, err := c.client.CoreV1().Services(coreService.Namespace).Create(ctx, coreService, metav1.CreateOptions{})
-			if err != nil {
-				klog.Errorf("TmpCreate failed: %v", err)
-				return nil, fmt.Errorf("create service failed: %w", err)
-			}
-			klog.InfoS("Service created", "namespace", appliedSvc.Namespace, "name", appliedSvc.Name)
-			return appliedSvc, nil
-		}
-		return nil, fmt.Errorf("failed to check service existence: %w", err)
-	}
-
-	// ✅ 复制必要字段 - 修复 Service 更新问题
-	if existingService != nil {
-		// 复制 ResourceVersion 用于乐观并发控制
-		coreService.ResourceVersion = existingService.ResourceVersion
-
-		// 复制 ClusterIP 和 ClusterIPs（不可变字段）
-		coreService.Spec.ClusterIP = existingService.Spec.ClusterIP
-		coreService.Spec.ClusterIPs = existingService.Spec.ClusterIPs
-
-		// 复制 IPFamilies（如果存在）
-		if len(existingService.Spec.IPFamilies) > 0 {
-			coreService.Spec.IPFamilies = existingService.Spec.IPFamilies
-		}
+// carryServiceFields copies the fields the API server owns or forbids changing from the live Service.
+func carryServiceFields(existing, desired *corev1.Service) {
+	// 复制 ResourceVersion 用于乐观并发控制
+	desired.ResourceVersion = existing.ResourceVersion
 
-		// 复制 SessionAffinityConfig（如果存在）
-		if existingService.Spec.SessionAffinityConfig != nil {
-			coreService.Spec.SessionAffinityConfig = existingService.Spec.SessionAffinityConfig
-		}
+	// 复制 ClusterIP 和 ClusterIPs（不可变字段）
+	desired.Spec.ClusterIP = existing.Spec.ClusterIP
+	desired.Spec.ClusterIPs = existing.Spec.ClusterIPs
 
-		// 复制其他可能需要保留的字段
-		if existingService.Spec.SessionAffinity != "" {
-			coreService.Spec.SessionAffinity = existingService.Spec.SessionAffinity
-		}
-
-		// 复制 LoadBalancerIP（如果存在）
-		if existingService.Spec.LoadBalancerIP != "" {
-			coreService.Spec.LoadBalancerIP = existingService.Spec.LoadBalancerIP
-		}
+	// 复制 IPFamilies（如果存在）
+	if len(existing.Spec.IPFamilies) > 0 {
+		desired.Spec.IPFamilies = existing.Spec.IPFamilies
+	}
 
-		// 复制 LoadBalancerSourceRanges（如果存在）
-		if len(existingService.Spec.LoadBalancerSourceRanges) > 0 {
-			coreService.Spec.LoadBalancerSourceRanges = existingService.Spec.LoadBalancerSourceRanges
-		}
+	// 复制 SessionAffinityConfig（如果存在）
+	if existing.Spec.SessionAffinityConfig != nil {
+		desired.Spec.SessionAffinityConfig = existing.Spec.SessionAffinityConfig
+	}
 
-		// 复制 ExternalName（如果存在）
-		if existingService.Spec.ExternalName != "" {
-			coreService.Spec.ExternalName = existingService.Spec.ExternalName
-		}
+	// 复制其他可能需要保留的字段
+	if existing.Spec.SessionAffinity != "" {
+		desired.Spec.SessionAffinity = existing.Spec.SessionAffinity
+	}
 
-		// 复制 ExternalTrafficPolicy（如果存在）
-		if existingService.Spec.ExternalTrafficPolicy != "" {
-			coreService.Spec.ExternalTrafficPolicy = existingService.Spec.ExternalTrafficPolicy
-		}
+	// 复制 LoadBalancerIP（如果存在）
+	if existing.Spec.LoadBalancerIP != "" {
+		desired.Spec.LoadBalancerIP = existing.Spec.LoadBalancerIP
+	}
 
-		// 复制 HealthCheckNodePort（如果存在）
-		if existingService.Spec.HealthCheckNodePort != 0 {
-			coreService.Spec.HealthCheckNodePort = existingService.Spec.HealthCheckNodePort
-		}
+	// 复制 LoadBalancerSourceRanges（如果存在）
+	if len(existing.Spec.LoadBalancerSourceRanges) > 0 {
+		desired.Spec.LoadBalancerSourceRanges = existing.Spec.LoadBalancerSourceRanges
+	}
 
-		// 复制 PublishNotReadyAddresses（如果存在）
-		if existingService.Spec.PublishNotReadyAddresses {
-			coreService.Spec.PublishNotReadyAddresses = existingService.Spec.PublishNotReadyAddresses
-		}
+	// 复制 ExternalName（如果存在）
+	if existing.Spec.ExternalName != "" {
+		desired.Spec.ExternalName = existing.Spec.ExternalName
+	}
 
-		// 复制 InternalTrafficPolicy（如果存在）
-		if existingService.Spec.InternalTrafficPolicy != nil {
-			coreService.Spec.InternalTrafficPolicy = existingService.Spec.InternalTrafficPolicy
-		}
+	// 复制 ExternalTrafficPolicy（如果存在）
+	if existing.Spec.ExternalTrafficPolicy != "" {
+		desired.Spec.ExternalTrafficPolicy = existing.Spec.ExternalTrafficPolicy
+	}
 
-		klog.Infof("Copying necessary fields from existing service %s/%s: ResourceVersion=%s, ClusterIP=%s",
-			existingService.Namespace, existingService.Name,
-			existingService.ResourceVersion, existingService.Spec.ClusterIP)
+	// 复制 HealthCheckNodePort（如果存在）
+	if existing.Spec.HealthCheckNodePort != 0 {
+		desired.Spec.HealthCheckNodePort = existing.Spec.HealthCheckNodePort
 	}
 
-	// 如果存在，则更新
-	appliedSvc, err := c.client.CoreV1().Services(coreService.Namespace).Update(ctx, coreService, metav1.UpdateOptions{})
-	if err != nil {
-		klog.Errorf("Update failed: %v", err)
-		return nil, fmt.Errorf("update service failed: %w", err)
+	// 复制 PublishNotReadyAddresses（如果存在）
+	if existing.Spec.PublishNotReadyAddresses {
+		desired.Spec.PublishNotReadyAddresses = existing.Spec.PublishNotReadyAddresses
 	}
 
-	klog.Infof("Service updated: %s/%s", appliedSvc.Namespace, appliedSvc.Name)
-	return appliedSvc, nil
+	// 复制 InternalTrafficPolicy（如果存在）
+	if existing.Spec.InternalTrafficPolicy != nil {
+		desired.Spec.InternalTrafficPolicy = existing.Spec.InternalTrafficPolicy
+	}
 }
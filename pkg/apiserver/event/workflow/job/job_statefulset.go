@@ -27,6 +27,8 @@ type DeployStatefulSetJobCtl struct {
 	client    kubernetes.Interface
 	store     datastore.DataStore
 	ack       func()
+	// diagnostics 等待就绪失败时采集的现场信息，随 JobInfo 一起保存
+	diagnostics *model.JobDiagnostics
 }
 
 func NewDeployStatefulSetJobCtl(job *model.JobTask, client kubernetes.Interface, store datastore.DataStore, ack func()) *DeployStatefulSetJobCtl {
@@ -84,12 +86,13 @@ func (c *DeployStatefulSetJobCtl) SaveInfo(ctx context.Context) error {
 		EndTime:     c.job.EndTime,
 		Error:       c.job.Error,
 		ServiceName: c.job.Name,
+		Attempt:     c.job.Attempt(),
 	}
 	err := c.store.Add(ctx, &jobInfo)
 	if err != nil {
 		return err
 	}
-	return nil
+	return saveDiagnostics(ctx, c.store, c.diagnostics, jobInfo.ID)
 }
 
 func (c *DeployStatefulSetJobCtl) Run(ctx context.Context) error {
@@ -117,6 +120,9 @@ func (c *DeployStatefulSetJobCtl) Run(ctx context.Context) error {
 		} else {
 			c.job.Status = config.StatusFailed
 		}
+		if shouldCollectDiagnostics(err) {
+			c.diagnostics = collectWorkloadDiagnostics(ctx, c.client, c.job, config.ResourceStatefulSet, buildStoreSeverName(c.job.Name, c.job.AppID))
+		}
 		return err
 	}
 	c.job.Status = config.StatusCompleted
@@ -0,0 +1,86 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+	wf "kubemin-cli/pkg/apiserver/workflow"
+)
+
+// ConditionVarsFunc returns the variables a wait step's until expression is evaluated against.
+type ConditionVarsFunc func(ctx context.Context) (map[string]interface{}, error)
+
+// conditionVarsKey is the private key used to stash the condition variables provider in the context.
+type conditionVarsKey struct{}
+
+// WithConditionVars attaches the provider used by wait steps polling an until expression.
+func WithConditionVars(ctx context.Context, vars ConditionVarsFunc) context.Context {
+	if vars == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, conditionVarsKey{}, vars)
+}
+
+func conditionVarsFromContext(ctx context.Context) ConditionVarsFunc {
+	vars, _ := ctx.Value(conditionVarsKey{}).(ConditionVarsFunc)
+	return vars
+}
+
+// WaitJobCtl 等待固定时长；设置 until 时在等待结束后轮询条件表达式，直到其为真
+type WaitJobCtl struct {
+	builtinJob
+}
+
+func NewWaitJobCtl(job *model.JobTask, store datastore.DataStore, ack func()) *WaitJobCtl {
+	if job == nil {
+		klog.Errorf("WaitJobCtl: job is nil")
+		return nil
+	}
+	return &WaitJobCtl{builtinJob{job: job, store: store, ack: ack}}
+}
+
+func (c *WaitJobCtl) Run(ctx context.Context) error {
+	return c.run(ctx, "WaitJob", c.wait)
+}
+
+func (c *WaitJobCtl) wait(ctx context.Context, spec *model.StepSpec) error {
+	logger := klog.FromContext(ctx)
+	if spec.DurationSeconds > 0 {
+		logger.Info("Waiting", "duration", spec.DurationSeconds)
+		if err := sleepContext(ctx, time.Duration(spec.DurationSeconds)*time.Second); err != nil {
+			return fmt.Errorf("wait %ds: %w", spec.DurationSeconds, err)
+		}
+	}
+	until := strings.TrimSpace(spec.Until)
+	if until == "" {
+		return nil
+	}
+	varsFn := conditionVarsFromContext(ctx)
+	if varsFn == nil {
+		return fmt.Errorf("no condition variables available for until %q", until)
+	}
+	interval := pollInterval(spec)
+	for {
+		vars, err := varsFn(ctx)
+		if err != nil {
+			return fmt.Errorf("prepare until condition: %w", err)
+		}
+		done, err := wf.EvaluateCondition(until, vars)
+		if err != nil {
+			return fmt.Errorf("evaluate until condition: %w", err)
+		}
+		if done {
+			logger.Info("Wait condition met", "until", until)
+			return nil
+		}
+		if err := sleepContext(ctx, interval); err != nil {
+			return fmt.Errorf("wait until %q: %w", until, err)
+		}
+	}
+}
@@ -7,6 +7,9 @@ import (
 func buildWebServiceName(name, appID string) string { return naming.WebServiceName(name, appID) }
 func buildServiceName(name, appID string) string    { return naming.ServiceName(name, appID) }
 func buildStoreSeverName(name, appID string) string { return naming.StoreServerName(name, appID) }
+func buildBatchJobName(name, appID string) string   { return naming.BatchJobName(name, appID) }
+func buildDaemonSetName(name, appID string) string  { return naming.DaemonSetName(name, appID) }
+func buildCronJobName(name, appID string) string    { return naming.CronJobName(name, appID) }
 
 // BuildIngressName returns a normalized ingress resource name for the given component/app.
 func BuildIngressName(name, appID string) string { return naming.IngressName(name, appID) }
@@ -0,0 +1,84 @@
+package job
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	applyv1 "k8s.io/client-go/applyconfigurations/core/v1"
+
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+// payloadKinds lists every concrete JobInfo type the job controllers accept. The kind
+// names are stored in execution plans and must not change without bumping
+// config.ExecutionPlanVersion.
+var payloadKinds = map[string]func() interface{}{
+	"Deployment":            func() interface{} { return &appsv1.Deployment{} },
+	"StatefulSet":           func() interface{} { return &appsv1.StatefulSet{} },
+	"DaemonSet":             func() interface{} { return &appsv1.DaemonSet{} },
+	"Job":                   func() interface{} { return &batchv1.Job{} },
+	"CronJob":               func() interface{} { return &batchv1.CronJob{} },
+	"Service":               func() interface{} { return &applyv1.ServiceApplyConfiguration{} },
+	"PersistentVolumeClaim": func() interface{} { return &corev1.PersistentVolumeClaim{} },
+	"Ingress":               func() interface{} { return &networkingv1.Ingress{} },
+	"ServiceAccount":        func() interface{} { return &corev1.ServiceAccount{} },
+	"Role":                  func() interface{} { return &rbacv1.Role{} },
+	"RoleBinding":           func() interface{} { return &rbacv1.RoleBinding{} },
+	"ClusterRole":           func() interface{} { return &rbacv1.ClusterRole{} },
+	"ClusterRoleBinding":    func() interface{} { return &rbacv1.ClusterRoleBinding{} },
+	"ConfigMap":             func() interface{} { return &corev1.ConfigMap{} },
+	"ConfigMapInput":        func() interface{} { return &model.ConfigMapInput{} },
+	"Secret":                func() interface{} { return &corev1.Secret{} },
+	"SecretInput":           func() interface{} { return &model.SecretInput{} },
+	"StepSpec":              func() interface{} { return &model.StepSpec{} },
+}
+
+var payloadKindByType = func() map[reflect.Type]string {
+	types := make(map[reflect.Type]string, len(payloadKinds))
+	for kind, factory := range payloadKinds {
+		types[reflect.TypeOf(factory())] = kind
+	}
+	return types
+}()
+
+// EncodeJobInfo serializes a JobInfo payload together with the kind needed to decode it.
+// A nil payload encodes to an empty kind.
+func EncodeJobInfo(info interface{}) (string, json.RawMessage, error) {
+	if info == nil {
+		return "", nil, nil
+	}
+	if v := reflect.ValueOf(info); v.Kind() == reflect.Ptr && v.IsNil() {
+		return "", nil, nil
+	}
+	kind, ok := payloadKindByType[reflect.TypeOf(info)]
+	if !ok {
+		return "", nil, fmt.Errorf("unsupported job payload type %T", info)
+	}
+	raw, err := json.Marshal(info)
+	if err != nil {
+		return "", nil, fmt.Errorf("encode %s payload: %w", kind, err)
+	}
+	return kind, raw, nil
+}
+
+// DecodeJobInfo restores a payload written by EncodeJobInfo.
+func DecodeJobInfo(kind string, raw json.RawMessage) (interface{}, error) {
+	if kind == "" {
+		return nil, nil
+	}
+	factory, ok := payloadKinds[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown job payload kind %q", kind)
+	}
+	info := factory()
+	if err := json.Unmarshal(raw, info); err != nil {
+		return nil, fmt.Errorf("decode %s payload: %w", kind, err)
+	}
+	return info, nil
+}
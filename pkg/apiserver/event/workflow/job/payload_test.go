@@ -0,0 +1,58 @@
+package job
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	applyv1 "k8s.io/client-go/applyconfigurations/core/v1"
+
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+func TestJobInfoRoundTrip(t *testing.T) {
+	replicas := int32(2)
+	payloads := []interface{}{
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Labels: map[string]string{"app": "web"}},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		},
+		applyv1.Service("web", "default").WithSpec(applyv1.ServiceSpec().WithPorts(applyv1.ServicePort().WithPort(80))),
+		&model.ConfigMapInput{Name: "conf", Data: map[string]string{"k": "v"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "token"}, StringData: map[string]string{"k": "v"}},
+		&model.StepSpec{DurationSeconds: 30},
+	}
+	for _, payload := range payloads {
+		kind, raw, err := EncodeJobInfo(payload)
+		require.NoError(t, err)
+		require.NotEmpty(t, kind)
+		decoded, err := DecodeJobInfo(kind, raw)
+		require.NoError(t, err)
+		require.Equal(t, payload, decoded)
+	}
+}
+
+func TestEncodeJobInfoEdgeCases(t *testing.T) {
+	kind, raw, err := EncodeJobInfo(nil)
+	require.NoError(t, err)
+	require.Empty(t, kind)
+	require.Nil(t, raw)
+	decoded, err := DecodeJobInfo(kind, raw)
+	require.NoError(t, err)
+	require.Nil(t, decoded)
+
+	kind, _, err = EncodeJobInfo((*appsv1.Deployment)(nil))
+	require.NoError(t, err)
+	require.Empty(t, kind)
+
+	_, _, err = EncodeJobInfo(appsv1.Deployment{})
+	require.Error(t, err)
+	_, err = DecodeJobInfo("Unknown", []byte(`{}`))
+	require.Error(t, err)
+}
+
+func TestPayloadKindsCoverEveryType(t *testing.T) {
+	require.Len(t, payloadKindByType, len(payloadKinds), "every kind must map to a distinct type")
+}
@@ -0,0 +1,220 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+// serverMetadataFields are populated by the API server and never part of a diff.
+var serverMetadataFields = []string{
+	"uid", "resourceVersion", "generation", "creationTimestamp", "deletionTimestamp",
+	"deletionGracePeriodSeconds", "managedFields", "selfLink", "ownerReferences",
+}
+
+const redactedValue = "<redacted>"
+
+// PlanKey identifies a planned resource; PlanOrphans uses it to tell which live objects are still wanted.
+func PlanKey(kind config.ResourceKind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+}
+
+// PlanJob compares the object a job would write with its live counterpart. The second
+// return value is false when the job writes nothing to the cluster.
+func PlanJob(ctx context.Context, client kubernetes.Interface, job *model.JobTask) (model.ResourcePlan, bool) {
+	plan := model.ResourcePlan{
+		Job:       job.Name,
+		JobType:   job.JobType,
+		Namespace: job.Namespace,
+	}
+	kind, obj, err := desiredObject(job)
+	plan.Kind = kind
+	if err != nil {
+		plan.Error = err.Error()
+		return plan, true
+	}
+	if obj == nil {
+		return plan, false
+	}
+	plan.Namespace = obj.GetNamespace()
+	plan.Name = obj.GetName()
+	if _, strategy := shareInfoFromLabels(obj.GetLabels()); strategy == config.ShareStrategyIgnore {
+		plan.Action = config.PlanActionNoop
+		return plan, true
+	}
+	accessor, ok := applyAccessors[kind]
+	if !ok || client == nil {
+		plan.Error = fmt.Sprintf("plan unsupported for %s", kind)
+		return plan, true
+	}
+	live, err := accessor.get(ctx, client, plan.Namespace, plan.Name)
+	if k8serrors.IsNotFound(err) {
+		plan.Action = config.PlanActionCreate
+		return plan, true
+	}
+	if err != nil {
+		plan.Error = fmt.Sprintf("get live object: %v", err)
+		return plan, true
+	}
+	accessor.prepare(live, obj)
+	changes, err := diffObjects(kind, live, obj)
+	if err != nil {
+		plan.Error = err.Error()
+		return plan, true
+	}
+	plan.Changes = changes
+	plan.Action = config.PlanActionNoop
+	if len(changes) > 0 {
+		plan.Action = config.PlanActionUpdate
+	}
+	if kind == config.ResourceStatefulSet && len(changes) > 0 {
+		plan.Error = fmt.Sprintf("statefulset %s/%s already exists and is not updated by the deploy job", plan.Namespace, plan.Name)
+	}
+	return plan, true
+}
+
+// PlanOrphans lists live objects labelled with the application that the plan no longer
+// produces. Shared and controller-owned objects are left out, and PVCs are never
+// reported because they hold data.
+func PlanOrphans(ctx context.Context, client kubernetes.Interface, appID string, namespaces []string, planned map[string]bool) []model.ResourcePlan {
+	if client == nil || appID == "" {
+		return nil
+	}
+	opts := metav1.ListOptions{LabelSelector: labels.Set{config.LabelAppID: appID}.String()}
+	var orphans []model.ResourcePlan
+	for kind, accessor := range applyAccessors {
+		if kind == config.ResourcePVC {
+			continue
+		}
+		scopes := namespaces
+		if isClusterScoped(kind) {
+			scopes = []string{""}
+		}
+		for _, ns := range scopes {
+			objects, err := accessor.list(ctx, client, ns, opts)
+			if err != nil {
+				klog.Warningf("list %s of app %s in namespace %q for plan failed: %v", kind, appID, ns, err)
+				continue
+			}
+			for _, obj := range objects {
+				if len(obj.GetOwnerReferences()) > 0 || obj.GetLabels()[config.LabelShareName] != "" {
+					continue
+				}
+				if planned[PlanKey(kind, obj.GetNamespace(), obj.GetName())] {
+					continue
+				}
+				orphans = append(orphans, model.ResourcePlan{
+					Kind:      kind,
+					Namespace: obj.GetNamespace(),
+					Name:      obj.GetName(),
+					Action:    config.PlanActionDelete,
+				})
+			}
+		}
+	}
+	sort.Slice(orphans, func(i, j int) bool {
+		return PlanKey(orphans[i].Kind, orphans[i].Namespace, orphans[i].Name) < PlanKey(orphans[j].Kind, orphans[j].Namespace, orphans[j].Name)
+	})
+	return orphans
+}
+
+// diffObjects reports the fields declared on desired that differ from live. Fields the
+// desired object leaves unset (server defaults, status, server metadata) are ignored.
+func diffObjects(kind config.ResourceKind, live, desired metav1.Object) ([]model.FieldChange, error) {
+	if secret, ok := desired.(*corev1.Secret); ok && len(secret.StringData) > 0 {
+		// The API server folds stringData into data, so compare in that form.
+		secret = secret.DeepCopy()
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		for k, v := range secret.StringData {
+			secret.Data[k] = []byte(v)
+		}
+		secret.StringData = nil
+		desired = secret
+	}
+	liveMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(live)
+	if err != nil {
+		return nil, fmt.Errorf("convert live object: %w", err)
+	}
+	desiredMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired)
+	if err != nil {
+		return nil, fmt.Errorf("convert desired object: %w", err)
+	}
+	delete(desiredMap, "status")
+	if metadata, ok := desiredMap["metadata"].(map[string]interface{}); ok {
+		for _, field := range serverMetadataFields {
+			delete(metadata, field)
+		}
+	}
+	changes := diffFields("", desiredMap, liveMap)
+	if kind == config.ResourceSecret {
+		for i := range changes {
+			changes[i].Live, changes[i].Desired = redact(changes[i].Live), redact(changes[i].Desired)
+		}
+	}
+	return changes, nil
+}
+
+func diffFields(path string, desired, live interface{}) []model.FieldChange {
+	switch d := desired.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return []model.FieldChange{{Path: path, Live: live, Desired: desired}}
+		}
+		keys := make([]string, 0, len(d))
+		for k := range d {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var changes []model.FieldChange
+		for _, k := range keys {
+			changes = append(changes, diffFields(joinPath(path, k), d[k], l[k])...)
+		}
+		return changes
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok || len(l) != len(d) {
+			return []model.FieldChange{{Path: path, Live: live, Desired: desired}}
+		}
+		var changes []model.FieldChange
+		for i := range d {
+			changes = append(changes, diffFields(fmt.Sprintf("%s[%d]", path, i), d[i], l[i])...)
+		}
+		return changes
+	default:
+		if !reflect.DeepEqual(desired, live) {
+			return []model.FieldChange{{Path: path, Live: live, Desired: desired}}
+		}
+		return nil
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func redact(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return redactedValue
+}
@@ -0,0 +1,100 @@
+package job
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+func newPlanDeployment(image string) *appsv1.Deployment {
+	labels := map[string]string{config.LabelAppID: "app-1"}
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: buildWebServiceName("web", "app-1"), Namespace: "default", Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "web", Image: image}}},
+			},
+		},
+	}
+}
+
+func newPlanDeployJob(image string) *model.JobTask {
+	return &model.JobTask{Name: "web", AppID: "app-1", Namespace: "default", JobType: string(config.JobDeploy), JobInfo: newPlanDeployment(image)}
+}
+
+func TestPlanJobIgnoresServerPopulatedFields(t *testing.T) {
+	live := newPlanDeployment("web:v1")
+	live.UID = "uid-1"
+	live.ResourceVersion = "42"
+	live.Generation = 3
+	live.Labels["pod-template-hash"] = "abc"
+	live.Spec.Template.Spec.DNSPolicy = corev1.DNSClusterFirst
+	live.Spec.Template.Spec.Containers[0].TerminationMessagePath = "/dev/termination-log"
+	live.Status = appsv1.DeploymentStatus{ReadyReplicas: 1}
+	client := fake.NewSimpleClientset(live)
+
+	plan, ok := PlanJob(context.Background(), client, newPlanDeployJob("web:v1"))
+	require.True(t, ok)
+	require.Empty(t, plan.Error)
+	require.Equal(t, config.PlanActionNoop, plan.Action)
+	require.Empty(t, plan.Changes)
+
+	plan, _ = PlanJob(context.Background(), client, newPlanDeployJob("web:v2"))
+	require.Equal(t, config.PlanActionUpdate, plan.Action)
+	require.Equal(t, []model.FieldChange{{
+		Path: "spec.template.spec.containers[0].image", Live: "web:v1", Desired: "web:v2",
+	}}, plan.Changes)
+}
+
+func TestPlanJobReportsCreateAndRedactsSecrets(t *testing.T) {
+	live := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+		Type:       corev1.SecretTypeOpaque,
+		Data:       map[string][]byte{"password": []byte("old")},
+	}
+	client := fake.NewSimpleClientset(live)
+
+	plan, ok := PlanJob(context.Background(), client, &model.JobTask{
+		Name: "app-config", Namespace: "default", JobType: string(config.JobDeployConfigMap),
+		JobInfo: &model.ConfigMapInput{Name: "app-config", Data: map[string]string{"k": "v"}},
+	})
+	require.True(t, ok)
+	require.Equal(t, config.PlanActionCreate, plan.Action)
+
+	plan, _ = PlanJob(context.Background(), client, &model.JobTask{
+		Name: "db", Namespace: "default", JobType: string(config.JobDeploySecret),
+		JobInfo: &model.SecretInput{Name: "db", Data: map[string]string{"password": "new"}},
+	})
+	require.Equal(t, config.PlanActionUpdate, plan.Action)
+	require.Equal(t, []model.FieldChange{{Path: "data.password", Live: redactedValue, Desired: redactedValue}}, plan.Changes)
+
+	_, ok = PlanJob(context.Background(), client, &model.JobTask{Name: "settle", JobType: string(config.JobWait)})
+	require.False(t, ok, "builtin steps write nothing")
+}
+
+func TestPlanOrphansListsUnplannedAppResources(t *testing.T) {
+	appLabels := map[string]string{config.LabelAppID: "app-1"}
+	client := fake.NewSimpleClientset(
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "kept", Namespace: "default", Labels: appLabels}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "stale", Namespace: "default", Labels: appLabels}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other-app", Namespace: "default", Labels: map[string]string{config.LabelAppID: "app-2"}}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "default", Labels: map[string]string{config.LabelAppID: "app-1", config.LabelShareName: "common"}}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default", Labels: appLabels}},
+	)
+	planned := map[string]bool{PlanKey(config.ResourceConfigMap, "default", "kept"): true}
+
+	orphans := PlanOrphans(context.Background(), client, "app-1", []string{"default"}, planned)
+	require.Equal(t, []model.ResourcePlan{{
+		Kind: config.ResourceConfigMap, Namespace: "default", Name: "stale", Action: config.PlanActionDelete,
+	}}, orphans)
+}
@@ -0,0 +1,75 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+// shouldRetryJob decides whether a failed attempt is retried: the job must have failed
+// (not been cancelled or timed out) with a transient error and have attempts left.
+func shouldRetryJob(ctx context.Context, job *model.JobTask, err error) bool {
+	if err == nil || job == nil || job.RetryPolicy == nil {
+		return false
+	}
+	if job.Attempt() >= job.RetryPolicy.Attempts {
+		return false
+	}
+	if job.Status != config.StatusFailed || ctx.Err() != nil {
+		return false
+	}
+	return IsTransientError(err)
+}
+
+// IsTransientError reports whether err is likely to succeed when retried, such as
+// optimistic-lock conflicts, API server overload or dropped connections.
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if k8serrors.IsConflict(err) ||
+		k8serrors.IsInternalError(err) ||
+		k8serrors.IsServerTimeout(err) ||
+		k8serrors.IsTimeout(err) ||
+		k8serrors.IsTooManyRequests(err) ||
+		k8serrors.IsServiceUnavailable(err) ||
+		k8serrors.IsUnexpectedServerError(err) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// retryDelay returns the backoff before the given retry (1-based):
+// delay * factor^(retry-1), capped by MaxDelay when set.
+func retryDelay(policy *model.RetryPolicy, retry int) time.Duration {
+	if policy == nil {
+		return 0
+	}
+	base := policy.BaseDelay()
+	if base <= 0 {
+		return 0
+	}
+	factor := policy.BackoffFactor
+	if factor < 1 {
+		factor = 1
+	}
+	if retry < 1 {
+		retry = 1
+	}
+	delay := float64(base) * math.Pow(factor, float64(retry-1))
+	if limit := policy.MaxBackoff(); limit > 0 && delay > float64(limit) {
+		delay = float64(limit)
+	}
+	return time.Duration(delay)
+}
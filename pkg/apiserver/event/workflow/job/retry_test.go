@@ -0,0 +1,64 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+func TestRetryDelayExponentialBackoff(t *testing.T) {
+	policy := &model.RetryPolicy{Attempts: 5, DelaySeconds: 2, BackoffFactor: 2, MaxDelay: 10}
+	require.Equal(t, 2*time.Second, retryDelay(policy, 1))
+	require.Equal(t, 4*time.Second, retryDelay(policy, 2))
+	require.Equal(t, 8*time.Second, retryDelay(policy, 3))
+	require.Equal(t, 10*time.Second, retryDelay(policy, 4))
+
+	constant := &model.RetryPolicy{Attempts: 3, DelaySeconds: 3}
+	require.Equal(t, 3*time.Second, retryDelay(constant, 3))
+	require.Zero(t, retryDelay(nil, 1))
+
+	millis := &model.RetryPolicy{Attempts: 3, DelayMillis: 200, BackoffFactor: 2, MaxDelayMillis: 300}
+	require.Equal(t, 200*time.Millisecond, retryDelay(millis, 1))
+	require.Equal(t, 300*time.Millisecond, retryDelay(millis, 2))
+}
+
+func TestIsTransientError(t *testing.T) {
+	gr := schema.GroupResource{Resource: "deployments"}
+	require.True(t, IsTransientError(k8serrors.NewConflict(gr, "demo", errors.New("modified"))))
+	require.True(t, IsTransientError(fmt.Errorf("update failed: %w", k8serrors.NewInternalError(errors.New("boom")))))
+	require.True(t, IsTransientError(k8serrors.NewTooManyRequests("slow down", 1)))
+	require.False(t, IsTransientError(k8serrors.NewBadRequest("invalid spec")))
+	require.False(t, IsTransientError(k8serrors.NewNotFound(gr, "demo")))
+	require.False(t, IsTransientError(context.Canceled))
+	require.False(t, IsTransientError(nil))
+}
+
+func TestShouldRetryJob(t *testing.T) {
+	conflict := k8serrors.NewConflict(schema.GroupResource{Resource: "services"}, "demo", errors.New("modified"))
+	job := &model.JobTask{Status: config.StatusFailed, RetryPolicy: &model.RetryPolicy{Attempts: 2}}
+	require.True(t, shouldRetryJob(context.Background(), job, conflict))
+
+	job.RetryCount = 1
+	require.False(t, shouldRetryJob(context.Background(), job, conflict), "attempts exhausted")
+
+	job.RetryCount = 0
+	job.Status = config.StatusTimeout
+	require.False(t, shouldRetryJob(context.Background(), job, conflict), "timeouts are not retried")
+
+	job.Status = config.StatusFailed
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.False(t, shouldRetryJob(ctx, job, conflict), "cancelled context")
+
+	job.RetryPolicy = nil
+	require.False(t, shouldRetryJob(context.Background(), job, conflict), "no policy")
+}
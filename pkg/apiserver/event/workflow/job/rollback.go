@@ -0,0 +1,373 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	applyv1 "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+)
+
+// snapshotRecorderKey is the private key used to stash the snapshot recorder in the context.
+type snapshotRecorderKey struct{}
+
+// snapshotTarget identifies the resource a job is about to modify.
+type snapshotTarget struct {
+	Kind      config.ResourceKind
+	Namespace string
+	Name      string
+}
+
+func (t snapshotTarget) key() string {
+	return fmt.Sprintf("%s/%s/%s", t.Kind, t.Namespace, t.Name)
+}
+
+// SnapshotRecorder captures the live spec of every resource touched by a workflow task
+// before the first job modifies it, so the task can be rolled back on failure.
+type SnapshotRecorder struct {
+	store  datastore.DataStore
+	taskID string
+	appID  string
+
+	mu   sync.Mutex
+	seen map[string]struct{}
+	seq  int
+}
+
+// NewSnapshotRecorder creates a recorder persisting snapshots for the given task. It is seeded
+// with the snapshots captured by earlier runs of the task (before a pause, an approval or a
+// hand-back), so resources changed by those runs are not captured again and sequence numbers
+// keep increasing.
+func NewSnapshotRecorder(ctx context.Context, store datastore.DataStore, taskID, appID string) (*SnapshotRecorder, error) {
+	recorder := &SnapshotRecorder{
+		store:  store,
+		taskID: taskID,
+		appID:  appID,
+		seen:   make(map[string]struct{}),
+	}
+	existing, err := ListTaskSnapshots(ctx, store, taskID)
+	if err != nil {
+		return nil, err
+	}
+	for _, snapshot := range existing {
+		target := snapshotTarget{Kind: snapshot.Kind, Namespace: snapshot.Namespace, Name: snapshot.Name}
+		recorder.seen[target.key()] = struct{}{}
+		if snapshot.Sequence > recorder.seq {
+			recorder.seq = snapshot.Sequence
+		}
+	}
+	return recorder, nil
+}
+
+// WithSnapshotRecorder attaches the recorder to ctx so job runs capture snapshots.
+func WithSnapshotRecorder(ctx context.Context, recorder *SnapshotRecorder) context.Context {
+	if recorder == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, snapshotRecorderKey{}, recorder)
+}
+
+func snapshotRecorderFromContext(ctx context.Context) *SnapshotRecorder {
+	recorder, _ := ctx.Value(snapshotRecorderKey{}).(*SnapshotRecorder)
+	return recorder
+}
+
+// captureResourceSnapshot records the pre-change state of the job's resource when the
+// workflow runs with a snapshot recorder; it is a no-op otherwise.
+func captureResourceSnapshot(ctx context.Context, client kubernetes.Interface, job *model.JobTask) error {
+	recorder := snapshotRecorderFromContext(ctx)
+	if recorder == nil {
+		return nil
+	}
+	if err := recorder.Capture(ctx, client, job); err != nil {
+		return fmt.Errorf("snapshot resource before change: %w", err)
+	}
+	return nil
+}
+
+// Capture stores the live object targeted by job. Only the first capture of a resource
+// within the task is kept, so retries and later steps never overwrite the original state.
+func (r *SnapshotRecorder) Capture(ctx context.Context, client kubernetes.Interface, job *model.JobTask) error {
+	target, ok := snapshotTargetForJob(job)
+	if !ok {
+		return nil
+	}
+	accessor, ok := snapshotAccessors[target.Kind]
+	if !ok {
+		return nil
+	}
+
+	key := target.key()
+	r.mu.Lock()
+	if _, exists := r.seen[key]; exists {
+		r.mu.Unlock()
+		return nil
+	}
+	r.seen[key] = struct{}{}
+	r.seq++
+	seq := r.seq
+	r.mu.Unlock()
+
+	snapshot := &model.ResourceSnapshot{
+		TaskID:    r.taskID,
+		AppID:     r.appID,
+		Kind:      target.Kind,
+		Namespace: target.Namespace,
+		Name:      target.Name,
+		Sequence:  seq,
+	}
+	err := func() error {
+		live, err := accessor.get(ctx, client, target.Namespace, target.Name)
+		if k8serrors.IsNotFound(err) {
+			return r.store.Add(ctx, snapshot)
+		}
+		if err != nil {
+			return err
+		}
+		object, err := model.NewJSONStructByStruct(live)
+		if err != nil {
+			return err
+		}
+		sanitizeSnapshotObject(object)
+		snapshot.Existed = true
+		snapshot.Object = object
+		return r.store.Add(ctx, snapshot)
+	}()
+	if err != nil {
+		r.mu.Lock()
+		delete(r.seen, key)
+		r.mu.Unlock()
+		return err
+	}
+	klog.FromContext(ctx).V(2).Info("Captured resource snapshot", "kind", target.Kind, "namespace", target.Namespace, "name", target.Name, "existed", snapshot.Existed)
+	return nil
+}
+
+// ListTaskSnapshots returns the snapshots captured for taskID in capture order.
+func ListTaskSnapshots(ctx context.Context, store datastore.DataStore, taskID string) ([]*model.ResourceSnapshot, error) {
+	entities, err := store.List(ctx, &model.ResourceSnapshot{TaskID: taskID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	snapshots := make([]*model.ResourceSnapshot, 0, len(entities))
+	for _, entity := range entities {
+		if snapshot, ok := entity.(*model.ResourceSnapshot); ok {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Sequence < snapshots[j].Sequence
+	})
+	return snapshots, nil
+}
+
+// RestoreSnapshots 按捕获的倒序恢复资源：执行前已存在的资源恢复为快照中的定义，执行前不存在的资源直接删除。
+// 已恢复的快照会被跳过，因此可以在部分失败后重复调用。
+func RestoreSnapshots(ctx context.Context, client kubernetes.Interface, store datastore.DataStore, snapshots []*model.ResourceSnapshot) error {
+	logger := klog.FromContext(ctx)
+	ordered := append([]*model.ResourceSnapshot(nil), snapshots...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Sequence > ordered[j].Sequence
+	})
+
+	var errs []error
+	for _, snapshot := range ordered {
+		if snapshot == nil || snapshot.Status == config.StatusRolledBack {
+			continue
+		}
+		err := restoreSnapshot(ctx, client, snapshot)
+		if err != nil {
+			logger.Error(err, "Failed to restore resource snapshot", "kind", snapshot.Kind, "namespace", snapshot.Namespace, "name", snapshot.Name)
+			errs = append(errs, fmt.Errorf("restore %s %s/%s: %w", snapshot.Kind, snapshot.Namespace, snapshot.Name, err))
+			snapshot.Status = config.StatusRollbackFailed
+			snapshot.Error = err.Error()
+		} else {
+			logger.Info("Restored resource snapshot", "kind", snapshot.Kind, "namespace", snapshot.Namespace, "name", snapshot.Name, "existed", snapshot.Existed)
+			snapshot.Status = config.StatusRolledBack
+			snapshot.Error = ""
+		}
+		if store != nil {
+			if putErr := store.Put(ctx, snapshot); putErr != nil {
+				logger.Error(putErr, "Failed to persist resource snapshot status", "id", snapshot.ID)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func restoreSnapshot(ctx context.Context, client kubernetes.Interface, snapshot *model.ResourceSnapshot) error {
+	accessor, ok := snapshotAccessors[snapshot.Kind]
+	if !ok {
+		return fmt.Errorf("unsupported resource kind %q", snapshot.Kind)
+	}
+	if !snapshot.Existed {
+		err := accessor.remove(ctx, client, snapshot.Namespace, snapshot.Name)
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if snapshot.Object == nil {
+		return fmt.Errorf("snapshot object is empty")
+	}
+	raw, err := json.Marshal(snapshot.Object)
+	if err != nil {
+		return err
+	}
+	return accessor.restore(ctx, client, snapshot.Namespace, raw)
+}
+
+func snapshotTargetForJob(job *model.JobTask) (snapshotTarget, bool) {
+	if job == nil {
+		return snapshotTarget{}, false
+	}
+	var target snapshotTarget
+	switch v := job.JobInfo.(type) {
+	case *appsv1.Deployment:
+		target = snapshotTarget{Kind: config.ResourceDeployment, Namespace: v.Namespace, Name: v.Name}
+	case *appsv1.StatefulSet:
+		target = snapshotTarget{Kind: config.ResourceStatefulSet, Namespace: v.Namespace, Name: v.Name}
+	case *appsv1.DaemonSet:
+		target = snapshotTarget{Kind: config.ResourceDaemonSet, Namespace: v.Namespace, Name: v.Name}
+	case *applyv1.ServiceApplyConfiguration:
+		target = snapshotTarget{Kind: config.ResourceService}
+		if v.Name != nil {
+			target.Name = *v.Name
+		}
+		if v.Namespace != nil {
+			target.Namespace = *v.Namespace
+		}
+	case *model.ConfigMapInput:
+		target = snapshotTarget{Kind: config.ResourceConfigMap, Namespace: v.Namespace, Name: v.Name}
+	case *corev1.ConfigMap:
+		target = snapshotTarget{Kind: config.ResourceConfigMap, Namespace: v.Namespace, Name: v.Name}
+	case *model.SecretInput:
+		target = snapshotTarget{Kind: config.ResourceSecret, Namespace: v.Namespace, Name: v.Name}
+	case *corev1.Secret:
+		target = snapshotTarget{Kind: config.ResourceSecret, Namespace: v.Namespace, Name: v.Name}
+	default:
+		return snapshotTarget{}, false
+	}
+	if target.Name == "" {
+		return snapshotTarget{}, false
+	}
+	if target.Namespace == "" {
+		target.Namespace = job.Namespace
+	}
+	return target, true
+}
+
+// sanitizeSnapshotObject drops server-populated fields so the snapshot can be written back.
+func sanitizeSnapshotObject(object *model.JSONStruct) {
+	delete(*object, "status")
+	metadata, ok := (*object)["metadata"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for _, field := range []string{"resourceVersion", "uid", "creationTimestamp", "generation", "managedFields", "selfLink", "deletionTimestamp", "deletionGracePeriodSeconds"} {
+		delete(metadata, field)
+	}
+}
+
+// resourceAccessor reads, restores and removes one kind of snapshotted resource.
+type resourceAccessor interface {
+	get(ctx context.Context, client kubernetes.Interface, namespace, name string) (metav1.Object, error)
+	restore(ctx context.Context, client kubernetes.Interface, namespace string, raw []byte) error
+	remove(ctx context.Context, client kubernetes.Interface, namespace, name string) error
+}
+
+// typedResourceClient is the subset of the typed client-go interfaces used for rollback.
+type typedResourceClient[T metav1.Object] interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (T, error)
+	Create(ctx context.Context, obj T, opts metav1.CreateOptions) (T, error)
+	Update(ctx context.Context, obj T, opts metav1.UpdateOptions) (T, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+}
+
+type typedAccessor[T metav1.Object] struct {
+	client    func(client kubernetes.Interface, namespace string) typedResourceClient[T]
+	newObject func() T
+}
+
+func (a typedAccessor[T]) get(ctx context.Context, client kubernetes.Interface, namespace, name string) (metav1.Object, error) {
+	return a.client(client, namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+func (a typedAccessor[T]) restore(ctx context.Context, client kubernetes.Interface, namespace string, raw []byte) error {
+	obj := a.newObject()
+	if err := json.Unmarshal(raw, obj); err != nil {
+		return fmt.Errorf("decode snapshot: %w", err)
+	}
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace(namespace)
+	}
+	cli := a.client(client, namespace)
+	current, err := cli.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		obj.SetResourceVersion("")
+		_, err = cli.Create(ctx, obj, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	obj.SetResourceVersion(current.GetResourceVersion())
+	_, err = cli.Update(ctx, obj, metav1.UpdateOptions{})
+	return err
+}
+
+func (a typedAccessor[T]) remove(ctx context.Context, client kubernetes.Interface, namespace, name string) error {
+	return a.client(client, namespace).Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+var snapshotAccessors = map[config.ResourceKind]resourceAccessor{
+	config.ResourceDeployment: typedAccessor[*appsv1.Deployment]{
+		client: func(c kubernetes.Interface, ns string) typedResourceClient[*appsv1.Deployment] {
+			return c.AppsV1().Deployments(ns)
+		},
+		newObject: func() *appsv1.Deployment { return &appsv1.Deployment{} },
+	},
+	config.ResourceStatefulSet: typedAccessor[*appsv1.StatefulSet]{
+		client: func(c kubernetes.Interface, ns string) typedResourceClient[*appsv1.StatefulSet] {
+			return c.AppsV1().StatefulSets(ns)
+		},
+		newObject: func() *appsv1.StatefulSet { return &appsv1.StatefulSet{} },
+	},
+	config.ResourceDaemonSet: typedAccessor[*appsv1.DaemonSet]{
+		client: func(c kubernetes.Interface, ns string) typedResourceClient[*appsv1.DaemonSet] {
+			return c.AppsV1().DaemonSets(ns)
+		},
+		newObject: func() *appsv1.DaemonSet { return &appsv1.DaemonSet{} },
+	},
+	config.ResourceService: typedAccessor[*corev1.Service]{
+		client: func(c kubernetes.Interface, ns string) typedResourceClient[*corev1.Service] {
+			return c.CoreV1().Services(ns)
+		},
+		newObject: func() *corev1.Service { return &corev1.Service{} },
+	},
+	config.ResourceConfigMap: typedAccessor[*corev1.ConfigMap]{
+		client: func(c kubernetes.Interface, ns string) typedResourceClient[*corev1.ConfigMap] {
+			return c.CoreV1().ConfigMaps(ns)
+		},
+		newObject: func() *corev1.ConfigMap { return &corev1.ConfigMap{} },
+	},
+	config.ResourceSecret: typedAccessor[*corev1.Secret]{
+		client: func(c kubernetes.Interface, ns string) typedResourceClient[*corev1.Secret] {
+			return c.CoreV1().Secrets(ns)
+		},
+		newObject: func() *corev1.Secret { return &corev1.Secret{} },
+	},
+}
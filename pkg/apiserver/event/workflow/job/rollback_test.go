@@ -0,0 +1,159 @@
+package job
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+)
+
+func TestSnapshotRecorderCapturesFirstStateOnly(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-conf", Namespace: "default", ResourceVersion: "7"},
+		Data:       map[string]string{"key": "v1"},
+	})
+	store := &jobInfoStore{}
+	recorder, err := NewSnapshotRecorder(ctx, store, "task-1", "app-1")
+	require.NoError(t, err)
+
+	cmJob := &model.JobTask{Namespace: "default", JobInfo: &model.ConfigMapInput{Name: "app-conf", Data: map[string]string{"key": "v2"}}}
+	require.NoError(t, recorder.Capture(ctx, client, cmJob))
+
+	_, err = client.CoreV1().ConfigMaps("default").Update(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-conf", Namespace: "default"},
+		Data:       map[string]string{"key": "v2"},
+	}, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, recorder.Capture(ctx, client, cmJob))
+
+	deployJob := &model.JobTask{Namespace: "default", JobInfo: &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web"}}}
+	require.NoError(t, recorder.Capture(ctx, client, deployJob))
+
+	require.Len(t, store.added, 2)
+	cmSnapshot := store.added[0].(*model.ResourceSnapshot)
+	require.Equal(t, config.ResourceConfigMap, cmSnapshot.Kind)
+	require.Equal(t, 1, cmSnapshot.Sequence)
+	require.True(t, cmSnapshot.Existed)
+	require.Equal(t, map[string]interface{}{"key": "v1"}, (*cmSnapshot.Object)["data"])
+	metadata := (*cmSnapshot.Object)["metadata"].(map[string]interface{})
+	require.NotContains(t, metadata, "resourceVersion")
+
+	deploySnapshot := store.added[1].(*model.ResourceSnapshot)
+	require.Equal(t, config.ResourceDeployment, deploySnapshot.Kind)
+	require.Equal(t, "default", deploySnapshot.Namespace)
+	require.Equal(t, 2, deploySnapshot.Sequence)
+	require.False(t, deploySnapshot.Existed)
+	require.Nil(t, deploySnapshot.Object)
+}
+
+// snapshotListStore returns the snapshots captured by an earlier run of the task.
+type snapshotListStore struct {
+	jobInfoStore
+	existing []datastore.Entity
+}
+
+func (s *snapshotListStore) List(context.Context, datastore.Entity, *datastore.ListOptions) ([]datastore.Entity, error) {
+	return s.existing, nil
+}
+
+func TestSnapshotRecorderResumesEarlierRun(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-conf", Namespace: "default"},
+		Data:       map[string]string{"key": "v2"},
+	})
+	store := &snapshotListStore{existing: []datastore.Entity{
+		&model.ResourceSnapshot{TaskID: "task-1", Kind: config.ResourceConfigMap, Namespace: "default", Name: "app-conf", Sequence: 1, Existed: true},
+		&model.ResourceSnapshot{TaskID: "task-1", Kind: config.ResourceDeployment, Namespace: "default", Name: "web", Sequence: 2},
+	}}
+	recorder, err := NewSnapshotRecorder(ctx, store, "task-1", "app-1")
+	require.NoError(t, err)
+
+	// The config map was changed before the pause; its original snapshot must be kept.
+	cmJob := &model.JobTask{Namespace: "default", JobInfo: &model.ConfigMapInput{Name: "app-conf", Data: map[string]string{"key": "v3"}}}
+	require.NoError(t, recorder.Capture(ctx, client, cmJob))
+	require.Empty(t, store.added)
+
+	apiJob := &model.JobTask{Namespace: "default", JobInfo: &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api"}}}
+	require.NoError(t, recorder.Capture(ctx, client, apiJob))
+	require.Len(t, store.added, 1)
+	require.Equal(t, 3, store.added[0].(*model.ResourceSnapshot).Sequence)
+}
+
+func TestCaptureResourceSnapshotWithoutRecorderIsNoop(t *testing.T) {
+	job := &model.JobTask{Namespace: "default", JobInfo: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "creds"}}}
+	require.NoError(t, captureResourceSnapshot(context.Background(), fake.NewSimpleClientset(), job))
+}
+
+func TestRestoreSnapshotsInReverseOrder(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-conf", Namespace: "default", ResourceVersion: "9"},
+			Data:       map[string]string{"key": "v2"},
+		},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}},
+	)
+	previous, err := model.NewJSONStructByStruct(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-conf", Namespace: "default"},
+		Data:       map[string]string{"key": "v1"},
+	})
+	require.NoError(t, err)
+	snapshots := []*model.ResourceSnapshot{
+		{ID: 1, Kind: config.ResourceConfigMap, Namespace: "default", Name: "app-conf", Sequence: 1, Existed: true, Object: previous},
+		{ID: 2, Kind: config.ResourceDeployment, Namespace: "default", Name: "web", Sequence: 2},
+	}
+
+	require.NoError(t, RestoreSnapshots(ctx, client, &jobInfoStore{}, snapshots))
+
+	var verbs []string
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "get" {
+			continue
+		}
+		verbs = append(verbs, action.GetVerb()+" "+action.GetResource().Resource)
+	}
+	require.Equal(t, []string{"delete deployments", "update configmaps"}, verbs)
+
+	cm, err := client.CoreV1().ConfigMaps("default").Get(ctx, "app-conf", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "v1", cm.Data["key"])
+	_, err = client.AppsV1().Deployments("default").Get(ctx, "web", metav1.GetOptions{})
+	require.True(t, k8serrors.IsNotFound(err))
+	for _, snapshot := range snapshots {
+		require.Equal(t, config.StatusRolledBack, snapshot.Status)
+	}
+
+	// Restored snapshots are skipped on a second pass.
+	client.ClearActions()
+	require.NoError(t, RestoreSnapshots(ctx, client, &jobInfoStore{}, snapshots))
+	require.Empty(t, client.Actions())
+}
+
+func TestRestoreSnapshotsRecreatesDeletedResource(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	previous, err := model.NewJSONStructByStruct(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "creds"},
+		StringData: map[string]string{"token": "abc"},
+	})
+	require.NoError(t, err)
+	snapshot := &model.ResourceSnapshot{Kind: config.ResourceSecret, Namespace: "default", Name: "creds", Sequence: 1, Existed: true, Object: previous}
+
+	require.NoError(t, RestoreSnapshots(ctx, client, nil, []*model.ResourceSnapshot{snapshot}))
+
+	secret, err := client.CoreV1().Secrets("default").Get(ctx, "creds", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "abc", secret.StringData["token"])
+	require.Equal(t, config.StatusRolledBack, snapshot.Status)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
@@ -21,12 +22,26 @@ import (
 	"kubemin-cli/pkg/apiserver/event/workflow/job"
 	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
 	"kubemin-cli/pkg/apiserver/utils"
+	wf "kubemin-cli/pkg/apiserver/workflow"
 )
 
 type StepExecution struct {
 	Name string
 	Mode config.WorkflowMode
 	Jobs map[int][]*model.JobTask
+	// Step is the workflow step this execution was generated from; a single
+	// StepByStep step may expand into several executions.
+	Step string
+	// DependsOn lists the workflow steps that must complete before Step starts.
+	DependsOn []string
+	// Retry is the step-level retry policy; nil falls back to the runtime defaults.
+	Retry *model.RetryPolicy
+	// Approval marks a manual approval gate; it carries no jobs.
+	Approval bool
+	// Condition is evaluated right before the execution runs; when false its jobs are skipped.
+	Condition string
+	// Timeout bounds the whole step; 0 falls back to the runtime default.
+	Timeout time.Duration
 }
 
 func GenerateJobTasks(ctx context.Context, task *model.WorkflowQueue, ds datastore.DataStore, defaultJobTimeoutSeconds int64) []StepExecution {
@@ -65,6 +80,26 @@ func GenerateJobTasks(ctx context.Context, task *model.WorkflowQueue, ds datasto
 	totalJobs := 0
 
 	for _, step := range workflowSteps.Steps {
+		emit := func(exec StepExecution) {
+			exec.Step = step.Name
+			exec.DependsOn = step.DependsOn
+			exec.Retry = step.Retry
+			exec.Condition = step.Condition
+			exec.Timeout = time.Duration(step.TimeoutSeconds) * time.Second
+			executions = append(executions, exec)
+		}
+		if step.WorkflowType == config.JobApproval {
+			emit(StepExecution{Name: step.Name, Mode: config.WorkflowModeStepByStep, Approval: true})
+			continue
+		}
+		if wf.IsBuiltinStep(step) {
+			buckets := newJobBuckets()
+			buckets[config.JobPriorityNormal] = append(buckets[config.JobPriorityNormal], newBuiltinJobTask(step, task))
+			totalJobs++
+			emit(StepExecution{Name: step.Name, Mode: config.WorkflowModeStepByStep, Jobs: buckets})
+			logGeneratedJobs(logger, task.WorkflowName, step.Name, config.WorkflowModeStepByStep, buckets)
+			continue
+		}
 		mode := step.Mode
 		if mode == "" {
 			mode = config.WorkflowModeStepByStep
@@ -82,7 +117,7 @@ func GenerateJobTasks(ctx context.Context, task *model.WorkflowQueue, ds datasto
 					if stepName == "" {
 						stepName = "parallel-group"
 					}
-					executions = append(executions, StepExecution{Name: stepName, Mode: mode, Jobs: buckets})
+					emit(StepExecution{Name: stepName, Mode: mode, Jobs: buckets})
 					logGeneratedJobs(logger, task.WorkflowName, stepName, mode, buckets)
 				}
 			} else {
@@ -98,7 +133,7 @@ func GenerateJobTasks(ctx context.Context, task *model.WorkflowQueue, ds datasto
 					if displayName == "" && len(subComponents) == 1 {
 						displayName = subComponents[0]
 					}
-					executions = append(executions, StepExecution{Name: displayName, Mode: config.WorkflowModeStepByStep, Jobs: buckets})
+					emit(StepExecution{Name: displayName, Mode: config.WorkflowModeStepByStep, Jobs: buckets})
 					logGeneratedJobs(logger, task.WorkflowName, displayName, config.WorkflowModeStepByStep, buckets)
 				}
 			}
@@ -118,7 +153,7 @@ func GenerateJobTasks(ctx context.Context, task *model.WorkflowQueue, ds datasto
 				if stepName == "" && len(componentNames) > 1 {
 					stepName = "parallel-group"
 				}
-				executions = append(executions, StepExecution{Name: stepName, Mode: mode, Jobs: buckets})
+				emit(StepExecution{Name: stepName, Mode: mode, Jobs: buckets})
 				logGeneratedJobs(logger, task.WorkflowName, stepName, mode, buckets)
 			}
 			continue
@@ -130,7 +165,7 @@ func GenerateJobTasks(ctx context.Context, task *model.WorkflowQueue, ds datasto
 				continue
 			}
 			totalJobs += countJobs(buckets)
-			executions = append(executions, StepExecution{Name: name, Mode: config.WorkflowModeStepByStep, Jobs: buckets})
+			emit(StepExecution{Name: name, Mode: config.WorkflowModeStepByStep, Jobs: buckets})
 			logGeneratedJobs(logger, task.WorkflowName, name, config.WorkflowModeStepByStep, buckets)
 		}
 	}
@@ -155,6 +190,28 @@ func NewJobTask(name, namespace, workflowID, projectID, appID, taskID string, ti
 	}
 }
 
+// newBuiltinJobTask builds the single job of a wait, http-check or notify step. The job is
+// named after the step so its JobInfo records map back to the step. Polling steps without a
+// step timeout fall back to DefaultBuiltinStepTimeout; a plain wait is bounded by its duration.
+func newBuiltinJobTask(step *model.WorkflowStep, task *model.WorkflowQueue) *model.JobTask {
+	jobTask := NewJobTask(step.Name, "", task.WorkflowID, task.ProjectID, task.AppID, task.TaskID, 0)
+	jobTask.JobType = string(step.WorkflowType)
+	spec := model.StepSpec{}
+	if step.Spec != nil {
+		spec = *step.Spec
+	}
+	jobTask.JobInfo = &spec
+	switch {
+	case step.TimeoutSeconds > 0:
+		jobTask.Timeout = int64(step.TimeoutSeconds)
+	case step.WorkflowType == config.JobWait && strings.TrimSpace(spec.Until) == "":
+		jobTask.Timeout = 0
+	default:
+		jobTask.Timeout = int64(config.DefaultBuiltinStepTimeout / time.Second)
+	}
+	return jobTask
+}
+
 // setDeployTimeout forces deployment-related jobs to use the standard deploy timeout (20 minutes).
 func setDeployTimeout(jobTask *model.JobTask) {
 	if jobTask == nil {
@@ -383,6 +440,15 @@ func buildJobsForComponent(ctx context.Context, component *model.ApplicationComp
 	case config.StoreJob:
 		storeJobs := job.GenerateStoreService(component)
 		queueServiceJobs(logger, buckets, component, task, namespace, config.JobDeployStore, storeJobs, defaultJobTimeoutSeconds, share)
+	case config.DaemonJob:
+		daemonJobs := job.GenerateDaemonSet(component, &properties)
+		queueServiceJobs(logger, buckets, component, task, namespace, config.JobDeployDaemonSet, daemonJobs, defaultJobTimeoutSeconds, share)
+	case config.BatchJob:
+		batchJobs := job.GenerateBatchJob(component, &properties)
+		queueServiceJobs(logger, buckets, component, task, namespace, config.JobDeployBatchJob, batchJobs, defaultJobTimeoutSeconds, share)
+	case config.CronJob:
+		cronJobs := job.GenerateCronJob(component, &properties)
+		queueServiceJobs(logger, buckets, component, task, namespace, config.JobDeployCronJob, cronJobs, defaultJobTimeoutSeconds, share)
 
 	case config.ConfJob:
 		jobTask := NewJobTask(component.Name, namespace, task.WorkflowID, task.ProjectID, task.AppID, task.TaskID, defaultJobTimeoutSeconds)
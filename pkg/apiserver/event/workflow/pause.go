@@ -0,0 +1,40 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/klog/v2"
+
+	"kubemin-cli/pkg/apiserver/workflow/signal"
+)
+
+// errWorkflowPaused is returned when a pause was requested for the task. It is a
+// suspension, so scheduling stops without the task being treated as failed.
+var errWorkflowPaused = fmt.Errorf("%w: paused by request", errWorkflowSuspended)
+
+// bucketKey identifies one priority bucket of a step execution; it is recorded once
+// the bucket finished so a resumed task continues with the next bucket.
+func bucketKey(exec StepExecution, priority int) string {
+	return fmt.Sprintf("%s#%d", executionKey(exec), priority)
+}
+
+// checkPause 在启动下一个优先级分组前检查暂停请求：已暂停时返回 errWorkflowPaused，
+// 当前分组的 Job 已全部结束，位置由已完成的分组记录保存
+func (w *WorkflowCtl) checkPause(ctx context.Context) error {
+	taskID := w.snapshotTask().TaskID
+	if taskID == "" {
+		return nil
+	}
+	paused, reason, err := signal.PauseRequested(ctx, taskID)
+	if err != nil {
+		// A transient signal backend error must not stop the rollout.
+		klog.FromContext(ctx).Error(err, "Failed to check workflow pause request")
+		return nil
+	}
+	if !paused {
+		return nil
+	}
+	klog.FromContext(ctx).Info("Workflow paused by request", "reason", reason)
+	return errWorkflowPaused
+}
@@ -0,0 +1,62 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/workflow/signal"
+)
+
+func TestRunStepExecutionStopsBeforeNextBucketWhenPaused(t *testing.T) {
+	ctx := context.Background()
+	first := &model.JobTask{Name: "config", Status: config.StatusQueued}
+	second := &model.JobTask{Name: "web", Status: config.StatusQueued}
+	exec := StepExecution{
+		Name: "deploy",
+		Step: "deploy",
+		Mode: config.WorkflowModeStepByStep,
+		Jobs: map[int][]*model.JobTask{
+			config.JobPriorityMaxHigh: {first},
+			config.JobPriorityNormal:  {second},
+		},
+	}
+	// The first bucket finished before the pause; only the second one is pending.
+	ctl := &WorkflowCtl{workflowTask: &model.WorkflowQueue{
+		TaskID:         "task-pause-1",
+		Status:         config.StatusRunning,
+		CompletedSteps: []string{bucketKey(exec, config.JobPriorityMaxHigh)},
+	}}
+
+	require.NoError(t, signal.Pause(ctx, "task-pause-1", "investigating"))
+	defer func() { _ = signal.ClearPause(ctx, "task-pause-1") }()
+
+	err := ctl.runStepExecution(ctx, exec, 1)
+	require.ErrorIs(t, err, errWorkflowPaused)
+	require.ErrorIs(t, err, errWorkflowSuspended)
+	require.Equal(t, config.StatusQueued, first.Status, "completed bucket must not run again")
+	require.Equal(t, config.StatusQueued, second.Status, "no job starts after the pause")
+}
+
+func TestSuspendPersistsPauseStatus(t *testing.T) {
+	store := &approvalStore{}
+	ctl := &WorkflowCtl{
+		workflowTask: &model.WorkflowQueue{TaskID: "task-pause-2", Status: config.StatusRunning},
+		Store:        store,
+	}
+
+	ctl.suspend(context.Background(), config.StatusPause)
+
+	require.Equal(t, []config.Status{config.StatusPause}, store.statusSwaps)
+	require.Equal(t, config.StatusPause, ctl.snapshotTask().Status)
+	require.True(t, isWorkflowSuspended(config.StatusPause))
+	require.False(t, isWorkflowSuspended(config.StatusRunning))
+}
+
+func TestBucketKey(t *testing.T) {
+	exec := StepExecution{Name: "api", Step: "Backend"}
+	require.Equal(t, "backend/api#2", bucketKey(exec, 2))
+}
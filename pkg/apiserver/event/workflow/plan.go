@@ -0,0 +1,51 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/event/workflow/job"
+)
+
+// PlanWorkflow 生成与真实执行相同的 Job 计划，逐个对比期望对象与集群中的线上对象，
+// 并列出应用已有但计划中不再生成的资源。只读取集群，不做任何修改
+func (w *Workflow) PlanWorkflow(ctx context.Context, task *model.WorkflowQueue) ([]model.ResourcePlan, error) {
+	if task == nil {
+		return nil, fmt.Errorf("task is nil")
+	}
+	if w.KubeClient == nil {
+		return nil, fmt.Errorf("kube client is nil")
+	}
+	executions := GenerateJobTasks(ctx, task, w.Store, resolveDefaultJobTimeout(w.Cfg))
+	var plans []model.ResourcePlan
+	planned := make(map[string]bool)
+	var namespaces []string
+	seenNamespace := make(map[string]bool)
+	for _, execution := range executions {
+		step := execution.Step
+		if step == "" {
+			step = execution.Name
+		}
+		for _, priority := range sortedPriorities(execution.Jobs) {
+			for _, jobTask := range execution.Jobs[priority] {
+				if jobTask == nil {
+					continue
+				}
+				plan, ok := job.PlanJob(ctx, w.KubeClient, jobTask)
+				if !ok {
+					continue
+				}
+				plan.Step = step
+				plans = append(plans, plan)
+				planned[job.PlanKey(plan.Kind, plan.Namespace, plan.Name)] = true
+				if plan.Namespace != "" && !seenNamespace[plan.Namespace] {
+					seenNamespace[plan.Namespace] = true
+					namespaces = append(namespaces, plan.Namespace)
+				}
+			}
+		}
+	}
+	plans = append(plans, job.PlanOrphans(ctx, w.KubeClient, task.AppID, namespaces, planned)...)
+	return plans, nil
+}
@@ -0,0 +1,82 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+// QueuedTaskReconciler 周期性地把停留在 queued、但队列中已没有其未确认分发消息的任务改回 waiting，
+// 由 Dispatcher 重新分发。消息转入死信、或在确认后任务启动前实例退出时，任务会停留在该状态
+func (w *Workflow) QueuedTaskReconciler(ctx context.Context) {
+	ticker := time.NewTicker(w.queuedReconcileInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			klog.V(3).Info("queued task reconciler stopped: context cancelled")
+			return
+		case <-ticker.C:
+		}
+		w.reconcileQueuedTasks(ctx)
+	}
+}
+
+// reconcileQueuedTasks re-queues the stale queued tasks without an outstanding dispatch
+// message and returns how many it re-queued. Nothing is re-queued when the outstanding
+// messages cannot be read.
+func (w *Workflow) reconcileQueuedTasks(ctx context.Context) int {
+	tasks, err := w.activeTasks(ctx)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			klog.Errorf("list active workflow tasks failed: %v", err)
+		}
+		return 0
+	}
+	// A task is queued shortly before its message is enqueued and after it is acked; only
+	// tasks that stayed queued for a whole interval are considered.
+	staleBefore := time.Now().Add(-w.queuedReconcileInterval())
+	var stale []*model.WorkflowQueue
+	for _, task := range tasks {
+		if task.Status == config.StatusQueued && task.UpdateTime.Before(staleBefore) {
+			stale = append(stale, task)
+		}
+	}
+	if len(stale) == 0 {
+		return 0
+	}
+
+	outstanding, err := w.Queue.Outstanding(ctx, w.consumerGroup())
+	if err != nil {
+		klog.Errorf("read outstanding dispatch messages failed: %v", err)
+		return 0
+	}
+	live := make(map[string]struct{}, len(outstanding))
+	for _, m := range outstanding {
+		if td, err := UnmarshalTaskDispatch(m.Payload); err == nil {
+			live[td.TaskID] = struct{}{}
+		}
+	}
+
+	requeued := 0
+	for _, task := range stale {
+		if _, ok := live[task.TaskID]; ok {
+			continue
+		}
+		ok, err := w.markTaskStatus(ctx, task.TaskID, config.StatusQueued, config.StatusWaiting)
+		if err != nil {
+			klog.Errorf("re-queue task %s left in queued failed: %v", task.TaskID, err)
+			continue
+		}
+		if ok {
+			klog.Infof("re-queued task %s left in queued without a dispatch message", task.TaskID)
+			requeued++
+		}
+	}
+	return requeued
+}
@@ -0,0 +1,74 @@
+package workflow
+
+import (
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+// jobRetrySettings holds the runtime retry defaults used when a step does not
+// declare its own retry policy.
+type jobRetrySettings struct {
+	policy model.RetryPolicy
+	byType map[string]int
+}
+
+func resolveJobRetrySettings(cfg *config.Config) jobRetrySettings {
+	settings := jobRetrySettings{
+		policy: model.RetryPolicy{
+			Attempts:       config.DefaultJobRetryAttempts,
+			DelayMillis:    config.DefaultJobRetryDelay.Milliseconds(),
+			BackoffFactor:  config.DefaultJobRetryBackoffFactor,
+			MaxDelayMillis: config.DefaultJobRetryMaxDelay.Milliseconds(),
+		},
+	}
+	if cfg == nil {
+		return settings
+	}
+	wf := cfg.Workflow
+	if wf.JobRetryAttempts > 0 {
+		settings.policy.Attempts = wf.JobRetryAttempts
+	}
+	if wf.JobRetryDelay > 0 {
+		settings.policy.DelayMillis = wf.JobRetryDelay.Milliseconds()
+	}
+	if wf.JobRetryBackoffFactor >= 1 {
+		settings.policy.BackoffFactor = wf.JobRetryBackoffFactor
+	}
+	if wf.JobRetryMaxDelay > 0 {
+		settings.policy.MaxDelayMillis = wf.JobRetryMaxDelay.Milliseconds()
+	}
+	settings.byType = wf.JobRetryAttemptsByType
+	return settings
+}
+
+// policyFor returns the retry policy for a job: the step policy wins, then the
+// per-job-type attempts override, then the global default.
+func (s jobRetrySettings) policyFor(step *model.RetryPolicy, jobType string) *model.RetryPolicy {
+	if step != nil {
+		policy := *step
+		if policy.Attempts < 1 {
+			policy.Attempts = 1
+		}
+		return &policy
+	}
+	policy := s.policy
+	if attempts, ok := s.byType[jobType]; ok && attempts > 0 {
+		policy.Attempts = attempts
+	}
+	if policy.Attempts < 1 {
+		policy.Attempts = 1
+	}
+	return &policy
+}
+
+func (s jobRetrySettings) apply(executions []StepExecution) {
+	for _, exec := range executions {
+		for _, jobs := range exec.Jobs {
+			for _, job := range jobs {
+				if job != nil && job.RetryPolicy == nil {
+					job.RetryPolicy = s.policyFor(exec.Retry, job.JobType)
+				}
+			}
+		}
+	}
+}
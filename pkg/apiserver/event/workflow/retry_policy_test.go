@@ -0,0 +1,44 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+func TestJobRetrySettingsPrecedence(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Workflow.JobRetryAttempts = 2
+	cfg.Workflow.JobRetryDelay = 500 * time.Millisecond
+	cfg.Workflow.JobRetryAttemptsByType = map[string]int{string(config.JobDeployService): 4}
+	settings := resolveJobRetrySettings(cfg)
+
+	deployPolicy := settings.policyFor(nil, string(config.JobDeploy))
+	require.Equal(t, 2, deployPolicy.Attempts)
+	require.Equal(t, 500*time.Millisecond, deployPolicy.BaseDelay(), "sub-second delays are kept")
+
+	servicePolicy := settings.policyFor(nil, string(config.JobDeployService))
+	require.Equal(t, 4, servicePolicy.Attempts)
+
+	stepPolicy := settings.policyFor(&model.RetryPolicy{Attempts: 6, DelaySeconds: 1}, string(config.JobDeployService))
+	require.Equal(t, 6, stepPolicy.Attempts)
+	require.Equal(t, 1, stepPolicy.DelaySeconds)
+}
+
+func TestJobRetrySettingsApply(t *testing.T) {
+	settings := resolveJobRetrySettings(nil)
+	stepJob := &model.JobTask{JobType: string(config.JobDeploy)}
+	defaultJob := &model.JobTask{JobType: string(config.JobDeploy)}
+	executions := []StepExecution{
+		{Name: "a", Retry: &model.RetryPolicy{Attempts: 1}, Jobs: map[int][]*model.JobTask{config.JobPriorityNormal: {stepJob}}},
+		{Name: "b", Jobs: map[int][]*model.JobTask{config.JobPriorityNormal: {defaultJob}}},
+	}
+	settings.apply(executions)
+
+	require.Equal(t, 1, stepJob.RetryPolicy.Attempts)
+	require.Equal(t, 1, defaultJob.RetryPolicy.Attempts, "retries are opt-in")
+}
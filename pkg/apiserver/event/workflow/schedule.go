@@ -0,0 +1,34 @@
+package workflow
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// ScheduleTrigger 周期性触发到点的工作流定时执行。Start 只在 leader 上运行，
+// 因此同一时刻只有一个实例创建定时任务
+func (w *Workflow) ScheduleTrigger(ctx context.Context) {
+	ticker := time.NewTicker(w.schedulePollInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			klog.V(3).Info("workflow schedule trigger stopped: context cancelled")
+			return
+		case now := <-ticker.C:
+			fired, err := w.WorkflowService.FireDueSchedules(ctx, now)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				klog.Errorf("fire workflow schedules failed: %v", err)
+				continue
+			}
+			if fired > 0 {
+				klog.V(2).Infof("fired %d workflow schedules", fired)
+			}
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	wf "kubemin-cli/pkg/apiserver/workflow"
+)
+
+// stepNode groups the executions generated from one workflow step. Executions
+// inside a node keep running in order; nodes run as soon as their dependencies
+// have completed.
+type stepNode struct {
+	name      string
+	dependsOn []string
+	execs     []StepExecution
+}
+
+func hasStepDependencies(executions []StepExecution) bool {
+	for _, exec := range executions {
+		if len(exec.DependsOn) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// buildStepGraph folds step executions into graph nodes keyed by the owning step,
+// preserving the declaration order of the workflow.
+func buildStepGraph(executions []StepExecution) []*stepNode {
+	var nodes []*stepNode
+	index := make(map[string]*stepNode)
+	for _, exec := range executions {
+		owner := exec.Step
+		if owner == "" {
+			owner = exec.Name
+		}
+		key := strings.ToLower(owner)
+		node, ok := index[key]
+		if !ok {
+			node = &stepNode{name: key, dependsOn: wf.NormalizeDependsOn(exec.DependsOn)}
+			index[key] = node
+			nodes = append(nodes, node)
+		}
+		node.execs = append(node.execs, exec)
+	}
+	return nodes
+}
+
+type stepResult struct {
+	name string
+	err  error
+}
+
+// runStepGraph 按依赖关系调度工作流步骤：依赖已满足的步骤并发执行，任一步骤失败后取消其余步骤并返回错误
+func (w *WorkflowCtl) runStepGraph(ctx context.Context, executions []StepExecution, seqLimit int) error {
+	logger := klog.FromContext(ctx)
+	nodes := buildStepGraph(executions)
+
+	known := make(map[string]struct{}, len(nodes))
+	for _, node := range nodes {
+		known[node.name] = struct{}{}
+	}
+	done := make(map[string]bool, len(nodes))
+	started := make(map[string]bool, len(nodes))
+
+	// A dependency on a step that produced no jobs is treated as satisfied.
+	ready := func(node *stepNode) bool {
+		for _, dep := range node.dependsOn {
+			if _, ok := known[dep]; ok && !done[dep] {
+				return false
+			}
+		}
+		return true
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan stepResult, len(nodes))
+	running := 0
+	var firstErr error
+	for {
+		if firstErr == nil {
+			for _, node := range nodes {
+				if started[node.name] || !ready(node) {
+					continue
+				}
+				started[node.name] = true
+				running++
+				logger.Info("Starting workflow step", "step", node.name, "dependsOn", node.dependsOn)
+				go func(node *stepNode) {
+					var err error
+					for _, exec := range node.execs {
+						if err = w.runTrackedStep(ctx, exec, seqLimit); err != nil {
+							break
+						}
+					}
+					results <- stepResult{name: node.name, err: err}
+				}(node)
+			}
+		}
+		if running == 0 {
+			break
+		}
+		res := <-results
+		running--
+		if res.err != nil {
+			// A suspension (approval gate or pause) only stops new steps from starting: running branches
+			// finish normally. A real failure still takes precedence and cancels them.
+			if errors.Is(res.err, errWorkflowSuspended) {
+				if firstErr == nil {
+					firstErr = res.err
+				}
+				continue
+			}
+			if firstErr == nil || errors.Is(firstErr, errWorkflowSuspended) {
+				firstErr = res.err
+				// Stop sibling branches; their jobs observe the cancelled context.
+				cancel()
+			}
+			continue
+		}
+		done[res.name] = true
+	}
+
+	if firstErr != nil {
+		return firstErr
+	}
+	if len(done) < len(nodes) {
+		var pending []string
+		for _, node := range nodes {
+			if !done[node.name] {
+				pending = append(pending, node.name)
+			}
+		}
+		sort.Strings(pending)
+		return fmt.Errorf("workflow steps %s have unsatisfiable dependencies", strings.Join(pending, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+func TestBuildStepGraphGroupsExecutionsByStep(t *testing.T) {
+	executions := []StepExecution{
+		{Name: "config", Step: "config"},
+		{Name: "api", Step: "backend", DependsOn: []string{"Config"}},
+		{Name: "worker", Step: "backend", DependsOn: []string{"Config"}},
+		{Name: "web", Step: "web", DependsOn: []string{" backend", "BACKEND "}},
+	}
+
+	nodes := buildStepGraph(executions)
+	require.Len(t, nodes, 3)
+	require.Equal(t, "config", nodes[0].name)
+	require.Empty(t, nodes[0].dependsOn)
+	require.Equal(t, "backend", nodes[1].name)
+	require.Equal(t, []string{"config"}, nodes[1].dependsOn)
+	require.Len(t, nodes[1].execs, 2)
+	require.Equal(t, "web", nodes[2].name)
+	require.Equal(t, []string{"backend"}, nodes[2].dependsOn)
+}
+
+func TestHasStepDependencies(t *testing.T) {
+	require.False(t, hasStepDependencies([]StepExecution{{Name: "a"}, {Name: "b"}}))
+	require.True(t, hasStepDependencies([]StepExecution{{Name: "a"}, {Name: "b", DependsOn: []string{"a"}}}))
+}
+
+func TestRunStepGraphCompletesAllSteps(t *testing.T) {
+	ctl := &WorkflowCtl{workflowTask: &model.WorkflowQueue{TaskID: "task-1", Status: config.StatusRunning}}
+	executions := []StepExecution{
+		{Name: "a", Step: "a"},
+		{Name: "b", Step: "b", DependsOn: []string{"a"}},
+		{Name: "c", Step: "c", DependsOn: []string{"a"}},
+		{Name: "d", Step: "d", DependsOn: []string{"b", "c", "skipped"}},
+	}
+	require.NoError(t, ctl.runStepGraph(context.Background(), executions, 1))
+}
+
+func TestRunStepGraphReportsUnsatisfiableDependencies(t *testing.T) {
+	ctl := &WorkflowCtl{workflowTask: &model.WorkflowQueue{TaskID: "task-1", Status: config.StatusRunning}}
+	executions := []StepExecution{
+		{Name: "a", Step: "a", DependsOn: []string{"b"}},
+		{Name: "b", Step: "b", DependsOn: []string{"a"}},
+	}
+	err := ctl.runStepGraph(context.Background(), executions, 1)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "a, b")
+}
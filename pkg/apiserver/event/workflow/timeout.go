@@ -0,0 +1,95 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+var (
+	// errWorkflowTimeout is the cancellation cause once a task or step deadline passes.
+	errWorkflowTimeout = errors.New("workflow timed out")
+	errTaskDeadline    = fmt.Errorf("%w: task deadline exceeded", errWorkflowTimeout)
+	errStepDeadline    = fmt.Errorf("%w: step deadline exceeded", errWorkflowTimeout)
+)
+
+// initTaskDeadline 在任务首次运行时确定截止时间；恢复运行的任务沿用已持久化的截止时间
+func (w *WorkflowCtl) initTaskDeadline(task *model.WorkflowQueue) {
+	if task.Deadline != nil || w.taskTimeout <= 0 {
+		return
+	}
+	deadline := time.Now().Add(w.taskTimeout)
+	task.Deadline = &deadline
+}
+
+// withTaskDeadline bounds the workflow context by the task deadline, if any.
+func (w *WorkflowCtl) withTaskDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	task := w.snapshotTask()
+	if task.Deadline == nil {
+		return context.WithCancel(ctx)
+	}
+	return context.WithDeadlineCause(ctx, *task.Deadline, errTaskDeadline)
+}
+
+// withStepDeadline bounds a step execution by the step timeout. Executions expanded from
+// the same step share one deadline, started by the first of them. Approval gates do not
+// wait inside the controller and are not bounded.
+func (w *WorkflowCtl) withStepDeadline(ctx context.Context, exec StepExecution) (context.Context, context.CancelFunc) {
+	timeout := exec.Timeout
+	if timeout <= 0 {
+		timeout = w.stepTimeout
+	}
+	if timeout <= 0 || exec.Approval {
+		return ctx, func() {}
+	}
+	key := stepResultKey(exec)
+	w.stepDeadlinesMutex.Lock()
+	deadline, ok := w.stepDeadlines[key]
+	if !ok {
+		deadline = time.Now().Add(timeout)
+		if w.stepDeadlines == nil {
+			w.stepDeadlines = make(map[string]time.Time)
+		}
+		w.stepDeadlines[key] = deadline
+	}
+	w.stepDeadlinesMutex.Unlock()
+	return context.WithDeadlineCause(ctx, deadline, errStepDeadline)
+}
+
+// stepTimedOut turns the failure of a step whose task or step deadline passed into a
+// timeout error, and records the step on the task so the status shows where it stopped.
+func (w *WorkflowCtl) stepTimedOut(ctx context.Context, exec StepExecution, err error) error {
+	cause := context.Cause(ctx)
+	if !errors.Is(cause, errWorkflowTimeout) || errors.Is(err, errWorkflowSuspended) {
+		return err
+	}
+	klog.FromContext(ctx).Info("Workflow step timed out", "step", exec.Step, "execution", exec.Name, "cause", cause)
+	w.recordStepResult(exec, config.StatusTimeout)
+	w.mutateTask(func(task *model.WorkflowQueue) {
+		if task.TimeoutStep == "" {
+			task.TimeoutStep = exec.Step
+		}
+	})
+	w.ack()
+	return fmt.Errorf("step %s: %w: %v", exec.Step, cause, err)
+}
+
+func resolveTaskTimeout(cfg *config.Config) time.Duration {
+	if cfg == nil {
+		return config.DefaultWorkflowTaskTimeout
+	}
+	return cfg.Workflow.TaskTimeout
+}
+
+func resolveStepTimeout(cfg *config.Config) time.Duration {
+	if cfg == nil {
+		return 0
+	}
+	return cfg.Workflow.StepTimeout
+}
@@ -0,0 +1,101 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+)
+
+// timeoutStore serves the workflow definition and records task status swaps.
+type timeoutStore struct {
+	fakeDataStore
+	statusSwaps []config.Status
+}
+
+func (s *timeoutStore) CompareAndSwap(_ context.Context, _ datastore.Entity, _ string, _ interface{}, updates map[string]interface{}) (bool, error) {
+	s.statusSwaps = append(s.statusSwaps, updates["status"].(config.Status))
+	return true, nil
+}
+
+func TestRunEndsInTimeoutAfterTaskDeadline(t *testing.T) {
+	steps := &model.WorkflowSteps{Steps: []*model.WorkflowStep{{Name: "config"}}}
+	stepsJSON, err := model.NewJSONStructByStruct(steps)
+	require.NoError(t, err)
+	configProps, err := model.NewJSONStructByStruct(model.Properties{Conf: map[string]string{"k": "v"}})
+	require.NoError(t, err)
+
+	store := &timeoutStore{fakeDataStore: fakeDataStore{
+		workflow: &model.Workflow{ID: "wf-1", Steps: stepsJSON},
+		components: []*model.ApplicationComponent{
+			{Name: "config", AppID: "app-1", Namespace: "default", ComponentType: config.ConfJob, Properties: configProps},
+		},
+	}}
+	// A task resumed after its deadline passed while it was paused.
+	deadline := time.Now().Add(-time.Minute)
+	task := &model.WorkflowQueue{TaskID: "task-timeout-1", AppID: "app-1", WorkflowID: "wf-1", WorkflowName: "demo", Deadline: &deadline}
+	ctl := NewWorkflowController(task, nil, store, config.NewConfig())
+
+	err = ctl.Run(context.Background(), 1)
+	require.ErrorIs(t, err, errWorkflowTimeout)
+
+	snapshot := ctl.snapshotTask()
+	require.Equal(t, config.StatusTimeout, snapshot.Status)
+	require.Equal(t, "config", snapshot.TimeoutStep)
+	require.Equal(t, config.StatusTimeout, snapshot.StepResults["config"])
+	require.Equal(t, deadline, *snapshot.Deadline, "an existing deadline is never extended")
+	require.Equal(t, []config.Status{config.StatusTimeout}, store.statusSwaps)
+}
+
+func TestInitTaskDeadline(t *testing.T) {
+	ctl := &WorkflowCtl{taskTimeout: time.Hour}
+	task := &model.WorkflowQueue{}
+	ctl.initTaskDeadline(task)
+	require.NotNil(t, task.Deadline)
+	require.WithinDuration(t, time.Now().Add(time.Hour), *task.Deadline, time.Minute)
+
+	disabled := &WorkflowCtl{}
+	task = &model.WorkflowQueue{}
+	disabled.initTaskDeadline(task)
+	require.Nil(t, task.Deadline)
+}
+
+func TestWithStepDeadlineSharedAcrossExecutions(t *testing.T) {
+	ctl := &WorkflowCtl{stepTimeout: time.Hour}
+	ctx := context.Background()
+
+	first, cancel := ctl.withStepDeadline(ctx, StepExecution{Name: "config", Step: "deploy", Timeout: time.Minute})
+	defer cancel()
+	second, cancel2 := ctl.withStepDeadline(ctx, StepExecution{Name: "web", Step: "deploy", Timeout: time.Minute})
+	defer cancel2()
+	firstDeadline, ok := first.Deadline()
+	require.True(t, ok)
+	secondDeadline, _ := second.Deadline()
+	require.Equal(t, firstDeadline, secondDeadline)
+	require.WithinDuration(t, time.Now().Add(time.Minute), firstDeadline, 10*time.Second)
+
+	// Steps without timeout_seconds use the runtime default; approval gates are unbounded.
+	other, cancel3 := ctl.withStepDeadline(ctx, StepExecution{Name: "db", Step: "db"})
+	defer cancel3()
+	otherDeadline, _ := other.Deadline()
+	require.WithinDuration(t, time.Now().Add(time.Hour), otherDeadline, 10*time.Second)
+
+	gate, cancel4 := ctl.withStepDeadline(ctx, StepExecution{Name: "gate", Step: "gate", Approval: true})
+	defer cancel4()
+	_, ok = gate.Deadline()
+	require.False(t, ok)
+}
+
+func TestStepTimedOutKeepsOtherErrors(t *testing.T) {
+	ctl := &WorkflowCtl{workflowTask: &model.WorkflowQueue{TaskID: "task-timeout-2", Status: config.StatusRunning}}
+	exec := StepExecution{Name: "web", Step: "web"}
+
+	err := ctl.stepTimedOut(context.Background(), exec, errWorkflowPaused)
+	require.ErrorIs(t, err, errWorkflowPaused)
+	require.Empty(t, ctl.snapshotTask().TimeoutStep)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"k8s.io/client-go/kubernetes/fake"
@@ -18,6 +19,7 @@ import (
 type workflowAckTestStore struct {
 	workflow *model.Workflow
 	task     *model.WorkflowQueue
+	getErr   error
 }
 
 func (s *workflowAckTestStore) Add(context.Context, datastore.Entity) error        { return nil }
@@ -29,6 +31,9 @@ func (s *workflowAckTestStore) DeleteByFilter(context.Context, datastore.Entity,
 }
 
 func (s *workflowAckTestStore) Get(ctx context.Context, entity datastore.Entity) error {
+	if s.getErr != nil {
+		return s.getErr
+	}
 	switch e := entity.(type) {
 	case *model.Workflow:
 		if s.workflow == nil {
@@ -68,7 +73,11 @@ func (s *workflowAckTestStore) CompareAndSwap(context.Context, datastore.Entity,
 }
 
 type stubWorkflowService struct {
-	updateOK bool
+	updateOK  bool
+	running   []*model.WorkflowQueue
+	cancelled []string
+	marked    []config.Status
+	updates   int
 }
 
 func (s *stubWorkflowService) ListApplicationWorkflow(context.Context, *model.Applications) error {
@@ -83,7 +92,7 @@ func (s *stubWorkflowService) ExecWorkflowTask(context.Context, string) (*apis.E
 	return nil, nil
 }
 
-func (s *stubWorkflowService) ExecWorkflowTaskForApp(context.Context, string, string) (*apis.ExecWorkflowResponse, error) {
+func (s *stubWorkflowService) ExecWorkflowTaskForApp(context.Context, string, string, map[string]interface{}, int) (*apis.ExecWorkflowResponse, error) {
 	return nil, nil
 }
 
@@ -91,19 +100,37 @@ func (s *stubWorkflowService) WaitingTasks(context.Context) ([]*model.WorkflowQu
 	return nil, nil
 }
 func (s *stubWorkflowService) UpdateTask(context.Context, *model.WorkflowQueue) bool {
+	s.updates++
 	return s.updateOK
 }
 func (s *stubWorkflowService) TaskRunning(context.Context) ([]*model.WorkflowQueue, error) {
-	return nil, nil
+	return s.running, nil
 }
-func (s *stubWorkflowService) CancelWorkflowTask(context.Context, string, string, string) error {
+func (s *stubWorkflowService) CancelWorkflowTask(_ context.Context, _ string, taskID, _ string) error {
+	s.cancelled = append(s.cancelled, taskID)
 	return nil
 }
 
 func (s *stubWorkflowService) CancelWorkflowTaskForApp(context.Context, string, string, string, string) error {
 	return nil
 }
-func (s *stubWorkflowService) MarkTaskStatus(context.Context, string, config.Status, config.Status) (bool, error) {
+func (s *stubWorkflowService) RollbackWorkflowTaskForApp(context.Context, string, string, string) (*apis.RollbackWorkflowResponse, error) {
+	return nil, nil
+}
+func (s *stubWorkflowService) RetryWorkflowTaskForApp(context.Context, string, string, string) (*apis.RetryWorkflowResponse, error) {
+	return nil, nil
+}
+func (s *stubWorkflowService) PauseWorkflowTaskForApp(context.Context, string, string, string, string) (*apis.PauseWorkflowResponse, error) {
+	return nil, nil
+}
+func (s *stubWorkflowService) ResumeWorkflowTaskForApp(context.Context, string, string, string) (*apis.PauseWorkflowResponse, error) {
+	return nil, nil
+}
+func (s *stubWorkflowService) DecideWorkflowApproval(context.Context, string, string, bool, apis.WorkflowApprovalRequest) (*apis.WorkflowApprovalResponse, error) {
+	return nil, nil
+}
+func (s *stubWorkflowService) MarkTaskStatus(_ context.Context, _ string, _ config.Status, to config.Status) (bool, error) {
+	s.marked = append(s.marked, to)
 	return true, nil
 }
 
@@ -111,6 +138,50 @@ func (s *stubWorkflowService) GetTaskStatus(context.Context, string) (*apis.Task
 	return nil, nil
 }
 
+func (s *stubWorkflowService) CreateWorkflowSchedule(context.Context, string, apis.CreateWorkflowScheduleRequest) (*model.WorkflowSchedule, error) {
+	return nil, nil
+}
+
+func (s *stubWorkflowService) ListWorkflowSchedules(context.Context, string) ([]*model.WorkflowSchedule, error) {
+	return nil, nil
+}
+
+func (s *stubWorkflowService) GetWorkflowSchedule(context.Context, string, string) (*model.WorkflowSchedule, error) {
+	return nil, nil
+}
+
+func (s *stubWorkflowService) UpdateWorkflowSchedule(context.Context, string, string, apis.UpdateWorkflowScheduleRequest) (*model.WorkflowSchedule, error) {
+	return nil, nil
+}
+
+func (s *stubWorkflowService) DeleteWorkflowSchedule(context.Context, string, string) error {
+	return nil
+}
+
+func (s *stubWorkflowService) FireDueSchedules(context.Context, time.Time) (int, error) {
+	return 0, nil
+}
+
+func (s *stubWorkflowService) DryRunWorkflowForApp(context.Context, string, apis.DryRunWorkflowRequest) (*apis.DryRunWorkflowResponse, error) {
+	return nil, nil
+}
+
+func (s *stubWorkflowService) PlanWorkflowForApp(context.Context, string, string) (*apis.WorkflowPlanResponse, error) {
+	return nil, nil
+}
+
+func (s *stubWorkflowService) WatchTask(context.Context, string) (<-chan apis.TaskProgressEvent, error) {
+	return nil, nil
+}
+
+func (s *stubWorkflowService) ListWorkflowTasks(context.Context, string, apis.ListWorkflowTasksQuery) (*apis.ListWorkflowTasksResponse, error) {
+	return nil, nil
+}
+
+func (s *stubWorkflowService) ListTaskJobs(context.Context, string, []string) (*apis.ListTaskJobsResponse, error) {
+	return nil, nil
+}
+
 func newWorkflowForAckTests(updateOK bool) *Workflow {
 	steps, _ := model.NewJSONStructByStruct(&model.WorkflowSteps{})
 	store := &workflowAckTestStore{
@@ -125,6 +196,7 @@ func newWorkflowForAckTests(updateOK bool) *Workflow {
 			AppID:        "app-1",
 			ProjectID:    "proj-1",
 			WorkflowName: "demo",
+			Status:       config.StatusQueued,
 		},
 	}
 
@@ -3,6 +3,7 @@ package workflow
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/semaphore"
@@ -14,7 +15,10 @@ import (
 	"kubemin-cli/pkg/apiserver/domain/model"
 	"kubemin-cli/pkg/apiserver/domain/service"
 	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+	"kubemin-cli/pkg/apiserver/infrastructure/locker"
 	msg "kubemin-cli/pkg/apiserver/infrastructure/messaging"
+	"kubemin-cli/pkg/apiserver/workflow/drain"
+	"kubemin-cli/pkg/apiserver/workflow/progress"
 )
 
 type Workflow struct {
@@ -23,11 +27,15 @@ type Workflow struct {
 	Store           datastore.DataStore     `inject:"datastore"`
 	WorkflowService service.WorkflowService `inject:""`
 	Queue           msg.Queue               `inject:"queue"`
+	Progress        *progress.Broker        `inject:""`
 	Cfg             *config.Config          `inject:""`
+	Drain           *drain.Controller       `inject:""`
 	taskGroup       *errgroup.Group
 	taskGroupCtx    context.Context
 	errChan         chan error
 	workflowLimiter *semaphore.Weighted
+	appLocker       locker.Locker
+	appLockerOnce   sync.Once
 }
 
 func (w *Workflow) Start(ctx context.Context, errChan chan error) {
@@ -54,6 +62,7 @@ func (w *Workflow) Start(ctx context.Context, errChan chan error) {
 		w.workerReadCount(),
 		w.workerReadBlock(),
 	)
+	go w.ScheduleTrigger(ctx)
 	// If queue is noop (local mode), fall back to direct DB scan executor for functionality.
 	if _, ok := w.Queue.(*msg.NoopQueue); ok {
 		go w.WorkflowTaskSender(ctx)
@@ -61,10 +70,12 @@ func (w *Workflow) Start(ctx context.Context, errChan chan error) {
 	}
 	// Redis Streams path: leader runs dispatcher; workers managed by server callbacks.
 	go w.Dispatcher(ctx)
+	go w.QueuedTaskReconciler(ctx)
 }
 
 // InitQueue 在服务启动时调用，将所有"运行中"的任务重新入队
 // 因为 Job 是通过 goroutine 执行的，进程重启后所有 goroutine 都会死亡
+// 重新运行时按任务保存的执行计划继续，已记录为完成的 Job 不会再次执行
 // 分布式场景下，Redis Streams 的 AutoClaim 机制会自动处理 pending 消息
 func (w *Workflow) InitQueue(ctx context.Context) {
 	if w.Store == nil {
@@ -105,9 +116,15 @@ func (w *Workflow) runWorkflowTask(ctx context.Context, task *model.WorkflowQueu
 	if w.taskGroupCtx != nil {
 		runnerCtx = w.taskGroupCtx
 	}
+	runnerCtx, done, tracked := w.trackTask(runnerCtx, task.TaskID)
+	if !tracked {
+		w.handBackTask(ctx, task.TaskID)
+		return
+	}
 	acquired := false
 	if w.workflowLimiter != nil {
 		if err := w.workflowLimiter.Acquire(runnerCtx, 1); err != nil {
+			done()
 			w.reportTaskError(fmt.Errorf("acquire workflow slot: %w", err))
 			return
 		}
@@ -116,7 +133,9 @@ func (w *Workflow) runWorkflowTask(ctx context.Context, task *model.WorkflowQueu
 	if w.taskGroup != nil {
 		taskCopy := task
 		w.taskGroup.Go(func() error {
+			defer done()
 			controller := NewWorkflowController(taskCopy, w.KubeClient, w.Store, w.Cfg)
+			controller.Progress = w.Progress
 			err := controller.Run(runnerCtx, concurrency)
 			if acquired {
 				w.workflowLimiter.Release(1)
@@ -129,7 +148,9 @@ func (w *Workflow) runWorkflowTask(ctx context.Context, task *model.WorkflowQueu
 		return
 	}
 	go func() {
+		defer done()
 		controller := NewWorkflowController(task, w.KubeClient, w.Store, w.Cfg)
+		controller.Progress = w.Progress
 		err := controller.Run(runnerCtx, concurrency)
 		if acquired {
 			w.workflowLimiter.Release(1)
@@ -140,6 +161,39 @@ func (w *Workflow) runWorkflowTask(ctx context.Context, task *model.WorkflowQueu
 	}()
 }
 
+// trackTask registers a task that starts running on this instance with the drain controller.
+// It returns false once the instance drains; the task must not start then.
+func (w *Workflow) trackTask(ctx context.Context, taskID string) (context.Context, func(), bool) {
+	if w.Drain == nil {
+		return ctx, func() {}, true
+	}
+	return w.Drain.Track(ctx, taskID)
+}
+
+// draining reports whether the instance stopped taking new tasks.
+func (w *Workflow) draining() bool {
+	return w.Drain != nil && w.Drain.Draining()
+}
+
+// drainStarted is closed when the instance starts draining; it never fires without a drain controller.
+func (w *Workflow) drainStarted() <-chan struct{} {
+	if w.Drain == nil {
+		return nil
+	}
+	return w.Drain.Started()
+}
+
+// handBackTask returns a task claimed while the instance started draining to waiting, so
+// the dispatcher hands it to another worker.
+func (w *Workflow) handBackTask(ctx context.Context, taskID string) {
+	ctx = context.WithoutCancel(ctx)
+	if _, err := w.markTaskStatus(ctx, taskID, config.StatusQueued, config.StatusWaiting); err != nil {
+		klog.Errorf("hand back task %s on drain failed: %v", taskID, err)
+		return
+	}
+	klog.Infof("worker draining, handed task %s back to the queue", taskID)
+}
+
 // reportTaskError logs workflow task errors.
 // Note: Workflow task failures are expected business errors (e.g., deployment failures,
 // validation errors) and should NOT cause the server to exit. Only infrastructure errors
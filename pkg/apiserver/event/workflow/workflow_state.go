@@ -14,6 +14,12 @@ func (w *Workflow) waitingTasks(ctx context.Context) ([]*model.WorkflowQueue, er
 	return w.WorkflowService.WaitingTasks(queryCtx)
 }
 
+func (w *Workflow) activeTasks(ctx context.Context) ([]*model.WorkflowQueue, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, config.WaitingTasksQueryTimeout)
+	defer cancel()
+	return w.WorkflowService.TaskRunning(queryCtx)
+}
+
 func (w *Workflow) markTaskStatus(ctx context.Context, taskID string, from, to config.Status) (bool, error) {
 	statusCtx, cancel := context.WithTimeout(ctx, config.TaskStateTransitionTimeout)
 	defer cancel()
@@ -108,3 +114,31 @@ func (w *Workflow) workerBackoffMax() time.Duration {
 	}
 	return config.DefaultWorkerBackoffMax
 }
+
+func (w *Workflow) schedulePollInterval() time.Duration {
+	if w.Cfg != nil && w.Cfg.Workflow.SchedulePollInterval > 0 {
+		return w.Cfg.Workflow.SchedulePollInterval
+	}
+	return config.DefaultSchedulePollInterval
+}
+
+func (w *Workflow) workerMaxDeliveries() int {
+	if w.Cfg != nil {
+		return w.Cfg.Workflow.WorkerMaxDeliveries
+	}
+	return config.DefaultWorkerMaxDeliveries
+}
+
+func (w *Workflow) queuedReconcileInterval() time.Duration {
+	if w.Cfg != nil && w.Cfg.Workflow.QueuedReconcileInterval > 0 {
+		return w.Cfg.Workflow.QueuedReconcileInterval
+	}
+	return config.DefaultQueuedReconcileInterval
+}
+
+func (w *Workflow) maxConcurrentPerProject() int {
+	if w.Cfg != nil {
+		return w.Cfg.Workflow.MaxConcurrentPerProject
+	}
+	return config.DefaultMaxConcurrentPerProject
+}
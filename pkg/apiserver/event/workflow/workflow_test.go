@@ -155,6 +155,74 @@ func TestGenerateJobTasksSequential(t *testing.T) {
 	require.Equal(t, cmJob.Name, cmInput.Name)
 }
 
+func TestGenerateJobTasksCarriesStepDependencies(t *testing.T) {
+	steps := &model.WorkflowSteps{
+		Steps: []*model.WorkflowStep{
+			{Name: "config"},
+			{Name: "server", DependsOn: []string{"config"}},
+		},
+	}
+	stepsJSON, err := model.NewJSONStructByStruct(steps)
+	require.NoError(t, err)
+
+	configProps, err := model.NewJSONStructByStruct(model.Properties{
+		Conf: map[string]string{"config": "value"},
+	})
+	require.NoError(t, err)
+	serverProps, err := model.NewJSONStructByStruct(model.Properties{
+		Image: "nginx:1.21",
+		Ports: []model.Ports{{Port: 80}},
+	})
+	require.NoError(t, err)
+
+	store := &fakeDataStore{
+		workflow: &model.Workflow{ID: "wf-1", Steps: stepsJSON},
+		components: []*model.ApplicationComponent{
+			{Name: "config", AppID: "app-1", Namespace: "default", ComponentType: config.ConfJob, Properties: configProps},
+			{Name: "server", AppID: "app-1", Namespace: "default", Image: "nginx:1.21", Replicas: 1, ComponentType: config.ServerJob, Properties: serverProps},
+		},
+	}
+	task := &model.WorkflowQueue{WorkflowID: "wf-1", AppID: "app-1", WorkflowName: "test-workflow"}
+
+	executions := GenerateJobTasks(context.Background(), task, store, int64(config.DefaultJobTaskTimeout))
+	require.Len(t, executions, 2)
+	require.Equal(t, "config", executions[0].Step)
+	require.Empty(t, executions[0].DependsOn)
+	require.Equal(t, "server", executions[1].Step)
+	require.Equal(t, []string{"config"}, executions[1].DependsOn)
+}
+
+func TestGenerateJobTasksEmitsApprovalStep(t *testing.T) {
+	steps := &model.WorkflowSteps{
+		Steps: []*model.WorkflowStep{
+			{Name: "config"},
+			{Name: "gate", WorkflowType: config.JobApproval, DependsOn: []string{"config"}},
+		},
+	}
+	stepsJSON, err := model.NewJSONStructByStruct(steps)
+	require.NoError(t, err)
+	configProps, err := model.NewJSONStructByStruct(model.Properties{
+		Conf: map[string]string{"config": "value"},
+	})
+	require.NoError(t, err)
+
+	store := &fakeDataStore{
+		workflow: &model.Workflow{ID: "wf-1", Steps: stepsJSON},
+		components: []*model.ApplicationComponent{
+			{Name: "config", AppID: "app-1", Namespace: "default", ComponentType: config.ConfJob, Properties: configProps},
+		},
+	}
+	task := &model.WorkflowQueue{WorkflowID: "wf-1", AppID: "app-1", WorkflowName: "test-workflow"}
+
+	executions := GenerateJobTasks(context.Background(), task, store, int64(config.DefaultJobTaskTimeout))
+	require.Len(t, executions, 2)
+	require.False(t, executions[0].Approval)
+	require.True(t, executions[1].Approval)
+	require.Equal(t, "gate", executions[1].Step)
+	require.Nil(t, executions[1].Jobs)
+	require.Equal(t, []string{"config"}, executions[1].DependsOn)
+}
+
 func TestGenerateJobTasksParallel(t *testing.T) {
 	frontendProps, err := model.NewJSONStructByStruct(model.Properties{
 		Image: "nginx:1.21",
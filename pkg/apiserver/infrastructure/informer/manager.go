@@ -7,6 +7,7 @@ import (
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
@@ -152,6 +153,66 @@ func (m *Manager) Start(ctx context.Context) error {
 	}
 	klog.V(2).Info("StatefulSet informer event handler registered")
 
+	// 设置 DaemonSet Informer
+	dsInformer := m.factory.Apps().V1().DaemonSets().Informer()
+	_, err = dsInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if ds, ok := obj.(*appsv1.DaemonSet); ok {
+				m.waiter.OnDaemonSetAdd(ds)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldDs, ok1 := oldObj.(*appsv1.DaemonSet)
+			newDs, ok2 := newObj.(*appsv1.DaemonSet)
+			if ok1 && ok2 {
+				m.waiter.OnDaemonSetUpdate(oldDs, newDs)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if ds, ok := obj.(*appsv1.DaemonSet); ok {
+				m.waiter.OnDaemonSetDelete(ds)
+			} else if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				if ds, ok := tombstone.Obj.(*appsv1.DaemonSet); ok {
+					m.waiter.OnDaemonSetDelete(ds)
+				}
+			}
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add daemonset event handler: %w", err)
+	}
+	klog.V(2).Info("DaemonSet informer event handler registered")
+
+	// 设置批处理 Job Informer
+	jobInformer := m.factory.Batch().V1().Jobs().Informer()
+	_, err = jobInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if job, ok := obj.(*batchv1.Job); ok {
+				m.waiter.OnJobAdd(job)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldJob, ok1 := oldObj.(*batchv1.Job)
+			newJob, ok2 := newObj.(*batchv1.Job)
+			if ok1 && ok2 {
+				m.waiter.OnJobUpdate(oldJob, newJob)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if job, ok := obj.(*batchv1.Job); ok {
+				m.waiter.OnJobDelete(job)
+			} else if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				if job, ok := tombstone.Obj.(*batchv1.Job); ok {
+					m.waiter.OnJobDelete(job)
+				}
+			}
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add job event handler: %w", err)
+	}
+	klog.V(2).Info("Job informer event handler registered")
+
 	// 启动所有 Informer
 	m.factory.Start(m.stopCh)
 
@@ -1,10 +1,14 @@
 package informer
 
 import (
+	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 
 	"kubemin-cli/pkg/apiserver/config"
 )
@@ -16,6 +20,8 @@ const (
 	ResourceTypeDeployment  ResourceType = "Deployment"
 	ResourceTypeStatefulSet ResourceType = "StatefulSet"
 	ResourceTypePod         ResourceType = "Pod"
+	ResourceTypeJob         ResourceType = "Job"
+	ResourceTypeDaemonSet   ResourceType = "DaemonSet"
 )
 
 // WaitEntry 等待条目
@@ -85,6 +91,31 @@ type StatefulSetStatus struct {
 	Ready         bool
 }
 
+// DaemonSetStatus 从 DaemonSet 提取的状态，副本数即应调度的节点数
+type DaemonSetStatus struct {
+	Name          string
+	Namespace     string
+	Labels        map[string]string // 资源标签，用于提取 appID/componentID
+	Desired       int32
+	ReadyReplicas int32
+	Updated       int32
+	Ready         bool
+}
+
+// JobStatus 从批处理 Job 提取的状态
+type JobStatus struct {
+	Name        string
+	Namespace   string
+	Labels      map[string]string // 资源标签，用于提取 appID/componentID
+	Completions int32
+	Active      int32
+	Succeeded   int32
+	Failed      int32
+	Complete    bool   // 已成功完成
+	FailedDone  bool   // 已失败（超过 backoffLimit 或 activeDeadlineSeconds）
+	Message     string // 失败原因
+}
+
 // ComponentStatusUpdate 组件状态更新信息（传递给数据库同步）
 type ComponentStatusUpdate struct {
 	AppID         string                 // 应用 ID
@@ -137,6 +168,64 @@ func ExtractStatefulSetStatus(sts *appsv1.StatefulSet) *StatefulSetStatus {
 	}
 }
 
+// ExtractDaemonSetStatus 从 DaemonSet 提取状态；控制器观察到最新版本且所有节点上的 Pod 均已更新并就绪时视为就绪
+func ExtractDaemonSetStatus(ds *appsv1.DaemonSet) *DaemonSetStatus {
+	if ds == nil {
+		return nil
+	}
+	desired := ds.Status.DesiredNumberScheduled
+	observed := ds.Status.ObservedGeneration >= ds.Generation
+	return &DaemonSetStatus{
+		Name:          ds.Name,
+		Namespace:     ds.Namespace,
+		Labels:        ds.Labels,
+		Desired:       desired,
+		ReadyReplicas: ds.Status.NumberReady,
+		Updated:       ds.Status.UpdatedNumberScheduled,
+		Ready:         observed && ds.Status.UpdatedNumberScheduled == desired && ds.Status.NumberReady == desired,
+	}
+}
+
+// ExtractJobStatus 从 Job 提取状态；失败以 Failed 条件为准，条件尚未写入时按 backoffLimit 判断
+func ExtractJobStatus(job *batchv1.Job) *JobStatus {
+	if job == nil {
+		return nil
+	}
+	completions := int32(1)
+	if job.Spec.Completions != nil {
+		completions = *job.Spec.Completions
+	}
+	status := &JobStatus{
+		Name:        job.Name,
+		Namespace:   job.Namespace,
+		Labels:      job.Labels,
+		Completions: completions,
+		Active:      job.Status.Active,
+		Succeeded:   job.Status.Succeeded,
+		Failed:      job.Status.Failed,
+	}
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			status.Complete = true
+		case batchv1.JobFailed:
+			status.FailedDone = true
+			status.Message = cond.Message
+			if cond.Reason != "" {
+				status.Message = strings.TrimSpace(cond.Reason + ": " + cond.Message)
+			}
+		}
+	}
+	if !status.Complete && !status.FailedDone && job.Spec.BackoffLimit != nil && job.Status.Failed > *job.Spec.BackoffLimit {
+		status.FailedDone = true
+		status.Message = fmt.Sprintf("BackoffLimitExceeded: %d pods failed, backoffLimit is %d", job.Status.Failed, *job.Spec.BackoffLimit)
+	}
+	return status
+}
+
 // WaitError 等待错误（携带状态）
 type WaitError struct {
 	Status config.Status
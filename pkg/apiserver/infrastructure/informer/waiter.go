@@ -8,6 +8,7 @@ import (
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	"k8s.io/klog/v2"
 
 	"kubemin-cli/pkg/apiserver/config"
@@ -47,6 +48,16 @@ func (w *ResourceReadyWaiter) WaitForStatefulSetReady(ctx context.Context, names
 	return w.waitForResource(ctx, ResourceTypeStatefulSet, namespace, name, timeout)
 }
 
+// WaitForDaemonSetReady 等待 DaemonSet 在所有目标节点上完成滚动
+func (w *ResourceReadyWaiter) WaitForDaemonSetReady(ctx context.Context, namespace, name string, timeout time.Duration) error {
+	return w.waitForResource(ctx, ResourceTypeDaemonSet, namespace, name, timeout)
+}
+
+// WaitForJobComplete 等待批处理 Job 执行成功；Job 失败时返回 StatusFailed
+func (w *ResourceReadyWaiter) WaitForJobComplete(ctx context.Context, namespace, name string, timeout time.Duration) error {
+	return w.waitForResource(ctx, ResourceTypeJob, namespace, name, timeout)
+}
+
 // waitForResource 通用等待逻辑
 func (w *ResourceReadyWaiter) waitForResource(ctx context.Context, resourceType ResourceType, namespace, name string, timeout time.Duration) error {
 	key := buildKey(resourceType, namespace, name)
@@ -195,6 +206,134 @@ func (w *ResourceReadyWaiter) OnStatefulSetDelete(sts *appsv1.StatefulSet) {
 	entry.SendError(NewWaitError(config.StatusFailed, fmt.Errorf("statefulset %s/%s was deleted", sts.Namespace, sts.Name)))
 }
 
+// OnDaemonSetAdd 处理 DaemonSet 创建事件 - 由 Informer 调用
+func (w *ResourceReadyWaiter) OnDaemonSetAdd(ds *appsv1.DaemonSet) {
+	w.OnDaemonSetUpdate(nil, ds)
+}
+
+// OnDaemonSetUpdate 处理 DaemonSet 更新事件 - 由 Informer 调用
+func (w *ResourceReadyWaiter) OnDaemonSetUpdate(oldDs, newDs *appsv1.DaemonSet) {
+	if newDs == nil {
+		return
+	}
+
+	status := ExtractDaemonSetStatus(newDs)
+
+	klog.V(4).Infof("DaemonSet %s/%s update: desired=%d, updated=%d, ready=%d, isReady=%v",
+		newDs.Namespace, newDs.Name, status.Desired, status.Updated, status.ReadyReplicas, status.Ready)
+
+	// 1. 同步状态到数据库；没有匹配节点的 DaemonSet 同样视为运行中
+	if status.Ready {
+		w.emitComponentStatus(status.Labels, config.ComponentStatusRunning, status.Desired, status.ReadyReplicas)
+	} else {
+		w.emitComponentStatus(status.Labels, config.ComponentStatusPending, status.Desired, status.ReadyReplicas)
+	}
+
+	// 2. 通知等待者
+	key := buildKey(ResourceTypeDaemonSet, newDs.Namespace, newDs.Name)
+	entryVal, ok := w.waiters.Load(key)
+	if !ok {
+		return
+	}
+
+	entry := entryVal.(*WaitEntry)
+	if entry.IsClosed() {
+		return
+	}
+
+	if status.Ready {
+		entry.Close()
+	}
+}
+
+// OnDaemonSetDelete 处理 DaemonSet 删除事件
+func (w *ResourceReadyWaiter) OnDaemonSetDelete(ds *appsv1.DaemonSet) {
+	if ds == nil {
+		return
+	}
+
+	klog.V(4).Infof("DaemonSet %s/%s deleted", ds.Namespace, ds.Name)
+
+	// 1. 同步删除状态到数据库
+	w.syncStatusToDB(ds.Labels, 0, 0, false)
+
+	// 2. 通知等待者（如果有）
+	key := buildKey(ResourceTypeDaemonSet, ds.Namespace, ds.Name)
+	entryVal, ok := w.waiters.Load(key)
+	if !ok {
+		return
+	}
+
+	entry := entryVal.(*WaitEntry)
+	entry.SendError(NewWaitError(config.StatusFailed, fmt.Errorf("daemonset %s/%s was deleted", ds.Namespace, ds.Name)))
+}
+
+// OnJobAdd 处理 Job 创建事件 - 由 Informer 调用
+func (w *ResourceReadyWaiter) OnJobAdd(job *batchv1.Job) {
+	w.OnJobUpdate(nil, job)
+}
+
+// OnJobUpdate 处理 Job 更新事件 - 由 Informer 调用
+func (w *ResourceReadyWaiter) OnJobUpdate(oldJob, newJob *batchv1.Job) {
+	if newJob == nil {
+		return
+	}
+
+	status := ExtractJobStatus(newJob)
+
+	klog.V(4).Infof("Job %s/%s update: active=%d, succeeded=%d, failed=%d, complete=%v",
+		newJob.Namespace, newJob.Name, status.Active, status.Succeeded, status.Failed, status.Complete)
+
+	// 1. 同步状态到数据库
+	componentStatus := config.ComponentStatusPending
+	switch {
+	case status.Complete:
+		componentStatus = config.ComponentStatusSucceeded
+	case status.FailedDone:
+		componentStatus = config.ComponentStatusFailed
+	case status.Active > 0:
+		componentStatus = config.ComponentStatusRunning
+	}
+	w.emitComponentStatus(status.Labels, componentStatus, status.Completions, status.Succeeded)
+
+	// 2. 通知等待者
+	key := buildKey(ResourceTypeJob, newJob.Namespace, newJob.Name)
+	entryVal, ok := w.waiters.Load(key)
+	if !ok {
+		return
+	}
+
+	entry := entryVal.(*WaitEntry)
+	if entry.IsClosed() {
+		return
+	}
+
+	switch {
+	case status.Complete:
+		entry.Close()
+	case status.FailedDone:
+		entry.SendError(NewWaitError(config.StatusFailed, fmt.Errorf("job %s/%s failed: %s", newJob.Namespace, newJob.Name, status.Message)))
+	}
+}
+
+// OnJobDelete 处理 Job 删除事件；执行完的 Job 会被 TTL 回收，因此不改写组件状态
+func (w *ResourceReadyWaiter) OnJobDelete(job *batchv1.Job) {
+	if job == nil {
+		return
+	}
+
+	klog.V(4).Infof("Job %s/%s deleted", job.Namespace, job.Name)
+
+	key := buildKey(ResourceTypeJob, job.Namespace, job.Name)
+	entryVal, ok := w.waiters.Load(key)
+	if !ok {
+		return
+	}
+
+	entry := entryVal.(*WaitEntry)
+	entry.SendError(NewWaitError(config.StatusFailed, fmt.Errorf("job %s/%s was deleted", job.Namespace, job.Name)))
+}
+
 // GetPendingCount 获取等待中的资源数量（用于监控）
 func (w *ResourceReadyWaiter) GetPendingCount() int {
 	count := 0
@@ -217,6 +356,24 @@ func (w *ResourceReadyWaiter) GetPendingKeys() []string {
 
 // syncStatusToDB 同步组件状态到数据库
 func (w *ResourceReadyWaiter) syncStatusToDB(labels map[string]string, replicas, readyReplicas int32, ready bool) {
+	// 计算状态
+	var status config.ComponentStatus
+
+	if ready {
+		status = config.ComponentStatusRunning
+	} else if readyReplicas > 0 {
+		status = config.ComponentStatusPending
+	} else if replicas > 0 {
+		status = config.ComponentStatusPending
+	} else {
+		// replicas=0 表示资源被删除或缩容为0
+		status = config.ComponentStatusFailed
+	}
+	w.emitComponentStatus(labels, status, replicas, readyReplicas)
+}
+
+// emitComponentStatus 将已计算好的组件状态交给同步回调
+func (w *ResourceReadyWaiter) emitComponentStatus(labels map[string]string, status config.ComponentStatus, replicas, readyReplicas int32) {
 	if w.statusSyncFunc == nil {
 		return
 	}
@@ -232,20 +389,6 @@ func (w *ResourceReadyWaiter) syncStatusToDB(labels map[string]string, replicas,
 
 	componentID, _ := strconv.Atoi(componentIDStr)
 
-	// 计算状态
-	var status config.ComponentStatus
-
-	if ready {
-		status = config.ComponentStatusRunning
-	} else if readyReplicas > 0 {
-		status = config.ComponentStatusPending
-	} else if replicas > 0 {
-		status = config.ComponentStatusPending
-	} else {
-		// replicas=0 表示资源被删除或缩容为0
-		status = config.ComponentStatusFailed
-	}
-
 	update := &ComponentStatusUpdate{
 		AppID:         appID,
 		ComponentID:   componentID,
@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -19,6 +22,10 @@ type KafkaConfig struct {
 	AutoOffsetReset string // "earliest" or "latest"
 }
 
+// DeliveriesSuffix is appended to the topic to name the compacted topic that keeps the
+// delivery count of every unacknowledged message.
+const DeliveriesSuffix = ".deliveries"
+
 // KafkaQueue implements Queue using Kafka Consumer Groups.
 // It uses kafka-go library for both producing and consuming messages.
 type KafkaQueue struct {
@@ -28,11 +35,24 @@ type KafkaQueue struct {
 	// reader is lazily initialized when EnsureGroup is called
 	mu     sync.RWMutex
 	reader *kafka.Reader
+	// group is the consumer group passed to EnsureGroup; Kafka consumes it as cfg.GroupID.
+	group string
 
 	// pendingMessages tracks messages that have been read but not yet acknowledged.
 	// Key is the message ID (partition:offset), value is the kafka message for commit.
 	pendingMu       sync.Mutex
 	pendingMessages map[string]kafka.Message
+	// deliveries counts the fetches of unacknowledged messages. Every fetch is also written to
+	// the deliveries topic, so the count survives restarts and rebalances.
+	deliveries map[string]int64
+	// nextOffsets is the offset expected next per partition. Any other offset means the
+	// partition was rewound or reassigned, and its counts are reloaded from the deliveries topic.
+	nextOffsets    map[int]int64
+	deliveryWriter *kafka.Writer
+
+	// deadWriter writes dead letters to the dead-letter topic; purged dead letters are
+	// written as tombstones with the same key.
+	deadWriter *kafka.Writer
 }
 
 // NewKafkaQueue creates a new KafkaQueue with the given configuration.
@@ -61,24 +81,51 @@ func NewKafkaQueue(cfg KafkaConfig) (*KafkaQueue, error) {
 		RequiredAcks: kafka.RequireOne,
 	}
 
+	deadWriter := &kafka.Writer{
+		Addr:         kafka.TCP(cfg.Brokers...),
+		Topic:        cfg.Topic + DeadLetterSuffix,
+		Balancer:     &kafka.Hash{},
+		BatchSize:    1,
+		BatchTimeout: 10 * time.Millisecond,
+		RequiredAcks: kafka.RequireAll,
+	}
+
+	deliveryWriter := &kafka.Writer{
+		Addr:         kafka.TCP(cfg.Brokers...),
+		Topic:        cfg.Topic + DeliveriesSuffix,
+		Balancer:     &kafka.Hash{},
+		BatchSize:    1,
+		BatchTimeout: 10 * time.Millisecond,
+		RequiredAcks: kafka.RequireAll,
+	}
+
 	return &KafkaQueue{
 		cfg:             cfg,
 		writer:          writer,
 		pendingMessages: make(map[string]kafka.Message),
+		deliveries:      make(map[string]int64),
+		nextOffsets:     make(map[int]int64),
+		deliveryWriter:  deliveryWriter,
+		deadWriter:      deadWriter,
 	}, nil
 }
 
 // EnsureGroup ensures the consumer group exists and initializes the reader.
 // In Kafka, consumer groups are created automatically when a consumer joins,
 // so this method primarily initializes the reader with the specified group.
+// The reader joins the Kafka consumer group cfg.GroupID; a queue serves a single group.
 func (k *KafkaQueue) EnsureGroup(ctx context.Context, group string) error {
 	k.mu.Lock()
 	defer k.mu.Unlock()
 
 	// If reader already exists, nothing to do
 	if k.reader != nil {
+		if group != k.group {
+			return fmt.Errorf("kafka reader already serves group %s, cannot serve %s", k.group, group)
+		}
 		return nil
 	}
+	k.group = group
 
 	// Determine start offset based on configuration
 	startOffset := kafka.FirstOffset
@@ -151,6 +198,7 @@ func (k *KafkaQueue) ReadGroup(ctx context.Context, group, consumer string, coun
 
 		// Generate a unique ID from partition and offset
 		msgID := k.messageID(msg)
+		deliveries := k.recordDelivery(ctx, msg)
 
 		// Store message for later acknowledgment
 		k.pendingMu.Lock()
@@ -158,8 +206,9 @@ func (k *KafkaQueue) ReadGroup(ctx context.Context, group, consumer string, coun
 		k.pendingMu.Unlock()
 
 		messages = append(messages, Message{
-			ID:      msgID,
-			Payload: msg.Value,
+			ID:         msgID,
+			Payload:    msg.Value,
+			Deliveries: deliveries,
 		})
 	}
 
@@ -180,9 +229,8 @@ func (k *KafkaQueue) Ack(ctx context.Context, group string, ids ...string) error
 		return errors.New("kafka reader not initialized")
 	}
 
+	var tombstones []kafka.Message
 	k.pendingMu.Lock()
-	defer k.pendingMu.Unlock()
-
 	for _, id := range ids {
 		msg, ok := k.pendingMessages[id]
 		if !ok {
@@ -191,12 +239,22 @@ func (k *KafkaQueue) Ack(ctx context.Context, group string, ids ...string) error
 		}
 
 		if err := reader.CommitMessages(ctx, msg); err != nil {
+			k.pendingMu.Unlock()
 			return err
 		}
 
 		delete(k.pendingMessages, id)
+		delete(k.deliveries, id)
+		tombstones = append(tombstones, kafka.Message{Key: []byte(id)})
 	}
+	k.pendingMu.Unlock()
 
+	// The offset is committed, so a stale count is harmless; it is only kept until compaction.
+	if len(tombstones) > 0 {
+		if err := k.deliveryWriter.WriteMessages(ctx, tombstones...); err != nil {
+			klog.Warningf("kafka ack: clear delivery counts failed: %v", err)
+		}
+	}
 	return nil
 }
 
@@ -219,6 +277,16 @@ func (k *KafkaQueue) Close(ctx context.Context) error {
 			errs = append(errs, err)
 		}
 	}
+	if k.deadWriter != nil {
+		if err := k.deadWriter.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if k.deliveryWriter != nil {
+		if err := k.deliveryWriter.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
 
 	k.mu.Lock()
 	if k.reader != nil {
@@ -263,3 +331,300 @@ func (k *KafkaQueue) Stats(ctx context.Context, group string) (backlog int64, pe
 func (k *KafkaQueue) messageID(msg kafka.Message) string {
 	return fmt.Sprintf("%d:%d", msg.Partition, msg.Offset)
 }
+
+// messagePartition returns the partition of a message ID generated by messageID.
+func messagePartition(id string) (int, bool) {
+	p, _, ok := strings.Cut(id, ":")
+	if !ok {
+		return 0, false
+	}
+	partition, err := strconv.Atoi(p)
+	return partition, err == nil
+}
+
+// recordDelivery counts a fetch of msg and persists the count to the deliveries topic.
+// A fetch that does not follow the previous one of the partition reloads the counts of the
+// partition first, as other consumers may have fetched its messages in the meantime.
+func (k *KafkaQueue) recordDelivery(ctx context.Context, msg kafka.Message) int64 {
+	k.pendingMu.Lock()
+	next, seen := k.nextOffsets[msg.Partition]
+	k.nextOffsets[msg.Partition] = msg.Offset + 1
+	k.pendingMu.Unlock()
+	if !seen || msg.Offset != next {
+		if err := k.loadDeliveries(ctx, msg.Partition); err != nil {
+			klog.Warningf("kafka: load delivery counts of partition %d failed: %v", msg.Partition, err)
+		}
+	}
+
+	id := k.messageID(msg)
+	k.pendingMu.Lock()
+	k.deliveries[id]++
+	deliveries := k.deliveries[id]
+	k.pendingMu.Unlock()
+
+	if err := k.deliveryWriter.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(id),
+		Value: []byte(strconv.FormatInt(deliveries, 10)),
+	}); err != nil {
+		klog.Warningf("kafka: persist delivery count of message %s failed: %v", id, err)
+	}
+	return deliveries
+}
+
+// loadDeliveries reads the deliveries topic and merges the counts of a partition.
+func (k *KafkaQueue) loadDeliveries(ctx context.Context, partition int) error {
+	topic := k.cfg.Topic + DeliveriesSuffix
+	partitions, err := k.partitions(ctx, topic)
+	if err != nil {
+		return err
+	}
+	var records []kafka.Message
+	for _, p := range partitions {
+		read, err := k.readPartition(ctx, topic, p, kafka.FirstOffset)
+		if err != nil {
+			return err
+		}
+		records = append(records, read...)
+	}
+	k.mergeDeliveries(partition, records)
+	return nil
+}
+
+// mergeDeliveries folds the deliveries topic records of a partition into the local counts.
+// The latest record of a message wins and a tombstone drops it; the local count is kept when
+// it is higher, e.g. because persisting it failed.
+func (k *KafkaQueue) mergeDeliveries(partition int, records []kafka.Message) {
+	stored := make(map[string]int64)
+	for _, m := range records {
+		id := string(m.Key)
+		if p, ok := messagePartition(id); !ok || p != partition {
+			continue
+		}
+		if m.Value == nil {
+			delete(stored, id)
+			continue
+		}
+		n, err := strconv.ParseInt(string(m.Value), 10, 64)
+		if err != nil {
+			continue
+		}
+		stored[id] = n
+	}
+	k.pendingMu.Lock()
+	defer k.pendingMu.Unlock()
+	for id, n := range stored {
+		if n > k.deliveries[id] {
+			k.deliveries[id] = n
+		}
+	}
+}
+
+// Outstanding reads the messages between the committed offset of the group and the end
+// of every partition. Messages read but not acknowledged yet are included as their offsets
+// are not committed. Only the group served by EnsureGroup is known to the queue.
+func (k *KafkaQueue) Outstanding(ctx context.Context, group string) ([]Message, error) {
+	k.mu.RLock()
+	served := k.group
+	k.mu.RUnlock()
+	if served == "" || group != served {
+		return nil, fmt.Errorf("kafka topic %s has no consumer group %s", k.cfg.Topic, group)
+	}
+	partitions, err := k.partitions(ctx, k.cfg.Topic)
+	if err != nil || len(partitions) == 0 {
+		return nil, err
+	}
+	client := &kafka.Client{Addr: kafka.TCP(k.cfg.Brokers...)}
+	resp, err := client.OffsetFetch(ctx, &kafka.OffsetFetchRequest{
+		GroupID: k.cfg.GroupID,
+		Topics:  map[string][]int{k.cfg.Topic: partitions},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	committed := make(map[int]int64, len(partitions))
+	for _, p := range resp.Topics[k.cfg.Topic] {
+		if p.Error != nil {
+			return nil, p.Error
+		}
+		committed[p.Partition] = p.CommittedOffset
+	}
+	var msgs []Message
+	for _, partition := range partitions {
+		// A negative offset means the group never committed; it starts at the first offset.
+		read, err := k.readPartition(ctx, k.cfg.Topic, partition, committed[partition])
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range read {
+			msgs = append(msgs, Message{ID: k.messageID(m), Payload: m.Value})
+		}
+	}
+	return msgs, nil
+}
+
+func (k *KafkaQueue) DeadLetter(ctx context.Context, m Message, reason string) error {
+	return k.deadWriter.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(m.ID),
+		Value: m.Payload,
+		Headers: []kafka.Header{
+			{Key: "error", Value: []byte(reason)},
+			{Key: "deliveries", Value: []byte(strconv.FormatInt(m.Deliveries, 10))},
+		},
+	})
+}
+
+func (k *KafkaQueue) DeadLetters(ctx context.Context, count int) ([]DeadLetter, error) {
+	letters, err := k.readDeadLetters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 && len(letters) > count {
+		letters = letters[:count]
+	}
+	return letters, nil
+}
+
+func (k *KafkaQueue) GetDeadLetter(ctx context.Context, id string) (*DeadLetter, error) {
+	letters, err := k.readDeadLetters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range letters {
+		if letters[i].ID == id {
+			return &letters[i], nil
+		}
+	}
+	return nil, ErrDeadLetterNotFound
+}
+
+// PurgeDeadLetters writes tombstones for the dead letters; the topic should be compacted so
+// that the broker eventually drops them.
+func (k *KafkaQueue) PurgeDeadLetters(ctx context.Context, ids ...string) (int64, error) {
+	letters, err := k.readDeadLetters(ctx)
+	if err != nil {
+		return 0, err
+	}
+	wanted := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		wanted[id] = struct{}{}
+	}
+	var tombstones []kafka.Message
+	for _, letter := range letters {
+		if _, ok := wanted[letter.ID]; ok || len(ids) == 0 {
+			tombstones = append(tombstones, kafka.Message{Key: []byte(letter.ID)})
+		}
+	}
+	if len(tombstones) == 0 {
+		return 0, nil
+	}
+	if err := k.deadWriter.WriteMessages(ctx, tombstones...); err != nil {
+		return 0, err
+	}
+	return int64(len(tombstones)), nil
+}
+
+// readDeadLetters reads the dead-letter topic and returns the dead letters that have no
+// tombstone, oldest first. A dead letter is keyed by the ID of the original message.
+func (k *KafkaQueue) readDeadLetters(ctx context.Context) ([]DeadLetter, error) {
+	topic := k.cfg.Topic + DeadLetterSuffix
+	partitions, err := k.partitions(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+	live := make(map[string]DeadLetter)
+	for _, partition := range partitions {
+		read, err := k.readPartition(ctx, topic, partition, kafka.FirstOffset)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range read {
+			id := string(m.Key)
+			if m.Value == nil {
+				delete(live, id)
+				continue
+			}
+			letter := DeadLetter{ID: id, MessageID: id, Payload: m.Value, DeadAt: m.Time}
+			for _, h := range m.Headers {
+				switch h.Key {
+				case "error":
+					letter.Error = string(h.Value)
+				case "deliveries":
+					letter.Deliveries, _ = strconv.ParseInt(string(h.Value), 10, 64)
+				}
+			}
+			live[id] = letter
+		}
+	}
+	letters := make([]DeadLetter, 0, len(live))
+	for _, letter := range live {
+		letters = append(letters, letter)
+	}
+	sort.Slice(letters, func(i, j int) bool { return letters[i].DeadAt.Before(letters[j].DeadAt) })
+	return letters, nil
+}
+
+// partitions returns the partition IDs of a topic; a topic that does not exist has none.
+func (k *KafkaQueue) partitions(ctx context.Context, topic string) ([]int, error) {
+	partitions, err := kafka.LookupPartitions(ctx, "tcp", k.cfg.Brokers[0], topic)
+	if err != nil {
+		if errors.Is(err, kafka.UnknownTopicOrPartition) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	ids := make([]int, 0, len(partitions))
+	for _, p := range partitions {
+		ids = append(ids, p.ID)
+	}
+	return ids, nil
+}
+
+// readPartition reads a partition from offset up to its current end. Offsets before the
+// first retained one start at the first offset.
+func (k *KafkaQueue) readPartition(ctx context.Context, topic string, partition int, offset int64) ([]kafka.Message, error) {
+	conn, err := kafka.DialLeader(ctx, "tcp", k.cfg.Brokers[0], topic, partition)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetReadDeadline(deadline)
+	}
+	first, last, err := conn.ReadOffsets()
+	if err != nil {
+		return nil, err
+	}
+	if offset < first {
+		offset = first
+	}
+	if offset >= last {
+		return nil, nil
+	}
+	if _, err := conn.Seek(offset, kafka.SeekAbsolute); err != nil {
+		return nil, err
+	}
+	var msgs []kafka.Message
+	for offset < last {
+		start := offset
+		batch := conn.ReadBatch(1, 10e6)
+		for offset < last {
+			m, err := batch.ReadMessage()
+			if err != nil {
+				break
+			}
+			msgs = append(msgs, m)
+			offset = m.Offset + 1
+		}
+		if err := batch.Close(); err != nil {
+			return nil, err
+		}
+		if offset == start {
+			// Only control records are left before the end.
+			break
+		}
+	}
+	return msgs, nil
+}
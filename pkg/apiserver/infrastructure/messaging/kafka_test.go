@@ -4,6 +4,8 @@ import (
 	"context"
 	"testing"
 	"time"
+
+	"github.com/segmentio/kafka-go"
 )
 
 func TestNewKafkaQueue_Validation(t *testing.T) {
@@ -307,3 +309,90 @@ func TestKafkaConfig_AutoOffsetResetValues(t *testing.T) {
 	}
 }
 
+
+func TestKafkaQueue_DeadLetterTopic(t *testing.T) {
+	kq, err := NewKafkaQueue(KafkaConfig{
+		Brokers: []string{"localhost:9092"},
+		Topic:   "test-topic",
+	})
+	if err != nil {
+		t.Fatalf("NewKafkaQueue() error: %v", err)
+	}
+	if kq.deadWriter.Topic != "test-topic.dead" {
+		t.Errorf("expected dead-letter topic 'test-topic.dead', got %q", kq.deadWriter.Topic)
+	}
+	if err := kq.Close(context.Background()); err != nil {
+		t.Errorf("Close() error: %v", err)
+	}
+}
+
+func TestKafkaQueue_DeliveriesTopic(t *testing.T) {
+	kq, err := NewKafkaQueue(KafkaConfig{
+		Brokers: []string{"localhost:9092"},
+		Topic:   "test-topic",
+	})
+	if err != nil {
+		t.Fatalf("NewKafkaQueue() error: %v", err)
+	}
+	if kq.deliveryWriter.Topic != "test-topic.deliveries" {
+		t.Errorf("expected deliveries topic 'test-topic.deliveries', got %q", kq.deliveryWriter.Topic)
+	}
+	if err := kq.Close(context.Background()); err != nil {
+		t.Errorf("Close() error: %v", err)
+	}
+}
+
+func TestKafkaQueue_MergeDeliveries(t *testing.T) {
+	kq, err := NewKafkaQueue(KafkaConfig{
+		Brokers: []string{"localhost:9092"},
+		Topic:   "test-topic",
+	})
+	if err != nil {
+		t.Fatalf("NewKafkaQueue() error: %v", err)
+	}
+	kq.deliveries["0:7"] = 4
+
+	// Counts written by an earlier process survive; the latest record wins and tombstones drop it.
+	kq.mergeDeliveries(0, []kafka.Message{
+		{Key: []byte("0:5"), Value: []byte("1")},
+		{Key: []byte("0:5"), Value: []byte("2")},
+		{Key: []byte("0:6"), Value: []byte("3")},
+		{Key: []byte("0:6")},
+		{Key: []byte("0:7"), Value: []byte("2")},
+		{Key: []byte("1:5"), Value: []byte("9")},
+	})
+
+	want := map[string]int64{"0:5": 2, "0:7": 4}
+	if len(kq.deliveries) != len(want) {
+		t.Fatalf("expected deliveries %v, got %v", want, kq.deliveries)
+	}
+	for id, n := range want {
+		if kq.deliveries[id] != n {
+			t.Errorf("expected %d deliveries of %s, got %d", n, id, kq.deliveries[id])
+		}
+	}
+}
+
+func TestKafkaQueue_OutstandingUnknownGroup(t *testing.T) {
+	kq, err := NewKafkaQueue(KafkaConfig{
+		Brokers: []string{"localhost:9092"},
+		Topic:   "test-topic",
+	})
+	if err != nil {
+		t.Fatalf("NewKafkaQueue() error: %v", err)
+	}
+	defer kq.Close(context.Background())
+
+	if _, err := kq.Outstanding(context.Background(), "workers"); err == nil {
+		t.Errorf("expected error before the group is ensured")
+	}
+	if err := kq.EnsureGroup(context.Background(), "workers"); err != nil {
+		t.Fatalf("EnsureGroup() error: %v", err)
+	}
+	if err := kq.EnsureGroup(context.Background(), "other"); err == nil {
+		t.Errorf("expected error when ensuring a second group")
+	}
+	if _, err := kq.Outstanding(context.Background(), "other"); err == nil {
+		t.Errorf("expected error for a group the queue does not serve")
+	}
+}
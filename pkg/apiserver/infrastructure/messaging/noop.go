@@ -26,3 +26,16 @@ func (n *NoopQueue) AutoClaim(ctx context.Context, group, consumer string, minId
 }
 func (n *NoopQueue) Close(ctx context.Context) error                               { return nil }
 func (n *NoopQueue) Stats(ctx context.Context, group string) (int64, int64, error) { return 0, 0, nil }
+func (n *NoopQueue) Outstanding(ctx context.Context, group string) ([]Message, error) {
+	return nil, nil
+}
+func (n *NoopQueue) DeadLetter(ctx context.Context, m Message, reason string) error { return nil }
+func (n *NoopQueue) DeadLetters(ctx context.Context, count int) ([]DeadLetter, error) {
+	return nil, nil
+}
+func (n *NoopQueue) GetDeadLetter(ctx context.Context, id string) (*DeadLetter, error) {
+	return nil, ErrDeadLetterNotFound
+}
+func (n *NoopQueue) PurgeDeadLetters(ctx context.Context, ids ...string) (int64, error) {
+	return 0, nil
+}
@@ -0,0 +1,206 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/segmentio/kafka-go"
+	"k8s.io/klog/v2"
+)
+
+// subscriptionBuffer bounds the number of undelivered messages per subscription.
+const subscriptionBuffer = 256
+
+// PubSub abstracts a broadcast channel: every subscriber receives every message
+// published after it subscribed. Unlike Queue, messages are neither persisted nor acked.
+type PubSub interface {
+	// Publish sends a payload to all current subscribers of the channel.
+	Publish(ctx context.Context, channel string, payload []byte) error
+	// Subscribe returns a stream of payloads published to the channel.
+	// The returned channel is closed once ctx is done or the subscription fails.
+	Subscribe(ctx context.Context, channel string) (<-chan []byte, error)
+	// Close releases any underlying resources.
+	Close(ctx context.Context) error
+}
+
+// LocalPubSub delivers messages in-process; used in local mode where a single replica
+// both runs workflows and serves the API.
+type LocalPubSub struct {
+	mu   sync.RWMutex
+	subs map[string]map[chan []byte]struct{}
+}
+
+// NewLocalPubSub creates an in-memory PubSub.
+func NewLocalPubSub() *LocalPubSub {
+	return &LocalPubSub{subs: make(map[string]map[chan []byte]struct{})}
+}
+
+func (l *LocalPubSub) Publish(ctx context.Context, channel string, payload []byte) error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for ch := range l.subs[channel] {
+		select {
+		case ch <- payload:
+		default:
+			klog.V(4).Infof("local pubsub subscriber of %s is full, dropping message", channel)
+		}
+	}
+	return nil
+}
+
+func (l *LocalPubSub) Subscribe(ctx context.Context, channel string) (<-chan []byte, error) {
+	ch := make(chan []byte, subscriptionBuffer)
+	l.mu.Lock()
+	if l.subs[channel] == nil {
+		l.subs[channel] = make(map[chan []byte]struct{})
+	}
+	l.subs[channel][ch] = struct{}{}
+	l.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		l.mu.Lock()
+		delete(l.subs[channel], ch)
+		l.mu.Unlock()
+		close(ch)
+	}()
+	return ch, nil
+}
+
+func (l *LocalPubSub) Close(ctx context.Context) error { return nil }
+
+// RedisPubSub implements PubSub with Redis PUBLISH/SUBSCRIBE.
+type RedisPubSub struct {
+	cli *redis.Client
+}
+
+// NewRedisPubSub creates a PubSub on top of an existing Redis client.
+func NewRedisPubSub(cli *redis.Client) (*RedisPubSub, error) {
+	if cli == nil {
+		return nil, errors.New("redis client is nil")
+	}
+	return &RedisPubSub{cli: cli}, nil
+}
+
+func (r *RedisPubSub) Publish(ctx context.Context, channel string, payload []byte) error {
+	return r.cli.Publish(ctx, channel, payload).Err()
+}
+
+func (r *RedisPubSub) Subscribe(ctx context.Context, channel string) (<-chan []byte, error) {
+	sub := r.cli.Subscribe(ctx, channel)
+	// Wait for the subscription confirmation so no message published afterwards is missed.
+	if _, err := sub.Receive(ctx); err != nil {
+		_ = sub.Close()
+		return nil, err
+	}
+	out := make(chan []byte, subscriptionBuffer)
+	go func() {
+		defer close(out)
+		defer sub.Close()
+		msgs := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case m, ok := <-msgs:
+				if !ok {
+					return
+				}
+				select {
+				case out <- []byte(m.Payload):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (r *RedisPubSub) Close(ctx context.Context) error { return nil }
+
+// KafkaPubSub implements PubSub on a Kafka topic per channel. Every subscriber reads the
+// topic without a consumer group starting at the latest offset, so each API replica sees
+// all messages. Channels are expected to be single-partition topics.
+type KafkaPubSub struct {
+	brokers []string
+
+	mu      sync.Mutex
+	writers map[string]*kafka.Writer
+}
+
+// NewKafkaPubSub creates a PubSub using the given brokers.
+func NewKafkaPubSub(brokers []string) (*KafkaPubSub, error) {
+	if len(brokers) == 0 {
+		return nil, errors.New("kafka brokers cannot be empty")
+	}
+	return &KafkaPubSub{brokers: brokers, writers: make(map[string]*kafka.Writer)}, nil
+}
+
+func (k *KafkaPubSub) writer(topic string) *kafka.Writer {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	w, ok := k.writers[topic]
+	if !ok {
+		w = &kafka.Writer{
+			Addr:                   kafka.TCP(k.brokers...),
+			Topic:                  topic,
+			BatchSize:              1,
+			BatchTimeout:           10 * time.Millisecond,
+			RequiredAcks:           kafka.RequireOne,
+			AllowAutoTopicCreation: true,
+		}
+		k.writers[topic] = w
+	}
+	return w
+}
+
+func (k *KafkaPubSub) Publish(ctx context.Context, channel string, payload []byte) error {
+	return k.writer(channel).WriteMessages(ctx, kafka.Message{Value: payload})
+}
+
+func (k *KafkaPubSub) Subscribe(ctx context.Context, channel string) (<-chan []byte, error) {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     k.brokers,
+		Topic:       channel,
+		StartOffset: kafka.LastOffset,
+		MinBytes:    1,
+		MaxBytes:    10e6,
+		MaxWait:     500 * time.Millisecond,
+	})
+	out := make(chan []byte, subscriptionBuffer)
+	go func() {
+		defer close(out)
+		defer reader.Close()
+		for {
+			msg, err := reader.ReadMessage(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					klog.Warningf("kafka pubsub read from %s failed: %v", channel, err)
+				}
+				return
+			}
+			select {
+			case out <- msg.Value:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (k *KafkaPubSub) Close(ctx context.Context) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	var errs []error
+	for topic, w := range k.writers {
+		if err := w.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		delete(k.writers, topic)
+	}
+	return errors.Join(errs...)
+}
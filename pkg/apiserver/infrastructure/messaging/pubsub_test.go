@@ -0,0 +1,84 @@
+package messaging
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func receive(t *testing.T, ch <-chan []byte) string {
+	t.Helper()
+	select {
+	case payload, ok := <-ch:
+		if !ok {
+			t.Fatalf("subscription closed unexpectedly")
+		}
+		return string(payload)
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for message")
+	}
+	return ""
+}
+
+func TestLocalPubSubFansOutPerChannel(t *testing.T) {
+	ps := NewLocalPubSub()
+	ctx, cancel := context.WithCancel(context.Background())
+	first, _ := ps.Subscribe(ctx, "events")
+	second, _ := ps.Subscribe(ctx, "events")
+	other, _ := ps.Subscribe(ctx, "other")
+
+	if err := ps.Publish(ctx, "events", []byte("hello")); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if got := receive(t, first); got != "hello" {
+		t.Fatalf("first subscriber got %q", got)
+	}
+	if got := receive(t, second); got != "hello" {
+		t.Fatalf("second subscriber got %q", got)
+	}
+	select {
+	case payload := <-other:
+		t.Fatalf("subscriber of another channel received %q", payload)
+	default:
+	}
+
+	cancel()
+	select {
+	case _, ok := <-first:
+		if ok {
+			t.Fatalf("expected subscription to close after cancel")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("subscription not closed after cancel")
+	}
+}
+
+func TestRedisPubSubDeliversToSubscribers(t *testing.T) {
+	server, err := miniredis.Run()
+	if err != nil {
+		t.Skipf("start miniredis: %v", err)
+	}
+	defer server.Close()
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	ps, err := NewRedisPubSub(client)
+	if err != nil {
+		t.Fatalf("new redis pubsub: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := ps.Subscribe(ctx, "kubemin.workflow.events")
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if err := ps.Publish(ctx, "kubemin.workflow.events", []byte(`{"task_id":"t-1"}`)); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if got := receive(t, ch); got != `{"task_id":"t-1"}` {
+		t.Fatalf("unexpected payload %q", got)
+	}
+}
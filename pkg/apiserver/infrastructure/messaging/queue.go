@@ -2,13 +2,35 @@ package messaging
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// DeadLetterSuffix is appended to the stream key or topic to name its dead-letter stream.
+const DeadLetterSuffix = ".dead"
+
+// ErrDeadLetterNotFound is returned when a dead letter does not exist.
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
 // Message represents a queue message with its ID and raw payload.
 type Message struct {
 	ID      string
 	Payload []byte
+	// Deliveries counts how often the message was handed to a consumer, 1 on the first read.
+	// It is 0 when the backend cannot tell.
+	Deliveries int64
+}
+
+// DeadLetter is a message moved out of the work queue because it could not be delivered.
+type DeadLetter struct {
+	// ID identifies the dead letter in the dead-letter stream.
+	ID string
+	// MessageID is the ID the message had in the work queue.
+	MessageID  string
+	Payload    []byte
+	Error      string
+	Deliveries int64
+	DeadAt     time.Time
 }
 
 // Queue abstracts a work queue with stream semantics (enqueue, group read, ack).
@@ -27,4 +49,17 @@ type Queue interface {
 	Close(ctx context.Context) error
 	// Stats returns stream backlog size and pending count for a group.
 	Stats(ctx context.Context, group string) (backlog int64, pending int64, err error)
+	// Outstanding returns the messages the group has not acknowledged yet, both the unread
+	// ones and the ones read but still pending.
+	Outstanding(ctx context.Context, group string) ([]Message, error)
+	// DeadLetter writes an undeliverable message and the reason to the dead-letter stream.
+	// The caller still acks the original message.
+	DeadLetter(ctx context.Context, m Message, reason string) error
+	// DeadLetters lists up to count dead letters, oldest first.
+	DeadLetters(ctx context.Context, count int) ([]DeadLetter, error)
+	// GetDeadLetter returns a dead letter by ID or ErrDeadLetterNotFound.
+	GetDeadLetter(ctx context.Context, id string) (*DeadLetter, error)
+	// PurgeDeadLetters removes the given dead letters, or all of them when no ID is given,
+	// and returns how many were removed.
+	PurgeDeadLetters(ctx context.Context, ids ...string) (int64, error)
 }
@@ -1,8 +1,12 @@
 package messaging
 
 import (
+	"cmp"
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -21,6 +25,12 @@ type redisCommander interface {
 	XAutoClaim(ctx context.Context, a *redis.XAutoClaimArgs) *redis.XAutoClaimCmd
 	XLen(ctx context.Context, stream string) *redis.IntCmd
 	XPending(ctx context.Context, stream, group string) *redis.XPendingCmd
+	XPendingExt(ctx context.Context, a *redis.XPendingExtArgs) *redis.XPendingExtCmd
+	XInfoGroups(ctx context.Context, stream string) *redis.XInfoGroupsCmd
+	XRange(ctx context.Context, stream, start, stop string) *redis.XMessageSliceCmd
+	XRangeN(ctx context.Context, stream, start, stop string, count int64) *redis.XMessageSliceCmd
+	XDel(ctx context.Context, stream string, ids ...string) *redis.IntCmd
+	Del(ctx context.Context, keys ...string) *redis.IntCmd
 	Close() error
 }
 
@@ -80,9 +90,9 @@ func (r *RedisStreams) ReadGroup(ctx context.Context, group, consumer string, co
 			if raw, ok := m.Values["p"]; ok {
 				switch v := raw.(type) {
 				case string:
-					mags = append(mags, Message{ID: m.ID, Payload: []byte(v)})
+					mags = append(mags, Message{ID: m.ID, Payload: []byte(v), Deliveries: 1})
 				case []byte:
-					mags = append(mags, Message{ID: m.ID, Payload: v})
+					mags = append(mags, Message{ID: m.ID, Payload: v, Deliveries: 1})
 				default:
 					klog.Warningf("redis stream malformed payload type id=%s type=%T", m.ID, v)
 				}
@@ -130,9 +140,28 @@ func (r *RedisStreams) AutoClaim(ctx context.Context, group, consumer string, mi
 			klog.Warningf("redis stream claimed message missing payload field 'p' id=%s", m.ID)
 		}
 	}
+	for i := range msgs {
+		msgs[i].Deliveries = r.deliveries(ctx, group, msgs[i].ID)
+	}
 	return msgs, nil
 }
 
+// deliveries returns the delivery count of a pending message, 0 when it cannot be read.
+func (r *RedisStreams) deliveries(ctx context.Context, group, id string) int64 {
+	pending, err := r.cli.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: r.key,
+		Group:  group,
+		Start:  id,
+		End:    id,
+		Count:  1,
+	}).Result()
+	if err != nil || len(pending) == 0 {
+		klog.V(4).Infof("redis stream delivery count unavailable id=%s: %v", id, err)
+		return 0
+	}
+	return pending[0].RetryCount
+}
+
 func (r *RedisStreams) Close(ctx context.Context) error { return r.cli.Close() }
 
 func (r *RedisStreams) Stats(ctx context.Context, group string) (int64, int64, error) {
@@ -150,3 +179,163 @@ func (r *RedisStreams) Stats(ctx context.Context, group string) (int64, int64, e
 	}
 	return xl, cnt, nil
 }
+
+func (r *RedisStreams) Outstanding(ctx context.Context, group string) ([]Message, error) {
+	groups, err := r.cli.XInfoGroups(ctx, r.key).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	lastDelivered := ""
+	for _, g := range groups {
+		if g.Name == group {
+			lastDelivered = g.LastDeliveredID
+		}
+	}
+	if lastDelivered == "" {
+		return nil, fmt.Errorf("redis stream %s has no consumer group %s", r.key, group)
+	}
+	summary, err := r.cli.XPending(ctx, r.key, group).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+	// Unread messages follow the last delivered ID; pending ones start at the lowest pending ID.
+	start := "(" + lastDelivered
+	pending := make(map[string]struct{})
+	if summary != nil && summary.Count > 0 {
+		entries, err := r.cli.XPendingExt(ctx, &redis.XPendingExtArgs{
+			Stream: r.key,
+			Group:  group,
+			Start:  "-",
+			End:    "+",
+			Count:  summary.Count,
+		}).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			pending[e.ID] = struct{}{}
+		}
+		start = summary.Lower
+	}
+	res, err := r.cli.XRange(ctx, r.key, start, "+").Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+	var msgs []Message
+	for _, m := range res {
+		if _, ok := pending[m.ID]; !ok && compareStreamID(m.ID, lastDelivered) <= 0 {
+			continue
+		}
+		if payload, ok := streamValue(m, "p"); ok {
+			msgs = append(msgs, Message{ID: m.ID, Payload: payload})
+		}
+	}
+	return msgs, nil
+}
+
+func (r *RedisStreams) deadLetterKey() string { return r.key + DeadLetterSuffix }
+
+func (r *RedisStreams) DeadLetter(ctx context.Context, m Message, reason string) error {
+	args := &redis.XAddArgs{
+		Stream: r.deadLetterKey(),
+		Values: map[string]interface{}{
+			"p":          m.Payload,
+			"msg_id":     m.ID,
+			"error":      reason,
+			"deliveries": m.Deliveries,
+			"dead_at":    time.Now().UTC().Format(time.RFC3339Nano),
+		},
+	}
+	if r.maxLen > 0 {
+		args.MaxLen = r.maxLen
+	}
+	return r.cli.XAdd(ctx, args).Err()
+}
+
+func (r *RedisStreams) DeadLetters(ctx context.Context, count int) ([]DeadLetter, error) {
+	res, err := r.cli.XRangeN(ctx, r.deadLetterKey(), "-", "+", int64(count)).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+	letters := make([]DeadLetter, 0, len(res))
+	for _, m := range res {
+		letters = append(letters, toDeadLetter(m))
+	}
+	return letters, nil
+}
+
+func (r *RedisStreams) GetDeadLetter(ctx context.Context, id string) (*DeadLetter, error) {
+	res, err := r.cli.XRange(ctx, r.deadLetterKey(), id, id).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, ErrDeadLetterNotFound
+	}
+	letter := toDeadLetter(res[0])
+	return &letter, nil
+}
+
+func (r *RedisStreams) PurgeDeadLetters(ctx context.Context, ids ...string) (int64, error) {
+	if len(ids) > 0 {
+		return r.cli.XDel(ctx, r.deadLetterKey(), ids...).Result()
+	}
+	n, err := r.cli.XLen(ctx, r.deadLetterKey()).Result()
+	if err != nil {
+		return 0, err
+	}
+	if err := r.cli.Del(ctx, r.deadLetterKey()).Err(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func toDeadLetter(m redis.XMessage) DeadLetter {
+	letter := DeadLetter{ID: m.ID}
+	letter.Payload, _ = streamValue(m, "p")
+	if v, ok := streamValue(m, "msg_id"); ok {
+		letter.MessageID = string(v)
+	}
+	if v, ok := streamValue(m, "error"); ok {
+		letter.Error = string(v)
+	}
+	if v, ok := streamValue(m, "deliveries"); ok {
+		letter.Deliveries, _ = strconv.ParseInt(string(v), 10, 64)
+	}
+	if v, ok := streamValue(m, "dead_at"); ok {
+		letter.DeadAt, _ = time.Parse(time.RFC3339Nano, string(v))
+	}
+	return letter
+}
+
+// streamValue returns a field of a stream entry as bytes.
+func streamValue(m redis.XMessage, field string) ([]byte, bool) {
+	switch v := m.Values[field].(type) {
+	case string:
+		return []byte(v), true
+	case []byte:
+		return v, true
+	default:
+		return nil, false
+	}
+}
+
+// compareStreamID compares two stream IDs of the form <ms>-<seq>.
+func compareStreamID(a, b string) int {
+	am, as := splitStreamID(a)
+	bm, bs := splitStreamID(b)
+	if c := cmp.Compare(am, bm); c != 0 {
+		return c
+	}
+	return cmp.Compare(as, bs)
+}
+
+func splitStreamID(id string) (uint64, uint64) {
+	ms, seq, _ := strings.Cut(id, "-")
+	m, _ := strconv.ParseUint(ms, 10, 64)
+	s, _ := strconv.ParseUint(seq, 10, 64)
+	return m, s
+}
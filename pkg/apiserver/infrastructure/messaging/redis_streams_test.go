@@ -2,13 +2,24 @@ package messaging
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/redis/go-redis/v9"
 )
 
-// fakeRedis implements redisCommander for testing without a real Redis.
-type fakeRedis struct{ closed bool }
+// fakeRedis implements redisCommander for testing without a real Redis. Streams are kept
+// in memory; the consumer group state is set by the tests.
+type fakeRedis struct {
+	closed        bool
+	seq           int
+	streams       map[string][]redis.XMessage
+	lastDelivered string
+	pending       []redis.XPendingExt
+}
 
 func (f *fakeRedis) Ping(ctx context.Context) *redis.StatusCmd {
 	cmd := redis.NewStatusCmd(ctx)
@@ -23,8 +34,22 @@ func (f *fakeRedis) XGroupCreateMkStream(ctx context.Context, stream, group, sta
 }
 
 func (f *fakeRedis) XAdd(ctx context.Context, a *redis.XAddArgs) *redis.StringCmd {
+	if f.streams == nil {
+		f.streams = map[string][]redis.XMessage{}
+	}
+	f.seq++
+	id := fmt.Sprintf("%d-0", f.seq)
+	values := map[string]interface{}{}
+	for k, v := range a.Values.(map[string]interface{}) {
+		if b, ok := v.([]byte); ok {
+			values[k] = string(b)
+		} else {
+			values[k] = fmt.Sprint(v)
+		}
+	}
+	f.streams[a.Stream] = append(f.streams[a.Stream], redis.XMessage{ID: id, Values: values})
 	cmd := redis.NewStringCmd(ctx)
-	cmd.SetVal("1-0")
+	cmd.SetVal(id)
 	return cmd
 }
 
@@ -45,12 +70,90 @@ func (f *fakeRedis) XAutoClaim(ctx context.Context, a *redis.XAutoClaimArgs) *re
 }
 
 func (f *fakeRedis) XLen(ctx context.Context, stream string) *redis.IntCmd {
-	return redis.NewIntCmd(ctx)
+	return redis.NewIntResult(int64(len(f.streams[stream])), nil)
 }
 
 func (f *fakeRedis) XPending(ctx context.Context, stream, group string) *redis.XPendingCmd {
-	var cmd redis.XPendingCmd
-	return &cmd
+	pending := &redis.XPending{Count: int64(len(f.pending))}
+	if len(f.pending) > 0 {
+		pending.Lower = f.pending[0].ID
+	}
+	return redis.NewXPendingResult(pending, nil)
+}
+
+func (f *fakeRedis) XPendingExt(ctx context.Context, a *redis.XPendingExtArgs) *redis.XPendingExtCmd {
+	var entries []redis.XPendingExt
+	for _, e := range f.pending {
+		if inRange(e.ID, a.Start, a.End) {
+			entries = append(entries, e)
+		}
+	}
+	cmd := redis.NewXPendingExtCmd(ctx)
+	cmd.SetVal(entries)
+	return cmd
+}
+
+func (f *fakeRedis) XInfoGroups(ctx context.Context, stream string) *redis.XInfoGroupsCmd {
+	cmd := redis.NewXInfoGroupsCmd(ctx, stream)
+	cmd.SetVal([]redis.XInfoGroup{{Name: "g", LastDeliveredID: f.lastDelivered}})
+	return cmd
+}
+
+func (f *fakeRedis) XRange(ctx context.Context, stream, start, stop string) *redis.XMessageSliceCmd {
+	var res []redis.XMessage
+	for _, m := range f.streams[stream] {
+		if inRange(m.ID, start, stop) {
+			res = append(res, m)
+		}
+	}
+	return redis.NewXMessageSliceCmdResult(res, nil)
+}
+
+func (f *fakeRedis) XRangeN(ctx context.Context, stream, start, stop string, count int64) *redis.XMessageSliceCmd {
+	res := f.XRange(ctx, stream, start, stop).Val()
+	if int64(len(res)) > count {
+		res = res[:count]
+	}
+	return redis.NewXMessageSliceCmdResult(res, nil)
+}
+
+func (f *fakeRedis) XDel(ctx context.Context, stream string, ids ...string) *redis.IntCmd {
+	var kept []redis.XMessage
+	for _, m := range f.streams[stream] {
+		if !slices.Contains(ids, m.ID) {
+			kept = append(kept, m)
+		}
+	}
+	removed := len(f.streams[stream]) - len(kept)
+	f.streams[stream] = kept
+	return redis.NewIntResult(int64(removed), nil)
+}
+
+func (f *fakeRedis) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	var removed int64
+	for _, key := range keys {
+		if _, ok := f.streams[key]; ok {
+			delete(f.streams, key)
+			removed++
+		}
+	}
+	return redis.NewIntResult(removed, nil)
+}
+
+// inRange reports whether id lies in the XRANGE interval [start, stop]; "(" marks an
+// exclusive start.
+func inRange(id, start, stop string) bool {
+	switch {
+	case strings.HasPrefix(start, "("):
+		if compareStreamID(id, start[1:]) <= 0 {
+			return false
+		}
+	case start != "-":
+		if compareStreamID(id, start) < 0 {
+			return false
+		}
+	}
+	return stop == "+" || compareStreamID(id, stop) <= 0
 }
 
 func (f *fakeRedis) Close() error { f.closed = true; return nil }
@@ -94,3 +197,78 @@ func TestRedisStreams_WithCommanderBasic(t *testing.T) {
 		t.Fatalf("expected fake client to be closed")
 	}
 }
+
+func TestRedisStreams_DeadLetters(t *testing.T) {
+	f := &fakeRedis{}
+	rs, err := NewRedisStreamsWithClient(f, "test-stream", 1000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+	for i, reason := range []string{"decode failed", "task not found"} {
+		m := Message{ID: fmt.Sprintf("%d-0", i+10), Payload: []byte(reason), Deliveries: 3}
+		if err := rs.DeadLetter(ctx, m, reason); err != nil {
+			t.Fatalf("DeadLetter error: %v", err)
+		}
+	}
+	if _, ok := f.streams["test-stream.dead"]; !ok {
+		t.Fatalf("expected dead letters in test-stream.dead, got streams %v", f.streams)
+	}
+
+	letters, err := rs.DeadLetters(ctx, 10)
+	if err != nil || len(letters) != 2 {
+		t.Fatalf("DeadLetters err=%v letters=%v", err, letters)
+	}
+	first := letters[0]
+	if first.MessageID != "10-0" || first.Error != "decode failed" || string(first.Payload) != "decode failed" || first.Deliveries != 3 || first.DeadAt.IsZero() {
+		t.Fatalf("unexpected dead letter: %+v", first)
+	}
+
+	got, err := rs.GetDeadLetter(ctx, letters[1].ID)
+	if err != nil || got.MessageID != "11-0" {
+		t.Fatalf("GetDeadLetter err=%v letter=%+v", err, got)
+	}
+	if _, err := rs.GetDeadLetter(ctx, "99-0"); !errors.Is(err, ErrDeadLetterNotFound) {
+		t.Fatalf("expected ErrDeadLetterNotFound, got %v", err)
+	}
+
+	if n, err := rs.PurgeDeadLetters(ctx, first.ID); err != nil || n != 1 {
+		t.Fatalf("PurgeDeadLetters(id) n=%d err=%v", n, err)
+	}
+	if n, err := rs.PurgeDeadLetters(ctx); err != nil || n != 1 {
+		t.Fatalf("PurgeDeadLetters() n=%d err=%v", n, err)
+	}
+	if letters, _ := rs.DeadLetters(ctx, 10); len(letters) != 0 {
+		t.Fatalf("expected no dead letters after purge, got %v", letters)
+	}
+}
+
+func TestRedisStreams_Outstanding(t *testing.T) {
+	f := &fakeRedis{}
+	rs, err := NewRedisStreamsWithClient(f, "test-stream", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+	for _, p := range []string{"acked", "pending", "unread"} {
+		if _, err := rs.Enqueue(ctx, []byte(p)); err != nil {
+			t.Fatalf("Enqueue error: %v", err)
+		}
+	}
+	f.lastDelivered = "2-0"
+	f.pending = []redis.XPendingExt{{ID: "2-0", RetryCount: 4}}
+
+	msgs, err := rs.Outstanding(ctx, "g")
+	if err != nil {
+		t.Fatalf("Outstanding error: %v", err)
+	}
+	if len(msgs) != 2 || string(msgs[0].Payload) != "pending" || string(msgs[1].Payload) != "unread" {
+		t.Fatalf("unexpected outstanding messages: %+v", msgs)
+	}
+	if _, err := rs.Outstanding(ctx, "other"); err == nil {
+		t.Fatalf("expected error for unknown group")
+	}
+	if n := rs.deliveries(ctx, "g", "2-0"); n != 4 {
+		t.Fatalf("expected 4 deliveries, got %d", n)
+	}
+}
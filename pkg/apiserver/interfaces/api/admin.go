@@ -0,0 +1,166 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"kubemin-cli/pkg/apiserver/config"
+	msg "kubemin-cli/pkg/apiserver/infrastructure/messaging"
+	apis "kubemin-cli/pkg/apiserver/interfaces/api/dto/v1"
+	"kubemin-cli/pkg/apiserver/utils/bcode"
+	"kubemin-cli/pkg/apiserver/workflow/drain"
+)
+
+func init() {
+	RegisterAPI(&admin{})
+}
+
+// admin 提供实例运维接口：滚动升级前通过排空接口让实例交出运行中的任务，
+// 以及查看、重放和清除无法投递的分发消息
+type admin struct {
+	Drain *drain.Controller `inject:""`
+	Queue msg.Queue         `inject:"queue"`
+}
+
+// GetName returns the API name for registration.
+func (a *admin) GetName() string {
+	return "admin"
+}
+
+// RegisterRoutes registers admin endpoints.
+func (a *admin) RegisterRoutes(group *gin.RouterGroup) {
+	group.GET("/admin/drain", a.drainStatus)
+	group.POST("/admin/drain", a.startDrain)
+	group.GET("/admin/dead-letters", a.listDeadLetters)
+	group.DELETE("/admin/dead-letters", a.purgeDeadLetters)
+	group.GET("/admin/dead-letters/:id", a.getDeadLetter)
+	group.DELETE("/admin/dead-letters/:id", a.purgeDeadLetters)
+	group.POST("/admin/dead-letters/:id/replay", a.replayDeadLetter)
+}
+
+// startDrain 开始排空本实例，重复调用返回当前进度
+func (a *admin) startDrain(c *gin.Context) {
+	if a.Drain == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "drain is not supported"})
+		return
+	}
+	a.Drain.Begin()
+	c.JSON(http.StatusAccepted, drainStatusResponse(a.Drain.Status()))
+}
+
+// drainStatus 返回本实例的排空进度
+func (a *admin) drainStatus(c *gin.Context) {
+	if a.Drain == nil {
+		c.JSON(http.StatusOK, apis.DrainStatusResponse{InFlight: []string{}})
+		return
+	}
+	c.JSON(http.StatusOK, drainStatusResponse(a.Drain.Status()))
+}
+
+func drainStatusResponse(status drain.Status) apis.DrainStatusResponse {
+	resp := apis.DrainStatusResponse{
+		Draining:    status.Draining,
+		GracePeriod: status.GracePeriod.String(),
+		InFlight:    status.InFlight,
+		Finished:    status.Finished,
+	}
+	if status.Draining {
+		since := status.Since
+		resp.Since = &since
+	}
+	return resp
+}
+
+// listDeadLetters 列出死信，limit 默认 50，最大 500
+func (a *admin) listDeadLetters(c *gin.Context) {
+	limit := config.DefaultDeadLetterListLimit
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			bcode.ReturnError(c, bcode.ErrDeadLetterLimitInvalid)
+			return
+		}
+		limit = min(n, config.MaxDeadLetterListLimit)
+	}
+	letters, err := a.Queue.DeadLetters(c.Request.Context(), limit)
+	if err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	resp := apis.ListDeadLettersResponse{DeadLetters: make([]apis.DeadLetterResponse, 0, len(letters))}
+	for _, letter := range letters {
+		resp.DeadLetters = append(resp.DeadLetters, deadLetterResponse(letter))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// getDeadLetter 查看一条死信
+func (a *admin) getDeadLetter(c *gin.Context) {
+	letter, err := a.deadLetter(c)
+	if err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, deadLetterResponse(*letter))
+}
+
+// replayDeadLetter 将死信重新写入分发队列并移除死信。worker 只运行仍处于 queued 的任务，
+// 已被重新分发或已结束的任务不会再次执行
+func (a *admin) replayDeadLetter(c *gin.Context) {
+	letter, err := a.deadLetter(c)
+	if err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	ctx := c.Request.Context()
+	id, err := a.Queue.Enqueue(ctx, letter.Payload)
+	if err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	if _, err := a.Queue.PurgeDeadLetters(ctx, letter.ID); err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, apis.ReplayDeadLetterResponse{ID: letter.ID, MessageID: id})
+}
+
+// purgeDeadLetters 清除指定的死信，未指定 ID 时清除全部
+func (a *admin) purgeDeadLetters(c *gin.Context) {
+	var ids []string
+	if id := c.Param("id"); id != "" {
+		if _, err := a.deadLetter(c); err != nil {
+			bcode.ReturnError(c, err)
+			return
+		}
+		ids = append(ids, id)
+	}
+	purged, err := a.Queue.PurgeDeadLetters(c.Request.Context(), ids...)
+	if err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, apis.PurgeDeadLettersResponse{Purged: purged})
+}
+
+func (a *admin) deadLetter(c *gin.Context) (*msg.DeadLetter, error) {
+	letter, err := a.Queue.GetDeadLetter(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, msg.ErrDeadLetterNotFound) {
+		return nil, bcode.ErrDeadLetterNotExist
+	}
+	return letter, err
+}
+
+func deadLetterResponse(letter msg.DeadLetter) apis.DeadLetterResponse {
+	return apis.DeadLetterResponse{
+		ID:         letter.ID,
+		MessageID:  letter.MessageID,
+		Payload:    string(letter.Payload),
+		Error:      letter.Error,
+		Deliveries: letter.Deliveries,
+		DeadAt:     letter.DeadAt,
+	}
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	msg "kubemin-cli/pkg/apiserver/infrastructure/messaging"
+	apis "kubemin-cli/pkg/apiserver/interfaces/api/dto/v1"
+	"kubemin-cli/pkg/apiserver/workflow/drain"
+)
+
+func TestAdminDrain(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	controller := drain.NewController(time.Minute)
+	a := &admin{Drain: controller}
+	r := gin.New()
+	a.RegisterRoutes(r.Group(""))
+
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/admin/drain", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	var status apis.DrainStatusResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &status))
+	require.False(t, status.Draining)
+	require.Nil(t, status.Since)
+
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/admin/drain", nil))
+	require.Equal(t, http.StatusAccepted, resp.Code)
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &status))
+	require.True(t, status.Draining)
+	require.NotNil(t, status.Since)
+	require.Equal(t, "1m0s", status.GracePeriod)
+	require.True(t, controller.Draining())
+}
+
+// deadLetterQueue keeps dead letters in memory and records replayed payloads.
+type deadLetterQueue struct {
+	msg.NoopQueue
+	letters  []msg.DeadLetter
+	enqueued [][]byte
+}
+
+func (q *deadLetterQueue) Enqueue(_ context.Context, payload []byte) (string, error) {
+	q.enqueued = append(q.enqueued, payload)
+	return "9-0", nil
+}
+
+func (q *deadLetterQueue) DeadLetters(_ context.Context, count int) ([]msg.DeadLetter, error) {
+	return q.letters[:min(count, len(q.letters))], nil
+}
+
+func (q *deadLetterQueue) GetDeadLetter(_ context.Context, id string) (*msg.DeadLetter, error) {
+	for i := range q.letters {
+		if q.letters[i].ID == id {
+			return &q.letters[i], nil
+		}
+	}
+	return nil, msg.ErrDeadLetterNotFound
+}
+
+func (q *deadLetterQueue) PurgeDeadLetters(_ context.Context, ids ...string) (int64, error) {
+	before := len(q.letters)
+	if len(ids) == 0 {
+		q.letters = nil
+		return int64(before), nil
+	}
+	var kept []msg.DeadLetter
+	for _, letter := range q.letters {
+		if letter.ID != ids[0] {
+			kept = append(kept, letter)
+		}
+	}
+	q.letters = kept
+	return int64(before - len(kept)), nil
+}
+
+func TestAdminDeadLetters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	q := &deadLetterQueue{letters: []msg.DeadLetter{
+		{ID: "1-0", MessageID: "100-0", Payload: []byte(`{"task_id":"task-1"}`), Error: "task task-1 does not exist", Deliveries: 1},
+		{ID: "2-0", MessageID: "101-0", Payload: []byte("oops"), Error: "decode dispatch", Deliveries: 1},
+		{ID: "3-0", MessageID: "102-0", Payload: []byte("oops"), Error: "decode dispatch", Deliveries: 1},
+	}}
+	a := &admin{Queue: q}
+	r := gin.New()
+	a.RegisterRoutes(r.Group(""))
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, httptest.NewRequest(method, path, nil))
+		return resp
+	}
+
+	resp := serve(http.MethodGet, "/admin/dead-letters?limit=2")
+	require.Equal(t, http.StatusOK, resp.Code)
+	var list apis.ListDeadLettersResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+	require.Len(t, list.DeadLetters, 2)
+	require.Equal(t, "100-0", list.DeadLetters[0].MessageID)
+	require.Equal(t, http.StatusBadRequest, serve(http.MethodGet, "/admin/dead-letters?limit=0").Code)
+
+	resp = serve(http.MethodGet, "/admin/dead-letters/2-0")
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Contains(t, resp.Body.String(), "decode dispatch")
+	require.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/admin/dead-letters/9-9").Code)
+
+	resp = serve(http.MethodPost, "/admin/dead-letters/1-0/replay")
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, [][]byte{[]byte(`{"task_id":"task-1"}`)}, q.enqueued)
+	require.Len(t, q.letters, 2, "a replayed dead letter is removed")
+
+	resp = serve(http.MethodDelete, "/admin/dead-letters/2-0")
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.JSONEq(t, `{"purged":1}`, resp.Body.String())
+	require.Equal(t, http.StatusNotFound, serve(http.MethodDelete, "/admin/dead-letters/2-0").Code)
+
+	resp = serve(http.MethodDelete, "/admin/dead-letters")
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.JSONEq(t, `{"purged":1}`, resp.Body.String())
+	require.Empty(t, q.letters)
+}
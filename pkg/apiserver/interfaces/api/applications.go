@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
@@ -18,6 +19,8 @@ type applications struct {
 	ApplicationService service.ApplicationsService `inject:""`
 	WorkflowService    service.WorkflowService     `inject:""`
 	ValidationService  service.ValidationService   `inject:""`
+	IdempotencyService service.IdempotencyService  `inject:""`
+	Cfg                *config.Config              `inject:""`
 }
 
 // NewApplications new applications manage
@@ -35,10 +38,28 @@ func (app *applications) RegisterRoutes(group *gin.RouterGroup) {
 	group.DELETE("/applications/:appID/resources", app.deleteApplicationResources)
 	group.POST("/applications/:appID/workflow/exec", app.execApplicationWorkflow)
 	group.POST("/applications/:appID/workflow/cancel", app.cancelApplicationWorkflow)
+	group.POST("/applications/:appID/workflow/pause", app.pauseApplicationWorkflow)
+	group.POST("/applications/:appID/workflow/resume", app.resumeApplicationWorkflow)
+	group.POST("/applications/:appID/workflow/tasks/:taskID/rollback", app.rollbackApplicationWorkflow)
+	group.POST("/applications/:appID/workflow/tasks/:taskID/retry", app.retryApplicationWorkflow)
+	group.POST("/applications/:appID/workflow/tasks/:taskID/approve", app.approveApplicationWorkflow)
+	group.POST("/applications/:appID/workflow/tasks/:taskID/reject", app.rejectApplicationWorkflow)
 	group.GET("/workflow/tasks/:taskID/status", app.getWorkflowTaskStatus)
+	group.GET("/workflow/tasks/:taskID/jobs", app.listWorkflowTaskJobs)
+	group.GET("/workflow/tasks/:taskID/events", app.streamWorkflowTask)
+	group.GET("/workflow/tasks/:taskID/ws", app.watchWorkflowTask)
+	group.GET("/applications/:appID/workflow/tasks", app.listWorkflowTasks)
+	group.GET("/applications/:appID/workflows/:workflowID/tasks", app.listWorkflowTasks)
+	group.GET("/applications/:appID/workflow/schedules", app.listWorkflowSchedules)
+	group.POST("/applications/:appID/workflow/schedules", app.createWorkflowSchedule)
+	group.GET("/applications/:appID/workflow/schedules/:scheduleID", app.getWorkflowSchedule)
+	group.PUT("/applications/:appID/workflow/schedules/:scheduleID", app.updateWorkflowSchedule)
+	group.DELETE("/applications/:appID/workflow/schedules/:scheduleID", app.deleteWorkflowSchedule)
 	group.POST("/applications/:appID/version", app.updateVersion)
 	group.POST("/applications/try", app.tryApplication)
 	group.POST("/applications/:appID/workflow/try", app.tryWorkflow)
+	group.POST("/applications/:appID/workflow/dry-run", app.dryRunWorkflow)
+	group.GET("/applications/:appID/workflow/plan", app.planWorkflow)
 }
 
 func (app *applications) createApplications(c *gin.Context) {
@@ -53,13 +74,9 @@ func (app *applications) createApplications(c *gin.Context) {
 		bcode.ReturnError(c, err)
 		return
 	}
-	ctx := c.Request.Context()
-	resp, err := app.ApplicationService.CreateApplications(ctx, req)
-	if err != nil {
-		bcode.ReturnError(c, err)
-		return
-	}
-	c.JSON(http.StatusOK, resp)
+	idempotent(c, app.IdempotencyService, idempotencyScopeCreateApplication, req, func(ctx context.Context) (interface{}, error) {
+		return app.ApplicationService.CreateApplications(ctx, req)
+	})
 }
 
 func (app *applications) listApplications(c *gin.Context) {
@@ -190,6 +207,9 @@ func (app *applications) updateApplicationWorkflow(c *gin.Context) {
 func normalizeWorkflowSteps(steps []apis.CreateWorkflowStepRequest) {
 	for i := range steps {
 		steps[i].Name = strings.ToLower(steps[i].Name)
+		for j := range steps[i].DependsOn {
+			steps[i].DependsOn[j] = strings.ToLower(steps[i].DependsOn[j])
+		}
 		for j := range steps[i].Components {
 			steps[i].Components[j] = strings.ToLower(steps[i].Components[j])
 		}
@@ -224,8 +244,51 @@ func (app *applications) execApplicationWorkflow(c *gin.Context) {
 		bcode.ReturnError(c, err)
 		return
 	}
+	idempotent(c, app.IdempotencyService, idempotencyScopeExecWorkflow+appID, req, func(ctx context.Context) (interface{}, error) {
+		return app.WorkflowService.ExecWorkflowTaskForApp(ctx, appID, req.WorkflowID, req.Inputs, req.Priority)
+	})
+}
+
+// dryRunWorkflow 按真实执行计划生成工作流的全部对象并以 DryRun=All 提交，返回每个 Job 的准入结果，不修改集群
+func (app *applications) dryRunWorkflow(c *gin.Context) {
+	appID := strings.TrimSpace(c.Param("appID"))
+	if appID == "" {
+		bcode.ReturnError(c, bcode.ErrApplicationNotExist)
+		return
+	}
+	var req apis.DryRunWorkflowRequest
+	if err := c.Bind(&req); err != nil {
+		klog.Error(err)
+		bcode.ReturnError(c, bcode.ErrWorkflowConfig)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	ctx := c.Request.Context()
+	resp, err := app.WorkflowService.DryRunWorkflowForApp(ctx, appID, req)
+	if err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// planWorkflow 预览执行工作流会对每个资源做的变更（create/update/no-op/delete）
+func (app *applications) planWorkflow(c *gin.Context) {
+	appID := strings.TrimSpace(c.Param("appID"))
+	if appID == "" {
+		bcode.ReturnError(c, bcode.ErrApplicationNotExist)
+		return
+	}
+	workflowID := strings.TrimSpace(c.Query("workflow_id"))
+	if workflowID == "" {
+		bcode.ReturnError(c, bcode.ErrWorkflowConfig)
+		return
+	}
 	ctx := c.Request.Context()
-	resp, err := app.WorkflowService.ExecWorkflowTaskForApp(ctx, appID, req.WorkflowID)
+	resp, err := app.WorkflowService.PlanWorkflowForApp(ctx, appID, workflowID)
 	if err != nil {
 		bcode.ReturnError(c, err)
 		return
@@ -261,6 +324,173 @@ func (app *applications) cancelApplicationWorkflow(c *gin.Context) {
 	c.JSON(http.StatusOK, apis.CancelWorkflowResponse{TaskID: req.TaskID, Status: string(config.StatusCancelled)})
 }
 
+// pauseApplicationWorkflow 暂停工作流任务，当前优先级分组执行完后停止启动新的 Job
+func (app *applications) pauseApplicationWorkflow(c *gin.Context) {
+	appID := strings.TrimSpace(c.Param("appID"))
+	if appID == "" {
+		bcode.ReturnError(c, bcode.ErrApplicationNotExist)
+		return
+	}
+	var req apis.PauseWorkflowRequest
+	if err := c.Bind(&req); err != nil {
+		klog.Error(err)
+		bcode.ReturnError(c, bcode.ErrWorkflowConfig)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	user := req.User
+	if user == "" {
+		user = config.DefaultTaskRevoker
+	}
+	ctx := c.Request.Context()
+	resp, err := app.WorkflowService.PauseWorkflowTaskForApp(ctx, appID, user, req.TaskID, req.Reason)
+	if err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// resumeApplicationWorkflow 恢复已暂停的工作流任务，从暂停位置继续执行
+func (app *applications) resumeApplicationWorkflow(c *gin.Context) {
+	appID := strings.TrimSpace(c.Param("appID"))
+	if appID == "" {
+		bcode.ReturnError(c, bcode.ErrApplicationNotExist)
+		return
+	}
+	var req apis.ResumeWorkflowRequest
+	if err := c.Bind(&req); err != nil {
+		klog.Error(err)
+		bcode.ReturnError(c, bcode.ErrWorkflowConfig)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	user := req.User
+	if user == "" {
+		user = config.DefaultTaskRevoker
+	}
+	ctx := c.Request.Context()
+	resp, err := app.WorkflowService.ResumeWorkflowTaskForApp(ctx, appID, user, req.TaskID)
+	if err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// rollbackApplicationWorkflow 手动回滚失败的工作流任务，恢复任务执行前的资源状态
+func (app *applications) rollbackApplicationWorkflow(c *gin.Context) {
+	appID := strings.TrimSpace(c.Param("appID"))
+	if appID == "" {
+		bcode.ReturnError(c, bcode.ErrApplicationNotExist)
+		return
+	}
+	taskID := strings.TrimSpace(c.Param("taskID"))
+	if taskID == "" {
+		bcode.ReturnError(c, bcode.ErrWorkflowTaskNotExist)
+		return
+	}
+	var req apis.RollbackWorkflowRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.Bind(&req); err != nil {
+			klog.Error(err)
+			bcode.ReturnError(c, bcode.ErrWorkflowConfig)
+			return
+		}
+	}
+	user := req.User
+	if user == "" {
+		user = config.DefaultTaskRevoker
+	}
+	ctx := c.Request.Context()
+	resp, err := app.WorkflowService.RollbackWorkflowTaskForApp(ctx, appID, user, taskID)
+	if err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// retryApplicationWorkflow 从失败处重试工作流任务：创建关联原任务的新任务，跳过同一修订版本下已完成的 Job
+func (app *applications) retryApplicationWorkflow(c *gin.Context) {
+	appID := strings.TrimSpace(c.Param("appID"))
+	if appID == "" {
+		bcode.ReturnError(c, bcode.ErrApplicationNotExist)
+		return
+	}
+	taskID := strings.TrimSpace(c.Param("taskID"))
+	if taskID == "" {
+		bcode.ReturnError(c, bcode.ErrWorkflowTaskNotExist)
+		return
+	}
+	var req apis.RetryWorkflowRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.Bind(&req); err != nil {
+			klog.Error(err)
+			bcode.ReturnError(c, bcode.ErrWorkflowConfig)
+			return
+		}
+	}
+	user := req.User
+	if user == "" {
+		user = config.DefaultTaskRevoker
+	}
+	ctx := c.Request.Context()
+	resp, err := app.WorkflowService.RetryWorkflowTaskForApp(ctx, appID, user, taskID)
+	if err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// approveApplicationWorkflow 批准等待审批的工作流任务，任务重新入队后从审批步骤的下一步继续
+func (app *applications) approveApplicationWorkflow(c *gin.Context) {
+	app.decideWorkflowApproval(c, true)
+}
+
+// rejectApplicationWorkflow 拒绝等待审批的工作流任务，任务重新入队后以 reject 状态结束
+func (app *applications) rejectApplicationWorkflow(c *gin.Context) {
+	app.decideWorkflowApproval(c, false)
+}
+
+func (app *applications) decideWorkflowApproval(c *gin.Context, approved bool) {
+	appID := strings.TrimSpace(c.Param("appID"))
+	if appID == "" {
+		bcode.ReturnError(c, bcode.ErrApplicationNotExist)
+		return
+	}
+	taskID := strings.TrimSpace(c.Param("taskID"))
+	if taskID == "" {
+		bcode.ReturnError(c, bcode.ErrWorkflowTaskNotExist)
+		return
+	}
+	var req apis.WorkflowApprovalRequest
+	if err := c.Bind(&req); err != nil {
+		klog.Error(err)
+		bcode.ReturnError(c, bcode.ErrWorkflowConfig)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	req.Step = strings.ToLower(strings.TrimSpace(req.Step))
+	ctx := c.Request.Context()
+	resp, err := app.WorkflowService.DecideWorkflowApproval(ctx, appID, taskID, approved, req)
+	if err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
 func (app *applications) getWorkflowTaskStatus(c *gin.Context) {
 	taskID := strings.TrimSpace(c.Param("taskID"))
 	if taskID == "" {
@@ -276,6 +506,164 @@ func (app *applications) getWorkflowTaskStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// listWorkflowTasks 分页查询应用（或其某个工作流）的任务历史，status 支持逗号分隔的多个状态
+func (app *applications) listWorkflowTasks(c *gin.Context) {
+	appID := strings.TrimSpace(c.Param("appID"))
+	if appID == "" {
+		bcode.ReturnError(c, bcode.ErrApplicationNotExist)
+		return
+	}
+	workflowID := strings.TrimSpace(c.Param("workflowID"))
+	if workflowID == "" {
+		workflowID = strings.TrimSpace(c.Query("workflow_id"))
+	}
+	page, pageSize, err := parsePagination(c)
+	if err != nil {
+		bcode.ReturnError(c, bcode.ErrWorkflowConfig)
+		return
+	}
+	query := apis.ListWorkflowTasksQuery{
+		WorkflowID: workflowID,
+		Status:     splitQueryList(c.Query("status")),
+		Page:       page,
+		PageSize:   pageSize,
+	}
+	ctx := c.Request.Context()
+	resp, err := app.WorkflowService.ListWorkflowTasks(ctx, appID, query)
+	if err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// listWorkflowTaskJobs 返回任务下每个 Job 的执行记录，可按 status 过滤
+func (app *applications) listWorkflowTaskJobs(c *gin.Context) {
+	taskID := strings.TrimSpace(c.Param("taskID"))
+	if taskID == "" {
+		bcode.ReturnError(c, bcode.ErrWorkflowTaskNotExist)
+		return
+	}
+	ctx := c.Request.Context()
+	resp, err := app.WorkflowService.ListTaskJobs(ctx, taskID, splitQueryList(c.Query("status")))
+	if err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// listWorkflowSchedules 列出应用的工作流定时执行
+func (app *applications) listWorkflowSchedules(c *gin.Context) {
+	appID := strings.TrimSpace(c.Param("appID"))
+	if appID == "" {
+		bcode.ReturnError(c, bcode.ErrApplicationNotExist)
+		return
+	}
+	ctx := c.Request.Context()
+	schedules, err := app.WorkflowService.ListWorkflowSchedules(ctx, appID)
+	if err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	resp := make([]*apis.WorkflowSchedule, 0, len(schedules))
+	for _, schedule := range schedules {
+		resp = append(resp, assembler.ConvertWorkflowScheduleModelToDTO(schedule))
+	}
+	c.JSON(http.StatusOK, apis.ListWorkflowSchedulesResponse{Schedules: resp})
+}
+
+// createWorkflowSchedule 为应用的工作流创建定时执行，由 leader 按 cron 表达式入队任务
+func (app *applications) createWorkflowSchedule(c *gin.Context) {
+	appID := strings.TrimSpace(c.Param("appID"))
+	if appID == "" {
+		bcode.ReturnError(c, bcode.ErrApplicationNotExist)
+		return
+	}
+	var req apis.CreateWorkflowScheduleRequest
+	if err := c.Bind(&req); err != nil {
+		klog.Error(err)
+		bcode.ReturnError(c, bcode.ErrWorkflowConfig)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	if req.User == "" {
+		req.User = config.DefaultTaskRevoker
+	}
+	ctx := c.Request.Context()
+	schedule, err := app.WorkflowService.CreateWorkflowSchedule(ctx, appID, req)
+	if err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, assembler.ConvertWorkflowScheduleModelToDTO(schedule))
+}
+
+func (app *applications) getWorkflowSchedule(c *gin.Context) {
+	appID, scheduleID, ok := workflowScheduleParams(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	schedule, err := app.WorkflowService.GetWorkflowSchedule(ctx, appID, scheduleID)
+	if err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, assembler.ConvertWorkflowScheduleModelToDTO(schedule))
+}
+
+// updateWorkflowSchedule 修改定时执行的 cron、时区、并发策略、参数或启停状态
+func (app *applications) updateWorkflowSchedule(c *gin.Context) {
+	appID, scheduleID, ok := workflowScheduleParams(c)
+	if !ok {
+		return
+	}
+	var req apis.UpdateWorkflowScheduleRequest
+	if err := c.Bind(&req); err != nil {
+		klog.Error(err)
+		bcode.ReturnError(c, bcode.ErrWorkflowConfig)
+		return
+	}
+	ctx := c.Request.Context()
+	schedule, err := app.WorkflowService.UpdateWorkflowSchedule(ctx, appID, scheduleID, req)
+	if err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, assembler.ConvertWorkflowScheduleModelToDTO(schedule))
+}
+
+func (app *applications) deleteWorkflowSchedule(c *gin.Context) {
+	appID, scheduleID, ok := workflowScheduleParams(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	if err := app.WorkflowService.DeleteWorkflowSchedule(ctx, appID, scheduleID); err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"id": scheduleID})
+}
+
+func workflowScheduleParams(c *gin.Context) (string, string, bool) {
+	appID := strings.TrimSpace(c.Param("appID"))
+	if appID == "" {
+		bcode.ReturnError(c, bcode.ErrApplicationNotExist)
+		return "", "", false
+	}
+	scheduleID := strings.TrimSpace(c.Param("scheduleID"))
+	if scheduleID == "" {
+		bcode.ReturnError(c, bcode.ErrWorkflowScheduleNotExist)
+		return "", "", false
+	}
+	return appID, scheduleID, true
+}
+
 // updateVersion 更新应用版本
 func (app *applications) updateVersion(c *gin.Context) {
 	appID := strings.TrimSpace(c.Param("appID"))
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"kubemin-cli/pkg/apiserver/config"
 	"kubemin-cli/pkg/apiserver/domain/model"
 	apisv1 "kubemin-cli/pkg/apiserver/interfaces/api/dto/v1"
 )
@@ -23,6 +24,8 @@ func ConvertAppModelToBase(app *model.Applications, workflowID string) *apisv1.A
 		Icon:        app.Icon,
 		WorkflowID:  workflowID,
 		TmpEnable:   app.TmpEnable,
+
+		ConcurrencyPolicy: config.ParseAppConcurrencyPolicy(string(app.ConcurrencyPolicy)),
 	}
 	return appBase
 }
@@ -49,6 +52,7 @@ func ConvertWorkflowModelToDTO(workflow *model.Workflow) (*apisv1.ApplicationWor
 		CreateTime:   workflow.CreateTime,
 		UpdateTime:   workflow.UpdateTime,
 		WorkflowType: workflow.WorkflowType,
+		RollbackMode: workflow.RollbackMode,
 	}, nil
 }
 
@@ -92,10 +96,25 @@ func convertWorkflowSteps(raw *model.JSONStruct) ([]apisv1.WorkflowStepDetail, e
 			continue
 		}
 		detail := apisv1.WorkflowStepDetail{
-			Name:         step.Name,
-			WorkflowType: step.WorkflowType,
-			Mode:         step.Mode,
-			Components:   flattenPolicies(step.Properties),
+			Name:           step.Name,
+			WorkflowType:   step.WorkflowType,
+			Mode:           step.Mode,
+			Components:     flattenPolicies(step.Properties),
+			DependsOn:      step.DependsOn,
+			Condition:      step.Condition,
+			TimeoutSeconds: step.TimeoutSeconds,
+		}
+		if step.Retry != nil {
+			detail.Retry = &apisv1.RetryPolicy{
+				Attempts:      step.Retry.Attempts,
+				DelaySeconds:  step.Retry.DelaySeconds,
+				BackoffFactor: step.Retry.BackoffFactor,
+				MaxDelay:      step.Retry.MaxDelay,
+			}
+		}
+		if step.Spec != nil {
+			spec := apisv1.StepSpec(*step.Spec)
+			detail.Spec = &spec
 		}
 		if len(step.SubSteps) > 0 {
 			subDetails := make([]apisv1.WorkflowSubStepDetail, 0, len(step.SubSteps))
@@ -146,3 +165,72 @@ func flattenPolicies(policies []model.Policies) []string {
 	}
 	return components
 }
+
+// ConvertWorkflowScheduleModelToDTO converts a workflow schedule into its API representation.
+func ConvertWorkflowScheduleModelToDTO(schedule *model.WorkflowSchedule) *apisv1.WorkflowSchedule {
+	if schedule == nil {
+		return nil
+	}
+	return &apisv1.WorkflowSchedule{
+		ID:                schedule.ID,
+		Name:              schedule.Name,
+		AppID:             schedule.AppID,
+		WorkflowID:        schedule.WorkflowID,
+		Cron:              schedule.Cron,
+		TimeZone:          schedule.TimeZone,
+		ConcurrencyPolicy: schedule.ConcurrencyPolicy,
+		Inputs:            schedule.Inputs,
+		Enabled:           schedule.Status == config.StatusEnabled,
+		Creator:           schedule.Creator,
+		LastRunTime:       schedule.LastRunTime,
+		NextRunTime:       schedule.NextRunTime,
+		LastTaskID:        schedule.LastTaskID,
+		LastResult:        schedule.LastResult,
+		CreateTime:        schedule.CreateTime,
+		UpdateTime:        schedule.UpdateTime,
+	}
+}
+
+// ConvertWebhookModelToDTO converts a webhook into its API representation without the secret.
+func ConvertWebhookModelToDTO(webhook *model.WorkflowWebhook) *apisv1.Webhook {
+	if webhook == nil {
+		return nil
+	}
+	dto := &apisv1.Webhook{
+		ID:         webhook.ID,
+		Name:       webhook.Name,
+		AppID:      webhook.AppID,
+		WorkflowID: webhook.WorkflowID,
+		Enabled:    webhook.Status == config.StatusEnabled,
+		Creator:    webhook.Creator,
+		CreateTime: webhook.CreateTime,
+		UpdateTime: webhook.UpdateTime,
+	}
+	if webhook.Mapping != nil {
+		dto.Mapping = apisv1.WebhookMapping{
+			Components:  webhook.Mapping.Components,
+			ImagePath:   webhook.Mapping.ImagePath,
+			TagPath:     webhook.Mapping.TagPath,
+			VersionPath: webhook.Mapping.VersionPath,
+			Strategy:    webhook.Mapping.Strategy,
+		}
+	}
+	return dto
+}
+
+// ConvertWebhookDeliveryModelToDTO converts a webhook delivery log entry into its API representation.
+func ConvertWebhookDeliveryModelToDTO(delivery *model.WebhookDelivery) *apisv1.WebhookDelivery {
+	if delivery == nil {
+		return nil
+	}
+	return &apisv1.WebhookDelivery{
+		ID:         delivery.ID,
+		Result:     string(delivery.Result),
+		Reason:     delivery.Reason,
+		Version:    delivery.Version,
+		TaskID:     delivery.TaskID,
+		Source:     delivery.Source,
+		Payload:    delivery.Payload,
+		CreateTime: delivery.CreateTime,
+	}
+}
@@ -21,6 +21,8 @@ type ApplicationBase struct {
 	Icon        string    `json:"icon"`
 	WorkflowID  string    `json:"workflow_id"`
 	TmpEnable   bool      `json:"tmp_enable"`
+
+	ConcurrencyPolicy config.AppConcurrencyPolicy `json:"concurrency_policy"`
 }
 
 // ProjectBase project base model
@@ -54,6 +56,9 @@ type CreateApplicationsRequest struct {
 
 	// TmpEnable 标记该应用是否允许作为模板被引用
 	TmpEnable *bool `json:"tmp_enable,omitempty"`
+
+	// ConcurrencyPolicy 同一应用多个工作流任务的串行策略：queue（默认）、reject、supersede
+	ConcurrencyPolicy string `json:"concurrency_policy,omitempty"`
 }
 
 type CreateComponentRequest struct {
@@ -73,12 +78,38 @@ type TemplateRef struct {
 }
 
 type CreateWorkflowStepRequest struct {
-	Name         string                         `json:"name"`
-	WorkflowType config.JobType                 `json:"job_type,omitempty"`
-	Properties   WorkflowProperties             `json:"properties,omitempty"`
-	Components   []string                       `json:"components,omitempty"`
-	Mode         string                         `json:"mode,omitempty"`
-	SubSteps     []CreateWorkflowSubStepRequest `json:"sub_steps,omitempty"`
+	Name           string                         `json:"name"`
+	WorkflowType   config.JobType                 `json:"job_type,omitempty"`
+	Properties     WorkflowProperties             `json:"properties,omitempty"`
+	Components     []string                       `json:"components,omitempty"`
+	Mode           string                         `json:"mode,omitempty"`
+	SubSteps       []CreateWorkflowSubStepRequest `json:"sub_steps,omitempty"`
+	DependsOn      []string                       `json:"depends_on,omitempty"`
+	Retry          *RetryPolicy                   `json:"retry,omitempty"`
+	Condition      string                         `json:"condition,omitempty"`       //条件表达式，为假时跳过该步骤
+	TimeoutSeconds int                            `json:"timeout_seconds,omitempty"` //步骤执行时限（秒）
+	Spec           *StepSpec                      `json:"spec,omitempty"`            //内置步骤（wait、http-check、notify）的参数
+}
+
+// StepSpec 内置步骤的参数
+type StepSpec struct {
+	DurationSeconds int               `json:"duration_seconds,omitempty"`
+	Until           string            `json:"until,omitempty"`
+	URL             string            `json:"url,omitempty"`
+	Method          string            `json:"method,omitempty"`
+	Headers         map[string]string `json:"headers,omitempty"`
+	Body            string            `json:"body,omitempty"`
+	ExpectStatus    []int             `json:"expect_status,omitempty"`
+	ExpectBody      string            `json:"expect_body,omitempty"`
+	IntervalSeconds int               `json:"interval_seconds,omitempty"`
+}
+
+// RetryPolicy 步骤级重试策略；attempts 包含首次执行
+type RetryPolicy struct {
+	Attempts      int     `json:"attempts"`
+	DelaySeconds  int     `json:"delay_seconds"`
+	BackoffFactor float64 `json:"backoff_factor,omitempty"`
+	MaxDelay      int     `json:"max_delay,omitempty"`
 }
 
 // ListApplicationResponse list applications by query params
@@ -177,6 +208,8 @@ type UpdateApplicationWorkflowRequest struct {
 	Name       string                      `json:"name,omitempty"`
 	Alias      string                      `json:"alias,omitempty"`
 	Workflow   []CreateWorkflowStepRequest `json:"workflow" validate:"required,min=1,dive"`
+	// RollbackMode 失败后的回滚方式：none（默认）、automatic、manual；为空时保持原配置
+	RollbackMode string `json:"rollback_mode,omitempty"`
 }
 
 type UpdateWorkflowResponse struct {
@@ -184,7 +217,9 @@ type UpdateWorkflowResponse struct {
 }
 
 type ExecWorkflowRequest struct {
-	WorkflowID string `json:"workflow_id" validate:"checkname"`
+	WorkflowID string                 `json:"workflow_id" validate:"checkname"`
+	Inputs     map[string]interface{} `json:"inputs,omitempty"`                            //执行参数，步骤条件中以 inputs.<name> 引用
+	Priority   int                    `json:"priority,omitempty" validate:"min=0,max=100"` //调度优先级 0-100，数值越大越先派发
 }
 
 type ExecWorkflowResponse struct {
@@ -202,14 +237,366 @@ type CancelWorkflowResponse struct {
 	Status string `json:"status"`
 }
 
+// PauseWorkflowRequest 暂停运行中的工作流任务：当前优先级分组执行完后不再启动新的 Job
+type PauseWorkflowRequest struct {
+	TaskID string `json:"task_id" validate:"required"`
+	User   string `json:"user,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ResumeWorkflowRequest 恢复已暂停的工作流任务，任务重新入队后从暂停位置继续执行
+type ResumeWorkflowRequest struct {
+	TaskID string `json:"task_id" validate:"required"`
+	User   string `json:"user,omitempty"`
+}
+
+// PauseWorkflowResponse 返回暂停/恢复后的任务状态；运行中的任务在当前分组结束后才进入 pause 状态
+type PauseWorkflowResponse struct {
+	TaskID string `json:"task_id"`
+	Status string `json:"status"`
+}
+
+// DrainStatusResponse 实例排空进度；排空开始后不再领取新任务，宽限期后仍在运行的任务交还队列
+type DrainStatusResponse struct {
+	Draining    bool       `json:"draining"`
+	Since       *time.Time `json:"since,omitempty"`
+	GracePeriod string     `json:"grace_period"`
+	InFlight    []string   `json:"in_flight"`
+	Finished    bool       `json:"finished"`
+}
+
+// DeadLetterResponse 一条无法投递而转入死信的分发消息
+type DeadLetterResponse struct {
+	ID         string    `json:"id"`
+	MessageID  string    `json:"message_id"`
+	Payload    string    `json:"payload"`
+	Error      string    `json:"error"`
+	Deliveries int64     `json:"deliveries"`
+	DeadAt     time.Time `json:"dead_at"`
+}
+
+// ListDeadLettersResponse 死信列表，按转入时间升序
+type ListDeadLettersResponse struct {
+	DeadLetters []DeadLetterResponse `json:"dead_letters"`
+}
+
+// ReplayDeadLetterResponse 重放后的死信；消息重新进入分发队列，死信被移除
+type ReplayDeadLetterResponse struct {
+	ID        string `json:"id"`
+	MessageID string `json:"message_id"`
+}
+
+// PurgeDeadLettersResponse 清除的死信数量
+type PurgeDeadLettersResponse struct {
+	Purged int64 `json:"purged"`
+}
+
+// WorkflowApprovalRequest 审批或拒绝等待审批的工作流任务
+type WorkflowApprovalRequest struct {
+	Approver string `json:"approver" validate:"required"`
+	Comment  string `json:"comment,omitempty"`
+	// Step 待审批的步骤名称；仅有一个待审批步骤时可省略
+	Step string `json:"step,omitempty"`
+}
+
+type WorkflowApprovalResponse struct {
+	TaskID     string `json:"task_id"`
+	Step       string `json:"step"`
+	Status     string `json:"status"`      //审批结果：passed 或 reject
+	TaskStatus string `json:"task_status"` //审批后的任务状态
+}
+
+// CreateWorkflowScheduleRequest 创建工作流定时执行；cron 为五段式表达式或 @daily 等描述符
+type CreateWorkflowScheduleRequest struct {
+	Name              string                 `json:"name" validate:"required"`
+	WorkflowID        string                 `json:"workflow_id" validate:"required"`
+	Cron              string                 `json:"cron" validate:"required"`
+	TimeZone          string                 `json:"time_zone,omitempty"`          //IANA 时区，默认 UTC
+	ConcurrencyPolicy string                 `json:"concurrency_policy,omitempty"` //allow、forbid（默认）、replace
+	Inputs            map[string]interface{} `json:"inputs,omitempty"`
+	Disabled          bool                   `json:"disabled,omitempty"`
+	User              string                 `json:"user,omitempty"`
+}
+
+// UpdateWorkflowScheduleRequest 修改定时执行，未填写的字段保持原值
+type UpdateWorkflowScheduleRequest struct {
+	Name              string                 `json:"name,omitempty"`
+	Cron              string                 `json:"cron,omitempty"`
+	TimeZone          string                 `json:"time_zone,omitempty"`
+	ConcurrencyPolicy string                 `json:"concurrency_policy,omitempty"`
+	Inputs            map[string]interface{} `json:"inputs,omitempty"`
+	Enabled           *bool                  `json:"enabled,omitempty"`
+}
+
+type WorkflowSchedule struct {
+	ID                string                           `json:"id"`
+	Name              string                           `json:"name"`
+	AppID             string                           `json:"app_id"`
+	WorkflowID        string                           `json:"workflow_id"`
+	Cron              string                           `json:"cron"`
+	TimeZone          string                           `json:"time_zone,omitempty"`
+	ConcurrencyPolicy config.ScheduleConcurrencyPolicy `json:"concurrency_policy"`
+	Inputs            map[string]interface{}           `json:"inputs,omitempty"`
+	Enabled           bool                             `json:"enabled"`
+	Creator           string                           `json:"creator,omitempty"`
+	LastRunTime       int64                            `json:"last_run_time,omitempty"`
+	NextRunTime       int64                            `json:"next_run_time,omitempty"`
+	LastTaskID        string                           `json:"last_task_id,omitempty"`
+	LastResult        string                           `json:"last_result,omitempty"`
+	CreateTime        time.Time                        `json:"create_time"`
+	UpdateTime        time.Time                        `json:"update_time"`
+}
+
+type ListWorkflowSchedulesResponse struct {
+	Schedules []*WorkflowSchedule `json:"schedules"`
+}
+
+// WebhookMapping 负载到版本更新参数的映射，路径以 . 分隔，数组使用下标
+type WebhookMapping struct {
+	Components  []string `json:"components" validate:"required,min=1"`
+	ImagePath   string   `json:"image_path,omitempty"`   //完整镜像地址的路径
+	TagPath     string   `json:"tag_path,omitempty"`     //镜像 tag 的路径
+	VersionPath string   `json:"version_path,omitempty"` //版本号的路径，默认使用 tag
+	Strategy    string   `json:"strategy,omitempty"`
+}
+
+// CreateWebhookRequest 创建入站 webhook；secret 为空时自动生成
+type CreateWebhookRequest struct {
+	Name       string         `json:"name" validate:"required"`
+	WorkflowID string         `json:"workflow_id,omitempty"` //为空时执行应用的默认工作流
+	Secret     string         `json:"secret,omitempty"`
+	Mapping    WebhookMapping `json:"mapping"`
+	Disabled   bool           `json:"disabled,omitempty"`
+	User       string         `json:"user,omitempty"`
+}
+
+type Webhook struct {
+	ID         string         `json:"id"`
+	Name       string         `json:"name"`
+	AppID      string         `json:"app_id"`
+	WorkflowID string         `json:"workflow_id,omitempty"`
+	Mapping    WebhookMapping `json:"mapping"`
+	Enabled    bool           `json:"enabled"`
+	Creator    string         `json:"creator,omitempty"`
+	CreateTime time.Time      `json:"create_time"`
+	UpdateTime time.Time      `json:"update_time"`
+}
+
+// CreateWebhookResponse 密钥与签名 URL 只在创建时返回
+type CreateWebhookResponse struct {
+	Webhook
+	Secret     string `json:"secret"`
+	TriggerURL string `json:"trigger_url"` //带 token 的触发地址，供无法对请求体签名的调用方使用
+}
+
+type ListWebhooksResponse struct {
+	Webhooks []*Webhook `json:"webhooks"`
+}
+
+// WebhookTriggerRequest 一次入站投递的原始内容
+type WebhookTriggerRequest struct {
+	Body      []byte
+	Signature string
+	Token     string
+	Source    string
+}
+
+type WebhookTriggerResponse struct {
+	DeliveryID int    `json:"delivery_id"`
+	Result     string `json:"result"`
+	Reason     string `json:"reason,omitempty"`
+	Version    string `json:"version,omitempty"`
+	TaskID     string `json:"task_id,omitempty"`
+}
+
+type WebhookDelivery struct {
+	ID         int       `json:"id"`
+	Result     string    `json:"result"`
+	Reason     string    `json:"reason,omitempty"`
+	Version    string    `json:"version,omitempty"`
+	TaskID     string    `json:"task_id,omitempty"`
+	Source     string    `json:"source,omitempty"`
+	Payload    string    `json:"payload,omitempty"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+type ListWebhookDeliveriesResponse struct {
+	Deliveries []*WebhookDelivery `json:"deliveries"`
+}
+
+type RollbackWorkflowRequest struct {
+	User string `json:"user,omitempty"`
+}
+
+type RollbackWorkflowResponse struct {
+	TaskID    string `json:"task_id"`
+	Status    string `json:"status"`
+	Resources int    `json:"resources"` //恢复的资源数量
+}
+
+// RetryWorkflowRequest 从失败处重试工作流任务
+type RetryWorkflowRequest struct {
+	User string `json:"user,omitempty"`
+}
+
+type RetryWorkflowResponse struct {
+	TaskID      string `json:"task_id"`      //新任务ID
+	RetryOf     string `json:"retry_of"`     //原失败任务ID
+	SkippedJobs int    `json:"skipped_jobs"` //沿用原任务结果、不再执行的 Job 数量
+}
+
+// DryRunWorkflowRequest 以 DryRun=All 提交工作流生成的全部对象，只做准入校验不落地
+type DryRunWorkflowRequest struct {
+	WorkflowID string                 `json:"workflow_id" validate:"checkname"`
+	Inputs     map[string]interface{} `json:"inputs,omitempty"`
+}
+
+type DryRunWorkflowResponse struct {
+	TaskID string            `json:"task_id"` //记录本次 dry-run 结果的测试任务ID
+	Passed bool              `json:"passed"`  //所有 Job 均被 API Server 接受
+	Jobs   []DryRunJobResult `json:"jobs"`
+}
+
+// WorkflowPlanResponse 工作流执行前的变更预览，按资源列出 create/update/no-op/delete
+type WorkflowPlanResponse struct {
+	WorkflowID string                 `json:"workflow_id"`
+	Summary    map[string]int         `json:"summary"` //按 action 统计的资源数量
+	Resources  []WorkflowPlanResource `json:"resources"`
+}
+
+type WorkflowPlanResource struct {
+	Step      string                    `json:"step,omitempty"`
+	Job       string                    `json:"job,omitempty"`
+	Kind      string                    `json:"kind"`
+	Namespace string                    `json:"namespace,omitempty"`
+	Name      string                    `json:"name"`
+	Action    string                    `json:"action"`
+	Changes   []WorkflowPlanFieldChange `json:"changes,omitempty"`
+	Error     string                    `json:"error,omitempty"`
+}
+
+// WorkflowPlanFieldChange 字段差异，只比较期望对象中声明的字段，Secret 的值会被隐藏
+type WorkflowPlanFieldChange struct {
+	Path    string      `json:"path"`
+	Live    interface{} `json:"live,omitempty"`
+	Desired interface{} `json:"desired,omitempty"`
+}
+
+// DryRunJobResult 单个 Job 的 dry-run 结果，Error 为 API Server 返回的准入错误
+type DryRunJobResult struct {
+	Step      string `json:"step"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Resource  string `json:"resource,omitempty"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
+
 type TaskStatusResponse struct {
+	TaskID        string                  `json:"task_id"`
+	Status        string                  `json:"status"`
+	WorkflowID    string                  `json:"workflow_id,omitempty"`
+	WorkflowName  string                  `json:"workflow_name,omitempty"`
+	AppID         string                  `json:"app_id,omitempty"`
+	Type          config.WorkflowTaskType `json:"type,omitempty"`
+	Components    []ComponentTaskStatus   `json:"components,omitempty"`
+	Steps         []StepTaskStatus        `json:"steps,omitempty"`
+	Approvals     []ApprovalTaskStatus    `json:"approvals,omitempty"`
+	Deadline      int64                   `json:"deadline,omitempty"`       //任务截止时间（Unix 秒）
+	TimeoutStep   string                  `json:"timeout_step,omitempty"`   //超时发生时所在的步骤
+	Priority      int                     `json:"priority"`                 //调度优先级
+	QueuePosition int                     `json:"queue_position,omitempty"` //等待中任务在派发队列中的位置，从 1 开始
+}
+
+// ListWorkflowTasksQuery 任务历史查询条件，Status 为空时返回全部状态
+type ListWorkflowTasksQuery struct {
+	WorkflowID string
+	Status     []string
+	Page       int
+	PageSize   int
+}
+
+type ListWorkflowTasksResponse struct {
+	Tasks    []WorkflowTaskSummary `json:"tasks"`
+	Total    int64                 `json:"total"`
+	Page     int                   `json:"page"`
+	PageSize int                   `json:"page_size"`
+}
+
+// WorkflowTaskSummary 任务历史中的一条记录，EndTime 与 Duration 仅在任务结束后填写
+type WorkflowTaskSummary struct {
 	TaskID       string                  `json:"task_id"`
-	Status       string                  `json:"status"`
-	WorkflowID   string                  `json:"workflow_id,omitempty"`
+	WorkflowID   string                  `json:"workflow_id"`
 	WorkflowName string                  `json:"workflow_name,omitempty"`
-	AppID        string                  `json:"app_id,omitempty"`
 	Type         config.WorkflowTaskType `json:"type,omitempty"`
-	Components   []ComponentTaskStatus   `json:"components,omitempty"`
+	Status       string                  `json:"status"`
+	TaskCreator  string                  `json:"task_creator,omitempty"`
+	TaskRevoker  string                  `json:"task_revoker,omitempty"`
+	Priority     int                     `json:"priority"`
+	RetryOf      string                  `json:"retry_of,omitempty"`
+	StartTime    int64                   `json:"start_time,omitempty"` //Unix 秒
+	EndTime      int64                   `json:"end_time,omitempty"`   //Unix 秒
+	Duration     int64                   `json:"duration,omitempty"`   //秒
+}
+
+type ListTaskJobsResponse struct {
+	TaskID string          `json:"task_id"`
+	Jobs   []TaskJobDetail `json:"jobs"`
+}
+
+// TaskJobDetail 任务中一次 Job 执行的记录，重试时每次尝试各有一条
+type TaskJobDetail struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	ServiceType string `json:"service_type,omitempty"`
+	Status      string `json:"status"`
+	Error       string `json:"error,omitempty"`
+	Attempt     int    `json:"attempt"`
+	StartTime   int64  `json:"start_time,omitempty"`
+	EndTime     int64  `json:"end_time,omitempty"`
+	Duration    int64  `json:"duration,omitempty"` //秒
+}
+
+// TaskProgressEvent 任务进度流中的一条事件：snapshot 为连接时的当前状态，task/job 为之后的状态变化
+type TaskProgressEvent struct {
+	Type      string              `json:"type"`
+	TaskID    string              `json:"task_id"`
+	Status    string              `json:"status"`
+	Snapshot  *TaskStatusResponse `json:"snapshot,omitempty"`
+	Job       *TaskJobProgress    `json:"job,omitempty"`
+	Timestamp int64               `json:"timestamp"` //Unix 毫秒
+}
+
+type TaskJobProgress struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+	Attempt   int    `json:"attempt"`
+	StartTime int64  `json:"start_time,omitempty"`
+	EndTime   int64  `json:"end_time,omitempty"`
+}
+
+// ApprovalTaskStatus describes an approval gate reached by the task.
+type ApprovalTaskStatus struct {
+	Step       string `json:"step"`
+	Status     string `json:"status"`
+	Approver   string `json:"approver,omitempty"`
+	Comment    string `json:"comment,omitempty"`
+	CreateTime int64  `json:"create_time,omitempty"`
+	DecideTime int64  `json:"decide_time,omitempty"`
+}
+
+// StepTaskStatus describes one node of the workflow step graph for a task.
+type StepTaskStatus struct {
+	Name       string   `json:"name"`
+	DependsOn  []string `json:"depends_on,omitempty"`
+	Status     string   `json:"status"`
+	Components []string `json:"components,omitempty"`
+	Condition  string   `json:"condition,omitempty"`
 }
 
 type ComponentTaskStatus struct {
@@ -219,6 +606,53 @@ type ComponentTaskStatus struct {
 	Error     string `json:"error,omitempty"`
 	StartTime int64  `json:"start_time,omitempty"`
 	EndTime   int64  `json:"end_time,omitempty"`
+	Attempts  int    `json:"attempts,omitempty"`
+	// Diagnostics is captured when a workload job of the latest attempt failed to become ready.
+	Diagnostics []JobDiagnostics `json:"diagnostics,omitempty"`
+}
+
+// JobDiagnostics describes the workload state captured when a deploy job failed.
+type JobDiagnostics struct {
+	JobName   string             `json:"job_name"`
+	Kind      string             `json:"kind"`
+	Namespace string             `json:"namespace"`
+	Name      string             `json:"name"`
+	Pods      []PodDiagnostics   `json:"pods,omitempty"`
+	Events    []EventDiagnostics `json:"events,omitempty"`
+	Error     string             `json:"error,omitempty"`
+}
+
+// PodDiagnostics describes the phase, unmet conditions and containers of one pod.
+type PodDiagnostics struct {
+	Name       string                 `json:"name"`
+	Phase      string                 `json:"phase"`
+	Reason     string                 `json:"reason,omitempty"`
+	Message    string                 `json:"message,omitempty"`
+	Conditions []string               `json:"conditions,omitempty"`
+	Containers []ContainerDiagnostics `json:"containers,omitempty"`
+}
+
+// ContainerDiagnostics describes why a container is not ready, with its last log lines.
+type ContainerDiagnostics struct {
+	Name              string `json:"name"`
+	Ready             bool   `json:"ready"`
+	RestartCount      int32  `json:"restart_count"`
+	WaitingReason     string `json:"waiting_reason,omitempty"`
+	WaitingMessage    string `json:"waiting_message,omitempty"`
+	TerminatedReason  string `json:"terminated_reason,omitempty"`
+	TerminatedMessage string `json:"terminated_message,omitempty"`
+	ExitCode          int32  `json:"exit_code,omitempty"`
+	Logs              string `json:"logs,omitempty"`
+}
+
+// EventDiagnostics is one Kubernetes Event of the workload, its ReplicaSets or pods.
+type EventDiagnostics struct {
+	Object   string `json:"object"`
+	Type     string `json:"type"`
+	Reason   string `json:"reason"`
+	Message  string `json:"message"`
+	Count    int32  `json:"count,omitempty"`
+	LastSeen int64  `json:"last_seen,omitempty"`
 }
 
 type ListApplicationWorkflowsResponse struct {
@@ -242,14 +676,20 @@ type ApplicationWorkflow struct {
 	CreateTime   time.Time               `json:"create_time"`
 	UpdateTime   time.Time               `json:"update_time"`
 	WorkflowType config.WorkflowTaskType `json:"workflow_type"`
+	RollbackMode config.RollbackMode     `json:"rollback_mode,omitempty"`
 }
 
 type WorkflowStepDetail struct {
-	Name         string                  `json:"name"`
-	WorkflowType config.JobType          `json:"workflow_type,omitempty"`
-	Mode         config.WorkflowMode     `json:"mode,omitempty"`
-	Components   []string                `json:"components,omitempty"`
-	SubSteps     []WorkflowSubStepDetail `json:"sub_steps,omitempty"`
+	Name           string                  `json:"name"`
+	WorkflowType   config.JobType          `json:"workflow_type,omitempty"`
+	Mode           config.WorkflowMode     `json:"mode,omitempty"`
+	Components     []string                `json:"components,omitempty"`
+	SubSteps       []WorkflowSubStepDetail `json:"sub_steps,omitempty"`
+	DependsOn      []string                `json:"depends_on,omitempty"`
+	Retry          *RetryPolicy            `json:"retry,omitempty"`
+	Condition      string                  `json:"condition,omitempty"`
+	TimeoutSeconds int                     `json:"timeout_seconds,omitempty"`
+	Spec           *StepSpec               `json:"spec,omitempty"`
 }
 
 type WorkflowSubStepDetail struct {
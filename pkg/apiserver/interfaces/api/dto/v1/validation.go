@@ -61,6 +61,7 @@ const (
 	ErrCodeInvalidComponentType = "INVALID_COMPONENT_TYPE"
 	ErrCodeMissingImage         = "MISSING_IMAGE"
 	ErrCodeDuplicateComponent   = "DUPLICATE_COMPONENT"
+	ErrCodeInvalidJobConfig     = "INVALID_JOB_CONFIG"
 
 	// Traits errors
 	ErrCodeInvalidTraitConfig    = "INVALID_TRAIT_CONFIG"
@@ -83,4 +84,10 @@ const (
 	ErrCodeEmptyWorkflowStep       = "EMPTY_WORKFLOW_STEP"
 	ErrCodeDuplicateWorkflowStep   = "DUPLICATE_WORKFLOW_STEP"
 	ErrCodeWorkflowStepNoComponent = "WORKFLOW_STEP_NO_COMPONENT"
+	ErrCodeInvalidStepDependency   = "INVALID_STEP_DEPENDENCY"
+	ErrCodeInvalidRetryPolicy      = "INVALID_RETRY_POLICY"
+	ErrCodeInvalidApprovalStep     = "INVALID_APPROVAL_STEP"
+	ErrCodeInvalidStepCondition    = "INVALID_STEP_CONDITION"
+	ErrCodeInvalidStepTimeout      = "INVALID_STEP_TIMEOUT"
+	ErrCodeInvalidBuiltinStep      = "INVALID_BUILTIN_STEP"
 )
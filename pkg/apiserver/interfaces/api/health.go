@@ -7,6 +7,7 @@ import (
 	"k8s.io/klog/v2"
 
 	msg "kubemin-cli/pkg/apiserver/infrastructure/messaging"
+	"kubemin-cli/pkg/apiserver/workflow/drain"
 )
 
 func init() {
@@ -15,7 +16,8 @@ func init() {
 
 // health provides health check endpoints for Kubernetes probes.
 type health struct {
-	Queue msg.Queue `inject:"queue"`
+	Queue msg.Queue         `inject:"queue"`
+	Drain *drain.Controller `inject:""`
 }
 
 // GetName returns the API name for registration.
@@ -41,9 +43,18 @@ func (h *health) healthCheck(c *gin.Context) {
 
 // readinessCheck checks if the server is ready to accept traffic.
 // It verifies connectivity to dependencies like the message queue.
+// A draining instance reports not ready so it is taken out of the service.
 func (h *health) readinessCheck(c *gin.Context) {
 	ctx := c.Request.Context()
 
+	if h.Drain != nil && h.Drain.Draining() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status": "not ready",
+			"error":  "draining",
+		})
+		return
+	}
+
 	// Check queue connectivity
 	if h.Queue != nil {
 		if _, ok := h.Queue.(*msg.NoopQueue); !ok {
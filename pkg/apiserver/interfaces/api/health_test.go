@@ -12,9 +12,11 @@ import (
 	"github.com/stretchr/testify/require"
 
 	msg "kubemin-cli/pkg/apiserver/infrastructure/messaging"
+	"kubemin-cli/pkg/apiserver/workflow/drain"
 )
 
 type mockHealthQueue struct {
+	msg.NoopQueue
 	statsError error
 }
 
@@ -142,3 +144,20 @@ func TestHealthGetName(t *testing.T) {
 	h := &health{}
 	require.Equal(t, "health", h.GetName())
 }
+
+func TestReadinessCheckWhileDraining(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	controller := drain.NewController(time.Minute)
+	controller.Begin()
+	h := &health{Queue: &mockHealthQueue{}, Drain: controller}
+	r := gin.New()
+	r.GET("/ready", h.readinessCheck)
+
+	req := httptest.NewRequest(http.MethodGet, "/ready", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusServiceUnavailable, resp.Code)
+	require.Contains(t, resp.Body.String(), "draining")
+}
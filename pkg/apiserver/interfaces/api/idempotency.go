@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/service"
+	"kubemin-cli/pkg/apiserver/utils/bcode"
+)
+
+const (
+	idempotencyScopeCreateApplication = "applications.create"
+	idempotencyScopeExecWorkflow      = "workflow.exec:"
+)
+
+// idempotent 执行写请求并返回 run 的结果。请求带 Idempotency-Key 时，有效期内相同的请求直接重放首次的响应，
+// 同一个 key 对应不同请求时返回冲突；失败的请求不保存
+func idempotent(c *gin.Context, svc service.IdempotencyService, scope string, request interface{}, run func(ctx context.Context) (interface{}, error)) {
+	ctx := c.Request.Context()
+	key := strings.TrimSpace(c.GetHeader(config.IdempotencyKeyHeader))
+	if key == "" || svc == nil {
+		resp, err := run(ctx)
+		if err != nil {
+			bcode.ReturnError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+	replay, err := svc.Begin(ctx, scope, key, request)
+	if err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	if replay != nil {
+		c.Header(config.IdempotencyReplayedHeader, "true")
+		c.Data(http.StatusOK, "application/json; charset=utf-8", replay)
+		return
+	}
+	resp, err := run(ctx)
+	// 客户端断开也要记录结果，否则重试会被当成处理中
+	saveCtx := context.WithoutCancel(ctx)
+	if err != nil {
+		svc.Release(saveCtx, scope, key)
+		bcode.ReturnError(c, err)
+		return
+	}
+	if err := svc.Complete(saveCtx, scope, key, resp); err != nil {
+		klog.Errorf("save idempotent response of %s failed: %v", scope, err)
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"kubemin-cli/pkg/apiserver/config"
+	apis "kubemin-cli/pkg/apiserver/interfaces/api/dto/v1"
+	"kubemin-cli/pkg/apiserver/utils/bcode"
+)
+
+type fakeIdempotencyRecord struct {
+	request  string
+	response []byte
+}
+
+// fakeIdempotencyService keeps records in memory with the conflict semantics of the real service.
+type fakeIdempotencyService struct {
+	records  map[string]*fakeIdempotencyRecord
+	released int
+}
+
+func (f *fakeIdempotencyService) Begin(_ context.Context, scope, key string, request interface{}) ([]byte, error) {
+	payload, _ := json.Marshal(request)
+	record, ok := f.records[scope+"/"+key]
+	if !ok {
+		f.records[scope+"/"+key] = &fakeIdempotencyRecord{request: string(payload)}
+		return nil, nil
+	}
+	if record.request != string(payload) {
+		return nil, bcode.ErrIdempotencyKeyConflict
+	}
+	if record.response == nil {
+		return nil, bcode.ErrIdempotencyKeyInProgress
+	}
+	return record.response, nil
+}
+
+func (f *fakeIdempotencyService) Complete(_ context.Context, scope, key string, response interface{}) error {
+	payload, err := json.Marshal(response)
+	f.records[scope+"/"+key].response = payload
+	return err
+}
+
+func (f *fakeIdempotencyService) Release(_ context.Context, scope, key string) {
+	f.released++
+	delete(f.records, scope+"/"+key)
+}
+
+func TestExecApplicationWorkflowIdempotencyKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := &fakeWorkflowService{}
+	idem := &fakeIdempotencyService{records: map[string]*fakeIdempotencyRecord{}}
+	appHandler := &applications{
+		ApplicationService: noopApplicationsService{},
+		WorkflowService:    svc,
+		IdempotencyService: idem,
+	}
+	r := gin.New()
+	r.POST("/applications/:appID/workflow/exec", appHandler.execApplicationWorkflow)
+
+	exec := func(body, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/applications/app-1/workflow/exec", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set(config.IdempotencyKeyHeader, key)
+		}
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp
+	}
+
+	first := exec(`{"workflow_id":"wf-123"}`, "ci-build-42")
+	if first.Code != http.StatusOK {
+		t.Fatalf("unexpected status code: %d", first.Code)
+	}
+	// Any new execution would now return a different task.
+	svc.execResp = &apis.ExecWorkflowResponse{TaskID: "duplicate-task"}
+	svc.execForAppCalled = false
+
+	replay := exec(`{ "workflow_id": "wf-123" }`, "ci-build-42")
+	if replay.Code != http.StatusOK {
+		t.Fatalf("unexpected replay status code: %d", replay.Code)
+	}
+	var payload apis.ExecWorkflowResponse
+	if err := json.Unmarshal(replay.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if payload.TaskID != "test-task" || svc.execForAppCalled {
+		t.Fatalf("expected the original task to be replayed without executing, got %s", payload.TaskID)
+	}
+	if replay.Header().Get(config.IdempotencyReplayedHeader) != "true" {
+		t.Fatalf("expected replayed header on replay")
+	}
+
+	conflict := exec(`{"workflow_id":"wf-456"}`, "ci-build-42")
+	if conflict.Code != http.StatusConflict {
+		t.Fatalf("expected reused key with a different body to conflict, got %d", conflict.Code)
+	}
+
+	fresh := exec(`{"workflow_id":"wf-123"}`, "")
+	if fresh.Code != http.StatusOK || !svc.execForAppCalled {
+		t.Fatalf("expected requests without a key to execute, got %d", fresh.Code)
+	}
+}
@@ -33,6 +33,7 @@ func GetRegisteredAPI() []Interface {
 func InitAPIBean() []interface{} {
 	RegisterAPI(NewApplications())
 	RegisterAPI(NewWorkflow())
+	RegisterAPI(NewWebhook())
 	var beans []interface{}
 	for i := range registeredAPI {
 		beans = append(beans, registeredAPI[i])
@@ -89,6 +89,12 @@ func CORS(opts CORSOptions) gin.HandlerFunc {
 	}
 }
 
+// OriginListed reports whether origin is listed in allowOrigins itself; the "*" wildcard
+// does not count. It is used where CORS does not apply, e.g. WebSocket upgrades.
+func OriginListed(origin string, allowOrigins []string) bool {
+	return originAllowed(origin, normalizeList(allowOrigins))
+}
+
 func originAllowed(origin string, allowed []string) bool {
 	for _, candidate := range allowed {
 		if strings.EqualFold(candidate, origin) {
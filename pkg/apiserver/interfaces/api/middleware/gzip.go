@@ -20,6 +20,12 @@ func Gzip() gin.HandlerFunc {
 			return
 		}
 
+		// Streaming responses (SSE, WebSocket upgrades) must not be buffered by the compressor
+		if strings.Contains(c.GetHeader("Accept"), "text/event-stream") || strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+			c.Next()
+			return
+		}
+
 		// Skip if already set by an upstream handler
 		if c.Writer.Header().Get("Content-Encoding") != "" {
 			c.Next()
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+
+	"kubemin-cli/pkg/apiserver/interfaces/api/middleware"
+	"kubemin-cli/pkg/apiserver/utils/bcode"
+)
+
+// progressKeepaliveInterval SSE 空闲时发送注释行，防止代理断开连接
+var progressKeepaliveInterval = 15 * time.Second
+
+// streamWorkflowTask 以 Server-Sent Events 推送任务进度，连接时先发送 snapshot 事件
+func (app *applications) streamWorkflowTask(c *gin.Context) {
+	taskID := strings.TrimSpace(c.Param("taskID"))
+	if taskID == "" {
+		bcode.ReturnError(c, bcode.ErrWorkflowTaskNotExist)
+		return
+	}
+	events, err := app.WorkflowService.WatchTask(c.Request.Context(), taskID)
+	if err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	keepalive := time.NewTicker(progressKeepaliveInterval)
+	defer keepalive.Stop()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent(event.Type, event)
+			return true
+		case <-keepalive.C:
+			_, err := io.WriteString(w, ": keepalive\n\n")
+			return err == nil
+		}
+	})
+}
+
+// watchWorkflowTask 与 streamWorkflowTask 相同的事件流，通过 WebSocket 以 JSON 文本帧推送
+func (app *applications) watchWorkflowTask(c *gin.Context) {
+	taskID := strings.TrimSpace(c.Param("taskID"))
+	if taskID == "" {
+		bcode.ReturnError(c, bcode.ErrWorkflowTaskNotExist)
+		return
+	}
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	events, err := app.WorkflowService.WatchTask(ctx, taskID)
+	if err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	server := websocket.Server{
+		Handshake: app.checkWebSocketOrigin,
+		Handler: func(conn *websocket.Conn) {
+			defer conn.Close()
+			// 客户端不需要发送数据，读取只用于感知连接断开
+			go func() {
+				_, _ = io.Copy(io.Discard, conn)
+				cancel()
+			}()
+			for event := range events {
+				if err := websocket.JSON.Send(conn, event); err != nil {
+					return
+				}
+			}
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// checkWebSocketOrigin 浏览器不对 WebSocket 升级请求执行 CORS，任意页面都能发起连接，因此在握手时校验 Origin：
+// 允许不带 Origin 的非浏览器客户端、同源页面以及 CORS 配置中显式列出的来源，通配符 * 不放行 WebSocket
+func (app *applications) checkWebSocketOrigin(wsConfig *websocket.Config, req *http.Request) error {
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+	originURL, err := url.ParseRequestURI(origin)
+	if err != nil {
+		return err
+	}
+	wsConfig.Origin = originURL
+	if strings.EqualFold(originURL.Host, req.Host) {
+		return nil
+	}
+	if app.Cfg != nil && middleware.OriginListed(origin, app.Cfg.CORS.AllowedOrigins) {
+		return nil
+	}
+	return fmt.Errorf("websocket origin %s is not allowed", origin)
+}
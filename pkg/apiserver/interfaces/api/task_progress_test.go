@@ -0,0 +1,120 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+
+	"kubemin-cli/pkg/apiserver/config"
+	apis "kubemin-cli/pkg/apiserver/interfaces/api/dto/v1"
+	"kubemin-cli/pkg/apiserver/utils/bcode"
+)
+
+func (f *fakeWorkflowService) WatchTask(_ context.Context, taskID string) (<-chan apis.TaskProgressEvent, error) {
+	if taskID == "missing" {
+		return nil, bcode.ErrWorkflowTaskNotExist
+	}
+	ch := make(chan apis.TaskProgressEvent, len(f.progressEvents))
+	for _, event := range f.progressEvents {
+		ch <- event
+	}
+	close(ch)
+	return ch, nil
+}
+
+func newProgressServer(t *testing.T, allowedOrigins ...string) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	svc := &fakeWorkflowService{progressEvents: []apis.TaskProgressEvent{
+		{Type: "snapshot", TaskID: "task-1", Status: string(config.StatusRunning), Snapshot: &apis.TaskStatusResponse{TaskID: "task-1"}},
+		{Type: "job", TaskID: "task-1", Status: string(config.StatusRunning), Job: &apis.TaskJobProgress{Name: "web", Status: string(config.StatusCompleted)}},
+		{Type: "task", TaskID: "task-1", Status: string(config.StatusCompleted)},
+	}}
+	appHandler := &applications{
+		ApplicationService: noopApplicationsService{},
+		WorkflowService:    svc,
+		Cfg:                &config.Config{CORS: config.CORSConfig{AllowedOrigins: allowedOrigins}},
+	}
+	r := gin.New()
+	r.GET("/workflow/tasks/:taskID/events", appHandler.streamWorkflowTask)
+	r.GET("/workflow/tasks/:taskID/ws", appHandler.watchWorkflowTask)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestStreamWorkflowTaskServerSentEvents(t *testing.T) {
+	server := newProgressServer(t)
+
+	resp, err := http.Get(server.URL + "/workflow/tasks/task-1/events")
+	if err != nil {
+		t.Fatalf("request stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	var names []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if name, ok := strings.CutPrefix(scanner.Text(), "event:"); ok {
+			names = append(names, name)
+		}
+	}
+	if strings.Join(names, ",") != "snapshot,job,task" {
+		t.Fatalf("unexpected event sequence %v", names)
+	}
+
+	resp, err = http.Get(server.URL + "/workflow/tasks/missing/events")
+	if err != nil {
+		t.Fatalf("request stream: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected unknown task to be rejected before streaming, got %d", resp.StatusCode)
+	}
+}
+
+func TestWatchWorkflowTaskWebSocket(t *testing.T) {
+	server := newProgressServer(t)
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/workflow/tasks/task-1/ws"
+	conn, err := websocket.Dial(wsURL, "", server.URL)
+	if err != nil {
+		t.Fatalf("dial websocket: %v", err)
+	}
+	defer conn.Close()
+	var types []string
+	for {
+		var event apis.TaskProgressEvent
+		if err := websocket.JSON.Receive(conn, &event); err != nil {
+			break
+		}
+		types = append(types, event.Type)
+	}
+	if strings.Join(types, ",") != "snapshot,job,task" {
+		t.Fatalf("unexpected event sequence %v", types)
+	}
+}
+
+func TestWatchWorkflowTaskWebSocketChecksOrigin(t *testing.T) {
+	server := newProgressServer(t, "*", "https://console.example.com")
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/workflow/tasks/task-1/ws"
+
+	// The wildcard covers CORS requests only; other pages cannot open the socket.
+	if conn, err := websocket.Dial(wsURL, "", "https://evil.example.com"); err == nil {
+		conn.Close()
+		t.Fatalf("expected websocket from a foreign origin to be rejected")
+	}
+	conn, err := websocket.Dial(wsURL, "", "https://console.example.com")
+	if err != nil {
+		t.Fatalf("dial websocket from allowed origin: %v", err)
+	}
+	conn.Close()
+}
@@ -17,10 +17,14 @@ limitations under the License.
 package api
 
 import (
+	"fmt"
 	"regexp"
+	"strconv"
+	"strings"
 	"unicode"
 
 	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -32,8 +36,9 @@ var (
 )
 
 const (
-	minPageSize = 5
-	maxPageSize = 100
+	minPageSize     = 5
+	maxPageSize     = 100
+	defaultPageSize = 20
 )
 
 func init() {
@@ -115,3 +120,40 @@ func ValidateWorkflowMode(fl validator.FieldLevel) bool {
 	}
 	return false
 }
+
+// parsePagination 解析 page/page_size 查询参数，page 默认为 1，page_size 限制在 [minPageSize, maxPageSize]
+func parsePagination(c *gin.Context) (int, int, error) {
+	page, pageSize := 1, defaultPageSize
+	if raw := strings.TrimSpace(c.Query("page")); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 1 {
+			return 0, 0, fmt.Errorf("invalid page %q", raw)
+		}
+		page = v
+	}
+	if raw := strings.TrimSpace(c.Query("page_size")); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 1 {
+			return 0, 0, fmt.Errorf("invalid page_size %q", raw)
+		}
+		pageSize = v
+	}
+	if pageSize < minPageSize {
+		pageSize = minPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	return page, pageSize, nil
+}
+
+// splitQueryList 将逗号分隔的查询参数拆分为去空白的列表
+func splitQueryList(raw string) []string {
+	var values []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
@@ -0,0 +1,186 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/service"
+	assembler "kubemin-cli/pkg/apiserver/interfaces/api/assembler/v1"
+	apis "kubemin-cli/pkg/apiserver/interfaces/api/dto/v1"
+	"kubemin-cli/pkg/apiserver/utils/bcode"
+)
+
+// maxWebhookBodyBytes bounds the size of an inbound webhook payload.
+const maxWebhookBodyBytes = 1 << 20
+
+type webhook struct {
+	WebhookService service.WebhookService `inject:""`
+}
+
+// NewWebhook new inbound webhook triggers
+func NewWebhook() Interface {
+	return &webhook{}
+}
+
+func (h *webhook) RegisterRoutes(group *gin.RouterGroup) {
+	group.GET("/applications/:appID/webhooks", h.listWebhooks)
+	group.POST("/applications/:appID/webhooks", h.createWebhook)
+	group.GET("/applications/:appID/webhooks/:webhookID", h.getWebhook)
+	group.DELETE("/applications/:appID/webhooks/:webhookID", h.deleteWebhook)
+	group.GET("/applications/:appID/webhooks/:webhookID/deliveries", h.listWebhookDeliveries)
+	group.POST("/webhooks/:webhookID", h.triggerWebhook)
+}
+
+// createWebhook 创建入站 webhook，响应中包含密钥与签名触发地址，之后不再返回
+func (h *webhook) createWebhook(c *gin.Context) {
+	appID := strings.TrimSpace(c.Param("appID"))
+	if appID == "" {
+		bcode.ReturnError(c, bcode.ErrApplicationNotExist)
+		return
+	}
+	var req apis.CreateWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		klog.Error(err)
+		bcode.ReturnError(c, bcode.ErrWebhookConfig)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	if req.User == "" {
+		req.User = config.DefaultTaskRevoker
+	}
+	ctx := c.Request.Context()
+	created, err := h.WebhookService.CreateWebhook(ctx, appID, req)
+	if err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, apis.CreateWebhookResponse{
+		Webhook:    *assembler.ConvertWebhookModelToDTO(created),
+		Secret:     created.Secret,
+		TriggerURL: fmt.Sprintf("%s/webhooks/%s?token=%s", versionPrefix, created.ID, service.WebhookTriggerToken(created)),
+	})
+}
+
+func (h *webhook) listWebhooks(c *gin.Context) {
+	appID := strings.TrimSpace(c.Param("appID"))
+	if appID == "" {
+		bcode.ReturnError(c, bcode.ErrApplicationNotExist)
+		return
+	}
+	ctx := c.Request.Context()
+	list, err := h.WebhookService.ListWebhooks(ctx, appID)
+	if err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	resp := make([]*apis.Webhook, 0, len(list))
+	for _, item := range list {
+		resp = append(resp, assembler.ConvertWebhookModelToDTO(item))
+	}
+	c.JSON(http.StatusOK, apis.ListWebhooksResponse{Webhooks: resp})
+}
+
+func (h *webhook) getWebhook(c *gin.Context) {
+	appID, webhookID, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	item, err := h.WebhookService.GetWebhook(ctx, appID, webhookID)
+	if err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, assembler.ConvertWebhookModelToDTO(item))
+}
+
+func (h *webhook) deleteWebhook(c *gin.Context) {
+	appID, webhookID, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	if err := h.WebhookService.DeleteWebhook(ctx, appID, webhookID); err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"id": webhookID})
+}
+
+// listWebhookDeliveries 返回最近的投递记录及其处理结果
+func (h *webhook) listWebhookDeliveries(c *gin.Context) {
+	appID, webhookID, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	list, err := h.WebhookService.ListWebhookDeliveries(ctx, appID, webhookID)
+	if err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	resp := make([]*apis.WebhookDelivery, 0, len(list))
+	for _, item := range list {
+		resp = append(resp, assembler.ConvertWebhookDeliveryModelToDTO(item))
+	}
+	c.JSON(http.StatusOK, apis.ListWebhookDeliveriesResponse{Deliveries: resp})
+}
+
+// triggerWebhook 接收 CI 或镜像仓库的推送。请求需携带请求体的 HMAC-SHA256 签名，
+// 或使用创建时返回的带 token 的触发地址
+func (h *webhook) triggerWebhook(c *gin.Context) {
+	webhookID := strings.TrimSpace(c.Param("webhookID"))
+	if webhookID == "" {
+		bcode.ReturnError(c, bcode.ErrWebhookNotExist)
+		return
+	}
+	body, err := readLimitedBody(c, maxWebhookBodyBytes)
+	if err != nil {
+		klog.Error(err)
+		bcode.ReturnError(c, bcode.ErrWebhookPayload)
+		return
+	}
+	signature := c.GetHeader(config.WebhookSignatureHeader)
+	if signature == "" {
+		signature = c.GetHeader(config.WebhookHubSignatureHeader)
+	}
+	ctx := c.Request.Context()
+	resp, err := h.WebhookService.TriggerWebhook(ctx, webhookID, apis.WebhookTriggerRequest{
+		Body:      body,
+		Signature: signature,
+		Token:     c.Query("token"),
+		Source:    c.ClientIP(),
+	})
+	if err != nil {
+		bcode.ReturnError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func readLimitedBody(c *gin.Context, limit int64) ([]byte, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+	return c.GetRawData()
+}
+
+func webhookParams(c *gin.Context) (string, string, bool) {
+	appID := strings.TrimSpace(c.Param("appID"))
+	if appID == "" {
+		bcode.ReturnError(c, bcode.ErrApplicationNotExist)
+		return "", "", false
+	}
+	webhookID := strings.TrimSpace(c.Param("webhookID"))
+	if webhookID == "" {
+		bcode.ReturnError(c, bcode.ErrWebhookNotExist)
+		return "", "", false
+	}
+	return appID, webhookID, true
+}
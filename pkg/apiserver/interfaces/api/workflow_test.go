@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -29,6 +30,27 @@ type fakeWorkflowService struct {
 	lastUser           string
 	lastReason         string
 	taskStatusResp     *apis.TaskStatusResponse
+	rollbackCalled     bool
+	lastRollbackAppID  string
+	lastRollbackUser   string
+	lastRollbackTaskID string
+	approvalCalled     bool
+	pausedTaskID       string
+	pauseReason        string
+	resumedTaskID      string
+	retriedTaskID      string
+	lastExecInputs     map[string]interface{}
+	lastExecPriority   int
+	lastApproved       bool
+	lastApprovalReq    apis.WorkflowApprovalRequest
+	scheduleReq        apis.CreateWorkflowScheduleRequest
+	deletedScheduleID  string
+	dryRunAppID        string
+	dryRunReq          apis.DryRunWorkflowRequest
+	planWorkflowID     string
+	taskQuery          apis.ListWorkflowTasksQuery
+	jobStatuses        []string
+	progressEvents     []apis.TaskProgressEvent
 }
 
 func (f *fakeWorkflowService) ListApplicationWorkflow(context.Context, *model.Applications) error {
@@ -43,8 +65,10 @@ func (f *fakeWorkflowService) ExecWorkflowTask(context.Context, string) (*apis.E
 	return nil, nil
 }
 
-func (f *fakeWorkflowService) ExecWorkflowTaskForApp(_ context.Context, appID, workflowID string) (*apis.ExecWorkflowResponse, error) {
+func (f *fakeWorkflowService) ExecWorkflowTaskForApp(_ context.Context, appID, workflowID string, inputs map[string]interface{}, priority int) (*apis.ExecWorkflowResponse, error) {
+	f.lastExecPriority = priority
 	f.execForAppCalled = true
+	f.lastExecInputs = inputs
 	f.lastExecAppID = appID
 	f.lastExecWorkflowID = workflowID
 	if f.execResp == nil {
@@ -80,6 +104,41 @@ func (f *fakeWorkflowService) CancelWorkflowTaskForApp(_ context.Context, appID,
 	return nil
 }
 
+func (f *fakeWorkflowService) RollbackWorkflowTaskForApp(_ context.Context, appID, userName, taskID string) (*apis.RollbackWorkflowResponse, error) {
+	f.rollbackCalled = true
+	f.lastRollbackAppID = appID
+	f.lastRollbackUser = userName
+	f.lastRollbackTaskID = taskID
+	return &apis.RollbackWorkflowResponse{TaskID: taskID, Status: string(config.StatusRolledBack), Resources: 2}, nil
+}
+
+func (f *fakeWorkflowService) RetryWorkflowTaskForApp(_ context.Context, _, _, taskID string) (*apis.RetryWorkflowResponse, error) {
+	f.retriedTaskID = taskID
+	return &apis.RetryWorkflowResponse{TaskID: "retry-1", RetryOf: taskID, SkippedJobs: 3}, nil
+}
+
+func (f *fakeWorkflowService) PauseWorkflowTaskForApp(_ context.Context, _, _, taskID, reason string) (*apis.PauseWorkflowResponse, error) {
+	f.pausedTaskID = taskID
+	f.pauseReason = reason
+	return &apis.PauseWorkflowResponse{TaskID: taskID, Status: string(config.StatusRunning)}, nil
+}
+
+func (f *fakeWorkflowService) ResumeWorkflowTaskForApp(_ context.Context, _, _, taskID string) (*apis.PauseWorkflowResponse, error) {
+	f.resumedTaskID = taskID
+	return &apis.PauseWorkflowResponse{TaskID: taskID, Status: string(config.StatusWaiting)}, nil
+}
+
+func (f *fakeWorkflowService) DecideWorkflowApproval(_ context.Context, _, taskID string, approved bool, req apis.WorkflowApprovalRequest) (*apis.WorkflowApprovalResponse, error) {
+	f.approvalCalled = true
+	f.lastApproved = approved
+	f.lastApprovalReq = req
+	decision := config.StatusPassed
+	if !approved {
+		decision = config.StatusReject
+	}
+	return &apis.WorkflowApprovalResponse{TaskID: taskID, Step: "gate", Status: string(decision), TaskStatus: string(config.StatusWaiting)}, nil
+}
+
 func (f *fakeWorkflowService) MarkTaskStatus(context.Context, string, config.Status, config.Status) (bool, error) {
 	return false, nil
 }
@@ -91,6 +150,41 @@ func (f *fakeWorkflowService) GetTaskStatus(context.Context, string) (*apis.Task
 	return f.taskStatusResp, nil
 }
 
+func (f *fakeWorkflowService) CreateWorkflowSchedule(_ context.Context, appID string, req apis.CreateWorkflowScheduleRequest) (*model.WorkflowSchedule, error) {
+	f.scheduleReq = req
+	return &model.WorkflowSchedule{ID: "sched-1", AppID: appID, WorkflowID: req.WorkflowID, Cron: req.Cron, Status: config.StatusEnabled}, nil
+}
+
+func (f *fakeWorkflowService) ListWorkflowSchedules(context.Context, string) ([]*model.WorkflowSchedule, error) {
+	return nil, nil
+}
+
+func (f *fakeWorkflowService) GetWorkflowSchedule(_ context.Context, appID, scheduleID string) (*model.WorkflowSchedule, error) {
+	return &model.WorkflowSchedule{ID: scheduleID, AppID: appID}, nil
+}
+
+func (f *fakeWorkflowService) UpdateWorkflowSchedule(_ context.Context, appID, scheduleID string, _ apis.UpdateWorkflowScheduleRequest) (*model.WorkflowSchedule, error) {
+	return &model.WorkflowSchedule{ID: scheduleID, AppID: appID}, nil
+}
+
+func (f *fakeWorkflowService) DeleteWorkflowSchedule(_ context.Context, _, scheduleID string) error {
+	f.deletedScheduleID = scheduleID
+	return nil
+}
+
+func (f *fakeWorkflowService) FireDueSchedules(context.Context, time.Time) (int, error) {
+	return 0, nil
+}
+
+func (f *fakeWorkflowService) DryRunWorkflowForApp(_ context.Context, appID string, req apis.DryRunWorkflowRequest) (*apis.DryRunWorkflowResponse, error) {
+	f.dryRunAppID = appID
+	f.dryRunReq = req
+	return &apis.DryRunWorkflowResponse{
+		TaskID: "dry-run-task",
+		Jobs:   []apis.DryRunJobResult{{Step: "web", Name: "web", Status: string(config.StatusFailed), Error: "exceeded quota"}},
+	}, nil
+}
+
 type noopApplicationsService struct{}
 
 func (noopApplicationsService) CreateApplications(context.Context, apis.CreateApplicationsRequest) (*apis.ApplicationBase, error) {
@@ -154,7 +248,7 @@ func TestExecApplicationWorkflowEndpoint(t *testing.T) {
 	r := gin.New()
 	r.POST("/applications/:appID/workflow/exec", appHandler.execApplicationWorkflow)
 
-	body := `{"workflow_id":"wf-123"}`
+	body := `{"workflow_id":"wf-123","inputs":{"debug":"true"},"priority":20}`
 	req := httptest.NewRequest(http.MethodPost, "/applications/app-1/workflow/exec", strings.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	resp := httptest.NewRecorder()
@@ -175,6 +269,99 @@ func TestExecApplicationWorkflowEndpoint(t *testing.T) {
 	if !svc.execForAppCalled || svc.lastExecAppID != "app-1" || svc.lastExecWorkflowID != "wf-123" {
 		t.Fatalf("expected exec workflow for app to be invoked")
 	}
+	if svc.lastExecInputs["debug"] != "true" {
+		t.Fatalf("expected exec inputs to be forwarded, got %v", svc.lastExecInputs)
+	}
+	if svc.lastExecPriority != 20 {
+		t.Fatalf("expected exec priority to be forwarded, got %d", svc.lastExecPriority)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/applications/app-1/workflow/exec", strings.NewReader(`{"workflow_id":"wf-123","priority":500}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected out-of-range priority to be rejected, got %d", resp.Code)
+	}
+}
+
+func (f *fakeWorkflowService) PlanWorkflowForApp(_ context.Context, _, workflowID string) (*apis.WorkflowPlanResponse, error) {
+	f.planWorkflowID = workflowID
+	return &apis.WorkflowPlanResponse{
+		WorkflowID: workflowID,
+		Summary:    map[string]int{string(config.PlanActionUpdate): 1},
+		Resources: []apis.WorkflowPlanResource{{
+			Kind:    string(config.ResourceDeployment),
+			Name:    "web",
+			Action:  string(config.PlanActionUpdate),
+			Changes: []apis.WorkflowPlanFieldChange{{Path: "spec.replicas", Live: 1, Desired: 3}},
+		}},
+	}, nil
+}
+
+func TestWorkflowPlanEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := &fakeWorkflowService{}
+	appHandler := &applications{
+		ApplicationService: noopApplicationsService{},
+		WorkflowService:    svc,
+	}
+	r := gin.New()
+	r.GET("/applications/:appID/workflow/plan", appHandler.planWorkflow)
+
+	req := httptest.NewRequest(http.MethodGet, "/applications/app-1/workflow/plan?workflow_id=wf-123", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("unexpected status code: %d", resp.Code)
+	}
+	var payload apis.WorkflowPlanResponse
+	if err := json.Unmarshal(resp.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if svc.planWorkflowID != "wf-123" || len(payload.Resources) != 1 || payload.Resources[0].Changes[0].Path != "spec.replicas" {
+		t.Fatalf("unexpected plan response %+v", payload)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/applications/app-1/workflow/plan", nil)
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected missing workflow_id to be rejected, got %d", resp.Code)
+	}
+}
+
+func TestDryRunWorkflowEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := &fakeWorkflowService{}
+	appHandler := &applications{
+		ApplicationService: noopApplicationsService{},
+		WorkflowService:    svc,
+	}
+	r := gin.New()
+	r.POST("/applications/:appID/workflow/dry-run", appHandler.dryRunWorkflow)
+
+	body := `{"workflow_id":"wf-123","inputs":{"env":"staging"}}`
+	req := httptest.NewRequest(http.MethodPost, "/applications/app-1/workflow/dry-run", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("unexpected status code: %d", resp.Code)
+	}
+	var payload apis.DryRunWorkflowResponse
+	if err := json.Unmarshal(resp.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if payload.TaskID != "dry-run-task" || len(payload.Jobs) != 1 || payload.Jobs[0].Error != "exceeded quota" {
+		t.Fatalf("unexpected dry-run response %+v", payload)
+	}
+	if svc.dryRunAppID != "app-1" || svc.dryRunReq.WorkflowID != "wf-123" || svc.dryRunReq.Inputs["env"] != "staging" {
+		t.Fatalf("expected dry-run request to be forwarded, got %s %+v", svc.dryRunAppID, svc.dryRunReq)
+	}
 }
 
 func TestCancelApplicationWorkflowEndpoint(t *testing.T) {
@@ -217,6 +404,160 @@ func TestCancelApplicationWorkflowEndpoint(t *testing.T) {
 	}
 }
 
+func TestRollbackApplicationWorkflowEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := &fakeWorkflowService{}
+	appHandler := &applications{
+		ApplicationService: noopApplicationsService{},
+		WorkflowService:    svc,
+	}
+	r := gin.New()
+	r.POST("/applications/:appID/workflow/tasks/:taskID/rollback", appHandler.rollbackApplicationWorkflow)
+
+	req := httptest.NewRequest(http.MethodPost, "/applications/app-3/workflow/tasks/task-9/rollback", nil)
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("unexpected status code: %d", resp.Code)
+	}
+	var payload apis.RollbackWorkflowResponse
+	if err := json.Unmarshal(resp.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if payload.Status != string(config.StatusRolledBack) || payload.Resources != 2 {
+		t.Fatalf("unexpected rollback response: %+v", payload)
+	}
+	if !svc.rollbackCalled || svc.lastRollbackAppID != "app-3" || svc.lastRollbackTaskID != "task-9" || svc.lastRollbackUser != config.DefaultTaskRevoker {
+		t.Fatalf("expected rollback for app to be invoked with default user")
+	}
+}
+
+func TestRetryApplicationWorkflowEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := &fakeWorkflowService{}
+	appHandler := &applications{
+		ApplicationService: noopApplicationsService{},
+		WorkflowService:    svc,
+	}
+	r := gin.New()
+	r.POST("/applications/:appID/workflow/tasks/:taskID/retry", appHandler.retryApplicationWorkflow)
+
+	req := httptest.NewRequest(http.MethodPost, "/applications/app-1/workflow/tasks/task-1/retry", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("unexpected status code: %d", resp.Code)
+	}
+	var payload apis.RetryWorkflowResponse
+	if err := json.Unmarshal(resp.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if svc.retriedTaskID != "task-1" || payload.RetryOf != "task-1" || payload.SkippedJobs != 3 {
+		t.Fatalf("unexpected retry response: %+v", payload)
+	}
+}
+
+func TestPauseAndResumeEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := &fakeWorkflowService{}
+	appHandler := &applications{
+		ApplicationService: noopApplicationsService{},
+		WorkflowService:    svc,
+	}
+	r := gin.New()
+	r.POST("/applications/:appID/workflow/pause", appHandler.pauseApplicationWorkflow)
+	r.POST("/applications/:appID/workflow/resume", appHandler.resumeApplicationWorkflow)
+
+	req := httptest.NewRequest(http.MethodPost, "/applications/app-1/workflow/pause", strings.NewReader(`{"task_id":"task-1","reason":"canary errors"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("unexpected status code: %d", resp.Code)
+	}
+	if svc.pausedTaskID != "task-1" || svc.pauseReason != "canary errors" {
+		t.Fatalf("unexpected pause call: task=%s reason=%s", svc.pausedTaskID, svc.pauseReason)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/applications/app-1/workflow/resume", strings.NewReader(`{"task_id":"task-1"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("unexpected status code: %d", resp.Code)
+	}
+	var payload apis.PauseWorkflowResponse
+	if err := json.Unmarshal(resp.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if svc.resumedTaskID != "task-1" || payload.Status != string(config.StatusWaiting) {
+		t.Fatalf("unexpected resume response: %+v", payload)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/applications/app-1/workflow/pause", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	if resp.Code == http.StatusOK {
+		t.Fatalf("expected missing task id to be rejected")
+	}
+}
+
+func TestApprovalEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := &fakeWorkflowService{}
+	appHandler := &applications{
+		ApplicationService: noopApplicationsService{},
+		WorkflowService:    svc,
+	}
+	r := gin.New()
+	r.POST("/applications/:appID/workflow/tasks/:taskID/approve", appHandler.approveApplicationWorkflow)
+	r.POST("/applications/:appID/workflow/tasks/:taskID/reject", appHandler.rejectApplicationWorkflow)
+
+	body := `{"approver":"alice","comment":"looks good","step":"Gate"}`
+	req := httptest.NewRequest(http.MethodPost, "/applications/app-1/workflow/tasks/task-1/approve", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("unexpected status code: %d", resp.Code)
+	}
+	if !svc.approvalCalled || !svc.lastApproved {
+		t.Fatalf("expected approval to be recorded")
+	}
+	if svc.lastApprovalReq.Approver != "alice" || svc.lastApprovalReq.Comment != "looks good" || svc.lastApprovalReq.Step != "gate" {
+		t.Fatalf("unexpected approval request: %+v", svc.lastApprovalReq)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/applications/app-1/workflow/tasks/task-1/reject", strings.NewReader(`{"approver":"bob"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("unexpected status code: %d", resp.Code)
+	}
+	var payload apis.WorkflowApprovalResponse
+	if err := json.Unmarshal(resp.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if svc.lastApproved || payload.Status != string(config.StatusReject) {
+		t.Fatalf("expected rejection, got %+v", payload)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/applications/app-1/workflow/tasks/task-1/approve", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	if resp.Code == http.StatusOK {
+		t.Fatalf("expected missing approver to be rejected")
+	}
+}
+
 func TestGetWorkflowTaskStatusEndpoint(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := &fakeWorkflowService{
@@ -367,6 +708,57 @@ func TestListTemplateApplicationsEndpoint(t *testing.T) {
 	}
 }
 
+func TestCreateWorkflowScheduleEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := &fakeWorkflowService{}
+	appHandler := &applications{
+		ApplicationService: noopApplicationsService{},
+		WorkflowService:    svc,
+	}
+	r := gin.New()
+	r.POST("/applications/:appID/workflow/schedules", appHandler.createWorkflowSchedule)
+	r.DELETE("/applications/:appID/workflow/schedules/:scheduleID", appHandler.deleteWorkflowSchedule)
+
+	body := `{"name":"nightly","workflow_id":"wf-1","cron":"0 2 * * *","time_zone":"Asia/Shanghai","concurrency_policy":"replace"}`
+	req := httptest.NewRequest(http.MethodPost, "/applications/app-1/workflow/schedules", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("unexpected status code: %d body=%s", resp.Code, resp.Body.String())
+	}
+	var payload apis.WorkflowSchedule
+	if err := json.Unmarshal(resp.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if payload.ID != "sched-1" || payload.AppID != "app-1" || !payload.Enabled {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+	if svc.scheduleReq.TimeZone != "Asia/Shanghai" || svc.scheduleReq.ConcurrencyPolicy != "replace" {
+		t.Fatalf("unexpected request forwarded: %+v", svc.scheduleReq)
+	}
+	if svc.scheduleReq.User != config.DefaultTaskRevoker {
+		t.Fatalf("expected default user, got %q", svc.scheduleReq.User)
+	}
+
+	missingCron := `{"name":"nightly","workflow_id":"wf-1"}`
+	req = httptest.NewRequest(http.MethodPost, "/applications/app-1/workflow/schedules", strings.NewReader(missingCron))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	if resp.Code == http.StatusOK {
+		t.Fatalf("expected missing cron to be rejected")
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/applications/app-1/workflow/schedules/sched-1", nil)
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK || svc.deletedScheduleID != "sched-1" {
+		t.Fatalf("unexpected delete result: code=%d id=%q", resp.Code, svc.deletedScheduleID)
+	}
+}
+
 func TestWorkflowCancelEndpointNotImplemented(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := &fakeWorkflowService{}
@@ -589,3 +981,72 @@ func TestUpdateVersionEndpointImageUpdate(t *testing.T) {
 		t.Fatalf("unexpected image: %s", appSvc.lastReq.Components[0].Image)
 	}
 }
+
+func (f *fakeWorkflowService) ListWorkflowTasks(_ context.Context, _ string, query apis.ListWorkflowTasksQuery) (*apis.ListWorkflowTasksResponse, error) {
+	f.taskQuery = query
+	return &apis.ListWorkflowTasksResponse{
+		Tasks:    []apis.WorkflowTaskSummary{{TaskID: "task-1", WorkflowID: query.WorkflowID, Status: string(config.StatusCompleted)}},
+		Total:    1,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}, nil
+}
+
+func (f *fakeWorkflowService) ListTaskJobs(_ context.Context, taskID string, statuses []string) (*apis.ListTaskJobsResponse, error) {
+	f.jobStatuses = statuses
+	return &apis.ListTaskJobsResponse{TaskID: taskID, Jobs: []apis.TaskJobDetail{{ID: 1, Name: "web", Status: string(config.StatusFailed), Error: "boom"}}}, nil
+}
+
+func TestListWorkflowTasksEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := &fakeWorkflowService{}
+	appHandler := &applications{
+		ApplicationService: noopApplicationsService{},
+		WorkflowService:    svc,
+	}
+	r := gin.New()
+	r.GET("/applications/:appID/workflow/tasks", appHandler.listWorkflowTasks)
+	r.GET("/applications/:appID/workflows/:workflowID/tasks", appHandler.listWorkflowTasks)
+	r.GET("/workflow/tasks/:taskID/jobs", appHandler.listWorkflowTaskJobs)
+
+	req := httptest.NewRequest(http.MethodGet, "/applications/app-1/workflows/wf-1/tasks?status=failed,%20completed&page=2&page_size=1000", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("unexpected status code: %d", resp.Code)
+	}
+	var payload apis.ListWorkflowTasksResponse
+	if err := json.Unmarshal(resp.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if svc.taskQuery.WorkflowID != "wf-1" || svc.taskQuery.Page != 2 || svc.taskQuery.PageSize != maxPageSize {
+		t.Fatalf("unexpected task query %+v", svc.taskQuery)
+	}
+	if len(svc.taskQuery.Status) != 2 || svc.taskQuery.Status[1] != "completed" {
+		t.Fatalf("unexpected status filter %v", svc.taskQuery.Status)
+	}
+	if payload.Total != 1 || len(payload.Tasks) != 1 || payload.Tasks[0].TaskID != "task-1" {
+		t.Fatalf("unexpected task history %+v", payload)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/applications/app-1/workflow/tasks?page=0", nil)
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected invalid page to be rejected, got %d", resp.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/workflow/tasks/task-1/jobs?status=failed", nil)
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("unexpected status code: %d", resp.Code)
+	}
+	var jobs apis.ListTaskJobsResponse
+	if err := json.Unmarshal(resp.Body.Bytes(), &jobs); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(svc.jobStatuses) != 1 || jobs.TaskID != "task-1" || jobs.Jobs[0].Error != "boom" {
+		t.Fatalf("unexpected job listing %+v (statuses %v)", jobs, svc.jobStatuses)
+	}
+}
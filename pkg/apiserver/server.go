@@ -35,6 +35,9 @@ import (
 	"kubemin-cli/pkg/apiserver/utils/cache"
 	"kubemin-cli/pkg/apiserver/utils/container"
 	"kubemin-cli/pkg/apiserver/utils/kube"
+	"kubemin-cli/pkg/apiserver/workflow/drain"
+	"kubemin-cli/pkg/apiserver/workflow/progress"
+	wfsignal "kubemin-cli/pkg/apiserver/workflow/signal"
 )
 
 // APIServer interface for call api server
@@ -52,6 +55,7 @@ type restServer struct {
 	KubeClient      kubernetes.Interface `inject:"kubeClient"` //inject 是注入IOC的name，如果tag中包含inject 那么必须有对应的容器注入服务,必须大写，小写会无法访问
 	KubeConfig      *rest.Config         `inject:"kubeConfig"`
 	Queue           msg.Queue            `inject:"queue"`
+	Drain           *drain.Controller    `inject:""`
 	InformerManager *informer.Manager    // Informer 管理器，用于 List-Watch 机制
 	workersStarted  bool
 	workersCancel   context.CancelFunc
@@ -144,6 +148,19 @@ func (s *restServer) buildIoCContainer() error {
 		return fmt.Errorf("fail to provides the queue bean to the container: %w", err)
 	}
 
+	// 信号后端按配置选择，使各部署模式下取消与暂停都能送达运行中的任务
+	wfsignal.SetBackend(s.buildSignalBackend())
+
+	// 任务进度事件通过消息后端广播，任意副本都可以提供进度流
+	if err := s.beanContainer.Provides(progress.NewBroker(s.buildPubSub(), s.progressChannel())); err != nil {
+		return fmt.Errorf("fail to provides the progress broker bean to the container: %w", err)
+	}
+
+	// 排空控制器由关闭信号与管理接口共同触发，工作协程与就绪探针据此停止接收新任务
+	if err := s.beanContainer.Provides(drain.NewController(s.cfg.Workflow.WorkerDrainGracePeriod)); err != nil {
+		return fmt.Errorf("fail to provides the drain controller bean to the container: %w", err)
+	}
+
 	// 将操作k8s的权限全都注入到IOC中
 	if err := s.beanContainer.ProvideWithName("kubeClient", kubeClient); err != nil {
 		return fmt.Errorf("fail to provides the kubeClient bean to the container: %w", err)
@@ -209,6 +226,72 @@ func (s *restServer) dispatchTopic() string {
 	return fmt.Sprintf("%s.workflow.dispatch", prefix)
 }
 
+// progressChannel 计算任务进度事件的广播通道名
+func (s *restServer) progressChannel() string {
+	prefix := s.cfg.Messaging.ChannelPrefix
+	if prefix == "" {
+		prefix = "kubemin"
+	}
+	return fmt.Sprintf("%s.workflow.events", prefix)
+}
+
+// buildSignalBackend selects how cancel and pause signals reach running tasks. When Redis is
+// unavailable it falls back to the datastore, which works across instances as well.
+func (s *restServer) buildSignalBackend() wfsignal.Backend {
+	polling := wfsignal.NewDatastoreBackend(s.dataStore, s.cfg.Workflow.CancelPollInterval)
+	switch strings.ToLower(strings.TrimSpace(s.cfg.Workflow.CancelBackend)) {
+	case config.CancelBackendMemory:
+		klog.Info("workflow signal backend: in-process (single instance only)")
+		return wfsignal.NewLocalBackend()
+	case config.CancelBackendDatastore:
+		klog.Infof("workflow signal backend: datastore polling every %s", s.cfg.Workflow.CancelPollInterval)
+		return polling
+	case config.CancelBackendRedis:
+		rcli, err := clients.EnsureRedis(s.cfg.Cache)
+		if err != nil {
+			klog.Warningf("init redis client for workflow signals failed, falling back to datastore polling: %v", err)
+			return polling
+		}
+		klog.Info("workflow signal backend: redis")
+		return wfsignal.NewRedisBackend(rcli)
+	default:
+		if rcli := cache.GetGlobalRedisClient(); rcli != nil {
+			klog.Info("workflow signal backend: redis")
+			return wfsignal.NewRedisBackend(rcli)
+		}
+		klog.Infof("workflow signal backend: datastore polling every %s", s.cfg.Workflow.CancelPollInterval)
+		return polling
+	}
+}
+
+// buildPubSub constructs the broadcast channel based on config, falling back to
+// in-process delivery when the backend is unavailable.
+func (s *restServer) buildPubSub() msg.PubSub {
+	switch strings.ToLower(s.cfg.Messaging.Type) {
+	case "redis":
+		rcli, err := clients.EnsureRedis(s.cfg.Cache)
+		if err != nil {
+			klog.Warningf("init redis client for pubsub failed, falling back to local: %v", err)
+			return msg.NewLocalPubSub()
+		}
+		ps, err := msg.NewRedisPubSub(rcli)
+		if err != nil {
+			klog.Warningf("init redis pubsub failed, falling back to local: %v", err)
+			return msg.NewLocalPubSub()
+		}
+		return ps
+	case "kafka":
+		ps, err := msg.NewKafkaPubSub(s.cfg.Messaging.KafkaBrokers)
+		if err != nil {
+			klog.Warningf("init kafka pubsub failed, falling back to local: %v", err)
+			return msg.NewLocalPubSub()
+		}
+		return ps
+	default:
+		return msg.NewLocalPubSub()
+	}
+}
+
 // buildQueue constructs the messaging queue based on config.
 // It returns a usable Queue in all cases, falling back to NoopQueue on failures.
 func (s *restServer) buildQueue(streamKey string) msg.Queue {
@@ -382,7 +465,8 @@ func (s *restServer) Run(ctx context.Context, errChan chan error) error {
 		select {
 		case sig := <-sigChan:
 			klog.Infof("received signal %v, initiating graceful shutdown", sig)
-			// Stop workers before canceling context
+			// Let in-flight tasks finish or hand them back before stopping workers
+			s.drainWorkers()
 			s.stopWorkers()
 			runCancel()
 		case <-ctx.Done():
@@ -451,6 +535,21 @@ func (s *restServer) startWorkers(ctx context.Context, errChan chan error) {
 	go event.StartWorkerSubscriber(wctx, errChan)
 }
 
+// drainWorkers stops taking new tasks and waits until the tasks running on this
+// instance finished or were handed back to the queue.
+func (s *restServer) drainWorkers() {
+	if s.Drain == nil {
+		return
+	}
+	s.Drain.Begin()
+	timeout := s.Drain.GracePeriod() + config.WorkerDrainHandoffTimeout
+	select {
+	case <-s.Drain.Finished():
+	case <-time.After(timeout):
+		klog.Warningf("worker drain did not finish within %s", timeout)
+	}
+}
+
 func (s *restServer) stopWorkers() {
 	if !s.workersStarted {
 		return
@@ -76,3 +76,9 @@ var ErrComponentRefNotFound = NewBcode(400, 10027, "workflow references a non-ex
 
 // ErrDuplicateComponentName duplicate component name in application
 var ErrDuplicateComponentName = NewBcode(400, 10028, "duplicate component name in application")
+
+// ErrApplicationConcurrencyPolicy unsupported application concurrency policy
+var ErrApplicationConcurrencyPolicy = NewBcode(400, 10029, "application concurrency policy must be one of queue, reject, supersede")
+
+// ErrInvalidBatchJobConfig invalid job or cronjob component properties
+var ErrInvalidBatchJobConfig = NewBcode(400, 10030, "invalid job properties: check restart_policy, concurrency_policy and the cronjob schedule")
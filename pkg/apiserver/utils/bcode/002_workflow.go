@@ -13,3 +13,35 @@ var ErrExecWorkflow = NewBcode(400, 20004, "workflow exec failure")
 var ErrWorkflowNotExist = NewBcode(404, 20005, "workflow not found")
 
 var ErrWorkflowTaskNotExist = NewBcode(404, 20006, "workflow task not found")
+
+var ErrWorkflowStepDependency = NewBcode(400, 20007, "workflow step dependencies are invalid")
+
+var ErrWorkflowRollbackMode = NewBcode(400, 20008, "workflow rollback mode must be one of none, automatic, manual")
+
+var ErrWorkflowRollbackNotAllowed = NewBcode(409, 20009, "workflow task cannot be rolled back in its current status")
+
+var ErrWorkflowApprovalNotPending = NewBcode(409, 20010, "workflow task is not waiting for approval")
+
+var ErrWorkflowApprovalStepRequired = NewBcode(400, 20011, "several approvals are pending, the approval step must be specified")
+
+var ErrWorkflowPauseNotAllowed = NewBcode(409, 20012, "workflow task cannot be paused in its current status")
+
+var ErrWorkflowNotPaused = NewBcode(409, 20013, "workflow task is not paused")
+
+var ErrWorkflowRetryNotAllowed = NewBcode(409, 20014, "only failed or timed out workflow tasks can be retried")
+
+var ErrWorkflowStepCondition = NewBcode(400, 20015, "workflow step condition is not a valid expression")
+
+var ErrWorkflowStepTimeout = NewBcode(400, 20016, "workflow step timeout_seconds must not be negative")
+
+var ErrWorkflowScheduleNotExist = NewBcode(404, 20017, "workflow schedule not found")
+
+var ErrWorkflowScheduleInvalid = NewBcode(400, 20018, "workflow schedule cron expression, time zone or concurrency policy is invalid")
+
+var ErrWorkflowTaskConflict = NewBcode(409, 20019, "another workflow task of the application is still active")
+
+var ErrWorkflowDryRun = NewBcode(500, 20020, "workflow dry-run could not be executed")
+
+var ErrWorkflowPlan = NewBcode(500, 20021, "workflow plan could not be computed")
+
+var ErrWorkflowProgressUnavailable = NewBcode(503, 20022, "workflow task progress streaming is unavailable")
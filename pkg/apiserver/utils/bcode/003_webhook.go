@@ -0,0 +1,9 @@
+package bcode
+
+var ErrWebhookNotExist = NewBcode(404, 30000, "webhook not found")
+
+var ErrWebhookConfig = NewBcode(400, 30001, "webhook mapping must name components and an image_path or tag_path")
+
+var ErrWebhookSignature = NewBcode(401, 30002, "webhook signature is missing or invalid")
+
+var ErrWebhookPayload = NewBcode(400, 30003, "webhook payload is not valid JSON")
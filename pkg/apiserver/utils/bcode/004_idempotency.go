@@ -0,0 +1,7 @@
+package bcode
+
+var ErrIdempotencyKeyInvalid = NewBcode(400, 40000, "Idempotency-Key must be at most 255 printable characters")
+
+var ErrIdempotencyKeyConflict = NewBcode(409, 40001, "Idempotency-Key was already used with a different request")
+
+var ErrIdempotencyKeyInProgress = NewBcode(409, 40002, "a request with the same Idempotency-Key is still being processed")
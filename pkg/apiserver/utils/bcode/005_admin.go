@@ -0,0 +1,5 @@
+package bcode
+
+var ErrDeadLetterNotExist = NewBcode(404, 50000, "dead letter not found")
+
+var ErrDeadLetterLimitInvalid = NewBcode(400, 50001, "limit must be a positive integer")
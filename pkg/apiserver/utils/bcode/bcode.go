@@ -11,15 +11,33 @@ import (
 
 // Bcode business error code
 type Bcode struct {
-	HTTPCode     int32  `json:"-"`
-	BusinessCode int32  `json:"business_code"`
-	Message      string `json:"message"`
+	HTTPCode     int32                  `json:"-"`
+	BusinessCode int32                  `json:"business_code"`
+	Message      string                 `json:"message"`
+	Detail       map[string]interface{} `json:"detail,omitempty"`
 }
 
 func (b Bcode) Error() string {
 	return fmt.Sprintf("HTTPCode:%d BusinessCode:%d Message:%s", b.HTTPCode, b.BusinessCode, b.Message)
 }
 
+// Is matches business codes so that errors carrying details still compare equal to the registered code.
+func (b *Bcode) Is(target error) bool {
+	t, ok := target.(*Bcode)
+	return ok && t != nil && t.BusinessCode == b.BusinessCode
+}
+
+// WithDetail returns a copy of the business code carrying an extra detail for the caller.
+func (b *Bcode) WithDetail(key string, value interface{}) *Bcode {
+	copied := *b
+	copied.Detail = make(map[string]interface{}, len(b.Detail)+1)
+	for k, v := range b.Detail {
+		copied.Detail[k] = v
+	}
+	copied.Detail[key] = value
+	return &copied
+}
+
 var bcodeMap map[int32]*Bcode
 
 // NewBcode new business code
@@ -39,10 +57,14 @@ func NewBcode(httpCode, businessCode int32, message string) *Bcode {
 func ReturnError(c *gin.Context, err error) {
 	var bcode *Bcode
 	if errors.As(err, &bcode) {
-		c.JSON(int(bcode.HTTPCode), gin.H{
+		body := gin.H{
 			"business_code": bcode.BusinessCode,
 			"message":       bcode.Message,
-		})
+		}
+		if len(bcode.Detail) > 0 {
+			body["detail"] = bcode.Detail
+		}
+		c.JSON(int(bcode.HTTPCode), body)
 		return
 	}
 
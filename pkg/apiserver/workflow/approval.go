@@ -0,0 +1,29 @@
+package workflow
+
+import (
+	"fmt"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+// IsApprovalStep reports whether the step is a manual approval gate.
+func IsApprovalStep(step *model.WorkflowStep) bool {
+	return step != nil && step.WorkflowType == config.JobApproval
+}
+
+// ValidateApprovalSteps 校验审批步骤：必须有名称（审批单按步骤名关联），且不能包含组件或子步骤
+func ValidateApprovalSteps(steps []*model.WorkflowStep) error {
+	for i, step := range steps {
+		if !IsApprovalStep(step) {
+			continue
+		}
+		if step.Name == "" {
+			return fmt.Errorf("approval step #%d must have a name", i+1)
+		}
+		if len(step.Properties) > 0 || len(step.SubSteps) > 0 {
+			return fmt.Errorf("approval step %q cannot contain components or sub steps", step.Name)
+		}
+	}
+	return nil
+}
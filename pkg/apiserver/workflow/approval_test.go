@@ -0,0 +1,28 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+func TestValidateApprovalSteps(t *testing.T) {
+	deploy := &model.WorkflowStep{Name: "deploy", Properties: []model.Policies{{Policies: []string{"api"}}}}
+	gate := &model.WorkflowStep{Name: "gate", WorkflowType: config.JobApproval}
+	require.True(t, IsApprovalStep(gate))
+	require.False(t, IsApprovalStep(deploy))
+	require.NoError(t, ValidateApprovalSteps([]*model.WorkflowStep{deploy, gate}))
+
+	unnamed := &model.WorkflowStep{WorkflowType: config.JobApproval}
+	require.ErrorContains(t, ValidateApprovalSteps([]*model.WorkflowStep{unnamed}), "must have a name")
+
+	withComponents := &model.WorkflowStep{
+		Name:         "gate",
+		WorkflowType: config.JobApproval,
+		Properties:   []model.Policies{{Policies: []string{"api"}}},
+	}
+	require.ErrorContains(t, ValidateApprovalSteps([]*model.WorkflowStep{withComponents}), "cannot contain components")
+}
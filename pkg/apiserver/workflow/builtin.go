@@ -0,0 +1,87 @@
+package workflow
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+// IsBuiltinStepType reports whether the job type is a built-in step that is not tied to components.
+func IsBuiltinStepType(jobType config.JobType) bool {
+	switch jobType {
+	case config.JobWait, config.JobHTTPCheck, config.JobNotify:
+		return true
+	}
+	return false
+}
+
+// IsBuiltinStep reports whether the step runs a built-in job (wait、http-check、notify).
+func IsBuiltinStep(step *model.WorkflowStep) bool {
+	return step != nil && IsBuiltinStepType(step.WorkflowType)
+}
+
+// ValidateBuiltinSteps 校验内置步骤：必须有名称（任务记录按步骤名关联），不能包含组件或子步骤，且参数完整
+func ValidateBuiltinSteps(steps []*model.WorkflowStep) error {
+	for i, step := range steps {
+		if !IsBuiltinStep(step) {
+			continue
+		}
+		if step.Name == "" {
+			return fmt.Errorf("%s step #%d must have a name", step.WorkflowType, i+1)
+		}
+		if len(step.Properties) > 0 || len(step.SubSteps) > 0 {
+			return fmt.Errorf("%s step %q cannot contain components or sub steps", step.WorkflowType, step.Name)
+		}
+		if err := ValidateStepSpec(step.WorkflowType, step.Spec); err != nil {
+			return fmt.Errorf("step %q: %w", step.Name, err)
+		}
+	}
+	return nil
+}
+
+// ValidateStepSpec checks the parameters required by a built-in step type.
+func ValidateStepSpec(jobType config.JobType, spec *model.StepSpec) error {
+	if spec == nil {
+		return fmt.Errorf("%s step requires a spec", jobType)
+	}
+	if spec.DurationSeconds < 0 || spec.IntervalSeconds < 0 {
+		return fmt.Errorf("duration_seconds and interval_seconds must not be negative")
+	}
+	switch jobType {
+	case config.JobWait:
+		if spec.DurationSeconds == 0 && strings.TrimSpace(spec.Until) == "" {
+			return fmt.Errorf("wait step requires duration_seconds or until")
+		}
+		if strings.TrimSpace(spec.Until) != "" {
+			if err := ValidateCondition(spec.Until); err != nil {
+				return fmt.Errorf("invalid until condition: %w", err)
+			}
+		}
+	case config.JobHTTPCheck, config.JobNotify:
+		parsed, err := url.Parse(spec.URL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("%s step requires an http(s) url", jobType)
+		}
+		if spec.Method != "" && !isHTTPMethod(spec.Method) {
+			return fmt.Errorf("unsupported http method %q", spec.Method)
+		}
+		for _, code := range spec.ExpectStatus {
+			if code < 100 || code > 599 {
+				return fmt.Errorf("invalid expected status %d", code)
+			}
+		}
+	}
+	return nil
+}
+
+func isHTTPMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
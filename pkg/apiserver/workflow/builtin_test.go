@@ -0,0 +1,33 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+func TestValidateBuiltinSteps(t *testing.T) {
+	deploy := &model.WorkflowStep{Name: "deploy", Properties: []model.Policies{{Policies: []string{"api"}}}}
+	wait := &model.WorkflowStep{Name: "settle", WorkflowType: config.JobWait, Spec: &model.StepSpec{DurationSeconds: 30}}
+	check := &model.WorkflowStep{Name: "health", WorkflowType: config.JobHTTPCheck, Spec: &model.StepSpec{URL: "http://api/healthz", ExpectStatus: []int{200}}}
+	notify := &model.WorkflowStep{Name: "announce", WorkflowType: config.JobNotify, Spec: &model.StepSpec{URL: "https://hooks.example.com/x"}}
+	require.True(t, IsBuiltinStep(wait))
+	require.False(t, IsBuiltinStep(deploy))
+	require.NoError(t, ValidateBuiltinSteps([]*model.WorkflowStep{deploy, wait, check, notify}))
+
+	for _, bad := range []*model.WorkflowStep{
+		{WorkflowType: config.JobWait, Spec: &model.StepSpec{DurationSeconds: 1}},
+		{Name: "w", WorkflowType: config.JobWait},
+		{Name: "w", WorkflowType: config.JobWait, Spec: &model.StepSpec{}},
+		{Name: "w", WorkflowType: config.JobWait, Spec: &model.StepSpec{Until: "inputs.ready =="}},
+		{Name: "h", WorkflowType: config.JobHTTPCheck, Spec: &model.StepSpec{URL: "ftp://host"}},
+		{Name: "h", WorkflowType: config.JobHTTPCheck, Spec: &model.StepSpec{URL: "http://host", ExpectStatus: []int{999}}},
+		{Name: "n", WorkflowType: config.JobNotify, Spec: &model.StepSpec{URL: "http://host", Method: "BREW"}},
+		{Name: "n", WorkflowType: config.JobNotify, Spec: &model.StepSpec{URL: "http://host"}, Properties: []model.Policies{{Policies: []string{"api"}}}},
+	} {
+		require.Error(t, ValidateBuiltinSteps([]*model.WorkflowStep{bad}), "step %+v", bad)
+	}
+}
@@ -0,0 +1,505 @@
+package workflow
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+// Step conditions are small boolean expressions evaluated right before a step runs:
+//
+//	inputs.debug == "true" && components.web.replicas > 1
+//	steps.migrate.status != "skipped" || !inputs.skip_migrate
+//
+// Grammar:
+//
+//	or      := and ("||" and)*
+//	and     := unary ("&&" unary)*
+//	unary   := "!" unary | compare
+//	compare := primary (("==" | "!=" | "<" | "<=" | ">" | ">=") primary)?
+//	primary := string | number | true | false | null | path | "(" or ")"
+//	path    := ident ("." ident | "[" string "]")*
+//
+// A path that does not resolve evaluates to null, so optional inputs can be tested directly.
+
+// Condition roots that expressions may reference.
+const (
+	ConditionInputs     = "inputs"
+	ConditionComponents = "components"
+	ConditionSteps      = "steps"
+)
+
+var conditionRoots = map[string]struct{}{
+	ConditionInputs:     {},
+	ConditionComponents: {},
+	ConditionSteps:      {},
+}
+
+// ValidateCondition parses expr and checks that it only references known roots.
+func ValidateCondition(expr string) error {
+	_, err := parseCondition(expr)
+	return err
+}
+
+// ValidateStepConditions 校验所有步骤的条件表达式，在保存工作流时尽早发现语法错误
+func ValidateStepConditions(steps []*model.WorkflowStep) error {
+	for _, step := range steps {
+		if step == nil || strings.TrimSpace(step.Condition) == "" {
+			continue
+		}
+		if err := ValidateCondition(step.Condition); err != nil {
+			return fmt.Errorf("step %q condition: %w", step.Name, err)
+		}
+	}
+	return nil
+}
+
+// EvaluateCondition evaluates expr against vars. An empty expression is always true.
+func EvaluateCondition(expr string, vars map[string]interface{}) (bool, error) {
+	if strings.TrimSpace(expr) == "" {
+		return true, nil
+	}
+	node, err := parseCondition(expr)
+	if err != nil {
+		return false, err
+	}
+	value, err := node.eval(vars)
+	if err != nil {
+		return false, err
+	}
+	return truthy(value), nil
+}
+
+func parseCondition(expr string) (conditionNode, error) {
+	tokens, err := tokenizeCondition(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &conditionParser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at offset %d", tok.text, tok.pos)
+	}
+	return node, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+)
+
+type conditionToken struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func tokenizeCondition(expr string) ([]conditionToken, error) {
+	var tokens []conditionToken
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			start := i
+			var sb strings.Builder
+			i++
+			for ; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				sb.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at offset %d", start)
+			}
+			i++
+			tokens = append(tokens, conditionToken{kind: tokenString, text: sb.String(), pos: start})
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, conditionToken{kind: tokenNumber, text: string(runes[start:i]), pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '-') {
+				i++
+			}
+			tokens = append(tokens, conditionToken{kind: tokenIdent, text: string(runes[start:i]), pos: start})
+		default:
+			start := i
+			two := ""
+			if i+1 < len(runes) {
+				two = string(runes[i : i+2])
+			}
+			switch two {
+			case "&&", "||", "==", "!=", "<=", ">=":
+				tokens = append(tokens, conditionToken{kind: tokenOperator, text: two, pos: start})
+				i += 2
+				continue
+			}
+			switch r {
+			case '!', '<', '>', '(', ')', '.', '[', ']':
+				tokens = append(tokens, conditionToken{kind: tokenOperator, text: string(r), pos: start})
+				i++
+			default:
+				return nil, fmt.Errorf("unexpected character %q at offset %d", r, start)
+			}
+		}
+	}
+	return append(tokens, conditionToken{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+type conditionParser struct {
+	tokens []conditionToken
+	pos    int
+}
+
+func (p *conditionParser) peek() conditionToken {
+	return p.tokens[p.pos]
+}
+
+func (p *conditionParser) next() conditionToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *conditionParser) acceptOperator(op string) bool {
+	if tok := p.peek(); tok.kind == tokenOperator && tok.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *conditionParser) parseOr() (conditionNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptOperator("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseAnd() (conditionNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptOperator("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseUnary() (conditionNode, error) {
+	if p.acceptOperator("!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *conditionParser) parseCompare() (conditionNode, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind == tokenOperator {
+		switch tok.text {
+		case "==", "!=", "<", "<=", ">", ">=":
+			p.next()
+			right, err := p.parsePrimary()
+			if err != nil {
+				return nil, err
+			}
+			return compareNode{op: tok.text, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parsePrimary() (conditionNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenString:
+		return literalNode{value: tok.text}, nil
+	case tokenNumber:
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at offset %d", tok.text, tok.pos)
+		}
+		return literalNode{value: f}, nil
+	case tokenIdent:
+		switch tok.text {
+		case "true":
+			return literalNode{value: true}, nil
+		case "false":
+			return literalNode{value: false}, nil
+		case "null":
+			return literalNode{value: nil}, nil
+		}
+		if _, ok := conditionRoots[tok.text]; !ok {
+			return nil, fmt.Errorf("unknown variable %q at offset %d, expected inputs, components or steps", tok.text, tok.pos)
+		}
+		path := []string{tok.text}
+		for {
+			if p.acceptOperator(".") {
+				field := p.next()
+				if field.kind != tokenIdent {
+					return nil, fmt.Errorf("expected field name at offset %d", field.pos)
+				}
+				path = append(path, field.text)
+				continue
+			}
+			if p.acceptOperator("[") {
+				key := p.next()
+				if key.kind != tokenString {
+					return nil, fmt.Errorf("expected quoted key at offset %d", key.pos)
+				}
+				if !p.acceptOperator("]") {
+					return nil, fmt.Errorf("expected ] at offset %d", p.peek().pos)
+				}
+				path = append(path, key.text)
+				continue
+			}
+			break
+		}
+		return pathNode{path: path}, nil
+	case tokenOperator:
+		if tok.text == "(" {
+			node, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if !p.acceptOperator(")") {
+				return nil, fmt.Errorf("expected ) at offset %d", p.peek().pos)
+			}
+			return node, nil
+		}
+	case tokenEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("unexpected %q at offset %d", tok.text, tok.pos)
+}
+
+type conditionNode interface {
+	eval(vars map[string]interface{}) (interface{}, error)
+}
+
+type literalNode struct{ value interface{} }
+
+func (n literalNode) eval(map[string]interface{}) (interface{}, error) { return n.value, nil }
+
+type pathNode struct{ path []string }
+
+func (n pathNode) eval(vars map[string]interface{}) (interface{}, error) {
+	var current interface{} = vars
+	for _, key := range n.path {
+		switch m := current.(type) {
+		case map[string]interface{}:
+			current = m[key]
+		case model.JSONStruct:
+			current = m[key]
+		case *model.JSONStruct:
+			if m == nil {
+				return nil, nil
+			}
+			current = (*m)[key]
+		case map[string]string:
+			value, ok := m[key]
+			if !ok {
+				return nil, nil
+			}
+			current = value
+		default:
+			return nil, nil
+		}
+	}
+	return current, nil
+}
+
+type notNode struct{ operand conditionNode }
+
+func (n notNode) eval(vars map[string]interface{}) (interface{}, error) {
+	value, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	return !truthy(value), nil
+}
+
+type logicalNode struct {
+	op          string
+	left, right conditionNode
+}
+
+func (n logicalNode) eval(vars map[string]interface{}) (interface{}, error) {
+	left, err := n.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	// Short-circuit so guards like `inputs.x && inputs.x > 1` stay valid.
+	if n.op == "&&" && !truthy(left) {
+		return false, nil
+	}
+	if n.op == "||" && truthy(left) {
+		return true, nil
+	}
+	right, err := n.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	return truthy(right), nil
+}
+
+type compareNode struct {
+	op          string
+	left, right conditionNode
+}
+
+func (n compareNode) eval(vars map[string]interface{}) (interface{}, error) {
+	left, err := n.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return valuesEqual(left, right), nil
+	case "!=":
+		return !valuesEqual(left, right), nil
+	}
+	if left == nil || right == nil {
+		return false, nil
+	}
+	lf, lok := toNumber(left)
+	rf, rok := toNumber(right)
+	var cmp int
+	switch {
+	case lok && rok:
+		cmp = compareFloats(lf, rf)
+	default:
+		ls, lok := left.(string)
+		rs, rok := right.(string)
+		if !lok || !rok {
+			return nil, fmt.Errorf("cannot compare %v %s %v", left, n.op, right)
+		}
+		cmp = strings.Compare(ls, rs)
+	}
+	switch n.op {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	default:
+		return cmp >= 0, nil
+	}
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// valuesEqual compares loosely typed values: exec inputs usually arrive as strings, so
+// "3" equals 3 and "true" equals true.
+func valuesEqual(left, right interface{}) bool {
+	if left == nil || right == nil {
+		return left == nil && right == nil
+	}
+	if lf, ok := toNumber(left); ok {
+		if rf, ok := toNumber(right); ok {
+			return lf == rf
+		}
+	}
+	if lb, ok := left.(bool); ok {
+		return strconv.FormatBool(lb) == strings.ToLower(fmt.Sprint(right))
+	}
+	if rb, ok := right.(bool); ok {
+		return strconv.FormatBool(rb) == strings.ToLower(fmt.Sprint(left))
+	}
+	return fmt.Sprint(left) == fmt.Sprint(right)
+}
+
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func truthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "", "false", "0":
+			return false
+		}
+		return true
+	default:
+		if f, ok := toNumber(v); ok {
+			return f != 0
+		}
+		return true
+	}
+}
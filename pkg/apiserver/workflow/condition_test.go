@@ -0,0 +1,73 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+func TestEvaluateCondition(t *testing.T) {
+	props := model.JSONStruct{"env": map[string]interface{}{"LOG_LEVEL": "debug"}}
+	vars := map[string]interface{}{
+		ConditionInputs: map[string]interface{}{"debug": "true", "replicas": "3", "skip": false},
+		ConditionComponents: map[string]interface{}{
+			"web-api": map[string]interface{}{"replicas": int32(2), "image": "nginx:1.21", "properties": &props},
+		},
+		ConditionSteps: map[string]interface{}{
+			"migrate": map[string]interface{}{"status": "skipped"},
+		},
+	}
+
+	cases := []struct {
+		expr string
+		want bool
+	}{
+		{"", true},
+		{`inputs.debug == "true"`, true},
+		{"inputs.debug == true", true},
+		{"inputs.debug", true},
+		{"inputs.skip", false},
+		{"!inputs.skip", true},
+		{"inputs.missing", false},
+		{"inputs.missing == null", true},
+		{"inputs.replicas > 2 && components.web-api.replicas >= 2", true},
+		{"components['web-api'].replicas < 2", false},
+		{`components.web-api.properties.env.LOG_LEVEL == 'debug'`, true},
+		{`steps.migrate.status != "skipped" || inputs.debug`, true},
+		{`(steps.migrate.status == "completed" || inputs.skip) && inputs.debug`, false},
+		{"inputs.missing && inputs.missing > 1", false},
+		{"inputs.replicas == -3 || inputs.replicas == 3.0", true},
+	}
+	for _, tc := range cases {
+		got, err := EvaluateCondition(tc.expr, vars)
+		require.NoError(t, err, tc.expr)
+		require.Equal(t, tc.want, got, tc.expr)
+	}
+
+	_, err := EvaluateCondition(`components.web-api.image > 1`, vars)
+	require.Error(t, err)
+}
+
+func TestValidateCondition(t *testing.T) {
+	require.NoError(t, ValidateCondition(`inputs.debug == "true"`))
+
+	for _, expr := range []string{
+		`input.debug`,
+		`inputs.debug ==`,
+		`inputs.debug = "true"`,
+		`(inputs.debug`,
+		`inputs["debug"`,
+		`"unterminated`,
+		`inputs.debug "x"`,
+	} {
+		require.Error(t, ValidateCondition(expr), expr)
+	}
+
+	steps := []*model.WorkflowStep{
+		{Name: "web", Condition: "inputs.web"},
+		{Name: "debug", Condition: "inputs.debug &&"},
+	}
+	require.ErrorContains(t, ValidateStepConditions(steps), `step "debug" condition`)
+}
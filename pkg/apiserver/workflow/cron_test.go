@@ -0,0 +1,57 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronNext(t *testing.T) {
+	base := time.Date(2026, 3, 14, 10, 17, 42, 0, time.UTC) // Saturday
+
+	cases := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 3, 14, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 3, 14, 10, 30, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2026, 3, 15, 2, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 3, 14, 11, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"30 9 * * mon-fri", time.Date(2026, 3, 16, 9, 30, 0, 0, time.UTC)},
+		{"0 0 1 jan,jul *", time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)},
+		// Day of month and day of week both restricted: either one matches.
+		{"0 0 20 * 1", time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC)},
+		// A stepped wildcard leaves the field unrestricted, so both fields must match: an odd Monday.
+		{"0 0 */2 * 1", time.Date(2026, 3, 23, 0, 0, 0, 0, time.UTC)},
+		{"0 0 13 * */2", time.Date(2026, 6, 13, 0, 0, 0, 0, time.UTC)},
+		{"5-10/5 10 14 3 *", time.Date(2027, 3, 14, 10, 5, 0, 0, time.UTC)},
+	}
+	for _, tc := range cases {
+		schedule, err := ParseCron(tc.expr, "")
+		require.NoError(t, err, tc.expr)
+		require.Equal(t, tc.want, schedule.Next(base), tc.expr)
+	}
+
+	never, err := ParseCron("0 0 30 2 *", "")
+	require.NoError(t, err)
+	require.True(t, never.Next(base).IsZero())
+}
+
+func TestCronNextInTimeZone(t *testing.T) {
+	schedule, err := ParseCron("0 2 * * *", "Asia/Shanghai")
+	require.NoError(t, err)
+	next := schedule.Next(time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC))
+	require.Equal(t, time.Date(2026, 3, 14, 18, 0, 0, 0, time.UTC), next.UTC())
+}
+
+func TestParseCronRejectsInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "@often", "* * * foo *"} {
+		_, err := ParseCron(expr, "")
+		require.Error(t, err, expr)
+	}
+	_, err := ParseCron("* * * * *", "Mars/Olympus")
+	require.Error(t, err)
+}
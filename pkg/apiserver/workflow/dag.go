@@ -0,0 +1,155 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+// HasStepDependencies reports whether any step declares explicit dependencies.
+// Workflows without depends_on keep the classic sequential semantics.
+func HasStepDependencies(steps []*model.WorkflowStep) bool {
+	for _, step := range steps {
+		if step != nil && len(step.DependsOn) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// StepDependencies returns the effective dependency list of every named step.
+// When no step declares depends_on, each step implicitly depends on the step
+// before it, which mirrors how the controller executes sequential workflows.
+func StepDependencies(steps []*model.WorkflowStep) map[string][]string {
+	deps := make(map[string][]string, len(steps))
+	explicit := HasStepDependencies(steps)
+	previous := ""
+	for _, step := range steps {
+		if step == nil || step.Name == "" {
+			continue
+		}
+		key := strings.ToLower(step.Name)
+		switch {
+		case explicit:
+			deps[key] = NormalizeDependsOn(step.DependsOn)
+		case previous != "":
+			deps[key] = []string{previous}
+		default:
+			deps[key] = nil
+		}
+		previous = key
+	}
+	return deps
+}
+
+// ValidateStepDependencies 校验步骤依赖：依赖的步骤必须存在、不能依赖自身，且依赖关系中不能有环
+func ValidateStepDependencies(steps []*model.WorkflowStep) error {
+	if !HasStepDependencies(steps) {
+		return nil
+	}
+	names := make(map[string]struct{}, len(steps))
+	for _, step := range steps {
+		if step == nil {
+			continue
+		}
+		if step.Name == "" {
+			if len(step.DependsOn) > 0 {
+				return fmt.Errorf("a step declaring depends_on must have a name")
+			}
+			continue
+		}
+		names[strings.ToLower(step.Name)] = struct{}{}
+	}
+
+	graph := make(map[string][]string, len(names))
+	var order []string
+	for _, step := range steps {
+		if step == nil || step.Name == "" {
+			continue
+		}
+		key := strings.ToLower(step.Name)
+		deps := NormalizeDependsOn(step.DependsOn)
+		for _, dep := range deps {
+			if dep == key {
+				return fmt.Errorf("step %q cannot depend on itself", step.Name)
+			}
+			if _, ok := names[dep]; !ok {
+				return fmt.Errorf("step %q depends on unknown step %q", step.Name, dep)
+			}
+		}
+		graph[key] = deps
+		order = append(order, key)
+	}
+
+	if cycle := findCycle(order, graph); len(cycle) > 0 {
+		return fmt.Errorf("step dependencies contain a cycle: %s", strings.Join(cycle, " -> "))
+	}
+	return nil
+}
+
+// findCycle walks the graph depth-first and returns the first cycle found,
+// with the starting step repeated at the end (a -> b -> a).
+func findCycle(order []string, graph map[string][]string) []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(order))
+	var stack []string
+	var cycle []string
+
+	var visit func(node string) bool
+	visit = func(node string) bool {
+		state[node] = visiting
+		stack = append(stack, node)
+		for _, dep := range graph[node] {
+			switch state[dep] {
+			case visiting:
+				for i, n := range stack {
+					if n == dep {
+						cycle = append(append([]string{}, stack[i:]...), dep)
+						break
+					}
+				}
+				return true
+			case unvisited:
+				if visit(dep) {
+					return true
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[node] = visited
+		return false
+	}
+
+	for _, node := range order {
+		if state[node] == unvisited && visit(node) {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// NormalizeDependsOn lowercases and trims depends_on entries, dropping blanks and duplicates.
+func NormalizeDependsOn(dependsOn []string) []string {
+	if len(dependsOn) == 0 {
+		return nil
+	}
+	seen := make(map[string]struct{}, len(dependsOn))
+	result := make([]string, 0, len(dependsOn))
+	for _, dep := range dependsOn {
+		key := strings.ToLower(strings.TrimSpace(dep))
+		if key == "" {
+			continue
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		result = append(result, key)
+	}
+	return result
+}
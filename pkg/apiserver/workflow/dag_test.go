@@ -0,0 +1,93 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+func TestValidateStepDependencies(t *testing.T) {
+	cases := []struct {
+		name    string
+		steps   []*model.WorkflowStep
+		wantErr string
+	}{
+		{
+			name: "no dependencies",
+			steps: []*model.WorkflowStep{
+				{Name: "a"},
+				{Name: "b"},
+			},
+		},
+		{
+			name: "diamond",
+			steps: []*model.WorkflowStep{
+				{Name: "a"},
+				{Name: "b", DependsOn: []string{"a"}},
+				{Name: "c", DependsOn: []string{"A"}},
+				{Name: "d", DependsOn: []string{"b", "c"}},
+			},
+		},
+		{
+			name: "unknown dependency",
+			steps: []*model.WorkflowStep{
+				{Name: "a", DependsOn: []string{"missing"}},
+			},
+			wantErr: "unknown step",
+		},
+		{
+			name: "self dependency",
+			steps: []*model.WorkflowStep{
+				{Name: "a", DependsOn: []string{"a"}},
+			},
+			wantErr: "itself",
+		},
+		{
+			name: "cycle",
+			steps: []*model.WorkflowStep{
+				{Name: "a", DependsOn: []string{"c"}},
+				{Name: "b", DependsOn: []string{"a"}},
+				{Name: "c", DependsOn: []string{"b"}},
+			},
+			wantErr: "a -> c -> b -> a",
+		},
+		{
+			name: "unnamed step with dependencies",
+			steps: []*model.WorkflowStep{
+				{Name: "a"},
+				{DependsOn: []string{"a"}},
+			},
+			wantErr: "must have a name",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateStepDependencies(tc.steps)
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.wantErr)
+		})
+	}
+}
+
+func TestStepDependencies(t *testing.T) {
+	sequential := StepDependencies([]*model.WorkflowStep{{Name: "a"}, {Name: "b"}, {Name: "c"}})
+	require.Nil(t, sequential["a"])
+	require.Equal(t, []string{"a"}, sequential["b"])
+	require.Equal(t, []string{"b"}, sequential["c"])
+
+	explicit := StepDependencies([]*model.WorkflowStep{
+		{Name: "a"},
+		{Name: "b"},
+		{Name: "c", DependsOn: []string{"A", "b"}},
+	})
+	require.Nil(t, explicit["a"])
+	require.Nil(t, explicit["b"])
+	require.Equal(t, []string{"a", "b"}, explicit["c"])
+}
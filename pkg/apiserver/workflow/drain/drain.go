@@ -0,0 +1,175 @@
+package drain
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"kubemin-cli/pkg/apiserver/config"
+)
+
+// ErrDrained 是排空超时后取消任务时使用的原因，任务据此交还队列而不是判定失败
+var ErrDrained = errors.New("worker drained, task handed back to the queue")
+
+// Status 排空进度
+type Status struct {
+	Draining    bool
+	Since       time.Time
+	GracePeriod time.Duration
+	InFlight    []string //仍在本实例运行的任务
+	Finished    bool     //所有任务已结束或已交还
+}
+
+// Controller 协调实例的排空：开始后不再领取新任务，运行中的任务在宽限期内结束，
+// 超时后被取消并交还队列。排空不可撤销，实例随后应被重启或下线。
+type Controller struct {
+	grace time.Duration
+
+	mu       sync.Mutex
+	started  chan struct{}
+	finished chan struct{}
+	since    time.Time
+	tasks    map[string]context.CancelCauseFunc
+	idle     chan struct{} // 排空期间最后一个任务结束时关闭
+}
+
+// NewController 创建排空控制器，grace 为运行中任务的宽限期
+func NewController(grace time.Duration) *Controller {
+	if grace <= 0 {
+		grace = config.DefaultWorkerDrainGracePeriod
+	}
+	return &Controller{
+		grace:    grace,
+		started:  make(chan struct{}),
+		finished: make(chan struct{}),
+		tasks:    make(map[string]context.CancelCauseFunc),
+	}
+}
+
+// Track 登记一个开始运行的任务。返回的 ctx 在排空超时时以 ErrDrained 取消，任务结束后调用 done；
+// 排空已开始时返回 false，调用方不应再运行该任务
+func (c *Controller) Track(ctx context.Context, taskID string) (context.Context, func(), bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.drainingLocked() {
+		return ctx, func() {}, false
+	}
+	taskCtx, cancel := context.WithCancelCause(ctx)
+	c.tasks[taskID] = cancel
+	var once sync.Once
+	return taskCtx, func() {
+		once.Do(func() {
+			cancel(nil)
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			delete(c.tasks, taskID)
+			if len(c.tasks) == 0 && c.idle != nil {
+				close(c.idle)
+				c.idle = nil
+			}
+		})
+	}, true
+}
+
+// Begin 开始排空并在后台等待运行中的任务；重复调用没有效果，返回是否由本次调用开始
+func (c *Controller) Begin() bool {
+	c.mu.Lock()
+	if c.drainingLocked() {
+		c.mu.Unlock()
+		return false
+	}
+	c.since = time.Now()
+	close(c.started)
+	idle := make(chan struct{})
+	if len(c.tasks) == 0 {
+		close(idle)
+	} else {
+		c.idle = idle
+	}
+	klog.Infof("worker drain started: inFlight=%d grace=%s", len(c.tasks), c.grace)
+	c.mu.Unlock()
+
+	go c.wait(idle)
+	return true
+}
+
+func (c *Controller) wait(idle chan struct{}) {
+	defer close(c.finished)
+	select {
+	case <-idle:
+		klog.Info("worker drain finished: all in-flight tasks completed")
+		return
+	case <-time.After(c.grace):
+	}
+	c.mu.Lock()
+	handedBack := make([]string, 0, len(c.tasks))
+	for taskID, cancel := range c.tasks {
+		cancel(ErrDrained)
+		handedBack = append(handedBack, taskID)
+	}
+	c.mu.Unlock()
+	klog.Infof("worker drain grace period elapsed, handing back tasks: %v", handedBack)
+	select {
+	case <-idle:
+		klog.Info("worker drain finished: interrupted tasks handed back")
+	case <-time.After(config.WorkerDrainHandoffTimeout):
+		klog.Warningf("worker drain finished before all interrupted tasks exited")
+	}
+}
+
+// Draining 排空是否已开始
+func (c *Controller) Draining() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.drainingLocked()
+}
+
+func (c *Controller) drainingLocked() bool {
+	select {
+	case <-c.started:
+		return true
+	default:
+		return false
+	}
+}
+
+// Started 排空开始时关闭
+func (c *Controller) Started() <-chan struct{} {
+	return c.started
+}
+
+// Finished 运行中的任务全部结束或交还后关闭
+func (c *Controller) Finished() <-chan struct{} {
+	return c.finished
+}
+
+// GracePeriod 运行中任务的宽限期
+func (c *Controller) GracePeriod() time.Duration {
+	return c.grace
+}
+
+// Status 返回当前的排空进度
+func (c *Controller) Status() Status {
+	c.mu.Lock()
+	status := Status{
+		Draining:    c.drainingLocked(),
+		Since:       c.since,
+		GracePeriod: c.grace,
+		InFlight:    make([]string, 0, len(c.tasks)),
+	}
+	for taskID := range c.tasks {
+		status.InFlight = append(status.InFlight, taskID)
+	}
+	c.mu.Unlock()
+	sort.Strings(status.InFlight)
+	select {
+	case <-c.finished:
+		status.Finished = true
+	default:
+	}
+	return status
+}
@@ -0,0 +1,60 @@
+package drain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBeginFinishesWhenTasksComplete(t *testing.T) {
+	c := NewController(time.Minute)
+	ctx, done, ok := c.Track(context.Background(), "task-1")
+	require.True(t, ok)
+
+	require.True(t, c.Begin())
+	require.False(t, c.Begin(), "a drain starts once")
+	require.True(t, c.Draining())
+	require.Equal(t, []string{"task-1"}, c.Status().InFlight)
+
+	done()
+	select {
+	case <-c.Finished():
+	case <-time.After(time.Second):
+		t.Fatal("drain did not finish after the last task completed")
+	}
+	require.NotErrorIs(t, context.Cause(ctx), ErrDrained, "a completed task is not drained")
+	status := c.Status()
+	require.True(t, status.Finished)
+	require.Empty(t, status.InFlight)
+}
+
+func TestBeginCancelsTasksAfterGracePeriod(t *testing.T) {
+	c := NewController(10 * time.Millisecond)
+	ctx, done, ok := c.Track(context.Background(), "task-1")
+	require.True(t, ok)
+	go func() {
+		<-ctx.Done()
+		done()
+	}()
+
+	c.Begin()
+	select {
+	case <-c.Finished():
+	case <-time.After(time.Second):
+		t.Fatal("drain did not finish after the grace period")
+	}
+	require.ErrorIs(t, context.Cause(ctx), ErrDrained)
+}
+
+func TestTrackRefusedWhileDraining(t *testing.T) {
+	c := NewController(0)
+	require.Positive(t, c.GracePeriod(), "the default grace period applies")
+	c.Begin()
+	<-c.Finished()
+
+	_, _, ok := c.Track(context.Background(), "task-1")
+	require.False(t, ok)
+	require.Empty(t, c.Status().InFlight)
+}
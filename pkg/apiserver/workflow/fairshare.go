@@ -0,0 +1,98 @@
+package workflow
+
+import (
+	"sort"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+// ActiveTasksByProject counts the tasks of every project that already hold a dispatch slot,
+// i.e. tasks that were claimed from the waiting queue and have not finished yet.
+func ActiveTasksByProject(tasks []*model.WorkflowQueue) map[string]int {
+	active := make(map[string]int)
+	for _, task := range tasks {
+		if task == nil {
+			continue
+		}
+		switch task.Status {
+		case config.StatusQueued, config.StatusRunning:
+			active[task.ProjectID]++
+		}
+	}
+	return active
+}
+
+// FairShareOrder returns waiting tasks in dispatch order. Higher priority always goes first;
+// among equal priorities the project with the fewest active plus already picked tasks goes
+// next, so one project submitting many tasks cannot starve the others. Within a project tasks
+// keep their submission order. Tasks without a project share one bucket.
+func FairShareOrder(waiting []*model.WorkflowQueue, active map[string]int) []*model.WorkflowQueue {
+	buckets := make(map[string][]*model.WorkflowQueue)
+	var projects []string
+	for _, task := range waiting {
+		if task == nil {
+			continue
+		}
+		if _, ok := buckets[task.ProjectID]; !ok {
+			projects = append(projects, task.ProjectID)
+		}
+		buckets[task.ProjectID] = append(buckets[task.ProjectID], task)
+	}
+	for _, project := range projects {
+		bucket := buckets[project]
+		sort.SliceStable(bucket, func(i, j int) bool {
+			if bucket[i].Priority != bucket[j].Priority {
+				return bucket[i].Priority > bucket[j].Priority
+			}
+			return bucket[i].SubmittedBefore(bucket[j])
+		})
+	}
+	sort.Strings(projects)
+
+	share := make(map[string]int, len(projects))
+	for _, project := range projects {
+		share[project] = active[project]
+	}
+	ordered := make([]*model.WorkflowQueue, 0, len(waiting))
+	for {
+		next := ""
+		found := false
+		for _, project := range projects {
+			if len(buckets[project]) == 0 {
+				continue
+			}
+			if !found || fairShareBefore(buckets[project][0], share[project], buckets[next][0], share[next]) {
+				next = project
+				found = true
+			}
+		}
+		if !found {
+			return ordered
+		}
+		ordered = append(ordered, buckets[next][0])
+		buckets[next] = buckets[next][1:]
+		share[next]++
+	}
+}
+
+func fairShareBefore(a *model.WorkflowQueue, aShare int, b *model.WorkflowQueue, bShare int) bool {
+	if a.Priority != b.Priority {
+		return a.Priority > b.Priority
+	}
+	if aShare != bShare {
+		return aShare < bShare
+	}
+	return a.SubmittedBefore(b)
+}
+
+// QueuePosition returns the 1-based dispatch position of taskID among the waiting tasks,
+// or 0 when the task is not waiting.
+func QueuePosition(waiting []*model.WorkflowQueue, active map[string]int, taskID string) int {
+	for i, task := range FairShareOrder(waiting, active) {
+		if task.TaskID == taskID {
+			return i + 1
+		}
+	}
+	return 0
+}
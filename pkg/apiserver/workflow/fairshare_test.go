@@ -0,0 +1,76 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+func queuedTask(id, project string, priority int, created time.Time) *model.WorkflowQueue {
+	task := &model.WorkflowQueue{TaskID: id, ProjectID: project, Priority: priority, Status: config.StatusWaiting}
+	task.CreateTime = created
+	return task
+}
+
+func taskIDs(tasks []*model.WorkflowQueue) []string {
+	ids := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.TaskID)
+	}
+	return ids
+}
+
+func TestFairShareOrderInterleavesProjects(t *testing.T) {
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	waiting := []*model.WorkflowQueue{
+		queuedTask("a1", "busy", 0, base),
+		queuedTask("a2", "busy", 0, base.Add(time.Second)),
+		queuedTask("a3", "busy", 0, base.Add(2*time.Second)),
+		queuedTask("b1", "quiet", 0, base.Add(3*time.Second)),
+		queuedTask("c1", "other", 0, base.Add(4*time.Second)),
+	}
+	require.Equal(t, []string{"a1", "b1", "c1", "a2", "a3"}, taskIDs(FairShareOrder(waiting, nil)))
+}
+
+func TestFairShareOrderHonoursPriorityAndActiveTasks(t *testing.T) {
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	waiting := []*model.WorkflowQueue{
+		queuedTask("a1", "busy", 0, base),
+		queuedTask("b1", "quiet", 0, base.Add(time.Second)),
+		queuedTask("b2", "quiet", 10, base.Add(2*time.Second)),
+	}
+	active := map[string]int{"busy": 2}
+	// The urgent task goes first; busy already runs two tasks, so quiet is preferred next.
+	require.Equal(t, []string{"b2", "b1", "a1"}, taskIDs(FairShareOrder(waiting, active)))
+	require.Equal(t, 3, QueuePosition(waiting, active, "a1"))
+	require.Zero(t, QueuePosition(waiting, nil, "missing"))
+}
+
+func TestFairShareOrderUsesSubmitTime(t *testing.T) {
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	handedBack := queuedTask("a1", "busy", 0, base.Add(time.Minute))
+	handedBack.SubmitTime = base.UnixNano()
+	newer := queuedTask("a2", "busy", 0, base.Add(time.Second))
+	newer.SubmitTime = base.Add(time.Second).UnixNano()
+	other := queuedTask("b1", "quiet", 0, base.Add(2*time.Second))
+	other.SubmitTime = base.Add(2 * time.Second).UnixNano()
+
+	// a1 ran before it was handed back, which refreshed its CreateTime; it keeps its place.
+	waiting := []*model.WorkflowQueue{newer, other, handedBack}
+	require.Equal(t, []string{"a1", "b1", "a2"}, taskIDs(FairShareOrder(waiting, nil)))
+	require.Equal(t, 1, QueuePosition(waiting, nil, "a1"))
+}
+
+func TestActiveTasksByProject(t *testing.T) {
+	active := ActiveTasksByProject([]*model.WorkflowQueue{
+		{ProjectID: "p1", Status: config.StatusRunning},
+		{ProjectID: "p1", Status: config.StatusQueued},
+		{ProjectID: "p1", Status: config.StatusWaiting},
+		{ProjectID: "p2", Status: config.StatusWaitingApprove},
+	})
+	require.Equal(t, map[string]int{"p1": 2}, active)
+}
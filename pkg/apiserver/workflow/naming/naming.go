@@ -11,6 +11,9 @@ const (
 	maxResourceNameLength = 63
 	defaultComponentName  = "component"
 	defaultAppSegment     = "app"
+
+	// maxCronJobNameLength leaves room for the 11-character suffix the CronJob controller appends to Job names.
+	maxCronJobNameLength = 52
 )
 
 // WebServiceName builds a deterministic deployment name for stateless components.
@@ -28,6 +31,25 @@ func StoreServerName(name, appID string) string {
 	return buildResourceName("store", name, appID)
 }
 
+// DaemonSetName builds a DaemonSet name for daemon components.
+func DaemonSetName(name, appID string) string {
+	return buildResourceName("ds", name, appID)
+}
+
+// BatchJobName builds a Job name for batch job components.
+func BatchJobName(name, appID string) string {
+	return buildResourceName("job", name, appID)
+}
+
+// CronJobName builds a CronJob name for cronjob components.
+func CronJobName(name, appID string) string {
+	result := buildResourceName("cron", name, appID)
+	if len(result) > maxCronJobNameLength {
+		result = strings.Trim(result[:maxCronJobNameLength], "-")
+	}
+	return result
+}
+
 // IngressName builds an ingress resource name tied to the component/app pair.
 func IngressName(name, appID string) string {
 	return buildResourceName("ing", name, appID)
@@ -0,0 +1,181 @@
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"kubemin-cli/pkg/apiserver/config"
+	msg "kubemin-cli/pkg/apiserver/infrastructure/messaging"
+)
+
+// EventType 区分任务级与 Job 级的状态变化
+type EventType string
+
+const (
+	EventTask EventType = "task"
+	EventJob  EventType = "job"
+)
+
+// subscriberBuffer 单个订阅者未消费事件的上限，超过后订阅被关闭，客户端重连即可重放当前状态
+const subscriberBuffer = 64
+
+// Event 工作流任务或其中某个 Job 的一次状态变化
+type Event struct {
+	Type       EventType     `json:"type"`
+	TaskID     string        `json:"task_id"`
+	WorkflowID string        `json:"workflow_id,omitempty"`
+	AppID      string        `json:"app_id,omitempty"`
+	Status     config.Status `json:"status"`
+	Job        *JobState     `json:"job,omitempty"`
+	Timestamp  int64         `json:"timestamp"` //Unix 毫秒
+}
+
+// JobState Job 事件携带的 Job 信息
+type JobState struct {
+	Name      string        `json:"name"`
+	Type      string        `json:"type"`
+	Status    config.Status `json:"status"`
+	Error     string        `json:"error,omitempty"`
+	Attempt   int           `json:"attempt"`
+	StartTime int64         `json:"start_time,omitempty"`
+	EndTime   int64         `json:"end_time,omitempty"`
+}
+
+// Broker 将事件发布到消息后端，并把后端收到的事件按 taskID 分发给本副本的订阅者。
+// 执行工作流的副本与提供流式接口的副本可以不同。
+type Broker struct {
+	pubsub  msg.PubSub
+	channel string
+
+	mu      sync.Mutex
+	running bool
+	cancel  context.CancelFunc
+	subs    map[string]map[chan Event]struct{}
+}
+
+// NewBroker 创建 Broker；channel 为所有副本共享的事件通道名
+func NewBroker(pubsub msg.PubSub, channel string) *Broker {
+	return &Broker{
+		pubsub:  pubsub,
+		channel: channel,
+		subs:    make(map[string]map[chan Event]struct{}),
+	}
+}
+
+// Publish 尽力发布事件，失败只记录日志，不影响工作流执行
+func (b *Broker) Publish(ctx context.Context, event Event) {
+	if b == nil || b.pubsub == nil || event.TaskID == "" {
+		return
+	}
+	if event.Timestamp == 0 {
+		event.Timestamp = time.Now().UnixMilli()
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		klog.Errorf("marshal progress event of task %s failed: %v", event.TaskID, err)
+		return
+	}
+	if err := b.pubsub.Publish(context.WithoutCancel(ctx), b.channel, payload); err != nil {
+		klog.Warningf("publish progress event of task %s failed: %v", event.TaskID, err)
+	}
+}
+
+// Subscribe 订阅某个任务的事件；返回的取消函数必须调用。订阅者消费过慢时通道会被关闭
+func (b *Broker) Subscribe(taskID string) (<-chan Event, func(), error) {
+	if b == nil || b.pubsub == nil {
+		return nil, nil, errors.New("progress broker is not configured")
+	}
+	b.mu.Lock()
+	if err := b.startLocked(); err != nil {
+		b.mu.Unlock()
+		return nil, nil, err
+	}
+	ch := make(chan Event, subscriberBuffer)
+	if b.subs[taskID] == nil {
+		b.subs[taskID] = make(map[chan Event]struct{})
+	}
+	b.subs[taskID][ch] = struct{}{}
+	b.mu.Unlock()
+	var once sync.Once
+	return ch, func() { once.Do(func() { b.remove(taskID, ch) }) }, nil
+}
+
+// Close 停止接收后端事件，并关闭所有订阅
+func (b *Broker) Close() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cancel != nil {
+		b.cancel()
+	}
+}
+
+// startLocked 在没有后端订阅时订阅事件通道，之后由单个 goroutine 分发；
+// 后端订阅中断时关闭全部订阅者，下一次订阅重新建立
+func (b *Broker) startLocked() error {
+	if b.running {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := b.pubsub.Subscribe(ctx, b.channel)
+	if err != nil {
+		cancel()
+		return err
+	}
+	b.running, b.cancel = true, cancel
+	go b.dispatch(stream)
+	return nil
+}
+
+func (b *Broker) dispatch(stream <-chan []byte) {
+	for payload := range stream {
+		var event Event
+		if err := json.Unmarshal(payload, &event); err != nil {
+			klog.Warningf("decode progress event failed: %v", err)
+			continue
+		}
+		b.mu.Lock()
+		for ch := range b.subs[event.TaskID] {
+			select {
+			case ch <- event:
+			default:
+				klog.Warningf("progress subscriber of task %s is too slow, closing its stream", event.TaskID)
+				b.removeLocked(event.TaskID, ch)
+			}
+		}
+		b.mu.Unlock()
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.running = false
+	for taskID, subs := range b.subs {
+		for ch := range subs {
+			b.removeLocked(taskID, ch)
+		}
+	}
+}
+
+func (b *Broker) remove(taskID string, ch chan Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.removeLocked(taskID, ch)
+}
+
+func (b *Broker) removeLocked(taskID string, ch chan Event) {
+	subs := b.subs[taskID]
+	if _, ok := subs[ch]; !ok {
+		return
+	}
+	delete(subs, ch)
+	close(ch)
+	if len(subs) == 0 {
+		delete(b.subs, taskID)
+	}
+}
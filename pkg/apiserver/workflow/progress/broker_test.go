@@ -0,0 +1,70 @@
+package progress
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/config"
+	msg "kubemin-cli/pkg/apiserver/infrastructure/messaging"
+)
+
+func nextEvent(t *testing.T, ch <-chan Event) (Event, bool) {
+	t.Helper()
+	select {
+	case event, ok := <-ch:
+		return event, ok
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for progress event")
+	}
+	return Event{}, false
+}
+
+func TestBrokerRoutesEventsByTask(t *testing.T) {
+	broker := NewBroker(msg.NewLocalPubSub(), "kubemin.workflow.events")
+	defer broker.Close()
+
+	events, unsubscribe, err := broker.Subscribe("task-1")
+	require.NoError(t, err)
+	defer unsubscribe()
+
+	ctx := context.Background()
+	broker.Publish(ctx, Event{Type: EventTask, TaskID: "task-2", Status: config.StatusRunning})
+	broker.Publish(ctx, Event{Type: EventJob, TaskID: "task-1", Status: config.StatusRunning,
+		Job: &JobState{Name: "web", Status: config.StatusFailed, Error: "image pull backoff", Attempt: 2}})
+
+	event, ok := nextEvent(t, events)
+	require.True(t, ok)
+	require.Equal(t, "task-1", event.TaskID)
+	require.Equal(t, "image pull backoff", event.Job.Error)
+	require.NotZero(t, event.Timestamp)
+
+	unsubscribe()
+	_, ok = nextEvent(t, events)
+	require.False(t, ok, "unsubscribe closes the stream")
+}
+
+func TestBrokerClosesSlowSubscriber(t *testing.T) {
+	broker := NewBroker(msg.NewLocalPubSub(), "kubemin.workflow.events")
+	defer broker.Close()
+
+	events, unsubscribe, err := broker.Subscribe("task-1")
+	require.NoError(t, err)
+	defer unsubscribe()
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		broker.Publish(context.Background(), Event{Type: EventTask, TaskID: "task-1", Status: config.StatusRunning})
+	}
+	require.Eventually(t, func() bool {
+		broker.mu.Lock()
+		defer broker.mu.Unlock()
+		return len(broker.subs["task-1"]) == 0
+	}, 2*time.Second, 10*time.Millisecond)
+	drained := 0
+	for range events {
+		drained++
+	}
+	require.Equal(t, subscriberBuffer, drained, "buffered events are still delivered before the stream ends")
+}
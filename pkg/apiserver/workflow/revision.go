@@ -0,0 +1,66 @@
+package workflow
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+// revisionComponent is the deployable part of a component; runtime status synced by
+// the informer and bookkeeping timestamps do not change what a job would apply.
+type revisionComponent struct {
+	Name          string            `json:"name"`
+	Namespace     string            `json:"namespace"`
+	Image         string            `json:"image"`
+	Replicas      int32             `json:"replicas"`
+	ComponentType config.JobType    `json:"component_type"`
+	Properties    *model.JSONStruct `json:"properties,omitempty"`
+	Traits        *model.JSONStruct `json:"traits,omitempty"`
+}
+
+// Revision 计算工作流的修订版本：由步骤定义与各组件的部署定义生成摘要，
+// 两次执行的修订版本相同说明生成的 Job 完全一致
+func Revision(workflow *model.Workflow, components []*model.ApplicationComponent) (string, error) {
+	items := make([]revisionComponent, 0, len(components))
+	for _, component := range components {
+		if component == nil {
+			continue
+		}
+		items = append(items, revisionComponent{
+			Name:          component.Name,
+			Namespace:     component.Namespace,
+			Image:         component.Image,
+			Replicas:      component.Replicas,
+			ComponentType: component.ComponentType,
+			Properties:    component.Properties,
+			Traits:        component.Traits,
+		})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+	var steps *model.JSONStruct
+	if workflow != nil {
+		steps = workflow.Steps
+	}
+	raw, err := json.Marshal(struct {
+		Steps      *model.JSONStruct   `json:"steps"`
+		Components []revisionComponent `json:"components"`
+	}{Steps: steps, Components: items})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// JobKey identifies a job across tasks of the same workflow; job names are derived
+// from component names, so they are stable between executions.
+func JobKey(jobType, name string) string {
+	return jobType + "/" + strings.ToLower(name)
+}
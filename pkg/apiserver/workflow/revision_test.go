@@ -0,0 +1,41 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+)
+
+func TestRevisionIgnoresRuntimeFields(t *testing.T) {
+	steps := model.JSONStruct{"steps": []interface{}{map[string]interface{}{"name": "web"}}}
+	wf := &model.Workflow{ID: "wf-1", Steps: &steps}
+	web := &model.ApplicationComponent{Name: "web", Image: "nginx:1.21", Replicas: 2, ComponentType: config.ServerJob}
+	conf := &model.ApplicationComponent{Name: "conf", ComponentType: config.ConfJob}
+
+	base, err := Revision(wf, []*model.ApplicationComponent{web, conf})
+	require.NoError(t, err)
+	require.Len(t, base, 64)
+
+	// Component order, informer status and timestamps do not change the revision.
+	synced := *web
+	synced.Status = "Running"
+	synced.ReadyReplicas = 2
+	synced.UpdateTime = time.Now()
+	same, err := Revision(wf, []*model.ApplicationComponent{conf, &synced})
+	require.NoError(t, err)
+	require.Equal(t, base, same)
+
+	changed := *web
+	changed.Image = "nginx:1.22"
+	other, err := Revision(wf, []*model.ApplicationComponent{&changed, conf})
+	require.NoError(t, err)
+	require.NotEqual(t, base, other)
+}
+
+func TestJobKey(t *testing.T) {
+	require.Equal(t, "deploy/web-api", JobKey("deploy", "Web-API"))
+}
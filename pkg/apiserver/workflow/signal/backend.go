@@ -0,0 +1,220 @@
+package signal
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"k8s.io/klog/v2"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+	"kubemin-cli/pkg/apiserver/utils/cache"
+)
+
+// CancelBackend 传递工作流任务的取消信号。Watch 为 Job 派生在任务被取消时结束的上下文，
+// Cancel 通知该任务的所有观察者；任务取消后新建的观察者会立即收到取消
+type CancelBackend interface {
+	Watch(ctx context.Context, taskID string) (*CancelWatcher, context.Context, context.CancelFunc, error)
+	Cancel(ctx context.Context, taskID, reason string) error
+}
+
+// PauseBackend 保存暂停请求，运行该任务的 Worker 在启动下一个优先级分组前检查
+type PauseBackend interface {
+	Pause(ctx context.Context, taskID, reason string) error
+	PauseRequested(ctx context.Context, taskID string) (bool, string, error)
+	ClearPause(ctx context.Context, taskID string) error
+}
+
+// Backend 传递取消与暂停信号，两者由同一配置项选择，各部署模式下语义一致
+type Backend interface {
+	CancelBackend
+	PauseBackend
+}
+
+var (
+	backendMu       sync.RWMutex
+	backendInstance Backend
+)
+
+// SetBackend 设置取消与暂停信号使用的后端；传入 nil 时恢复为按全局 Redis 客户端选择
+func SetBackend(b Backend) {
+	backendMu.Lock()
+	defer backendMu.Unlock()
+	backendInstance = b
+}
+
+func currentBackend() Backend {
+	backendMu.RLock()
+	b := backendInstance
+	backendMu.RUnlock()
+	if b != nil {
+		return b
+	}
+	return NewRedisBackend(cache.GetGlobalRedisClient())
+}
+
+// localBackend 在进程内登记观察者与暂停请求，只适用于单实例部署
+type localBackend struct {
+	registry *localCancelRegistry
+	pauses   *localPauseRegistry
+}
+
+// NewLocalBackend creates a backend that delivers signals within this process only.
+func NewLocalBackend() Backend {
+	return &localBackend{registry: newLocalCancelRegistry(), pauses: newLocalPauseRegistry()}
+}
+
+func (b *localBackend) Watch(ctx context.Context, taskID string) (*CancelWatcher, context.Context, context.CancelFunc, error) {
+	watcher, derivedCtx, cancelFn := b.registry.watch(ctx, taskID)
+	return watcher, derivedCtx, cancelFn, nil
+}
+
+func (b *localBackend) Cancel(_ context.Context, taskID, reason string) error {
+	b.registry.cancel(taskID, normalizeCancelReason(reason))
+	return nil
+}
+
+func (b *localBackend) Pause(_ context.Context, taskID, reason string) error {
+	b.pauses.pause(taskID, normalizePauseReason(reason))
+	return nil
+}
+
+func (b *localBackend) PauseRequested(_ context.Context, taskID string) (bool, string, error) {
+	ok, reason := b.pauses.requested(taskID)
+	return ok, reason, nil
+}
+
+func (b *localBackend) ClearPause(_ context.Context, taskID string) error {
+	b.pauses.clear(taskID)
+	return nil
+}
+
+// redisBackend 通过 Redis 键在实例间传递信号
+type redisBackend struct {
+	cli *redis.Client
+}
+
+// NewRedisBackend creates a backend that signals through Redis keys. A nil client falls
+// back to the shared in-process registries.
+func NewRedisBackend(cli *redis.Client) Backend {
+	return &redisBackend{cli: cli}
+}
+
+func (b *redisBackend) Watch(ctx context.Context, taskID string) (*CancelWatcher, context.Context, context.CancelFunc, error) {
+	return WatchWithClient(ctx, taskID, b.cli)
+}
+
+func (b *redisBackend) Cancel(ctx context.Context, taskID, reason string) error {
+	return CancelWithClient(ctx, taskID, reason, b.cli)
+}
+
+func (b *redisBackend) Pause(ctx context.Context, taskID, reason string) error {
+	return PauseWithClient(ctx, taskID, reason, b.cli)
+}
+
+func (b *redisBackend) PauseRequested(ctx context.Context, taskID string) (bool, string, error) {
+	return PauseRequestedWithClient(ctx, taskID, b.cli)
+}
+
+func (b *redisBackend) ClearPause(ctx context.Context, taskID string) error {
+	return ClearPauseWithClient(ctx, taskID, b.cli)
+}
+
+// datastoreBackend 轮询任务记录的状态发现取消，暂停请求保存在独立的表中，不依赖额外组件即可跨实例生效。
+// 取消状态与原因由调用方写入任务记录，Cancel 只负责立即通知本实例的观察者
+type datastoreBackend struct {
+	store    datastore.DataStore
+	interval time.Duration
+	registry *localCancelRegistry
+}
+
+// NewDatastoreBackend creates a backend that polls the task record every interval and
+// cancels the watchers once the task is cancelled. Pause requests are stored as records.
+func NewDatastoreBackend(store datastore.DataStore, interval time.Duration) Backend {
+	if interval <= 0 {
+		interval = config.DefaultCancelPollInterval
+	}
+	return &datastoreBackend{store: store, interval: interval, registry: newLocalCancelRegistry()}
+}
+
+func (b *datastoreBackend) Watch(ctx context.Context, taskID string) (*CancelWatcher, context.Context, context.CancelFunc, error) {
+	watcher, derivedCtx, cancelFn := b.registry.watch(ctx, taskID)
+	if derivedCtx.Err() != nil {
+		return watcher, derivedCtx, cancelFn, nil
+	}
+	b.check(derivedCtx, watcher)
+	if derivedCtx.Err() != nil {
+		return watcher, derivedCtx, cancelFn, nil
+	}
+	watcher.wg.Add(1)
+	go b.poll(derivedCtx, watcher)
+	return watcher, derivedCtx, cancelFn, nil
+}
+
+func (b *datastoreBackend) Cancel(_ context.Context, taskID, reason string) error {
+	b.registry.cancel(taskID, normalizeCancelReason(reason))
+	return nil
+}
+
+func (b *datastoreBackend) poll(ctx context.Context, watcher *CancelWatcher) {
+	defer watcher.wg.Done()
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-watcher.stopCh:
+			return
+		case <-ticker.C:
+			b.check(ctx, watcher)
+		}
+	}
+}
+
+// check cancels the watcher when the task record is cancelled. Read failures are logged
+// and retried on the next tick.
+func (b *datastoreBackend) check(ctx context.Context, watcher *CancelWatcher) {
+	task := &model.WorkflowQueue{TaskID: watcher.taskID}
+	if err := b.store.Get(ctx, task); err != nil {
+		if !errors.Is(err, datastore.ErrRecordNotExist) && !errors.Is(err, context.Canceled) {
+			klog.Warningf("cancel watcher read task %s failed: %v", watcher.taskID, err)
+		}
+		return
+	}
+	if task.Status == config.StatusCancelled {
+		watcher.fire(normalizeCancelReason(task.CancelReason))
+	}
+}
+
+func (b *datastoreBackend) Pause(ctx context.Context, taskID, reason string) error {
+	request := &model.WorkflowPauseRequest{TaskID: taskID, Reason: normalizePauseReason(reason)}
+	err := b.store.Add(ctx, request)
+	if errors.Is(err, datastore.ErrRecordExist) {
+		return b.store.Put(ctx, request)
+	}
+	return err
+}
+
+func (b *datastoreBackend) PauseRequested(ctx context.Context, taskID string) (bool, string, error) {
+	request := &model.WorkflowPauseRequest{TaskID: taskID}
+	if err := b.store.Get(ctx, request); err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return false, "", nil
+		}
+		return false, "", err
+	}
+	return true, request.Reason, nil
+}
+
+func (b *datastoreBackend) ClearPause(ctx context.Context, taskID string) error {
+	err := b.store.Delete(ctx, &model.WorkflowPauseRequest{TaskID: taskID})
+	if errors.Is(err, datastore.ErrRecordNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,242 @@
+package signal
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"kubemin-cli/pkg/apiserver/config"
+	"kubemin-cli/pkg/apiserver/domain/model"
+	"kubemin-cli/pkg/apiserver/infrastructure/datastore"
+)
+
+// taskStatusStore serves a single workflow task record; other methods are not used.
+type taskStatusStore struct {
+	datastore.DataStore
+	mu     sync.Mutex
+	status config.Status
+	reason string
+}
+
+func (s *taskStatusStore) Get(_ context.Context, entity datastore.Entity) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task := entity.(*model.WorkflowQueue)
+	task.Status = s.status
+	task.CancelReason = s.reason
+	return nil
+}
+
+func (s *taskStatusStore) cancel(reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = config.StatusCancelled
+	s.reason = reason
+}
+
+func waitCancelled(t *testing.T, ctx context.Context) {
+	t.Helper()
+	select {
+	case <-ctx.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected cancel signal to close context")
+	}
+}
+
+func TestLocalCancelBackend(t *testing.T) {
+	backend := NewLocalBackend()
+
+	watcher, jobCtx, cancelFn, err := backend.Watch(context.Background(), "task-memory")
+	if err != nil {
+		t.Fatalf("watcher setup failed: %v", err)
+	}
+	defer cancelFn()
+	if err := backend.Cancel(context.Background(), "task-memory", "manual stop"); err != nil {
+		t.Fatalf("send cancel signal: %v", err)
+	}
+	waitCancelled(t, jobCtx)
+	if reason := watcher.Reason(); reason != "manual stop" {
+		t.Fatalf("unexpected cancel reason: %s", reason)
+	}
+	watcher.Stop(context.Background())
+
+	// A job started after the cancellation must not run.
+	late, lateCtx, lateCancel, err := backend.Watch(context.Background(), "task-memory")
+	if err != nil {
+		t.Fatalf("late watcher setup failed: %v", err)
+	}
+	defer lateCancel()
+	if lateCtx.Err() == nil {
+		t.Fatalf("expected watcher of a cancelled task to start cancelled")
+	}
+	if reason := ReasonFromContext(lateCtx); reason != "manual stop" {
+		t.Fatalf("unexpected cancel reason: %s", reason)
+	}
+	late.Stop(context.Background())
+}
+
+func TestDatastoreCancelBackendPollsTaskStatus(t *testing.T) {
+	store := &taskStatusStore{status: config.StatusRunning}
+	backend := NewDatastoreBackend(store, 10*time.Millisecond)
+
+	watcher, jobCtx, cancelFn, err := backend.Watch(context.Background(), "task-db")
+	if err != nil {
+		t.Fatalf("watcher setup failed: %v", err)
+	}
+	defer cancelFn()
+	if jobCtx.Err() != nil {
+		t.Fatalf("running task should not be cancelled")
+	}
+
+	// Another instance cancels the task: only the task record changes.
+	store.cancel("cancelled by admin")
+	waitCancelled(t, jobCtx)
+	if reason := watcher.Reason(); reason != "cancelled by admin" {
+		t.Fatalf("unexpected cancel reason: %s", reason)
+	}
+	watcher.Stop(context.Background())
+
+	late, lateCtx, lateCancel, err := backend.Watch(context.Background(), "task-db")
+	if err != nil {
+		t.Fatalf("late watcher setup failed: %v", err)
+	}
+	defer lateCancel()
+	if lateCtx.Err() == nil {
+		t.Fatalf("expected watcher of a cancelled task to start cancelled")
+	}
+	late.Stop(context.Background())
+}
+
+func TestDatastoreCancelBackendLocalCancel(t *testing.T) {
+	store := &taskStatusStore{status: config.StatusRunning}
+	backend := NewDatastoreBackend(store, time.Hour)
+
+	watcher, jobCtx, cancelFn, err := backend.Watch(context.Background(), "task-db-local")
+	if err != nil {
+		t.Fatalf("watcher setup failed: %v", err)
+	}
+	defer cancelFn()
+	if err := backend.Cancel(context.Background(), "task-db-local", ""); err != nil {
+		t.Fatalf("send cancel signal: %v", err)
+	}
+	waitCancelled(t, jobCtx)
+	if reason := watcher.Reason(); reason != "cancelled" {
+		t.Fatalf("unexpected cancel reason: %s", reason)
+	}
+	watcher.Stop(context.Background())
+}
+
+func TestSetBackend(t *testing.T) {
+	backend := NewLocalBackend()
+	SetBackend(backend)
+	defer SetBackend(nil)
+
+	watcher, jobCtx, cancelFn, err := Watch(context.Background(), "task-configured")
+	if err != nil {
+		t.Fatalf("watcher setup failed: %v", err)
+	}
+	defer cancelFn()
+	if err := Cancel(context.Background(), "task-configured", "configured"); err != nil {
+		t.Fatalf("send cancel signal: %v", err)
+	}
+	waitCancelled(t, jobCtx)
+	if reason := watcher.Reason(); reason != "configured" {
+		t.Fatalf("unexpected cancel reason: %s", reason)
+	}
+	watcher.Stop(context.Background())
+}
+
+// pauseStore keeps pause request records in memory; other methods are not used.
+type pauseStore struct {
+	datastore.DataStore
+	requests map[string]string
+}
+
+func (s *pauseStore) Add(_ context.Context, entity datastore.Entity) error {
+	request := entity.(*model.WorkflowPauseRequest)
+	if _, ok := s.requests[request.TaskID]; ok {
+		return datastore.ErrRecordExist
+	}
+	s.requests[request.TaskID] = request.Reason
+	return nil
+}
+
+func (s *pauseStore) Put(_ context.Context, entity datastore.Entity) error {
+	request := entity.(*model.WorkflowPauseRequest)
+	s.requests[request.TaskID] = request.Reason
+	return nil
+}
+
+func (s *pauseStore) Get(_ context.Context, entity datastore.Entity) error {
+	request := entity.(*model.WorkflowPauseRequest)
+	reason, ok := s.requests[request.TaskID]
+	if !ok {
+		return datastore.ErrRecordNotExist
+	}
+	request.Reason = reason
+	return nil
+}
+
+func (s *pauseStore) Delete(_ context.Context, entity datastore.Entity) error {
+	request := entity.(*model.WorkflowPauseRequest)
+	if _, ok := s.requests[request.TaskID]; !ok {
+		return datastore.ErrRecordNotExist
+	}
+	delete(s.requests, request.TaskID)
+	return nil
+}
+
+func TestDatastoreBackendPause(t *testing.T) {
+	ctx := context.Background()
+	store := &pauseStore{requests: make(map[string]string)}
+	// Two instances share the datastore: a pause sent to one is seen by the other.
+	api := NewDatastoreBackend(store, time.Hour)
+	worker := NewDatastoreBackend(store, time.Hour)
+
+	paused, _, err := worker.PauseRequested(ctx, "task-pause")
+	if err != nil || paused {
+		t.Fatalf("expected no pause request, got paused=%v err=%v", paused, err)
+	}
+	if err := api.Pause(ctx, "task-pause", ""); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	if err := api.Pause(ctx, "task-pause", "investigating"); err != nil {
+		t.Fatalf("repeat pause: %v", err)
+	}
+	paused, reason, err := worker.PauseRequested(ctx, "task-pause")
+	if err != nil || !paused || reason != "investigating" {
+		t.Fatalf("expected pause request, got paused=%v reason=%q err=%v", paused, reason, err)
+	}
+
+	if err := api.ClearPause(ctx, "task-pause"); err != nil {
+		t.Fatalf("clear pause: %v", err)
+	}
+	if err := api.ClearPause(ctx, "task-pause"); err != nil {
+		t.Fatalf("clearing a missing request should not fail: %v", err)
+	}
+	paused, _, err = worker.PauseRequested(ctx, "task-pause")
+	if err != nil || paused {
+		t.Fatalf("expected pause request to be cleared, got paused=%v err=%v", paused, err)
+	}
+}
+
+func TestSetBackendRoutesPause(t *testing.T) {
+	ctx := context.Background()
+	SetBackend(NewLocalBackend())
+	defer SetBackend(nil)
+
+	if err := Pause(ctx, "task-configured-pause", "hold"); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	if paused, _, _ := PauseRequestedWithClient(ctx, "task-configured-pause", nil); paused {
+		t.Fatalf("pause should be stored by the configured backend, not the fallback registry")
+	}
+	paused, reason, err := PauseRequested(ctx, "task-configured-pause")
+	if err != nil || !paused || reason != "hold" {
+		t.Fatalf("expected pause request, got paused=%v reason=%q err=%v", paused, reason, err)
+	}
+	if err := ClearPause(ctx, "task-configured-pause"); err != nil {
+		t.Fatalf("clear pause: %v", err)
+	}
+}
@@ -10,8 +10,6 @@ import (
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"k8s.io/klog/v2"
-
-	"kubemin-cli/pkg/apiserver/utils/cache"
 )
 
 const (
@@ -23,11 +21,12 @@ const (
 	extendInterval = 10 * time.Second
 )
 
-// CancelWatcher coordinates redis-backed cancellation signalling for a workflow task.
+// CancelWatcher coordinates cancellation signalling for a workflow task.
 type CancelWatcher struct {
 	cli      *redis.Client
 	key      string
 	token    string
+	registry *localCancelRegistry
 	stopCh   chan struct{}
 	once     sync.Once
 	wg       sync.WaitGroup
@@ -53,25 +52,54 @@ func (c *cancelState) get() string {
 	return c.reason
 }
 
+// localCancelRegistry tracks in-process watchers together with recently cancelled tasks,
+// so a job that starts shortly after the cancellation is cancelled immediately, as with Redis.
 type localCancelRegistry struct {
-	mu       sync.Mutex
-	watchers map[string]map[*CancelWatcher]struct{}
+	mu        sync.Mutex
+	watchers  map[string]map[*CancelWatcher]struct{}
+	cancelled map[string]localCancelMark
 }
 
-var localCancelRegistryInstance = &localCancelRegistry{
-	watchers: make(map[string]map[*CancelWatcher]struct{}),
+type localCancelMark struct {
+	reason    string
+	expiresAt time.Time
 }
 
-func (r *localCancelRegistry) add(taskID string, watcher *CancelWatcher) {
-	if watcher == nil {
-		return
+func newLocalCancelRegistry() *localCancelRegistry {
+	return &localCancelRegistry{
+		watchers:  make(map[string]map[*CancelWatcher]struct{}),
+		cancelled: make(map[string]localCancelMark),
+	}
+}
+
+var localCancelRegistryInstance = newLocalCancelRegistry()
+
+// watch registers a watcher for the task. The returned context is already cancelled when
+// the task was cancelled within defaultExpiry.
+func (r *localCancelRegistry) watch(ctx context.Context, taskID string) (*CancelWatcher, context.Context, context.CancelFunc) {
+	watcher := &CancelWatcher{
+		registry: r,
+		state:    &cancelState{},
+		stopCh:   make(chan struct{}),
+		taskID:   taskID,
 	}
+	derivedCtx, cancelFn := context.WithCancel(ctx)
+	derivedCtx = context.WithValue(derivedCtx, cancelStateKey{}, watcher.state)
+	watcher.cancelFn = cancelFn
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	r.pruneLocked(time.Now())
+	if mark, ok := r.cancelled[taskID]; ok {
+		watcher.state.set(mark.reason)
+		cancelFn()
+		return watcher, derivedCtx, cancelFn
+	}
 	if _, ok := r.watchers[taskID]; !ok {
 		r.watchers[taskID] = make(map[*CancelWatcher]struct{})
 	}
 	r.watchers[taskID][watcher] = struct{}{}
+	return watcher, derivedCtx, cancelFn
 }
 
 func (r *localCancelRegistry) remove(taskID string, watcher *CancelWatcher) {
@@ -89,30 +117,31 @@ func (r *localCancelRegistry) remove(taskID string, watcher *CancelWatcher) {
 }
 
 func (r *localCancelRegistry) cancel(taskID, reason string) {
+	now := time.Now()
 	r.mu.Lock()
+	r.pruneLocked(now)
+	r.cancelled[taskID] = localCancelMark{reason: reason, expiresAt: now.Add(defaultExpiry)}
 	listeners := r.watchers[taskID]
 	delete(r.watchers, taskID)
 	r.mu.Unlock()
-	if len(listeners) == 0 {
-		return
-	}
 	for watcher := range listeners {
-		if watcher == nil {
-			continue
-		}
-		watcher.state.set(reason)
-		if watcher.cancelFn != nil {
-			watcher.cancelFn()
+		watcher.fire(reason)
+	}
+}
+
+func (r *localCancelRegistry) pruneLocked(now time.Time) {
+	for taskID, mark := range r.cancelled {
+		if now.After(mark.expiresAt) {
+			delete(r.cancelled, taskID)
 		}
 	}
 }
 
-// Watch establishes a cancellation watcher for the given workflow task. When Redis
-// is not configured, the context is returned unchanged and cleanup becomes a no-op.
-// Note: This function uses the global Redis client. For dependency injection in tests,
-// use WatchWithClient instead.
+// Watch establishes a cancellation watcher for the given workflow task using the
+// configured Backend. Without one, the global Redis client is used when set and
+// an in-process registry otherwise.
 func Watch(ctx context.Context, taskID string) (*CancelWatcher, context.Context, context.CancelFunc, error) {
-	return WatchWithClient(ctx, taskID, cache.GetGlobalRedisClient())
+	return currentBackend().Watch(ctx, taskID)
 }
 
 // WatchWithClient is like Watch but accepts an explicit Redis client for dependency injection.
@@ -120,16 +149,7 @@ func Watch(ctx context.Context, taskID string) (*CancelWatcher, context.Context,
 func WatchWithClient(ctx context.Context, taskID string, cli *redis.Client) (*CancelWatcher, context.Context, context.CancelFunc, error) {
 	if cli == nil {
 		// No redis available; fall back to an in-memory registry.
-		state := &cancelState{}
-		watcher := &CancelWatcher{
-			state:  state,
-			stopCh: make(chan struct{}),
-			taskID: taskID,
-		}
-		derivedCtx, cancelFn := context.WithCancel(ctx)
-		derivedCtx = context.WithValue(derivedCtx, cancelStateKey{}, watcher.state)
-		watcher.cancelFn = cancelFn
-		localCancelRegistryInstance.add(taskID, watcher)
+		watcher, derivedCtx, cancelFn := localCancelRegistryInstance.watch(ctx, taskID)
 		return watcher, derivedCtx, cancelFn, nil
 	}
 
@@ -174,18 +194,16 @@ func WatchWithClient(ctx context.Context, taskID string, cli *redis.Client) (*Ca
 	return watcher, derivedCtx, cancelFn, nil
 }
 
-// Cancel marks the workflow task as cancelled. Running watchers will detect the
-// marker and cancel their contexts.
-// Note: This function uses the global Redis client. For dependency injection in tests,
-// use CancelWithClient instead.
+// Cancel marks the workflow task as cancelled through the configured Backend.
+// Running watchers will detect the marker and cancel their contexts.
 func Cancel(ctx context.Context, taskID, reason string) error {
-	return CancelWithClient(ctx, taskID, reason, cache.GetGlobalRedisClient())
+	return currentBackend().Cancel(ctx, taskID, reason)
 }
 
 // CancelWithClient is like Cancel but accepts an explicit Redis client for dependency injection.
 func CancelWithClient(ctx context.Context, taskID, reason string, cli *redis.Client) error {
 	if cli == nil {
-		localCancelRegistryInstance.cancel(taskID, extractCancelReason(cancelMarker(reason)))
+		localCancelRegistryInstance.cancel(taskID, normalizeCancelReason(reason))
 		return nil
 	}
 	value := cancelMarker(reason)
@@ -202,8 +220,10 @@ func (w *CancelWatcher) Stop(ctx context.Context) {
 			close(w.stopCh)
 		}
 		w.wg.Wait()
+		if w.registry != nil {
+			w.registry.remove(w.taskID, w)
+		}
 		if w.cli == nil {
-			localCancelRegistryInstance.remove(w.taskID, w)
 			return
 		}
 		val, err := w.cli.Get(ctx, w.key).Result()
@@ -227,6 +247,14 @@ func (w *CancelWatcher) Reason() string {
 	return w.state.get()
 }
 
+// fire records the cancellation reason and cancels the watcher's context.
+func (w *CancelWatcher) fire(reason string) {
+	w.state.set(reason)
+	if w.cancelFn != nil {
+		w.cancelFn()
+	}
+}
+
 func (w *CancelWatcher) maintain(ctx context.Context, cancelFn context.CancelFunc) {
 	defer w.wg.Done()
 	ticker := time.NewTicker(extendInterval)
@@ -268,11 +296,15 @@ func (w *CancelWatcher) step(ctx context.Context, cancelFn context.CancelFunc) {
 }
 
 func cancelMarker(reason string) string {
+	return "cancelled:" + normalizeCancelReason(reason)
+}
+
+func normalizeCancelReason(reason string) string {
 	trimmed := strings.TrimSpace(reason)
 	if trimmed == "" {
-		trimmed = "cancelled"
+		return "cancelled"
 	}
-	return "cancelled:" + trimmed
+	return trimmed
 }
 
 func isCancelledToken(val string) bool {
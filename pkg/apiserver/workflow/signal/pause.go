@@ -0,0 +1,108 @@
+package signal
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// pauseKeyPrefix is the Redis prefix for workflow pause requests.
+	pauseKeyPrefix = "kubemin:workflow:pause:"
+	// pauseExpiry bounds how long an unobserved pause request lives. The controller only
+	// checks between priority buckets, so it must outlast the longest job.
+	pauseExpiry = 24 * time.Hour
+)
+
+// localPauseRegistry holds pause requests when Redis is not configured.
+type localPauseRegistry struct {
+	mu       sync.Mutex
+	requests map[string]string
+}
+
+func newLocalPauseRegistry() *localPauseRegistry {
+	return &localPauseRegistry{requests: make(map[string]string)}
+}
+
+var localPauseRegistryInstance = newLocalPauseRegistry()
+
+func (r *localPauseRegistry) pause(taskID, reason string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests[taskID] = reason
+}
+
+func (r *localPauseRegistry) requested(taskID string) (bool, string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reason, ok := r.requests[taskID]
+	return ok, reason
+}
+
+func (r *localPauseRegistry) clear(taskID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.requests, taskID)
+}
+
+// Pause requests the workflow task to stop before its next priority bucket, using the
+// configured Backend.
+func Pause(ctx context.Context, taskID, reason string) error {
+	return currentBackend().Pause(ctx, taskID, reason)
+}
+
+// PauseWithClient is like Pause but accepts an explicit Redis client for dependency injection.
+func PauseWithClient(ctx context.Context, taskID, reason string, cli *redis.Client) error {
+	reason = normalizePauseReason(reason)
+	if cli == nil {
+		localPauseRegistryInstance.pause(taskID, reason)
+		return nil
+	}
+	return cli.Set(ctx, pauseKeyPrefix+taskID, reason, pauseExpiry).Err()
+}
+
+// PauseRequested reports whether a pause is pending for the task, together with its reason.
+func PauseRequested(ctx context.Context, taskID string) (bool, string, error) {
+	return currentBackend().PauseRequested(ctx, taskID)
+}
+
+// PauseRequestedWithClient is like PauseRequested but accepts an explicit Redis client.
+func PauseRequestedWithClient(ctx context.Context, taskID string, cli *redis.Client) (bool, string, error) {
+	if cli == nil {
+		ok, reason := localPauseRegistryInstance.requested(taskID)
+		return ok, reason, nil
+	}
+	reason, err := cli.Get(ctx, pauseKeyPrefix+taskID).Result()
+	if err == redis.Nil {
+		return false, "", nil
+	}
+	if err != nil {
+		return false, "", err
+	}
+	return true, reason, nil
+}
+
+// ClearPause removes a pending pause request, e.g. on resume or once the task has finished.
+func ClearPause(ctx context.Context, taskID string) error {
+	return currentBackend().ClearPause(ctx, taskID)
+}
+
+// ClearPauseWithClient is like ClearPause but accepts an explicit Redis client.
+func ClearPauseWithClient(ctx context.Context, taskID string, cli *redis.Client) error {
+	if cli == nil {
+		localPauseRegistryInstance.clear(taskID)
+		return nil
+	}
+	return cli.Del(ctx, pauseKeyPrefix+taskID).Err()
+}
+
+func normalizePauseReason(reason string) string {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return "paused"
+	}
+	return reason
+}
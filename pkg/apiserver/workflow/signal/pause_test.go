@@ -0,0 +1,50 @@
+package signal
+
+import (
+	"context"
+	"testing"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPauseSignalRedis(t *testing.T) {
+	server, err := miniredis.Run()
+	if err != nil {
+		t.Skipf("start miniredis: %v", err)
+	}
+	defer server.Close()
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	ctx := context.Background()
+
+	paused, _, err := PauseRequestedWithClient(ctx, "task-pause", client)
+	require.NoError(t, err)
+	require.False(t, paused)
+
+	require.NoError(t, PauseWithClient(ctx, "task-pause", "investigating", client))
+	paused, reason, err := PauseRequestedWithClient(ctx, "task-pause", client)
+	require.NoError(t, err)
+	require.True(t, paused)
+	require.Equal(t, "investigating", reason)
+	require.Positive(t, server.TTL(pauseKeyPrefix+"task-pause"))
+
+	require.NoError(t, ClearPauseWithClient(ctx, "task-pause", client))
+	paused, _, err = PauseRequestedWithClient(ctx, "task-pause", client)
+	require.NoError(t, err)
+	require.False(t, paused)
+}
+
+func TestPauseSignalLocalFallback(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, PauseWithClient(ctx, "task-local-pause", "", nil))
+	paused, reason, err := PauseRequestedWithClient(ctx, "task-local-pause", nil)
+	require.NoError(t, err)
+	require.True(t, paused)
+	require.Equal(t, "paused", reason)
+
+	require.NoError(t, ClearPauseWithClient(ctx, "task-local-pause", nil))
+	paused, _, err = PauseRequestedWithClient(ctx, "task-local-pause", nil)
+	require.NoError(t, err)
+	require.False(t, paused)
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/klog/v2"
@@ -460,6 +461,10 @@ func getPodTemplateFromWorkload(workload runtime.Object) (*corev1.PodTemplateSpe
 		return &w.Spec.Template, nil
 	case *appsv1.DaemonSet:
 		return &w.Spec.Template, nil
+	case *batchv1.Job:
+		return &w.Spec.Template, nil
+	case *batchv1.CronJob:
+		return &w.Spec.JobTemplate.Spec.Template, nil
 	default:
 		return nil, fmt.Errorf("unsupported workload type: %T", workload)
 	}